@@ -0,0 +1,99 @@
+// Package analysis computes technical indicators over price series
+package analysis
+
+import (
+	"sort"
+	"strings"
+)
+
+// Indicator computes the latest value of an indicator over close prices ordered from oldest to newest,
+// it reports false when there are not enough prices for the period
+type Indicator func(closes []float64, period int) (float64, bool)
+
+// indicators are the indicators known by name
+var indicators = map[string]Indicator{
+	"rsi": RSI,
+	"sma": SMA,
+	"ema": EMA,
+}
+
+// Lookup returns the indicator with the given case-insensitive name
+func Lookup(name string) (Indicator, bool) {
+	indicator, ok := indicators[strings.ToLower(name)]
+	return indicator, ok
+}
+
+// Names returns names of all known indicators in sorted order
+func Names() []string {
+	names := make([]string, 0, len(indicators))
+	for name := range indicators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SMA returns the simple moving average of the last period prices
+func SMA(closes []float64, period int) (float64, bool) {
+	if period < 1 || len(closes) < period {
+		return 0, false
+	}
+
+	var sum float64
+	for _, price := range closes[len(closes)-period:] {
+		sum += price
+	}
+
+	return sum / float64(period), true
+}
+
+// EMA returns the exponential moving average, seeded with the SMA of the first period prices
+func EMA(closes []float64, period int) (float64, bool) {
+	ema, ok := SMA(closes[:min(period, len(closes))], period)
+	if !ok {
+		return 0, false
+	}
+
+	k := 2 / float64(period+1)
+	for _, price := range closes[period:] {
+		ema = price*k + ema*(1-k)
+	}
+
+	return ema, true
+}
+
+// RSI returns the relative strength index with Wilder's smoothing, it needs at least period+1 prices
+func RSI(closes []float64, period int) (float64, bool) {
+	if period < 1 || len(closes) <= period {
+		return 0, false
+	}
+
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := closes[i] - closes[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+
+	for i := period + 1; i < len(closes); i++ {
+		change := closes[i] - closes[i-1]
+		up, down := max(change, 0), max(-change, 0)
+		gain = (gain*float64(period-1) + up) / float64(period)
+		loss = (loss*float64(period-1) + down) / float64(period)
+	}
+
+	if loss == 0 {
+		if gain == 0 {
+			return 50, true
+		}
+		return 100, true
+	}
+
+	return 100 - 100/(1+gain/loss), true
+}
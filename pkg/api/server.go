@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+	"github.com/kirill/deriv-teletrader/pkg/metrics"
+)
+
+// derivOAuthURL is the Deriv OAuth authorization endpoint
+const derivOAuthURL = "https://oauth.deriv.com/oauth2/authorize"
+
+// stateCookie keeps the OAuth state between the login redirect and the callback
+const stateCookie = "teletrader_oauth_state"
+
+// Config holds configuration of the HTTP server
+type Config struct {
+	Listen  string `mapstructure:"listen"`  // Address to listen on, e.g. ":8080"; empty disables the server
+	Metrics bool   `mapstructure:"metrics"` // Serve metrics of the bot at /metrics in the Prometheus text format
+}
+
+// AccountLinker completes OAuth account linking
+type AccountLinker interface {
+	CompleteAccountLink(ctx context.Context, state string, accounts []core.LinkedAccount) error
+}
+
+// Server serves HTTP endpoints of the bot
+type Server struct {
+	cfg      *Config
+	appID    string
+	linker   AccountLinker
+	registry *metrics.Registry
+}
+
+// NewServer creates a new HTTP server
+func NewServer(cfg *Config, appID string, linker AccountLinker, registry *metrics.Registry) *Server {
+	return &Server{
+		cfg:      cfg,
+		appID:    appID,
+		linker:   linker,
+		registry: registry,
+	}
+}
+
+// Run serves HTTP requests until the context is canceled
+func (s *Server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /oauth/start", s.handleOAuthStart)
+	mux.HandleFunc("GET /oauth/callback", s.handleOAuthCallback)
+	if s.cfg.Metrics {
+		mux.HandleFunc("GET /metrics", s.handleMetrics)
+	}
+
+	server := &http.Server{
+		Addr:              s.cfg.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to shut down HTTP server: %v", err)
+		}
+	}()
+
+	log.Printf("Starting HTTP server on %s", s.cfg.Listen)
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP server failed: %w", err)
+	}
+
+	return nil
+}
+
+// handleMetrics serves metrics of the bot in the Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	if err := s.registry.WritePrometheus(w); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+	}
+}
+
+// handleOAuthStart remembers the login state in a cookie and redirects to Deriv OAuth
+func (s *Server) handleOAuthStart(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	if state == "" {
+		renderPage(w, http.StatusBadRequest, "Invalid login link", "Please request a new link with /connect in Telegram.")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    state,
+		Path:     "/oauth",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, derivOAuthURL+"?app_id="+url.QueryEscape(s.appID), http.StatusFound)
+}
+
+// handleOAuthCallback receives tokens from Deriv OAuth and links them to the user
+func (s *Server) handleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(stateCookie)
+	if err != nil {
+		renderPage(w, http.StatusBadRequest, "Login session expired", "Please request a new link with /connect in Telegram.")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: stateCookie, Path: "/oauth", MaxAge: -1})
+
+	accounts := parseAccounts(r.URL.Query())
+
+	if err := s.linker.CompleteAccountLink(r.Context(), cookie.Value, accounts); err != nil {
+		if errors.Is(err, core.ErrInvalidLinkState) {
+			renderPage(w, http.StatusBadRequest, "Login link expired", "Please request a new link with /connect in Telegram.")
+			return
+		}
+
+		log.Printf("Failed to link Deriv account: %v", err)
+		renderPage(w, http.StatusInternalServerError, "Failed to connect account", "Please try again later.")
+		return
+	}
+
+	renderPage(w, http.StatusOK, "Account connected", "Your Deriv account is connected. You can return to Telegram now.")
+}
+
+// parseAccounts extracts accounts from Deriv OAuth callback parameters acct1, token1, cur1, acct2, ...
+func parseAccounts(query url.Values) []core.LinkedAccount {
+	var accounts []core.LinkedAccount
+	for i := 1; ; i++ {
+		n := strconv.Itoa(i)
+
+		token := query.Get("token" + n)
+		if token == "" {
+			return accounts
+		}
+
+		accounts = append(accounts, core.LinkedAccount{
+			LoginID:  query.Get("acct" + n),
+			Token:    token,
+			Currency: query.Get("cur" + n),
+		})
+	}
+}
+
+// pageTemplate is a minimal page shown to users in the browser
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 10%">
+<h1>{{.Title}}</h1>
+<p>{{.Text}}</p>
+</body>
+</html>
+`))
+
+// renderPage writes a simple HTML page
+func renderPage(w http.ResponseWriter, status int, title, text string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	if err := pageTemplate.Execute(w, struct{ Title, Text string }{title, text}); err != nil {
+		log.Printf("Failed to render page: %v", err)
+	}
+}
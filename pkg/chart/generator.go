@@ -10,8 +10,15 @@ import (
 	"github.com/wcharczuk/go-chart/v2"
 )
 
-// GeneratePriceChart creates a price chart for the given historical data
-func GeneratePriceChart(data []types.HistoricalDataPoint, symbol string) (string, error) {
+// Level is a price drawn as a labeled horizontal line, e.g. the threshold of an alert
+type Level struct {
+	Label string
+	Price float64
+}
+
+// GeneratePriceChart creates a price chart for the given historical data with the levels drawn over it,
+// the watermark is written in the bottom right corner unless it's empty
+func GeneratePriceChart(data []types.HistoricalDataPoint, symbol string, levels []Level, watermark string) (string, error) {
 	// Create temporary directory if it doesn't exist
 	tmpDir := filepath.Join(os.TempDir(), "deriv-teletrader")
 	if err := os.MkdirAll(tmpDir, 0755); err != nil {
@@ -75,9 +82,48 @@ func GeneratePriceChart(data []types.HistoricalDataPoint, symbol string) (string
 		Series: []chart.Series{series},
 	}
 
+	// Levels span the whole time range, their labels are placed at its end
+	if len(xValues) > 0 && len(levels) > 0 {
+		first, last := xValues[0], xValues[len(xValues)-1]
+		labels := chart.AnnotationSeries{Name: "Levels"}
+
+		for _, level := range levels {
+			graph.Series = append(graph.Series, chart.TimeSeries{
+				Name:    level.Label,
+				XValues: []time.Time{first, last},
+				YValues: []float64{level.Price, level.Price},
+				Style: chart.Style{
+					StrokeColor:     chart.ColorOrange,
+					StrokeWidth:     1,
+					StrokeDashArray: []float64{5, 5},
+				},
+			})
+			labels.Annotations = append(labels.Annotations, chart.Value2{
+				XValue: chart.TimeToFloat64(last),
+				YValue: level.Price,
+				Label:  level.Label,
+			})
+		}
+
+		graph.Series = append(graph.Series, labels)
+	}
+
 	// Add title
 	graph.Title = fmt.Sprintf("%s Price Chart", symbol)
 
+	// Forwarded charts keep telling where they come from
+	if watermark != "" {
+		graph.Elements = append(graph.Elements, func(r chart.Renderer, canvasBox chart.Box, defaults chart.Style) {
+			style := chart.Style{
+				Font:      defaults.Font,
+				FontSize:  9,
+				FontColor: chart.ColorAlternateGray,
+			}
+			size := chart.Draw.MeasureText(r, watermark, style)
+			chart.Draw.Text(r, watermark, canvasBox.Right-size.Width()-5, canvasBox.Bottom-5, style)
+		})
+	}
+
 	// Create output file
 	outputPath := filepath.Join(tmpDir, fmt.Sprintf("%s_%d.png", symbol, time.Now().Unix()))
 	f, err := os.Create(outputPath)
@@ -4,8 +4,17 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/kirill/deriv-teletrader/pkg/api"
+	"github.com/kirill/deriv-teletrader/pkg/core"
+	"github.com/kirill/deriv-teletrader/pkg/journal"
+	"github.com/kirill/deriv-teletrader/pkg/leader"
+	"github.com/kirill/deriv-teletrader/pkg/plugin"
 	"github.com/kirill/deriv-teletrader/pkg/prov/deriv"
 	"github.com/kirill/deriv-teletrader/pkg/prov/llm"
+	"github.com/kirill/deriv-teletrader/pkg/prov/ohlc"
+	"github.com/kirill/deriv-teletrader/pkg/prov/webhook"
+	"github.com/kirill/deriv-teletrader/pkg/recording"
+	"github.com/kirill/deriv-teletrader/pkg/store"
 	"github.com/kirill/deriv-teletrader/pkg/telegram"
 	"github.com/spf13/viper"
 )
@@ -19,6 +28,33 @@ type Config struct {
 
 	// LLM settings
 	LLM llm.Config `mapstructure:"llm"`
+
+	// Core bot settings
+	Bot core.Config `mapstructure:"bot"`
+
+	// Persistence settings
+	Store store.Config `mapstructure:"store"`
+
+	// Trade journal database
+	Journal journal.Config `mapstructure:"journal"`
+
+	// HTTP server settings
+	HTTP api.Config `mapstructure:"http"`
+
+	// External plugins
+	Plugins []plugin.Config `mapstructure:"plugins"`
+
+	// Coordination of several instances sharing the store
+	HA leader.Config `mapstructure:"ha"`
+
+	// Delivery of trade events to user webhooks
+	Webhooks webhook.Config `mapstructure:"webhooks"`
+
+	// Archiving ticks of selected symbols
+	Recording recording.Config `mapstructure:"recording"`
+
+	// External read-only prices cross-checked against Deriv ones
+	Benchmark ohlc.Config `mapstructure:"benchmark"`
 }
 
 // InitConfig initializes the configuration using Viper
@@ -54,6 +90,10 @@ func InitConfig(cfgFile string) (*Config, error) {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	if err := deriv.ApplyPreset(&cfg.Deriv); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -64,6 +104,46 @@ func InitConfig(cfgFile string) (*Config, error) {
 func setDefaults() {
 	viper.SetDefault("deriv.endpoint", "wss://ws.binaryws.com/websockets/v3")
 	viper.SetDefault("deriv.symbols", []string{"R_10", "R_25", "R_50", "R_75", "R_100"})
+	viper.SetDefault("deriv.retry.max_attempts", 3)
+	viper.SetDefault("deriv.retry.initial_backoff", "200ms")
+	viper.SetDefault("deriv.retry.max_backoff", "2s")
+	viper.SetDefault("bot.command_timeout", "30s")
+	viper.SetDefault("bot.command_timeouts.text", "2m")
+	viper.SetDefault("bot.trade_timeout", "2m")
+	viper.SetDefault("bot.price_cache_ttl", "1s")
+	viper.SetDefault("bot.candle_cache_ttl", "90s")
+	viper.SetDefault("bot.prewarm_interval", "1m")
+	viper.SetDefault("bot.contract_limits_ttl", "10m")
+	viper.SetDefault("bot.loss_streak.threshold", 3)
+	viper.SetDefault("bot.loss_streak.cooldown", "5m")
+	viper.SetDefault("bot.dashboard.refresh_interval", "30s")
+	viper.SetDefault("bot.dashboard.max_duration", "1h")
+	viper.SetDefault("bot.subscription_idle_timeout", "20m")
+	viper.SetDefault("bot.wipe_retention", "720h")
+	viper.SetDefault("bot.trade_confirm_timeout", "30s")
+	viper.SetDefault("bot.llm_cost.confirm_above", 20000)
+	viper.SetDefault("bot.reconciliation.at", "30m")
+	viper.SetDefault("bot.activity_alerts", true)
+	viper.SetDefault("llm.max_tool_calls", 5)
+	viper.SetDefault("llm.max_candles", 500)
+	viper.SetDefault("bot.expiry_alerts.lead", "30s")
+	viper.SetDefault("bot.expiry_alerts.max_duration", "10m")
+	viper.SetDefault("bot.watchdog.check_interval", "15s")
+	viper.SetDefault("bot.watchdog.window", "5m")
+	viper.SetDefault("bot.watchdog.max_error_rate", 0.5)
+	viper.SetDefault("bot.watchdog.min_calls", 10)
+	viper.SetDefault("bot.watchdog.max_reconnects", 5)
+	viper.SetDefault("bot.watchdog.probe_symbol", "R_100")
+	viper.SetDefault("bot.watchdog.max_price_age", "30s")
+	viper.SetDefault("telegram.idle_poll_timeout", "5m")
+	viper.SetDefault("store.path", "data/teletrader.json")
+	viper.SetDefault("store.encrypted_buckets", []string{"credentials", "settings", "webhooks", "copying", "wiped"})
+	viper.SetDefault("webhooks.timeout", "10s")
+	viper.SetDefault("benchmark.timeout", "10s")
+	viper.SetDefault("benchmark.name", "Benchmark")
+	viper.SetDefault("recording.dir", "recordings")
+	viper.SetDefault("recording.retention", 30)
+	viper.SetDefault("ha.lease_ttl", "15s")
 	viper.SetDefault("debug", false)
 }
 
@@ -75,7 +155,7 @@ func (c *Config) validate() error {
 		return fmt.Errorf("telegram.allowed_usernames is required")
 	}
 	if c.Deriv.AppID == "" {
-		return fmt.Errorf("deriv.app_id is required")
+		return fmt.Errorf("deriv.app_id is required, register an app with `deriv-teletrader register-app` or set deriv.preset: demo")
 	}
 	if c.Deriv.APIToken == "" {
 		return fmt.Errorf("deriv.api_token is required")
@@ -83,5 +163,27 @@ func (c *Config) validate() error {
 	if c.LLM.APIKey == "" {
 		return fmt.Errorf("llm.api_key is required")
 	}
+	if c.Bot.PublicURL != "" && c.HTTP.Listen == "" {
+		return fmt.Errorf("http.listen is required when bot.public_url is set")
+	}
+	if chaos := c.Deriv.Chaos; chaos.Enabled {
+		if c.Deriv.TrafficMode != deriv.TrafficModeReplay {
+			return fmt.Errorf("deriv.chaos requires deriv.traffic_mode: replay, chaos is never injected into live traffic")
+		}
+		if chaos.ErrorRate < 0 || chaos.DisconnectRate < 0 || chaos.ErrorRate+chaos.DisconnectRate > 1 {
+			return fmt.Errorf("deriv.chaos error_rate and disconnect_rate must be between 0 and 1 and add up to at most 1")
+		}
+	}
+	if c.Benchmark.Enabled && c.Benchmark.URL == "" {
+		return fmt.Errorf("benchmark.url is required when the benchmark is enabled")
+	}
+	for i, p := range c.Plugins {
+		if p.Name == "" || p.Command == "" {
+			return fmt.Errorf("plugins[%d]: name and command are required", i)
+		}
+		if len(p.Commands) == 0 && len(p.Strategies) == 0 {
+			return fmt.Errorf("plugins[%d]: at least one command or strategy is required", i)
+		}
+	}
 	return nil
 }
@@ -32,6 +32,9 @@ market data access.`,
 
 	// Add commands
 	rootCmd.AddCommand(newStartCmd(&cfg))
+	rootCmd.AddCommand(newStoreCmd(&cfg))
+	rootCmd.AddCommand(newRegisterAppCmd())
+	rootCmd.AddCommand(newDoctorCmd(&cfg))
 
 	return rootCmd
 }
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kirill/deriv-teletrader/pkg/prov/deriv"
+	"github.com/spf13/cobra"
+)
+
+// requiredScopes are token permissions the bot needs
+var requiredScopes = []string{"read", "trade"}
+
+// newRegisterAppCmd creates and returns the command registering a Deriv app for the bot
+func newRegisterAppCmd() *cobra.Command {
+	var token, name, redirectURI, endpoint string
+	var scopes []string
+
+	cmd := &cobra.Command{
+		Use:   "register-app",
+		Short: "Register a Deriv application and print the config to use it",
+		Long: `Register an application with Deriv and print the deriv section of config.yaml.
+
+Create an API token with the Admin scope at https://app.deriv.com/account/api-token
+(on a demo account to start with virtual money) and pass it with --token. The app
+belongs to that account. Give --redirect-uri to let users link accounts with OAuth,
+it's <bot.public_url>/oauth/callback.
+
+No config file is needed to run this command.`,
+		// Registration comes before there is a working config, so it isn't loaded
+		PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRegisterAppCmd(cmd.Context(), endpoint, token, deriv.AppRegistration{
+				Name:        name,
+				RedirectURI: redirectURI,
+				Scopes:      scopes,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&token, "token", "", "Deriv API token with the admin scope")
+	cmd.Flags().StringVar(&name, "name", "deriv-teletrader", "application name")
+	cmd.Flags().StringVar(&redirectURI, "redirect-uri", "", "OAuth redirect URI, optional")
+	cmd.Flags().StringSliceVar(&scopes, "scopes", requiredScopes, "permissions users grant the app")
+	cmd.Flags().StringVar(&endpoint, "endpoint", "wss://ws.derivws.com/websockets/v3", "Deriv API endpoint")
+
+	return cmd
+}
+
+// runRegisterAppCmd handles the register-app command execution
+func runRegisterAppCmd(ctx context.Context, endpoint, token string, app deriv.AppRegistration) error {
+	if token == "" {
+		return fmt.Errorf("--token is required, create one with the Admin scope at https://app.deriv.com/account/api-token")
+	}
+
+	appID, err := deriv.RegisterApp(ctx, endpoint, token, app)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Registered app %q with ID %s. Add it to config.yaml:\n\n", app.Name, appID)
+	fmt.Printf("deriv:\n  app_id: %q\n  endpoint: %q\n  api_token: \"<token with read and trade scopes>\"\n\n", appID, endpoint)
+	fmt.Println("Then run `deriv-teletrader doctor` to check the app ID and token work together.")
+
+	return nil
+}
+
+// newDoctorCmd creates and returns the command checking the configured Deriv app and token
+func newDoctorCmd(cfg **Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the Deriv app ID and API token work together",
+		Long: `Connect to Deriv with the configured app ID, authorize the API token and report
+the account it belongs to, whether it's a demo or real one, and missing token scopes.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctorCmd(cmd.Context(), *cfg)
+		},
+	}
+}
+
+// runDoctorCmd handles the doctor command execution
+func runDoctorCmd(ctx context.Context, cfg *Config) error {
+	fmt.Printf("Endpoint: %s\n", cfg.Deriv.Endpoint)
+	if cfg.Deriv.Preset != "" {
+		fmt.Printf("Preset: %s\n", cfg.Deriv.Preset)
+	}
+	fmt.Printf("App ID: %s\n", cfg.Deriv.AppID)
+
+	// Traffic recording is for the bot itself, checks always go to the real API
+	derivCfg := cfg.Deriv
+	derivCfg.TrafficMode = ""
+
+	client, err := deriv.NewClient(&derivCfg)
+	if err != nil {
+		fmt.Println("❌ App ID is invalid")
+		return err
+	}
+
+	if err := client.Connect(ctx); err != nil {
+		fmt.Println("❌ The app ID and token don't work together, check both were created on the same Deriv site")
+		return err
+	}
+	defer client.Close()
+
+	account := client.Account()
+	kind := "real"
+	if account.Virtual {
+		kind = "demo"
+	}
+	fmt.Printf("✅ Token authorized for %s (%s account, %s)\n", account.LoginID, kind, client.Currency())
+
+	var missing []string
+	for _, scope := range requiredScopes {
+		if !slices.Contains(account.Scopes, scope) {
+			missing = append(missing, scope)
+		}
+	}
+
+	if len(missing) > 0 {
+		fmt.Printf("❌ Token lacks scopes: %s\n", strings.Join(missing, ", "))
+		return fmt.Errorf("token lacks required scopes")
+	}
+	fmt.Printf("✅ Token scopes: %s\n", strings.Join(account.Scopes, ", "))
+
+	if !account.Virtual {
+		fmt.Println("⚠️ Trades will use real money. Use a demo account token or deriv.preset: demo to try the bot first.")
+	}
+
+	return nil
+}
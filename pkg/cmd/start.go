@@ -6,11 +6,21 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sync"
 	"syscall"
 
+	"github.com/kirill/deriv-teletrader/pkg/api"
 	"github.com/kirill/deriv-teletrader/pkg/core"
+	"github.com/kirill/deriv-teletrader/pkg/journal"
+	"github.com/kirill/deriv-teletrader/pkg/leader"
+	"github.com/kirill/deriv-teletrader/pkg/plugin"
 	"github.com/kirill/deriv-teletrader/pkg/prov/deriv"
 	"github.com/kirill/deriv-teletrader/pkg/prov/llm"
+	"github.com/kirill/deriv-teletrader/pkg/prov/ohlc"
+	"github.com/kirill/deriv-teletrader/pkg/prov/webhook"
+	"github.com/kirill/deriv-teletrader/pkg/recording"
+	"github.com/kirill/deriv-teletrader/pkg/store"
 	"github.com/kirill/deriv-teletrader/pkg/telegram"
 	"github.com/spf13/cobra"
 )
@@ -55,17 +65,104 @@ func runStartCmd(ctx context.Context, cfg *Config, debug bool) error {
 		return fmt.Errorf("failed to create Deriv client: %w", err)
 	}
 
+	// Initialize pool of connections authorized with user tokens
+	derivPool := deriv.NewPool(&cfg.Deriv)
+	defer derivPool.Close()
+
 	// Initialize LLM client
 	llmClient, err := llm.NewClient(&cfg.LLM)
 	if err != nil {
 		return fmt.Errorf("failed to create LLM client: %w", err)
 	}
 
+	// Initialize persistence
+	dataStore, err := store.New(&cfg.Store)
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+	defer dataStore.Close()
+
 	// Initialize core bot
-	coreBot, err := core.NewBot(derivClient, llmClient, cfg.Telegram.AllowedUsernames, cfg.Deriv.Symbols)
+	coreBot, err := core.NewBot(&cfg.Bot, derivClient, derivPool, llmClient, dataStore, cfg.Telegram.AllowedUsernames, cfg.Deriv.Symbols)
+	if err != nil {
+		return err
+	}
+
+	// Stop when an admin asks for it with /shutdown
+	go func() {
+		select {
+		case <-coreBot.ShutdownRequested():
+			log.Println("Shutdown requested by an admin, shutting down...")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Feed the watchdog with outcomes of Deriv API calls
+	derivClient.SetHealthObserver(coreBot.Watchdog())
+	derivPool.SetHealthObserver(coreBot.Watchdog())
+
+	if cfg.Webhooks.Enabled {
+		coreBot.SetWebhookSender(webhook.NewClient(&cfg.Webhooks))
+	}
+
+	if cfg.Benchmark.Enabled {
+		coreBot.SetBenchmark(ohlc.NewClient(&cfg.Benchmark))
+	}
+
+	// Trades are journaled in a SQLite database, next to the data store unless its path is configured.
+	// Ones recorded in the store by earlier versions are moved over so the trading history is kept
+	if cfg.Journal.Path == "" {
+		cfg.Journal.Path = filepath.Join(filepath.Dir(cfg.Store.Path), journal.DefaultFile)
+	}
+
+	tradeJournal, err := journal.Open(&cfg.Journal)
 	if err != nil {
 		return err
 	}
+	defer tradeJournal.Close()
+
+	moved, err := core.MoveJournal(ctx, dataStore, tradeJournal)
+	if err != nil {
+		return fmt.Errorf("failed to move trades to the journal: %w", err)
+	}
+	if moved > 0 {
+		log.Printf("Moved %d trades from the store to the journal", moved)
+	}
+
+	coreBot.SetJournal(tradeJournal)
+
+	if cfg.Recording.Enabled {
+		recorder, err := recording.New(&cfg.Recording)
+		if err != nil {
+			return err
+		}
+		coreBot.SetTickRecorder(recorder)
+	}
+
+	// Start plugins and register their commands and strategies
+	for i := range cfg.Plugins {
+		p, err := plugin.Start(ctx, &cfg.Plugins[i])
+		if err != nil {
+			return err
+		}
+		defer p.Close()
+
+		for _, command := range p.Commands() {
+			if err := coreBot.RegisterCommand(command, p.Description(), p.Handle); err != nil {
+				return fmt.Errorf("plugin %s: %w", p.Name(), err)
+			}
+		}
+
+		for _, strategy := range p.Strategies() {
+			signal := func(ctx context.Context, symbol string, prices []float64, params map[string]float64) (string, error) {
+				return p.Signal(ctx, strategy, symbol, prices, params)
+			}
+			if err := coreBot.RegisterStrategy(strategy, p.Description(), signal); err != nil {
+				return fmt.Errorf("plugin %s: %w", p.Name(), err)
+			}
+		}
+	}
 
 	// Connect to Deriv API
 	if err := derivClient.Connect(ctx); err != nil {
@@ -73,12 +170,58 @@ func runStartCmd(ctx context.Context, cfg *Config, debug bool) error {
 	}
 	defer derivClient.Close()
 
+	if !cfg.HA.Enabled {
+		return serve(ctx, cfg, coreBot, debug)
+	}
+
+	// With several instances sharing the store only the leader talks to users and runs background jobs
+	return leader.Run(ctx, &cfg.HA, dataStore, func(ctx context.Context) error {
+		// The previous leader may have changed data since it was loaded
+		if err := dataStore.Reload(); err != nil {
+			return err
+		}
+
+		return serve(ctx, cfg, coreBot, debug)
+	})
+}
+
+// serve polls Telegram for updates, runs background jobs and the HTTP server until ctx is canceled
+func serve(ctx context.Context, cfg *Config, coreBot *core.Bot, debug bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Everything started here must stop before another instance may take over
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// Start HTTP server for OAuth callbacks
+	if cfg.HTTP.Listen != "" {
+		server := api.NewServer(&cfg.HTTP, cfg.Deriv.AppID, coreBot, coreBot.Metrics())
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := server.Run(ctx); err != nil {
+				log.Printf("HTTP server stopped: %v", err)
+			}
+		}()
+	}
+
 	// Initialize telegram bot
 	bot, err := telegram.NewBot(&cfg.Telegram, coreBot)
 	if err != nil {
 		return err
 	}
 
+	// Deliver messages from background jobs such as dashboards
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := coreBot.Run(ctx, bot); err != nil {
+			log.Printf("Background jobs stopped: %v", err)
+		}
+	}()
+
 	// Start bot
 	log.Printf("Starting bot (debug: %v)...\n", debug)
 	if err := bot.Start(ctx); err != nil {
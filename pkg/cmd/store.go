@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kirill/deriv-teletrader/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+// newStoreCmd creates and returns the command group for store maintenance
+func newStoreCmd(cfg **Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: "Maintain the bot data store",
+	}
+
+	cmd.AddCommand(newStoreRekeyCmd(cfg))
+
+	return cmd
+}
+
+// newStoreRekeyCmd creates and returns the command re-encrypting the store with a new key
+func newStoreRekeyCmd(cfg **Config) *cobra.Command {
+	var newKey, newKeyFile string
+
+	cmd := &cobra.Command{
+		Use:   "rekey",
+		Short: "Re-encrypt sensitive data with a new encryption key",
+		Long: `Re-encrypt values of encrypted buckets with a new key. Values are decrypted
+with the configured encryption key or any of old_encryption_keys. Plain values
+left in buckets that were marked sensitive later are encrypted as well.
+
+After rekeying, set the new key as store.encryption_key (or encryption_key_file).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStoreRekeyCmd(*cfg, newKey, newKeyFile)
+		},
+	}
+
+	cmd.Flags().StringVar(&newKey, "new-key", "", "new encryption passphrase")
+	cmd.Flags().StringVar(&newKeyFile, "new-key-file", "", "file containing the new encryption passphrase")
+
+	return cmd
+}
+
+// runStoreRekeyCmd handles the store rekey command execution
+func runStoreRekeyCmd(cfg *Config, newKey, newKeyFile string) error {
+	key, err := store.LoadKey(newKey, newKeyFile)
+	if err != nil {
+		return err
+	}
+
+	if key == "" {
+		return fmt.Errorf("either --new-key or --new-key-file is required")
+	}
+
+	dataStore, err := store.New(&cfg.Store)
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+
+	count, err := dataStore.Rekey(key)
+	if err != nil {
+		return fmt.Errorf("failed to rekey store: %w", err)
+	}
+
+	fmt.Printf("Re-encrypted %d values. Update store.encryption_key to the new key before starting the bot.\n", count)
+
+	return nil
+}
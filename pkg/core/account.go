@@ -0,0 +1,215 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Account is a Deriv account a token is authorized for
+type Account struct {
+	LoginID  string
+	Virtual  bool // Demo account trading with virtual money
+	Currency string
+}
+
+// Label names the account and its kind, e.g. "CR123456 (real)"
+func (a Account) Label() string {
+	if a.Virtual {
+		return a.LoginID + " (demo)"
+	}
+	return a.LoginID + " (real)"
+}
+
+// accountOption is an account the user can switch to, with the token authorizing it
+type accountOption struct {
+	Account
+	token string // Empty for the main shared account
+}
+
+// handleAccount lists accounts of the user and switches the one trades go to, "/account [loginid]"
+func (b *Bot) handleAccount(ctx context.Context, msg *Message) (*Response, error) {
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	active := client.ActiveAccount()
+
+	var creds Credentials
+	err = b.storage.Get(ctx, bucketCredentials, msg.Username, &creds)
+	own := err == nil
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	var options []accountOption
+	switch {
+	case own:
+		options = linkedOptions(&creds, client)
+	case b.cfg.UserTokens[msg.Username] == "":
+		options = b.sharedAccounts(ctx)
+	}
+
+	if len(msg.Args) == 1 {
+		return b.switchAccount(ctx, msg, &creds, own, options, msg.Args[0])
+	}
+
+	var sb strings.Builder
+	sb.WriteString("👤 Accounts\n\n")
+	fmt.Fprintf(&sb, "✅ %s · %s (active)\n", active.Label(), active.Currency)
+
+	var buttons [][]Button
+	listed := map[string]bool{active.LoginID: true}
+	for _, option := range options {
+		if listed[option.LoginID] {
+			continue
+		}
+		listed[option.LoginID] = true
+
+		fmt.Fprintf(&sb, "▫️ %s · %s\n", option.Label(), option.Currency)
+		buttons = append(buttons, []Button{{Text: "🔁 Switch to " + option.Label(), CallbackData: "account:" + option.LoginID}})
+	}
+
+	for _, account := range client.AccountList() {
+		if listed[account.LoginID] {
+			continue
+		}
+		listed[account.LoginID] = true
+
+		fmt.Fprintf(&sb, "🔒 %s · %s, not authorized\n", account.Label(), account.Currency)
+	}
+
+	if len(buttons) == 0 {
+		sb.WriteString("\nThere are no other accounts to switch to.")
+	}
+
+	return NewResponse(msg).Text(strings.TrimRight(sb.String(), "\n")).Keyboard(buttons).Build(), nil
+}
+
+// switchAccount makes the account with the login ID the one trades of the user go to
+func (b *Bot) switchAccount(ctx context.Context, msg *Message, creds *Credentials, own bool, options []accountOption, loginID string) (*Response, error) {
+	rb := NewResponse(msg)
+	if msg.CallbackData != "" {
+		rb.Edit(msg.MessageID)
+	}
+
+	var target *accountOption
+	for i := range options {
+		if options[i].LoginID == loginID {
+			target = &options[i]
+			break
+		}
+	}
+
+	if target == nil {
+		return rb.Textf("❌ Account %s is not available to switch to. Use /account to see your accounts.", loginID).Build(), nil
+	}
+
+	if own {
+		if target.token == creds.Token {
+			return rb.Textf("ℹ️ %s is already active.", target.Label()).Build(), nil
+		}
+
+		// The new account must work before the old connection is dropped
+		if _, err := b.pool.Client(ctx, target.token); err != nil {
+			log.Printf("Failed to connect account %s of %s: %v", loginID, msg.Username, err)
+			return rb.Textf("❌ Failed to connect %s. Please log in again with /connect.", target.Label()).Build(), nil
+		}
+
+		previous := creds.Token
+		creds.Token = target.token
+		if err := b.storage.Put(ctx, bucketCredentials, msg.Username, creds); err != nil {
+			return nil, fmt.Errorf("failed to save credentials: %w", err)
+		}
+
+		if err := b.pool.Release(previous); err != nil {
+			log.Printf("Failed to release previous connection of %s: %v", msg.Username, err)
+		}
+	} else {
+		settings, err := b.getSettings(ctx, msg.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		settings.Account = ""
+		if target.token != "" {
+			settings.Account = target.LoginID
+		}
+
+		if err := b.saveSettings(ctx, msg.Username, settings); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("Audit: %s switched to account %s", msg.Username, target.LoginID)
+
+	return rb.Textf("✅ Trading on %s now.", target.Label()).Build(), nil
+}
+
+// linkedOptions returns accounts authorized for the user through OAuth, the account list of the client tells which are demo ones
+func linkedOptions(creds *Credentials, client DerivClient) []accountOption {
+	kinds := make(map[string]bool)
+	for _, account := range client.AccountList() {
+		kinds[account.LoginID] = account.Virtual
+	}
+
+	options := make([]accountOption, 0, len(creds.Accounts))
+	for _, linked := range creds.Accounts {
+		options = append(options, accountOption{
+			Account: Account{LoginID: linked.LoginID, Virtual: kinds[linked.LoginID], Currency: linked.Currency},
+			token:   linked.Token,
+		})
+	}
+
+	return options
+}
+
+// sharedAccounts returns the main shared account and the accounts of the configured account tokens,
+// tokens that fail to connect are left out
+func (b *Bot) sharedAccounts(ctx context.Context) []accountOption {
+	options := []accountOption{{Account: b.derivClient.ActiveAccount()}}
+
+	for _, token := range b.cfg.AccountTokens {
+		client, err := b.pool.Client(ctx, token)
+		if err != nil {
+			log.Printf("Failed to connect shared account token: %v", err)
+			continue
+		}
+
+		options = append(options, accountOption{Account: client.ActiveAccount(), token: token})
+	}
+
+	return options
+}
+
+// sharedClient returns the client of the shared account the user picked with /account, the main one by default
+func (b *Bot) sharedClient(ctx context.Context, username string) (DerivClient, error) {
+	if len(b.cfg.AccountTokens) == 0 {
+		return b.derivClient, nil
+	}
+
+	settings, err := b.getSettings(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.Account == "" {
+		return b.derivClient, nil
+	}
+
+	for _, option := range b.sharedAccounts(ctx) {
+		if option.LoginID == settings.Account && option.token != "" {
+			client, err := b.pool.Client(ctx, option.token)
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect shared account: %w", err)
+			}
+			return client, nil
+		}
+	}
+
+	// The picked account is no longer configured
+	return b.derivClient, nil
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// activityScanInterval is how often accounts of users active in this session are checked for a transaction stream
+const activityScanInterval = time.Minute
+
+// activityGrace is how long a buy waits for the bot to record its trade before it's reported as made elsewhere
+const activityGrace = 10 * time.Second
+
+// activityWatch is a transaction stream of the own account of a user
+type activityWatch struct {
+	token  string
+	cancel context.CancelFunc
+}
+
+// activityWatches holds running transaction streams by username
+type activityWatches struct {
+	mu      sync.Mutex
+	running map[string]*activityWatch
+}
+
+// runActivityMonitor watches transactions of own accounts of users active in this session,
+// so trades and transfers made from the Deriv website or app show up in the chat
+func (b *Bot) runActivityMonitor(ctx context.Context, notifier Notifier) {
+	ticker := time.NewTicker(activityScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for username, chatID := range b.sessions.all() {
+			token, err := b.activityToken(ctx, username)
+			if err != nil {
+				log.Printf("Failed to check the account of %s for activity: %v", username, err)
+				continue
+			}
+
+			b.watchActivity(ctx, notifier, username, chatID, token)
+		}
+	}
+}
+
+// activityToken returns the token of the user's own account, empty when they trade on the shared one.
+// It doesn't count as use of the account, so watching doesn't keep idle sessions alive.
+func (b *Bot) activityToken(ctx context.Context, username string) (string, error) {
+	var creds Credentials
+	err := b.storage.Get(ctx, bucketCredentials, username, &creds)
+	switch {
+	case err == nil && creds.Expired:
+		return "", nil
+	case err == nil:
+		return creds.Token, nil
+	case !errors.Is(err, ErrNotFound):
+		return "", fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	return b.cfg.UserTokens[username], nil
+}
+
+// watchActivity starts streaming transactions of the account with the token, replacing a stream of another account
+func (b *Bot) watchActivity(ctx context.Context, notifier Notifier, username string, chatID int64, token string) {
+	b.activity.mu.Lock()
+	defer b.activity.mu.Unlock()
+
+	current, ok := b.activity.running[username]
+	if ok && current.token == token {
+		return
+	}
+
+	if ok {
+		current.cancel()
+		delete(b.activity.running, username)
+	}
+
+	if token == "" {
+		return
+	}
+
+	client, err := b.pool.Client(ctx, token)
+	if err != nil {
+		log.Printf("Failed to connect the account of %s for activity: %v", username, err)
+		return
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	transactions, err := client.WatchTransactions(watchCtx)
+	if err != nil {
+		cancel()
+		log.Printf("Failed to watch activity of %s: %v", username, err)
+		return
+	}
+
+	watch := &activityWatch{token: token, cancel: cancel}
+
+	err = b.background.goService(func(context.Context, Notifier) {
+		defer cancel()
+
+		for tx := range transactions {
+			b.reportActivity(watchCtx, notifier, username, chatID, client.Currency(), tx)
+		}
+
+		// The stream ended, e.g. the connection was released, the next scan starts it again
+		b.activity.mu.Lock()
+		if b.activity.running[username] == watch {
+			delete(b.activity.running, username)
+		}
+		b.activity.mu.Unlock()
+	})
+	if err != nil {
+		cancel()
+		return
+	}
+
+	b.activity.running[username] = watch
+}
+
+// reportActivity tells the user about a transaction the bot didn't make.
+// Sells are left out, as payouts at expiry are sells as well.
+func (b *Bot) reportActivity(ctx context.Context, notifier Notifier, username string, chatID int64, currency string, tx Transaction) {
+	switch tx.Action {
+	case "sell":
+		return
+	case "buy":
+		go func() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(activityGrace):
+			}
+
+			_, err := b.journal.Get(ctx, username, tx.ContractID)
+			if err == nil {
+				return
+			} else if !errors.Is(err, ErrNotFound) {
+				log.Printf("Failed to look up trade %d of %s: %v", tx.ContractID, username, err)
+				return
+			}
+
+			b.sendActivity(ctx, notifier, username, chatID, currency, tx)
+		}()
+	default:
+		b.sendActivity(ctx, notifier, username, chatID, currency, tx)
+	}
+}
+
+// sendActivity notifies the user of a transaction made outside the bot
+func (b *Bot) sendActivity(ctx context.Context, notifier Notifier, username string, chatID int64, currency string, tx Transaction) {
+	f := b.formatterFor(ctx, username, "")
+
+	action, ok := historyActions[tx.Action]
+	if !ok {
+		action = tx.Action
+	}
+
+	var sb strings.Builder
+	sb.WriteString("👀 Account activity outside the bot\n\n")
+	fmt.Fprintf(&sb, "%s %s, balance %s\n", action, f.SignedMoney(tx.Amount, currency), f.Money(tx.BalanceAfter, currency))
+	if tx.Longcode != "" {
+		fmt.Fprintf(&sb, "%s\n", tx.Longcode)
+	}
+	sb.WriteString("\nIf this wasn't you, change your Deriv password and revoke your API tokens, then use /disconnect.")
+
+	if _, err := b.notify(ctx, notifier, username, NotifyActivity, NewNotification(chatID).Text(sb.String()).Build()); err != nil {
+		log.Printf("Failed to report activity of %s: %v", username, err)
+	}
+}
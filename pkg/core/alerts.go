@@ -0,0 +1,488 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/analysis"
+	"github.com/kirill/deriv-teletrader/pkg/chart"
+)
+
+// bucketAlerts is the storage bucket holding indicator alerts of users
+const bucketAlerts = "alerts"
+
+const (
+	maxAlertsPerUser = 20                 // Alerts a user may have at once
+	maxAlertPeriod   = 200                // Longest indicator period accepted
+	alertHistory     = 3 * maxAlertPeriod // Closed candles kept per stream, RSI and EMA settle over several periods
+	alertTimeout     = 30 * time.Second   // Bounds loading history and evaluating alerts of a candle
+)
+
+// priceLevelIndicators are indicators measured in price, their alert thresholds are drawn on charts
+var priceLevelIndicators = map[string]bool{
+	"sma": true,
+	"ema": true,
+}
+
+// alertConditionPattern matches conditions such as "rsi(14,5m) < 25"
+var alertConditionPattern = regexp.MustCompile(`^([a-zA-Z]+)\(\s*(\d+)\s*,\s*(\w+)\s*\)\s*(<=|>=|<|>)\s*(-?[0-9.]+)$`)
+
+// IndicatorAlert notifies the user once an indicator of a symbol crosses the threshold on a candle close
+type IndicatorAlert struct {
+	ID        int       `json:"id"`
+	Symbol    string    `json:"symbol"`
+	Indicator string    `json:"indicator"`
+	Period    int       `json:"period"`
+	Timeframe string    `json:"timeframe"` // Candle size as accepted by /data, e.g. 5m
+	Operator  string    `json:"operator"`
+	Threshold float64   `json:"threshold"`
+	ChatID    int64     `json:"chat_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// String renders the alert condition, e.g. "R_50 rsi(14,5m) < 25"
+func (a *IndicatorAlert) String() string {
+	return fmt.Sprintf("%s %s(%d,%s) %s %s", a.Symbol, a.Indicator, a.Period, a.Timeframe, a.Operator,
+		strconv.FormatFloat(a.Threshold, 'f', -1, 64))
+}
+
+// granularity returns the candle size of the alert in seconds
+func (a *IndicatorAlert) granularity() int {
+	return exportGranularities[a.Timeframe]
+}
+
+// met reports whether the indicator value satisfies the condition
+func (a *IndicatorAlert) met(value float64) bool {
+	switch a.Operator {
+	case "<":
+		return value < a.Threshold
+	case "<=":
+		return value <= a.Threshold
+	case ">":
+		return value > a.Threshold
+	case ">=":
+		return value >= a.Threshold
+	}
+	return false
+}
+
+// parseAlertCondition parses a condition such as "rsi(14,5m) < 25" into an alert without symbol and chat
+func parseAlertCondition(condition string) (*IndicatorAlert, error) {
+	m := alertConditionPattern.FindStringSubmatch(strings.TrimSpace(condition))
+	if m == nil {
+		return nil, fmt.Errorf("condition should look like rsi(14,5m) < 25")
+	}
+
+	indicator := strings.ToLower(m[1])
+	if _, ok := analysis.Lookup(indicator); !ok {
+		return nil, fmt.Errorf("unknown indicator %s, available: %s", m[1], strings.Join(analysis.Names(), ", "))
+	}
+
+	period, err := strconv.Atoi(m[2])
+	if err != nil || period < 1 || period > maxAlertPeriod {
+		return nil, fmt.Errorf("period should be from 1 to %d", maxAlertPeriod)
+	}
+
+	timeframe := strings.ToLower(m[3])
+	if _, ok := exportGranularities[timeframe]; !ok {
+		return nil, fmt.Errorf("unknown timeframe %s, e.g. 1m, 5m, 1h or 1d", m[3])
+	}
+
+	threshold, err := strconv.ParseFloat(m[5], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid threshold %s", m[5])
+	}
+
+	return &IndicatorAlert{
+		Indicator: indicator,
+		Period:    period,
+		Timeframe: timeframe,
+		Operator:  m[4],
+		Threshold: threshold,
+	}, nil
+}
+
+// alertStream identifies candles alerts are evaluated on
+type alertStream struct {
+	Symbol      string
+	Granularity int
+}
+
+// alertStreamState holds closed candles of a stream and users having alerts on it
+type alertStreamState struct {
+	closes      []HistoricalDataPoint
+	users       map[string]int // Alerts on the stream by username
+	unsubscribe func()
+}
+
+// alertWatcher evaluates indicator alerts on candle close events
+type alertWatcher struct {
+	mu      sync.Mutex
+	streams map[alertStream]*alertStreamState
+	storage sync.Mutex // Serializes changes of users' alert lists
+}
+
+// getAlerts loads alerts of the user
+func (b *Bot) getAlerts(ctx context.Context, username string) ([]IndicatorAlert, error) {
+	var alerts []IndicatorAlert
+
+	err := b.storage.Get(ctx, bucketAlerts, username, &alerts)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// saveAlerts stores alerts of the user, removing the entry when there are none left
+func (b *Bot) saveAlerts(ctx context.Context, username string, alerts []IndicatorAlert) error {
+	if len(alerts) == 0 {
+		if err := b.storage.Delete(ctx, bucketAlerts, username); err != nil && !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("failed to delete alerts: %w", err)
+		}
+		return nil
+	}
+
+	if err := b.storage.Put(ctx, bucketAlerts, username, alerts); err != nil {
+		return fmt.Errorf("failed to save alerts: %w", err)
+	}
+
+	return nil
+}
+
+// watchAlert starts evaluating candles of the alert's stream for the user
+func (b *Bot) watchAlert(username string, alert *IndicatorAlert) {
+	stream := alertStream{Symbol: alert.Symbol, Granularity: alert.granularity()}
+
+	b.alerts.mu.Lock()
+	defer b.alerts.mu.Unlock()
+
+	state, ok := b.alerts.streams[stream]
+	if !ok {
+		state = &alertStreamState{users: make(map[string]int)}
+		b.alerts.streams[stream] = state
+		state.unsubscribe = b.events.Subscribe(
+			EventFilter{Type: EventCandleClose, Symbol: stream.Symbol, Granularity: stream.Granularity},
+			b.onAlertCandle,
+		)
+
+		go b.loadAlertHistory(stream, alert.Timeframe)
+	}
+
+	state.users[username]++
+}
+
+// unwatchAlert stops evaluating the alert's stream for the user, closing it when no alerts are left
+func (b *Bot) unwatchAlert(username string, alert *IndicatorAlert) {
+	stream := alertStream{Symbol: alert.Symbol, Granularity: alert.granularity()}
+
+	b.alerts.mu.Lock()
+	defer b.alerts.mu.Unlock()
+
+	state, ok := b.alerts.streams[stream]
+	if !ok {
+		return
+	}
+
+	state.users[username]--
+	if state.users[username] <= 0 {
+		delete(state.users, username)
+	}
+
+	if len(state.users) == 0 {
+		state.unsubscribe()
+		delete(b.alerts.streams, stream)
+	}
+}
+
+// loadAlertHistory seeds the stream with closed candles, so indicators can be computed right away
+func (b *Bot) loadAlertHistory(stream alertStream, timeframe string) {
+	ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+	defer cancel()
+
+	req, _ := exportRequest(stream.Symbol, timeframe, alertHistory)
+
+	candles, err := b.derivClient.GetHistoricalData(ctx, req)
+	if err != nil {
+		log.Printf("Failed to load candles of %s for alerts: %v", stream.Symbol, err)
+		return
+	}
+
+	// The latest candle may still be open
+	closedBefore := time.Now().Unix() - int64(stream.Granularity)
+
+	b.alerts.mu.Lock()
+	defer b.alerts.mu.Unlock()
+
+	state, ok := b.alerts.streams[stream]
+	if !ok {
+		return
+	}
+
+	var history []HistoricalDataPoint
+	for _, candle := range candles {
+		if candle.Timestamp > closedBefore || (len(state.closes) > 0 && candle.Timestamp >= state.closes[0].Timestamp) {
+			continue
+		}
+		history = append(history, candle)
+	}
+
+	state.closes = trimCandles(append(history, state.closes...))
+}
+
+// onAlertCandle records the closed candle and evaluates alerts on it in the background
+func (b *Bot) onAlertCandle(event Event) {
+	stream := alertStream{Symbol: event.Symbol, Granularity: event.Granularity}
+
+	b.alerts.mu.Lock()
+	state, ok := b.alerts.streams[stream]
+	if !ok {
+		b.alerts.mu.Unlock()
+		return
+	}
+
+	if n := len(state.closes); n == 0 || event.Price.Timestamp > state.closes[n-1].Timestamp {
+		state.closes = trimCandles(append(state.closes, event.Price))
+	}
+
+	closes := make([]float64, len(state.closes))
+	for i, candle := range state.closes {
+		closes[i] = candle.Close
+	}
+
+	usernames := make([]string, 0, len(state.users))
+	for username := range state.users {
+		usernames = append(usernames, username)
+	}
+	b.alerts.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		defer cancel()
+
+		for _, username := range usernames {
+			if err := b.checkAlerts(ctx, username, stream, closes); err != nil {
+				log.Printf("Failed to check alerts of %s: %v", username, err)
+			}
+		}
+	}()
+}
+
+// checkAlerts notifies the user about alerts of the stream whose condition is met and removes them
+func (b *Bot) checkAlerts(ctx context.Context, username string, stream alertStream, closes []float64) error {
+	notifier := b.background.currentNotifier()
+	if notifier == nil {
+		return nil
+	}
+
+	b.alerts.storage.Lock()
+	defer b.alerts.storage.Unlock()
+
+	alerts, err := b.getAlerts(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	var remaining, triggered []IndicatorAlert
+	values := make(map[int]float64)
+
+	for _, alert := range alerts {
+		if alert.Symbol != stream.Symbol || alert.granularity() != stream.Granularity {
+			remaining = append(remaining, alert)
+			continue
+		}
+
+		indicator, ok := analysis.Lookup(alert.Indicator)
+		if !ok {
+			remaining = append(remaining, alert)
+			continue
+		}
+
+		value, ok := indicator(closes, alert.Period)
+		if !ok || !alert.met(value) {
+			remaining = append(remaining, alert)
+			continue
+		}
+
+		triggered = append(triggered, alert)
+		values[alert.ID] = value
+	}
+
+	if len(triggered) == 0 {
+		return nil
+	}
+
+	if err := b.saveAlerts(ctx, username, remaining); err != nil {
+		return err
+	}
+
+	for i := range triggered {
+		alert := &triggered[i]
+		b.unwatchAlert(username, alert)
+
+		resp := NewNotification(alert.ChatID).
+			Textf("🔔 Alert #%d: %s(%d,%s) of %s is %.2f on the candle close, condition %s %s was met",
+				alert.ID, alert.Indicator, alert.Period, alert.Timeframe, alert.Symbol, values[alert.ID],
+				alert.Operator, strconv.FormatFloat(alert.Threshold, 'f', -1, 64)).
+			Build()
+		if _, err := b.notify(ctx, notifier, username, NotifyAlerts, resp); err != nil {
+			log.Printf("Failed to send alert #%d to %s: %v", alert.ID, username, err)
+		}
+	}
+
+	return nil
+}
+
+// chartLevels returns thresholds of the user's alerts on the symbol that are price levels, for drawing them on charts
+func (b *Bot) chartLevels(ctx context.Context, username, symbol string) []chart.Level {
+	alerts, err := b.getAlerts(ctx, username)
+	if err != nil {
+		log.Printf("Failed to load alerts of %s for the chart: %v", username, err)
+		return nil
+	}
+
+	var levels []chart.Level
+	for _, alert := range alerts {
+		if alert.Symbol == symbol && priceLevelIndicators[alert.Indicator] {
+			levels = append(levels, chart.Level{
+				Label: fmt.Sprintf("#%d %s(%d,%s) %s", alert.ID, alert.Indicator, alert.Period, alert.Timeframe, alert.Operator),
+				Price: alert.Threshold,
+			})
+		}
+	}
+
+	return levels
+}
+
+// trimCandles keeps the latest candles needed by alerts
+func trimCandles(candles []HistoricalDataPoint) []HistoricalDataPoint {
+	if len(candles) > alertHistory {
+		return candles[len(candles)-alertHistory:]
+	}
+	return candles
+}
+
+// runAlerts starts evaluating alerts of all users, it's a service of the bot
+func (b *Bot) runAlerts(ctx context.Context, _ Notifier) {
+	usernames, err := b.storage.Keys(ctx, bucketAlerts)
+	if err != nil {
+		log.Printf("Failed to list alerts: %v", err)
+		return
+	}
+
+	for _, username := range usernames {
+		alerts, err := b.getAlerts(ctx, username)
+		if err != nil {
+			log.Printf("Failed to load alerts of %s: %v", username, err)
+			continue
+		}
+
+		for i := range alerts {
+			b.watchAlert(username, &alerts[i])
+		}
+	}
+}
+
+// handleAlert manages indicator alerts, "/alert <symbol> <indicator>(<period>,<timeframe>) <op> <value>",
+// "/alert" lists them and "/alert del <id>" removes one
+func (b *Bot) handleAlert(ctx context.Context, msg *Message) (*Response, error) {
+	b.alerts.storage.Lock()
+	defer b.alerts.storage.Unlock()
+
+	alerts, err := b.getAlerts(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Args) == 0 {
+		return NewResponse(msg).Text(formatAlerts(alerts)).Build(), nil
+	}
+
+	if strings.EqualFold(msg.Args[0], "del") {
+		if len(msg.Args) != 2 {
+			return NewResponse(msg).Text("❌ Usage: /alert del <id>").Build(), nil
+		}
+
+		id, err := strconv.Atoi(strings.TrimPrefix(msg.Args[1], "#"))
+		if err != nil {
+			return NewResponse(msg).Text("❌ Usage: /alert del <id>").Build(), nil
+		}
+
+		for i := range alerts {
+			if alerts[i].ID != id {
+				continue
+			}
+
+			removed := alerts[i]
+			if err := b.saveAlerts(ctx, msg.Username, append(alerts[:i:i], alerts[i+1:]...)); err != nil {
+				return nil, err
+			}
+			b.unwatchAlert(msg.Username, &removed)
+
+			return NewResponse(msg).Textf("✅ Alert #%d removed", id).Build(), nil
+		}
+
+		return NewResponse(msg).Textf("❌ Alert #%d not found", id).Build(), nil
+	}
+
+	if len(msg.Args) < 2 {
+		return NewResponse(msg).Text("❌ Usage: /alert <symbol> <indicator>(<period>,<timeframe>) <op> <value>, e.g. /alert R_50 rsi(14,5m) < 25").Build(), nil
+	}
+
+	alert, err := parseAlertCondition(strings.Join(msg.Args[1:], " "))
+	if err != nil {
+		return NewResponse(msg).Textf("❌ %v", err).Build(), nil
+	}
+
+	if len(alerts) >= maxAlertsPerUser {
+		return NewResponse(msg).Textf("❌ You can have at most %d alerts, remove one with /alert del <id>", maxAlertsPerUser).Build(), nil
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "alert:" + strings.Join(append([]string{symbol}, msg.Args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	alert.Symbol = symbol
+	alert.ChatID = msg.ChatID
+	alert.CreatedAt = time.Now()
+	for _, existing := range alerts {
+		alert.ID = max(alert.ID, existing.ID)
+	}
+	alert.ID++
+
+	if err := b.saveAlerts(ctx, msg.Username, append(alerts, *alert)); err != nil {
+		return nil, err
+	}
+	b.watchAlert(msg.Username, alert)
+
+	return NewResponse(msg).Textf("✅ Alert #%d set: %s\nIt's checked on every candle close and fires once.", alert.ID, alert).Build(), nil
+}
+
+// formatAlerts renders the user's alerts ordered by ID
+func formatAlerts(alerts []IndicatorAlert) string {
+	if len(alerts) == 0 {
+		return "🔔 No alerts. Set one with /alert <symbol> <indicator>(<period>,<timeframe>) <op> <value>, e.g. /alert R_50 rsi(14,5m) < 25"
+	}
+
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].ID < alerts[j].ID })
+
+	var sb strings.Builder
+	sb.WriteString("🔔 Your alerts:\n")
+	for _, alert := range alerts {
+		fmt.Fprintf(&sb, "#%d %s\n", alert.ID, &alert)
+	}
+	sb.WriteString("\nRemove one with /alert del <id>")
+
+	return sb.String()
+}
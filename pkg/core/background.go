@@ -0,0 +1,181 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Notifier delivers messages that aren't replies to a user message, e.g. periodic updates
+type Notifier interface {
+	// Send sends the message or edits the one set in EditMessageID, returning the message ID
+	Send(ctx context.Context, resp *Response) (int, error)
+}
+
+// background runs long-lived jobs that outlive the command that started them
+type background struct {
+	mu         sync.Mutex
+	ctx        context.Context // Lifetime of the bot
+	jobs       context.Context // Lifetime of user jobs, canceled by the kill switch
+	cancelJobs context.CancelFunc
+	running    atomic.Int64 // User jobs in progress, not guarded by the lock as jobs finish without it
+	notifier   Notifier
+	wg         sync.WaitGroup
+}
+
+// Go starts a user job such as a dashboard, it fails when the bot isn't running
+func (bg *background) Go(job func(ctx context.Context, notifier Notifier)) error {
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+
+	if bg.jobs == nil || bg.jobs.Err() != nil {
+		return fmt.Errorf("bot is not running")
+	}
+
+	bg.running.Add(1)
+	bg.start(bg.jobs, func(ctx context.Context, notifier Notifier) {
+		defer bg.running.Add(-1)
+		job(ctx, notifier)
+	})
+
+	return nil
+}
+
+// goService starts a job running for the whole lifetime of the bot, it isn't affected by the kill switch
+func (bg *background) goService(job func(ctx context.Context, notifier Notifier)) error {
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+
+	if bg.ctx == nil || bg.ctx.Err() != nil {
+		return fmt.Errorf("bot is not running")
+	}
+
+	bg.start(bg.ctx, job)
+
+	return nil
+}
+
+// start runs the job in a goroutine, it must be called with the lock held
+func (bg *background) start(ctx context.Context, job func(ctx context.Context, notifier Notifier)) {
+	notifier := bg.notifier
+
+	bg.wg.Add(1)
+	go func() {
+		defer bg.wg.Done()
+		job(ctx, notifier)
+	}()
+}
+
+// cancelAll stops all running user jobs and returns their number, new jobs can be started right away
+func (bg *background) cancelAll() int {
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+
+	if bg.ctx == nil {
+		return 0
+	}
+
+	bg.cancelJobs()
+	bg.jobs, bg.cancelJobs = context.WithCancel(bg.ctx)
+
+	return int(bg.running.Load())
+}
+
+// currentNotifier returns the notifier of the running bot, nil when it isn't running
+func (bg *background) currentNotifier() Notifier {
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+
+	return bg.notifier
+}
+
+// Run enables background jobs delivering messages through the notifier.
+// It blocks until the context is canceled, sends users their session summaries and waits for all jobs to finish.
+func (b *Bot) Run(ctx context.Context, notifier Notifier) error {
+	started := time.Now()
+
+	b.background.mu.Lock()
+	b.background.ctx = ctx
+	b.background.jobs, b.background.cancelJobs = context.WithCancel(ctx)
+	b.background.notifier = notifier
+	b.background.mu.Unlock()
+
+	if b.cfg.Watchdog.CheckInterval > 0 {
+		if err := b.background.goService(b.runWatchdog); err != nil {
+			return fmt.Errorf("failed to start watchdog: %w", err)
+		}
+	}
+
+	if err := b.background.goService(b.runOutbox); err != nil {
+		return fmt.Errorf("failed to start notification delivery: %w", err)
+	}
+
+	if err := b.background.goService(b.runAlerts); err != nil {
+		return fmt.Errorf("failed to start alerts: %w", err)
+	}
+
+	if err := b.background.goService(b.runOrders); err != nil {
+		return fmt.Errorf("failed to start conditional orders: %w", err)
+	}
+
+	if err := b.background.goService(b.runStrategies); err != nil {
+		return fmt.Errorf("failed to start strategies: %w", err)
+	}
+
+	if b.cfg.PrewarmInterval > 0 && b.cfg.CandleCacheTTL > 0 {
+		if err := b.background.goService(b.runPrewarm); err != nil {
+			return fmt.Errorf("failed to start cache pre-warming: %w", err)
+		}
+	}
+
+	if b.recorder != nil {
+		if err := b.background.goService(b.runRecording); err != nil {
+			return fmt.Errorf("failed to start tick recording: %w", err)
+		}
+	}
+
+	if b.cfg.AccountIdleTimeout > 0 {
+		if err := b.background.goService(b.runSessionExpiry); err != nil {
+			return fmt.Errorf("failed to start account logouts: %w", err)
+		}
+	}
+
+	if b.cfg.SubscriptionIdleTimeout > 0 {
+		if err := b.background.goService(b.runSubscriptionGC); err != nil {
+			return fmt.Errorf("failed to start subscription cleanup: %w", err)
+		}
+	}
+
+	if b.cfg.ActivityAlerts {
+		if err := b.background.goService(b.runActivityMonitor); err != nil {
+			return fmt.Errorf("failed to start account activity monitor: %w", err)
+		}
+	}
+
+	if b.cfg.Reconciliation.Enabled {
+		if err := b.background.goService(b.runReconciliation); err != nil {
+			return fmt.Errorf("failed to start trade reconciliation: %w", err)
+		}
+	}
+
+	if err := b.background.goService(b.runWipePurge); err != nil {
+		return fmt.Errorf("failed to start wiped data purge: %w", err)
+	}
+
+	<-ctx.Done()
+
+	b.sendSessionSummaries(notifier, started)
+
+	// The lock isn't held while waiting, jobs may need it to finish. With the bot's context done no job can start.
+	b.background.mu.Lock()
+	b.background.cancelJobs()
+	b.background.mu.Unlock()
+
+	b.background.wg.Wait()
+
+	b.events.Close()
+
+	return nil
+}
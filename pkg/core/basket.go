@@ -0,0 +1,264 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucketBaskets is the storage bucket holding baskets of trades placed together
+const bucketBaskets = "baskets"
+
+// basketNamePattern restricts basket names, so they are safe to use in storage keys
+var basketNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Basket is a group of trades with the same stake and direction placed on several symbols at once
+type Basket struct {
+	Name        string            `json:"name"`
+	Username    string            `json:"username"`
+	Direction   string            `json:"direction"`
+	Stake       float64           `json:"stake"`
+	ContractIDs []int64           `json:"contract_ids"`
+	Failed      map[string]string `json:"failed,omitempty"` // Errors by symbol for legs that couldn't be placed
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// basketKey builds the storage key of a basket, keys are prefixed by username for per-user lookups
+func basketKey(username, name string) string {
+	return username + "/" + name
+}
+
+// basketLeg is the outcome of placing a single basket trade
+type basketLeg struct {
+	symbol string
+	result *TradeResult
+	err    error
+}
+
+// handleBasket places the same trade on several symbols, "/basket buy R_25,R_50 2 [up|down] [name]"
+func (b *Bot) handleBasket(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) < 3 || msg.Args[0] != "buy" {
+		return NewResponse(msg).Text("❌ Usage: /basket buy <symbol,symbol,...> <stake> [up|down] [name]\nExample: /basket buy R_25,R_50,R_100 2").Build(), nil
+	}
+
+	stake, err := strconv.ParseFloat(msg.Args[2], 64)
+	if err != nil || stake <= 0 {
+		return NewResponse(msg).Text("❌ Invalid stake. Please provide a positive number.").Build(), nil
+	}
+
+	direction := "CALL"
+	if len(msg.Args) > 3 {
+		switch strings.ToLower(msg.Args[3]) {
+		case "up":
+		case "down":
+			direction = "PUT"
+		default:
+			return NewResponse(msg).Text("❌ Direction must be up or down").Build(), nil
+		}
+	}
+
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, input := range strings.Split(msg.Args[1], ",") {
+		if input = strings.TrimSpace(input); input == "" {
+			continue
+		}
+
+		matches := b.resolver.Resolve(input)
+		if len(matches) > 1 {
+			return NewResponse(msg).Textf("❌ Symbol %q is ambiguous: %s", input, strings.Join(matches, ", ")).Build(), nil
+		}
+
+		if !seen[matches[0]] {
+			seen[matches[0]] = true
+			symbols = append(symbols, matches[0])
+		}
+	}
+
+	if len(symbols) < 2 {
+		return NewResponse(msg).Text("❌ A basket needs at least two different symbols").Build(), nil
+	}
+
+	name := ""
+	if len(msg.Args) > 4 {
+		name = msg.Args[4]
+		if !basketNamePattern.MatchString(name) {
+			return NewResponse(msg).Text("❌ Basket names may only contain letters, digits, dashes and underscores").Build(), nil
+		}
+	}
+
+	name, err = b.basketName(ctx, msg.Username, name)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return NewResponse(msg).Textf("❌ Basket %s already exists, please pick another name", msg.Args[4]).Build(), nil
+	}
+
+	if prompt, err := b.cooldownPrompt(ctx, msg); err != nil || prompt != nil {
+		return prompt, err
+	}
+
+	f := b.formatter(ctx, msg)
+
+	// All legs are validated up front, so the basket isn't left half-placed because of a bad stake
+	for _, symbol := range symbols {
+		if reason, err := b.tradingBlocked(ctx, symbol); err != nil {
+			return nil, err
+		} else if reason != "" {
+			return NewResponse(msg).Text(reason).Build(), nil
+		}
+
+		if reason, err := b.validateStake(ctx, f, symbol, stake, direction); err != nil {
+			return nil, err
+		} else if reason != "" {
+			return NewResponse(msg).Text(reason).Build(), nil
+		}
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason, err := b.openContractsLimited(ctx, client, symbols...); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	stakes := make([]float64, len(symbols))
+	for i := range stakes {
+		stakes[i] = stake
+	}
+
+	if reason, err := b.riskLimited(ctx, msg, client, stakes...); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	// Legs are placed concurrently to keep their entry times close
+	legs := make([]basketLeg, len(symbols))
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := b.placeTrade(ctx, msg, client, &TradeRequest{
+				Symbol:       symbol,
+				Amount:       stake,
+				ContractType: direction,
+				Duration:     b.defaultDuration(ctx, symbol),
+			})
+			legs[i] = basketLeg{symbol: symbol, result: result, err: err}
+		}()
+	}
+	wg.Wait()
+
+	basket := &Basket{
+		Name:      name,
+		Username:  msg.Username,
+		Direction: direction,
+		Stake:     stake,
+		CreatedAt: time.Now(),
+	}
+
+	var lines []string
+	for _, leg := range legs {
+		if leg.err != nil {
+			log.Printf("Failed to place basket %s trade on %s: %v", name, leg.symbol, leg.err)
+			if basket.Failed == nil {
+				basket.Failed = make(map[string]string)
+			}
+			basket.Failed[leg.symbol] = leg.err.Error()
+			lines = append(lines, fmt.Sprintf("❌ %s: not placed", leg.symbol))
+			continue
+		}
+
+		if err := b.recordTrade(ctx, msg.Username, leg.symbol, direction, "", nil, leg.result); err != nil {
+			log.Printf("Failed to record trade %d: %v", leg.result.ContractID, err)
+		}
+
+		basket.ContractIDs = append(basket.ContractIDs, leg.result.ContractID)
+		lines = append(lines, fmt.Sprintf("✅ %s: contract %d", leg.symbol, leg.result.ContractID))
+	}
+
+	if len(basket.ContractIDs) > 0 {
+		if err := b.storage.Put(ctx, bucketBaskets, basketKey(msg.Username, name), basket); err != nil {
+			return nil, fmt.Errorf("failed to save basket: %w", err)
+		}
+	}
+
+	header := fmt.Sprintf("🧺 Basket %s %s %s per symbol: %d of %d trades placed", name, f.ContractIcon(direction), f.Money(stake, client.Currency()), len(basket.ContractIDs), len(legs))
+	if len(basket.Failed) > 0 {
+		header += "\n⚠️ Some trades failed, the placed ones stay open. Check /portfolio for the basket P&L."
+	}
+
+	return NewResponse(msg).Text(header + "\n\n" + strings.Join(lines, "\n")).Build(), nil
+}
+
+// basketName returns the name for a new basket, generating one when it's empty.
+// It returns an empty name when the requested one is already taken.
+func (b *Bot) basketName(ctx context.Context, username, name string) (string, error) {
+	baskets, err := b.userBaskets(ctx, username)
+	if err != nil {
+		return "", err
+	}
+
+	taken := make(map[string]bool, len(baskets))
+	for _, basket := range baskets {
+		taken[basket.Name] = true
+	}
+
+	if name != "" {
+		if taken[name] {
+			return "", nil
+		}
+		return name, nil
+	}
+
+	for i := len(baskets) + 1; ; i++ {
+		if name = fmt.Sprintf("basket-%d", i); !taken[name] {
+			return name, nil
+		}
+	}
+}
+
+// userBaskets returns baskets of the user ordered by creation time
+func (b *Bot) userBaskets(ctx context.Context, username string) ([]*Basket, error) {
+	keys, err := b.storage.Keys(ctx, bucketBaskets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list baskets: %w", err)
+	}
+
+	prefix := username + "/"
+	var baskets []*Basket
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		var basket Basket
+		if err := b.storage.Get(ctx, bucketBaskets, key, &basket); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to load basket %s: %w", key, err)
+		}
+		baskets = append(baskets, &basket)
+	}
+
+	sort.Slice(baskets, func(i, j int) bool {
+		return baskets[i].CreatedAt.Before(baskets[j].CreatedAt)
+	})
+
+	return baskets, nil
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// Benchmark is a read-only market data source Deriv prices are cross-checked against
+type Benchmark interface {
+	MarketDataProvider
+	Name() string
+}
+
+// SetBenchmark enables cross-checking prices of the symbols the benchmark covers
+func (b *Bot) SetBenchmark(benchmark Benchmark) {
+	b.benchmark = benchmark
+}
+
+// benchmarkSymbols returns Deriv symbols the benchmark covers, none when there is no benchmark
+func (b *Bot) benchmarkSymbols(ctx context.Context) []string {
+	if b.benchmark == nil {
+		return nil
+	}
+
+	symbols, err := b.benchmark.GetAvailableSymbols(ctx)
+	if err != nil {
+		log.Printf("Failed to list symbols of %s: %v", b.benchmark.Name(), err)
+		return nil
+	}
+
+	return symbols
+}
+
+// benchmarkLine compares the Deriv price of the symbol with the benchmark,
+// it's empty when the symbol isn't covered or the benchmark fails, as the Deriv price is still useful
+func (b *Bot) benchmarkLine(ctx context.Context, f Formatter, symbol string, price float64) string {
+	if !slices.Contains(b.benchmarkSymbols(ctx), symbol) {
+		return ""
+	}
+
+	reference, err := b.benchmark.GetPrice(ctx, symbol)
+	if err != nil {
+		log.Printf("Failed to get %s price of %s: %v", b.benchmark.Name(), symbol, err)
+		return ""
+	}
+
+	line := fmt.Sprintf("📐 %s: %s", b.benchmark.Name(), f.Number(reference, 2))
+	if reference != 0 {
+		line += fmt.Sprintf(" (Deriv %s)", f.Percent((price-reference)/reference*100, 3))
+	}
+
+	return line
+}
+
+// benchmarkContext adds benchmark prices of covered symbols mentioned in the question to the LLM input
+func (b *Bot) benchmarkContext(ctx context.Context, text string) string {
+	var lines []string
+	for _, symbol := range b.benchmarkSymbols(ctx) {
+		if !strings.Contains(strings.ToLower(text), strings.ToLower(symbol)) {
+			continue
+		}
+
+		price, err := b.benchmark.GetPrice(ctx, symbol)
+		if err != nil {
+			log.Printf("Failed to get %s price of %s: %v", b.benchmark.Name(), symbol, err)
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("- %s: %v", symbol, price))
+	}
+
+	if len(lines) == 0 {
+		return text
+	}
+
+	return text + "\n\nFor cross-checking, current prices of the same instruments on " + b.benchmark.Name() +
+		" (an external market, not Deriv):\n" + strings.Join(lines, "\n")
+}
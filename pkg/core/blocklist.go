@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// parseBlockedMarkets converts configured market types to Deriv market codes
+func parseBlockedMarkets(marketTypeNames []string) (map[string]struct{}, error) {
+	markets := make(map[string]struct{}, len(marketTypeNames))
+
+	for _, name := range marketTypeNames {
+		market, ok := marketTypes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown market type %q in blocked markets", name)
+		}
+		markets[market] = struct{}{}
+	}
+
+	return markets, nil
+}
+
+// tradingBlocked checks whether the symbol may be traded: it isn't blocked by the operator and its market is open.
+// It returns a user-facing message when trading isn't allowed, or an empty string when it is.
+func (b *Bot) tradingBlocked(ctx context.Context, symbol string) (string, error) {
+	if _, blocked := b.blockedSymbols[symbol]; blocked {
+		return fmt.Sprintf("🚫 Trading %s is disabled on this bot", symbol), nil
+	}
+
+	symbols, err := b.activeSymbols(ctx)
+	if err != nil {
+		// Blocked markets must be enforced, while market hours are left to Deriv to check
+		if len(b.blockedMarkets) > 0 {
+			return "", fmt.Errorf("failed to get active symbols: %w", err)
+		}
+		log.Printf("Failed to check market hours of %s: %v", symbol, err)
+		return "", nil
+	}
+
+	for _, s := range symbols {
+		if s.Symbol != symbol {
+			continue
+		}
+
+		if _, blocked := b.blockedMarkets[s.Market]; blocked {
+			return fmt.Sprintf("🚫 Trading %s symbols is disabled on this bot", s.MarketName), nil
+		}
+
+		if !s.IsOpen {
+			return fmt.Sprintf("🕑 The market of %s is closed now. Please try again when it opens.", symbol), nil
+		}
+
+		break
+	}
+
+	return "", nil
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/metrics"
 )
 
 // BalanceInfo contains balance amount and currency
@@ -16,8 +21,26 @@ type BalanceInfo struct {
 type DerivClient interface {
 	MarketDataProvider
 	GetBalance(ctx context.Context) (*BalanceInfo, error)
-	PlaceTrade(ctx context.Context, symbol string, amount float64, direction string) error
-	GetPosition(ctx context.Context) (string, error)
+	GetQuote(ctx context.Context, req *TradeRequest) (*Quote, error)
+	PlaceTrade(ctx context.Context, req *TradeRequest) (*TradeResult, error)
+	SellContract(ctx context.Context, contractID int64) (*SellResult, error)
+	GetContract(ctx context.Context, contractID int64) (*ContractInfo, error)
+	GetOpenPositions(ctx context.Context) ([]PositionInfo, error)
+	GetStatement(ctx context.Context, limit, offset int) ([]Transaction, error)
+	GetProfitTable(ctx context.Context, from, to time.Time) ([]ClosedContract, error)
+	GetCopyTradingList(ctx context.Context) (*CopyTradingList, error)
+	GetCopyStatistics(ctx context.Context, traderID string) (*CopyStats, error)
+	StartCopying(ctx context.Context, traderToken string, opts CopyOptions) error
+	StopCopying(ctx context.Context, traderToken string) error
+	WatchContract(ctx context.Context, contractID int64) (<-chan *ContractInfo, error)
+	WatchTicks(ctx context.Context, symbol string) (<-chan HistoricalDataPoint, error)
+	WatchTransactions(ctx context.Context) (<-chan Transaction, error)
+	GetContractLimits(ctx context.Context, symbol string) ([]ContractLimits, error)
+	GetExchangeRate(ctx context.Context, from, to string) (float64, error)
+	GetActiveSymbols(ctx context.Context) ([]SymbolInfo, error)
+	Currency() string       // Currency of the account stakes and payouts are quoted in
+	ActiveAccount() Account // Account the client trades on
+	AccountList() []Account // All accounts of the user, including ones without a token
 }
 
 // Message represents a chat message with parsed command and arguments
@@ -27,7 +50,10 @@ type Message struct {
 	ChatID       int64
 	MessageID    int
 	Username     string
+	LanguageCode string // IETF language tag of the user, e.g. en or pt-br
 	CallbackData string // For callback queries from inline buttons
+	ArgsText     string // Arguments as typed, keeping line breaks
+	InGroup      bool   // Sent in a group chat rather than a private one
 }
 
 // TradeState represents the state of a trade operation
@@ -58,6 +84,13 @@ func ParseCallbackData(data string) map[string]string {
 type Button struct {
 	Text         string
 	CallbackData string
+	URL          string // Opens the URL instead of sending callback data
+}
+
+// Document is a file sent to the chat, the response text becomes its caption
+type Document struct {
+	Name string
+	Data []byte
 }
 
 // Response represents a response to a chat message
@@ -67,21 +100,77 @@ type Response struct {
 	ChatID           int64
 	Buttons          [][]Button // Keyboard buttons in a grid layout
 	PhotoPath        string     // Path to photo file to send
+	Document         *Document  // File to send, e.g. exported data
+	DeleteMessageID  int        // Message to delete from the chat, e.g. one containing secrets
+	EditMessageID    int        // Edit this message instead of sending a new one
+	Preformatted     bool       // Show the text in a monospace block, e.g. aligned tables
+	Silent           bool       // Deliver without a notification sound
 }
 
 // Bot handles the business logic for processing chat messages
 type Bot struct {
+	cfg             *Config
 	derivClient     DerivClient
+	pool            DerivClientPool
 	llmClient       LLMClient
+	storage         Storage
+	journal         Journal
 	allowedUsers    map[string]struct{}
+	admins          map[string]struct{}
 	commandHandlers map[string]CommandHandler
+	customCommands  map[string]string // Custom command descriptions by command name
+	macros          map[string]string // Macro descriptions by command name
+	middlewares     []Middleware
+	features        *FeatureFlags
+	links           *accountLinks
+	prices          *flightGroup[float64]
+	rates           *flightGroup[float64]
+	active          *flightGroup[[]SymbolInfo]
+	limits          *flightGroup[[]ContractLimits]
+	candles         *flightGroup[[]HistoricalDataPoint]
+	durations       map[string]Duration // Default contract durations by Deriv market code
 	symbols         []string
+	blockedSymbols  map[string]struct{}
+	blockedMarkets  map[string]struct{} // Deriv market codes
+	resolver        *symbolResolver
+	background      background
+	dashboards      dashboards
+	subscriptions   subscriptions // Streams of chats, stopped when the chat goes idle
+	pendingQueries  pendingQueries
+	pendingTrades   pendingTrades
+	watches         priceWatches
+	metrics         *metrics.Registry
+	watchdog        *Watchdog
+	risk            *RiskManager
+	lastActivity    atomic.Int64 // Unix time in nanoseconds of the latest user message
+	commands        commandLog   // Latest commands of each user, shown by /user
+	outbox          sync.Mutex   // Serializes delivery of queued notifications
+	events          *eventBus
+	alerts          alertWatcher
+	orders          orderWatcher
+	strategies      strategyRunners
+	extStrategies   []strategyDef // Strategies registered with RegisterStrategy, e.g. by plugins
+	tradeKeys       tradeKeys
+	progress        progressRuns
+	theme           *Theme
+	recorder        TickRecorder
+	sessions        sessionChats  // Private chats of users active since the bot started, they get a summary on shutdown
+	shutdown        chan struct{} // Closed by /shutdown
+	shutdownOnce    sync.Once
+	trading         userLocks     // Serializes trading commands of each user
+	settling        userLocks     // Serializes settling trades and updating the loss streak of each user
+	webhooks        WebhookSender // Delivers trade events to user webhooks, nil when they are disabled
+	copying         sync.Mutex    // Serializes changes of users' copy sessions
+	activity        activityWatches
+	limitWatches    limitWatches // Stop-loss and take-profit watches of open contracts
+	benchmark       Benchmark    // External prices to cross-check Deriv ones, nil when not configured
 }
 
+// CommandHandler handles a chat command, a nil response means there is nothing to reply
 type CommandHandler func(ctx context.Context, msg *Message) (*Response, error)
 
 // NewBot creates a new instance of the bot
-func NewBot(derivClient DerivClient, llmClient LLMClient, allowedUsers []string, symbols []string) (*Bot, error) {
+func NewBot(cfg *Config, derivClient DerivClient, pool DerivClientPool, llmClient LLMClient, storage Storage, allowedUsers []string, symbols []string) (*Bot, error) {
 
 	// Create allowed users map for faster lookup
 	allowedUsersMap := make(map[string]struct{})
@@ -89,43 +178,224 @@ func NewBot(derivClient DerivClient, llmClient LLMClient, allowedUsers []string,
 		allowedUsersMap[username] = struct{}{}
 	}
 
+	adminsMap := make(map[string]struct{})
+	for _, username := range cfg.Admins {
+		adminsMap[username] = struct{}{}
+	}
+
+	features, err := NewFeatureFlags(cfg.Features)
+	if err != nil {
+		return nil, err
+	}
+
+	durations, err := parseContractDefaults(cfg.Defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedMarkets, err := parseBlockedMarkets(cfg.BlockedMarkets)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedSymbols := make(map[string]struct{}, len(cfg.BlockedSymbols))
+	for _, symbol := range cfg.BlockedSymbols {
+		blockedSymbols[symbol] = struct{}{}
+	}
+
+	registry := metrics.NewRegistry()
+	registry.SetBuckets(metricCommandLatency, commandLatencyBuckets...)
+
 	bot := &Bot{
-		derivClient:  derivClient,
-		llmClient:    llmClient,
-		allowedUsers: allowedUsersMap,
-		symbols:      symbols,
+		cfg:            cfg,
+		derivClient:    derivClient,
+		pool:           pool,
+		llmClient:      timedLLM{LLMClient: llmClient, metrics: registry},
+		storage:        storage,
+		journal:        &storageJournal{storage: storage},
+		allowedUsers:   allowedUsersMap,
+		admins:         adminsMap,
+		customCommands: make(map[string]string),
+		macros:         make(map[string]string),
+		features:       features,
+		links:          &accountLinks{pending: make(map[string]pendingLink)},
+		prices:         newFlightGroup[float64](cfg.PriceCacheTTL),
+		rates:          newFlightGroup[float64](exchangeRateTTL),
+		active:         newFlightGroup[[]SymbolInfo](activeSymbolsTTL),
+		limits:         newFlightGroup[[]ContractLimits](cfg.ContractLimitsTTL),
+		candles:        newFlightGroup[[]HistoricalDataPoint](cfg.CandleCacheTTL),
+		durations:      durations,
+		symbols:        symbols,
+		blockedSymbols: blockedSymbols,
+		blockedMarkets: blockedMarkets,
+		resolver:       newSymbolResolver(symbols, cfg.SymbolAliases),
+		dashboards:     dashboards{running: make(map[int64]*dashboardRun)},
+		pendingQueries: pendingQueries{queries: make(map[string]string)},
+		pendingTrades:  pendingTrades{trades: make(map[int64]*pendingTrade)},
+		watches:        priceWatches{running: make(map[int64]map[string]*priceWatch)},
+		subscriptions:  subscriptions{active: make(map[*subscription]struct{}), lastSeen: make(map[int64]time.Time)},
+		commands:       commandLog{entries: make(map[string][]commandEntry)},
+		sessions:       sessionChats{chats: make(map[string]int64)},
+		activity:       activityWatches{running: make(map[string]*activityWatch)},
+		limitWatches:   limitWatches{running: make(map[int64]*limitWatch)},
+		shutdown:       make(chan struct{}),
+		trading:        userLocks{locks: make(map[string]*sync.Mutex)},
+		settling:       userLocks{locks: make(map[string]*sync.Mutex)},
+		events:         newEventBus(derivClient.WatchTicks),
+		alerts:         alertWatcher{streams: make(map[alertStream]*alertStreamState)},
+		orders:         orderWatcher{feeds: make(map[string]*orderFeed), prices: make(map[string]float64), wake: make(chan struct{}, 1)},
+		strategies:     strategyRunners{running: make(map[string]*strategyRunner), drafts: make(map[string]map[string]float64)},
+		tradeKeys:      tradeKeys{seen: make(map[string]time.Time)},
+		progress:       progressRuns{runs: make(map[string]*progressMessage)},
+		theme:          NewTheme(cfg.Theme),
+		metrics:        registry,
+		watchdog:       newWatchdog(cfg.Watchdog, registry),
 	}
 
+	bot.risk = newRiskManager(cfg.Risk, storage, bot.convert)
+
 	// Initialize command handlers
 	bot.commandHandlers = map[string]CommandHandler{
-		"start":    bot.handleStart,
-		"help":     bot.handleHelp,
-		"symbols":  bot.handleSymbols,
-		"balance":  bot.handleBalance,
-		"price":    bot.handlePrice,
-		"buy":      bot.handleBuy,
-		"position": bot.handlePosition,
+		"start":          bot.handleStart,
+		"help":           bot.handleHelp,
+		"symbols":        bot.handleSymbols,
+		"balance":        bot.handleBalance,
+		"price":          bot.handlePrice,
+		"buy":            bot.handleBuy,
+		"confirmtrade":   bot.handleConfirmTrade,
+		"direction":      bot.handleDirection,
+		"cancelprogress": bot.handleCancelProgress,
+		"proposal":       bot.handleProposal,
+		"tp":             bot.handleTakeProfit,
+		"hedge":          bot.handleHedge,
+		"trail":          bot.handleTrail,
+		"position":       bot.handlePosition,
+		"exposure":       bot.handleExposure,
+		"history":        bot.handleHistory,
+		"profits":        bot.handleProfits,
+		"currency":       bot.handleCurrency,
+		"cooldown":       bot.handleCooldown,
+		llmQueryCommand:  bot.handleLLMQuery,
+		"connect":        bot.handleConnect,
+		"disconnect":     bot.handleDisconnect,
+		"account":        bot.handleAccount,
+		"wipe":           bot.handleWipe,
+		"feature":        bot.handleFeature,
+		"dashboard":      bot.handleDashboard,
+		"expiryalerts":   bot.handleExpiryAlerts,
+		"basket":         bot.handleBasket,
+		"mult":           bot.handleMult,
+		"touch":          bot.handleTouch,
+		"digit":          bot.handleDigit,
+		"watch":          bot.handleWatch,
+		"portfolio":      bot.handlePortfolio,
+		"stats":          bot.handleStats,
+		"status":         bot.handleStatus,
+		"alert":          bot.handleAlert,
+		"order":          bot.handleOrder,
+		"orders":         bot.handleOrders,
+		"cancelorder":    bot.handleCancelOrder,
+		"strategy":       bot.handleStrategy,
+		"reconcile":      bot.handleReconcile,
+		"copy":           bot.handleCopy,
+		"markets":        bot.handleMarkets,
+		"chart":          bot.handleChart,
+		"compare":        bot.handleCompare,
+		"mtf":            bot.handleMTF,
+		"explain":        bot.handleExplain,
+		"pnl":            bot.handlePnL,
+		"learn":          bot.handleLearn,
+		"locale":         bot.handleLocale,
+		"stakes":         bot.handleStakes,
+		"simulate":       bot.handleSimulate,
+		"notifications":  bot.handleNotifications,
+		"size":           bot.handleSize,
+		"data":           bot.handleData,
+		"weekly":         bot.handleWeekly,
+		"teamportfolio":  bot.handleTeamPortfolio,
+		"ticks":          bot.handleTicks,
+		"plain":          bot.handlePlain,
+		"webhook":        bot.handleWebhook,
+		"track":          bot.handleTrack,
+		"contract":       bot.handleContract,
+		"sell":           bot.handleSell,
+		"resume":         bot.handleResume,
+		"killswitch":     bot.handleKillSwitch,
+		"shutdown":       bot.handleShutdown,
+		"user":           bot.handleUser,
+		"setcontent":     bot.handleSetContent,
 	}
 
+	if err := bot.registerMacros(cfg.Macros); err != nil {
+		return nil, err
+	}
+
+	// Initialize middlewares applied to every command handler
+	bot.middlewares = []Middleware{
+		bot.recordCommand,
+		bot.plainOutput,
+		bot.featureGate,
+		bot.watchdogGuard,
+		bot.staleDataNotice,
+		bot.commandTimeout,
+		bot.accountGuard,
+		bot.serializeTrades,
+		bot.friendlyErrors,
+		bot.measureCommand,
+	}
+
+	// Execution quality is measured from trade events
+	bot.events.Subscribe(EventFilter{Type: EventTradeOpened}, bot.observeTradeMetrics)
+	bot.events.Subscribe(EventFilter{Type: EventTradeSettled}, bot.observeTradeMetrics)
+
+	// Daily loss limits count realized P&L of settled trades
+	bot.events.Subscribe(EventFilter{Type: EventTradeSettled}, bot.recordDailyPnL)
+
+	// Users' own automations follow their trades through webhooks
+	bot.events.Subscribe(EventFilter{Type: EventTradeOpened}, bot.postTradeEvent)
+	bot.events.Subscribe(EventFilter{Type: EventTradeSettled}, bot.postTradeEvent)
+
+	// The idle timer starts with the bot
+	bot.touch()
+
 	return bot, nil
 }
 
+// RegisterCommand adds a handler for a custom command, e.g. one provided by a plugin.
+// Built-in and already registered commands cannot be overridden.
+func (b *Bot) RegisterCommand(name, description string, handler CommandHandler) error {
+	if _, exists := b.commandHandlers[name]; exists {
+		return fmt.Errorf("command %s is already registered", name)
+	}
+
+	b.commandHandlers[name] = handler
+	b.customCommands[name] = description
+
+	return nil
+}
+
 // ProcessMessage processes an incoming message and returns a response
 func (b *Bot) ProcessMessage(ctx context.Context, msg *Message) (*Response, error) {
 	// Check if user is allowed
 	if !b.isUserAllowed(msg.Username) {
-		return &Response{
-			Text:             "⚠️ You are not authorized to use this bot.",
-			ReplyToMessageID: msg.MessageID,
-			ChatID:           msg.ChatID,
-		}, nil
+		return NewResponse(msg).Text("⚠️ You are not authorized to use this bot.").Build(), nil
 	}
 
+	b.touch()
+	b.subscriptions.seen(msg.ChatID, time.Now())
+
+	// Admin chats are needed to deliver watchdog alerts
+	b.rememberAdminChat(ctx, msg)
+
 	// Handle callback queries (button clicks)
 	if msg.CallbackData != "" {
 		data := ParseCallbackData(msg.CallbackData)
 		if data["action"] == "trade" {
 			msg.Command = "buy" // Treat trade callbacks as buy commands
+		} else if _, exists := b.commandHandlers[data["action"]]; exists {
+			// Other callbacks are routed to the command with the same name, "cmd:arg1:arg2" is treated as /cmd arg1 arg2
+			msg.Command = data["action"]
+			msg.Args = strings.Split(msg.CallbackData, ":")[1:]
 		}
 	}
 
@@ -133,37 +403,50 @@ func (b *Bot) ProcessMessage(ctx context.Context, msg *Message) (*Response, erro
 	if msg.Command != "" {
 		handler, exists := b.commandHandlers[msg.Command]
 		if !exists {
-			return &Response{
-				Text:             "❌ Unknown command. Type /help for available commands.",
-				ReplyToMessageID: msg.MessageID,
-				ChatID:           msg.ChatID,
-			}, nil
+			return NewResponse(msg).Text("❌ Unknown command. Type /help for available commands.").Build(), nil
 		}
-		return handler(ctx, msg)
+		return chain(handler, b.middlewares...)(ctx, msg)
 	}
 
 	// Handle free-form text
+	return chain(b.handleText, b.middlewares...)(ctx, msg)
+}
+
+// handleText processes free-form text with LLM using market data functions
+func (b *Bot) handleText(ctx context.Context, msg *Message) (*Response, error) {
 	text := strings.Join(msg.Args, " ")
 	if text == "" {
-		return &Response{
-			Text:             "❌ Please provide some text for me to process.",
-			ReplyToMessageID: msg.MessageID,
-			ChatID:           msg.ChatID,
-		}, nil
+		return NewResponse(msg).Text("❌ Please provide some text for me to process.").Build(), nil
+	}
+
+	// Heavy analyses are confirmed first, so they don't run up the API bill by surprise
+	if prompt := b.llmCostPrompt(msg, text); prompt != nil {
+		return prompt, nil
+	}
+
+	return b.answerText(ctx, msg, text)
+}
+
+// answerText answers a free-form question with LLM using market data functions, the answer proposes trades
+// only while the llm_trade_proposals feature is enabled
+func (b *Bot) answerText(ctx context.Context, msg *Message, text string) (*Response, error) {
+	input := b.benchmarkContext(ctx, text)
+	if !b.features.Enabled(FeatureLLMTradeProposals) {
+		input += noTradeProposalsInstruction
 	}
 
-	// Process text with LLM using market data functions
-	response, err := b.llmClient.ProcessWithFunctions(ctx, text, b.derivClient, MarketDataFunctions)
+	response, err := b.llmClient.ProcessWithFunctions(ctx, input, b.derivClient, MarketDataFunctions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process text: %w", err)
 	}
 
-	return &Response{
-		Text:             response,
-		ReplyToMessageID: msg.MessageID,
-		ChatID:           msg.ChatID,
-	}, nil
+	if !b.features.Enabled(FeatureLLMTradeProposals) {
+		if analysis, stripped := withoutTradeProposals(response); stripped {
+			response = analysis + "\n\nℹ️ Trade proposals are disabled, only the market analysis is shown."
+		}
+	}
 
+	return NewResponse(msg).Text(response).Build(), nil
 }
 
 // isUserAllowed checks if a user is allowed to use the bot
@@ -174,3 +457,12 @@ func (b *Bot) isUserAllowed(username string) bool {
 	_, allowed := b.allowedUsers[username]
 	return allowed
 }
+
+// isAdmin checks if a user is allowed to run admin commands
+func (b *Bot) isAdmin(username string) bool {
+	if username == "" {
+		return false
+	}
+	_, admin := b.admins[username]
+	return admin
+}
@@ -0,0 +1,14 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// chartWatermark returns the configured watermark of charts rendered for the user
+func (b *Bot) chartWatermark(username string) string {
+	return strings.NewReplacer(
+		"{user}", "@"+username,
+		"{time}", time.Now().UTC().Format("2006-01-02 15:04 UTC"),
+	).Replace(b.cfg.Chart.Watermark)
+}
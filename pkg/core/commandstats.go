@@ -0,0 +1,95 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/metrics"
+)
+
+// Usage metrics of command handlers, labeled by command
+const (
+	metricCommandLatency = "command_latency_ms"   // Handler run time, without waiting for other trades of the user
+	metricCommandErrors  = "command_errors_total" // Handler calls that failed
+)
+
+// commandLatencyBuckets are upper bounds of the command latency histogram, in ms
+var commandLatencyBuckets = []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+
+// measureCommand records the run time and failure of command handlers.
+// It's the innermost middleware, so errors are counted before they're turned into friendly replies.
+func (b *Bot) measureCommand(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		start := time.Now()
+		resp, err := next(ctx, msg)
+
+		command := commandName(msg)
+		b.metrics.Observe(metrics.Name(metricCommandLatency, "command", command), float64(time.Since(start).Milliseconds()))
+		if err != nil {
+			b.metrics.Inc(metrics.Name(metricCommandErrors, "command", command))
+		}
+
+		return resp, err
+	}
+}
+
+// commandUsage is the usage of a command since the bot started
+type commandUsage struct {
+	Command string
+	Calls   int64
+	Errors  int64
+	Latency []float64 // Percentiles of recent calls in statsPercentiles order
+}
+
+// handleCommandStats shows calls, error rates and latency of commands since the bot started, slowest first,
+// "/stats commands", admin only
+func (b *Bot) handleCommandStats(_ context.Context, msg *Message) (*Response, error) {
+	if !b.isAdmin(msg.Username) {
+		return NewResponse(msg).Text("⚠️ This command is available to admins only.").Build(), nil
+	}
+
+	commands := []string{textCommand}
+	for command := range b.commandHandlers {
+		commands = append(commands, command)
+	}
+
+	var usage []commandUsage
+	for _, command := range commands {
+		name := metrics.Name(metricCommandLatency, "command", command)
+
+		calls := b.metrics.Count(name)
+		if calls == 0 {
+			continue
+		}
+
+		usage = append(usage, commandUsage{
+			Command: command,
+			Calls:   calls,
+			Errors:  b.metrics.Counter(metrics.Name(metricCommandErrors, "command", command)),
+			Latency: b.metrics.Percentiles(name, statsPercentiles...),
+		})
+	}
+
+	if len(usage) == 0 {
+		return NewResponse(msg).Text("ℹ️ No commands were handled since the bot started.").Build(), nil
+	}
+
+	// The p90 shows which commands are slow for most users, not just on the odd call
+	slices.SortFunc(usage, func(a, b commandUsage) int {
+		return cmp.Compare(b.Latency[1], a.Latency[1])
+	})
+
+	var sb strings.Builder
+	sb.WriteString("⚙️ Commands since the bot started, slowest first\n")
+	sb.WriteString("calls, errors, latency p50 / p90 / p99 ms\n\n")
+	for _, u := range usage {
+		fmt.Fprintf(&sb, "/%s: %d calls, %.1f%% errors, %.0f / %.0f / %.0f\n",
+			u.Command, u.Calls, float64(u.Errors)/float64(u.Calls)*100, u.Latency[0], u.Latency[1], u.Latency[2])
+	}
+
+	return NewResponse(msg).Text(sb.String()).Build(), nil
+}
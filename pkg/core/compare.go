@@ -0,0 +1,172 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxCompareSymbols is the most symbols /compare puts side by side
+const maxCompareSymbols = 5
+
+// timeframe is a range of history summarized by /mtf
+type timeframe struct {
+	label string
+	req   HistoricalDataRequest
+}
+
+// mtfTimeframes are the ranges /mtf fetches for a symbol, from the shortest
+func mtfTimeframes(symbol string) []timeframe {
+	return []timeframe{
+		{label: "1h", req: HistoricalDataRequest{Symbol: symbol, Interval: IntervalHour, Style: StyleCandles, Count: 60}},
+		{label: "24h", req: dayCandlesRequest(symbol)},
+		{label: "7d", req: HistoricalDataRequest{Symbol: symbol, Interval: IntervalWeek, Style: StyleCandles, Count: 7 * 6, Granularity: 4 * 3600}},
+		{label: "30d", req: HistoricalDataRequest{Symbol: symbol, Interval: IntervalMonth, Style: StyleCandles, Count: 30, Granularity: 24 * 3600}},
+	}
+}
+
+// fetchHistories gets history of every request concurrently, results are in the order of requests.
+// Each fetched history is reported to progress.
+// The first failure cancels the fetches still running, so a timed out command doesn't keep the API busy.
+func (b *Bot) fetchHistories(ctx context.Context, reqs []HistoricalDataRequest, progress Progress) ([][]HistoricalDataPoint, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		fetched  atomic.Int32
+	)
+
+	results := make([][]HistoricalDataPoint, len(reqs))
+	for i, req := range reqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			data, err := b.derivClient.GetHistoricalData(ctx, req)
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("failed to get history of %s: %w", req.Symbol, err)
+					cancel()
+				})
+				return
+			}
+
+			results[i] = data
+			progress.Step(int(fetched.Add(1)), len(reqs))
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return results, nil
+}
+
+// handleCompare puts the 24h movement of several symbols side by side, "/compare <symbol> <symbol> [...]"
+func (b *Bot) handleCompare(ctx context.Context, msg *Message) (*Response, error) {
+	rb := NewResponse(msg)
+
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, input := range msg.Args {
+		matches := b.resolver.Resolve(input)
+		if len(matches) > 1 {
+			return rb.Textf("❌ Symbol %q is ambiguous: %s", input, strings.Join(matches, ", ")).Build(), nil
+		}
+
+		if !seen[matches[0]] {
+			seen[matches[0]] = true
+			symbols = append(symbols, matches[0])
+		}
+	}
+
+	if len(symbols) < 2 || len(symbols) > maxCompareSymbols {
+		return rb.Textf("❌ Usage: /compare <symbol> <symbol> [...], up to %d different symbols\nExample: /compare R_50 R_100", maxCompareSymbols).Build(), nil
+	}
+
+	reqs := make([]HistoricalDataRequest, len(symbols))
+	for i, symbol := range symbols {
+		reqs[i] = dayCandlesRequest(symbol)
+	}
+
+	ctx, progress := b.startProgress(ctx, msg, "Comparing "+strings.Join(symbols, ", "))
+
+	histories, err := b.fetchHistories(ctx, reqs, progress)
+	if err != nil {
+		return progress.finish(b, msg, nil, err)
+	}
+
+	f := b.formatter(ctx, msg)
+	names := b.symbolNames(ctx)
+
+	var sb strings.Builder
+	sb.WriteString("⚖️ Last 24h\n")
+	for i, symbol := range symbols {
+		fmt.Fprintf(&sb, "\n%s\n%s\n", symbolLabel(names, symbol), formatRangeStats(f, histories[i]))
+	}
+
+	rb.Text(strings.TrimRight(sb.String(), "\n"))
+
+	return progress.finish(b, msg, rb.Build(), nil)
+}
+
+// handleMTF summarizes the movement of a symbol over several timeframes, "/mtf <symbol>"
+func (b *Bot) handleMTF(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) != 1 {
+		return NewResponse(msg).Text("❌ Usage: /mtf <symbol>\nExample: /mtf R_50").Build(), nil
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "mtf:" + symbol
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	timeframes := mtfTimeframes(symbol)
+	reqs := make([]HistoricalDataRequest, len(timeframes))
+	for i, tf := range timeframes {
+		reqs[i] = tf.req
+	}
+
+	ctx, progress := b.startProgress(ctx, msg, "Analyzing "+symbol)
+
+	histories, err := b.fetchHistories(ctx, reqs, progress)
+	if err != nil {
+		return progress.finish(b, msg, nil, err)
+	}
+
+	f := b.formatter(ctx, msg)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🧭 %s across timeframes\n", symbolLabel(b.symbolNames(ctx), symbol))
+	for i, tf := range timeframes {
+		fmt.Fprintf(&sb, "\n%s: %s", tf.label, formatRangeStats(f, histories[i]))
+	}
+
+	return progress.finish(b, msg, NewResponse(msg).Text(sb.String()).Build(), nil)
+}
+
+// formatRangeStats renders the change and the range of candles, the last close is taken as the current price
+func formatRangeStats(f Formatter, candles []HistoricalDataPoint) string {
+	if len(candles) == 0 {
+		return "no data"
+	}
+
+	stats, _ := dayStats(candles, candles[len(candles)-1].Close)
+
+	arrow := "▲"
+	if stats.Change < 0 {
+		arrow = "▼"
+	}
+
+	return fmt.Sprintf("%s %s · range %s – %s", arrow, f.Percent(stats.ChangePct, 2), f.Number(stats.Low, 2), f.Number(stats.High, 2))
+}
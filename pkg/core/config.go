@@ -0,0 +1,112 @@
+package core
+
+import "time"
+
+// textCommand is the name used to configure free-form text processing like a command
+const textCommand = "text"
+
+// Config holds configuration of the core bot logic
+type Config struct {
+	Admins   []string        `mapstructure:"admins"`   // Usernames allowed to run admin commands
+	Features map[string]bool `mapstructure:"features"` // Initial state of feature flags
+
+	// Timeouts for command handlers, overrides are keyed by command name ("text" for free-form LLM queries)
+	CommandTimeout  time.Duration            `mapstructure:"command_timeout"`
+	CommandTimeouts map[string]time.Duration `mapstructure:"command_timeouts"`
+	TradeTimeout    time.Duration            `mapstructure:"trade_timeout"` // Commands placing or selling contracts, command_timeout when 0
+
+	// SymbolAliases maps shorthands like vol50 to Deriv symbols, in addition to built-in ones
+	SymbolAliases map[string]string `mapstructure:"symbol_aliases"`
+
+	// Macros are quick commands running several other commands, keyed by command name
+	Macros map[string]MacroConfig `mapstructure:"macros"`
+
+	// Defaults holds contract parameters used when users omit them, keyed by market type:
+	// synthetics, forex, crypto, commodities or indices
+	Defaults map[string]ContractDefaults `mapstructure:"defaults"`
+
+	// PriceCacheTTL is how long a fetched price is shared between identical requests
+	PriceCacheTTL time.Duration `mapstructure:"price_cache_ttl"`
+
+	// CandleCacheTTL is how long candles of charts and 24h statistics are reused, 0 disables caching
+	CandleCacheTTL time.Duration `mapstructure:"candle_cache_ttl"`
+
+	// PrewarmInterval is how often candles of watched symbols are fetched ahead of requests, 0 disables it
+	PrewarmInterval time.Duration `mapstructure:"prewarm_interval"`
+
+	// ContractLimitsTTL is how long contract constraints of a symbol (durations, barriers, stakes) are reused
+	ContractLimitsTTL time.Duration `mapstructure:"contract_limits_ttl"`
+
+	// Per-user Deriv accounts, users can also link their own with /connect
+	UserTokens        map[string]string `mapstructure:"user_tokens"`         // Deriv API tokens by username
+	RequireOwnAccount bool              `mapstructure:"require_own_account"` // Deny account commands to users without own token
+
+	// AccountTokens are more tokens of the shared account's user, e.g. of its demo and real accounts,
+	// users without an own account pick the one they trade on with /account
+	AccountTokens []string `mapstructure:"account_tokens"`
+
+	// ActivityAlerts reports trades and transfers made outside the bot on own accounts of users active in the session
+	ActivityAlerts bool `mapstructure:"activity_alerts"`
+
+	// AccountIdleTimeout logs out accounts linked with /connect after this long without use, 0 keeps them linked
+	AccountIdleTimeout time.Duration `mapstructure:"account_idle_timeout"`
+
+	// SubscriptionIdleTimeout stops dashboards, tracked contracts and watches of chats without interaction for this long, 0 disables it
+	SubscriptionIdleTimeout time.Duration `mapstructure:"subscription_idle_timeout"`
+
+	// TradeConfirmTimeout is how long a quoted /buy trade waits for confirmation, 0 places trades without confirming
+	TradeConfirmTimeout time.Duration `mapstructure:"trade_confirm_timeout"`
+
+	// WipeRetention is how long data deleted with /wipe me can be restored before it is purged
+	WipeRetention time.Duration `mapstructure:"wipe_retention"`
+
+	// Open contracts a user may hold on one symbol at a time, 0 means no limit; overrides are keyed by symbol
+	MaxOpenContracts         int            `mapstructure:"max_open_contracts"`
+	MaxOpenContractsBySymbol map[string]int `mapstructure:"max_open_contracts_by_symbol"`
+
+	// Symbols and market types (synthetics, forex, crypto, commodities or indices) users can't trade
+	BlockedSymbols []string `mapstructure:"blocked_symbols"`
+	BlockedMarkets []string `mapstructure:"blocked_markets"`
+
+	// PublicURL is the external base URL of the bot HTTP server, enables OAuth account linking
+	PublicURL string `mapstructure:"public_url"`
+
+	// Responsible-trading nudges after consecutive losses
+	LossStreak LossStreakConfig `mapstructure:"loss_streak"`
+
+	// Per-user limits of daily loss, stake and open positions
+	Risk RiskConfig `mapstructure:"risk"`
+
+	// Live dashboard refreshed in place
+	Dashboard DashboardConfig `mapstructure:"dashboard"`
+
+	// Notifications shortly before short-duration contracts expire, enabled per user with /expiryalerts
+	ExpiryAlerts ExpiryAlertConfig `mapstructure:"expiry_alerts"`
+
+	// Safety watchdog pausing trading when Deriv API misbehaves
+	Watchdog WatchdogConfig `mapstructure:"watchdog"`
+
+	// Power-saving mode of deployments that are rarely used
+	Idle IdleConfig `mapstructure:"idle"`
+
+	// Confirmation of free-form questions estimated to use many LLM tokens
+	LLMCost LLMCostConfig `mapstructure:"llm_cost"`
+
+	// Branding of generated charts
+	Chart ChartConfig `mapstructure:"chart"`
+
+	// Welcome and help texts of the deployment
+	Content ContentConfig `mapstructure:"content"`
+
+	// Icons of contract types and trade outcomes in trade messages
+	Theme ThemeConfig `mapstructure:"theme"`
+
+	// Nightly reconciliation of the trade journal against Deriv
+	Reconciliation ReconciliationConfig `mapstructure:"reconciliation"`
+}
+
+// ChartConfig holds settings of generated charts
+type ChartConfig struct {
+	// Watermark is written onto charts, {user} is replaced with the username and {time} with the UTC render time
+	Watermark string `mapstructure:"watermark"`
+}
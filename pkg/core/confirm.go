@@ -0,0 +1,213 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// directionChoiceTimeout is how long Up/Down buttons of /buy stay valid when the trade waits on the server
+const directionChoiceTimeout = 15 * time.Minute
+
+// pendingTrade is a quoted trade waiting for the user to confirm it, or a trade waiting for its direction
+// when /buy arguments don't fit into callback data
+type pendingTrade struct {
+	id       int64
+	chatID   int64
+	username string
+	account  string // Login ID of the account the trade was quoted for, empty when no quote was shown
+	req      *TradeRequest
+	tags     []string
+	expires  time.Time
+}
+
+// pendingTrades holds the trade waiting for confirmation of each user in each chat, by ID
+type pendingTrades struct {
+	mu     sync.Mutex
+	lastID int64
+	trades map[int64]*pendingTrade
+}
+
+// put stores the trade of the user in the chat, replacing their earlier one, and returns its ID.
+// Expired trades are dropped on the way.
+func (p *pendingTrades) put(chatID int64, trade *pendingTrade) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for id, other := range p.trades {
+		if (other.chatID == chatID && other.username == trade.username) || now.After(other.expires) {
+			delete(p.trades, id)
+		}
+	}
+
+	p.lastID++
+	trade.id = p.lastID
+	trade.chatID = chatID
+	p.trades[trade.id] = trade
+
+	return trade.id
+}
+
+// take removes and returns the trade of the chat with the ID, buttons of replaced trades find nothing.
+// A trade of another user is returned without removing it and owned is false.
+func (p *pendingTrades) take(chatID, id int64, username string) (trade *pendingTrade, owned bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	trade, ok := p.trades[id]
+	if !ok || trade.chatID != chatID {
+		return nil, false
+	}
+
+	if trade.username != username {
+		return trade, false
+	}
+	delete(p.trades, id)
+
+	return trade, true
+}
+
+// confirmTradePrompt quotes the trade and asks the user to confirm it before it's placed
+func (b *Bot) confirmTradePrompt(ctx context.Context, msg *Message, client DerivClient, req *TradeRequest, tags []string) (*Response, error) {
+	quote, err := client.GetQuote(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	// Trades that are always confirmed, like hedges, keep the quote as long as a proposal when confirmation is off
+	timeout := b.cfg.TradeConfirmTimeout
+	if timeout <= 0 {
+		timeout = proposalValidity
+	}
+
+	// The quoted price is what gets bought on Confirm
+	req.Quote = quote
+	account := client.ActiveAccount()
+	id := b.pendingTrades.put(msg.ChatID, &pendingTrade{
+		username: msg.Username,
+		account:  account.LoginID,
+		req:      req,
+		tags:     tags,
+		expires:  time.Now().Add(timeout),
+	})
+
+	f := b.formatter(ctx, msg)
+
+	var sb strings.Builder
+	sb.WriteString("📝 Confirm trade\n\n")
+	fmt.Fprintf(&sb, "Account: %s\n", account.Label())
+	fmt.Fprintf(&sb, "Symbol: %s %s\n", req.Symbol, contractLabel(f, req))
+	fmt.Fprintf(&sb, "Price: %s\n", f.Money(quote.AskPrice, quote.Currency))
+	// Multipliers have no payout and run until sold or closed by their limits
+	if !req.IsMultiplier() {
+		fmt.Fprintf(&sb, "Payout: %s\n", f.Money(quote.Payout, quote.Currency))
+		fmt.Fprintf(&sb, "Duration: %s\n", req.Duration)
+	}
+	writeLimits(&sb, f, req.Limits, quote.Currency)
+	if len(tags) > 0 {
+		fmt.Fprintf(&sb, "Tags: %s\n", formatTags(tags))
+	}
+	fmt.Fprintf(&sb, "\nConfirm buys at this price, the quote expires in %s.", timeout)
+
+	callback := "confirmtrade:" + strconv.FormatInt(id, 10)
+
+	return NewResponse(msg).Text(sb.String()).Keyboard([][]Button{{
+		{Text: "✅ Confirm", CallbackData: callback + ":yes"},
+		{Text: "✖️ Cancel", CallbackData: callback + ":no"},
+	}}).Build(), nil
+}
+
+// handleConfirmTrade places or drops the trade waiting for confirmation, "/confirmtrade <id> yes|no".
+// The confirmation message is edited with the outcome.
+func (b *Bot) handleConfirmTrade(ctx context.Context, msg *Message) (*Response, error) {
+	rb := NewResponse(msg).Edit(msg.MessageID)
+
+	if len(msg.Args) != 2 {
+		return rb.Text("❌ Invalid confirmation").Build(), nil
+	}
+
+	id, err := strconv.ParseInt(msg.Args[0], 10, 64)
+	if err != nil {
+		return rb.Text("❌ Invalid confirmation").Build(), nil
+	}
+
+	trade, owned := b.pendingTrades.take(msg.ChatID, id, msg.Username)
+	if trade != nil && !owned {
+		// Someone else in a group chat can't confirm the trade, it keeps waiting for its owner
+		return NewResponse(msg).Text("⚠️ Only the user who requested this trade can confirm it.").Build(), nil
+	}
+
+	switch {
+	case trade == nil:
+		return rb.Text("ℹ️ This trade is no longer waiting for confirmation. Please use /buy again.").Build(), nil
+	case msg.Args[1] != "yes":
+		return rb.Text("✖️ Trade canceled.").Build(), nil
+	case time.Now().After(trade.expires):
+		return rb.Text("⌛ The quote of this trade expired. Please use /buy again.").Build(), nil
+	}
+
+	req := trade.req
+	if !req.StartAt.IsZero() && !req.StartAt.After(time.Now()) {
+		return rb.Text("❌ The start time of this trade has passed. Please use /buy again.").Build(), nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	// The trade is placed only on the account shown when it was quoted
+	if trade.account != "" && client.ActiveAccount().LoginID != trade.account {
+		return rb.Textf("⚠️ This trade was quoted for account %s, but you switched accounts since. Please use /buy again.",
+			trade.account).Build(), nil
+	}
+
+	// Things may have changed while the user was deciding
+	if stop, err := b.checkTrade(ctx, msg, client, req); err != nil || stop != nil {
+		return stop, err
+	}
+
+	receipt, err := b.executeTrade(ctx, msg, client, req, trade.tags)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt.ReplyToMessageID = 0
+	receipt.EditMessageID = msg.MessageID
+
+	return receipt, nil
+}
+
+// handleDirection places the trade waiting for its Up/Down direction, "/direction <id> up|down"
+func (b *Bot) handleDirection(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) != 2 || (msg.Args[1] != "up" && msg.Args[1] != "down") {
+		return NewResponse(msg).Text("❌ Invalid direction").Build(), nil
+	}
+
+	id, err := strconv.ParseInt(msg.Args[0], 10, 64)
+	if err != nil {
+		return NewResponse(msg).Text("❌ Invalid direction").Build(), nil
+	}
+
+	trade, owned := b.pendingTrades.take(msg.ChatID, id, msg.Username)
+	switch {
+	case trade != nil && !owned:
+		return NewResponse(msg).Text("⚠️ Only the user who requested this trade can place it.").Build(), nil
+	case trade == nil:
+		return NewResponse(msg).Text("ℹ️ This trade is no longer available. Please use /buy again.").Build(), nil
+	case time.Now().After(trade.expires):
+		return NewResponse(msg).Text("⌛ These buttons expired. Please use /buy again.").Build(), nil
+	}
+
+	req := *trade.req
+	req.ContractType = "CALL"
+	if msg.Args[1] == "down" {
+		req.ContractType = "PUT"
+	}
+
+	return b.tradeDirection(ctx, msg, &req, trade.tags)
+}
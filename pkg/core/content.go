@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// bucketContent is the storage bucket holding texts set by admins with /setcontent, keyed by content name
+const bucketContent = "content"
+
+// Names of texts operators can replace
+const (
+	contentWelcome = "welcome"
+	contentHelp    = "help"
+)
+
+// commandsPlaceholder in a custom help text is replaced with the built-in list of commands
+const commandsPlaceholder = "{commands}"
+
+// defaultWelcome is the /start text unless the operator replaced it
+const defaultWelcome = `👋 Welcome to Deriv Trading Bot!
+
+Use /help to see available commands.`
+
+// ContentConfig holds texts replacing the built-in ones of the deployment, /setcontent overrides them at runtime
+type ContentConfig struct {
+	Welcome string `mapstructure:"welcome"` // Reply to /start
+	Help    string `mapstructure:"help"`    // Reply to /help, {commands} is replaced with the built-in list of commands
+}
+
+// content returns the text an admin set with /setcontent, or the configured one, or fallback when neither is set
+func (b *Bot) content(ctx context.Context, name, fallback string) string {
+	var text string
+	err := b.storage.Get(ctx, bucketContent, name, &text)
+	if err == nil {
+		return text
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		log.Printf("Failed to load %s content: %v", name, err)
+	}
+
+	configured := b.cfg.Content.Welcome
+	if name == contentHelp {
+		configured = b.cfg.Content.Help
+	}
+
+	if configured != "" {
+		return configured
+	}
+
+	return fallback
+}
+
+// handleSetContent replaces the welcome or help text of the bot, admin only.
+// "/setcontent <welcome|help> <text>" keeps line breaks of the text, "/setcontent <welcome|help> reset" restores the configured one.
+func (b *Bot) handleSetContent(ctx context.Context, msg *Message) (*Response, error) {
+	if !b.isAdmin(msg.Username) {
+		return NewResponse(msg).Text("⚠️ This command is available to admins only.").Build(), nil
+	}
+
+	usage := "❌ Usage: /setcontent <welcome|help> <text|reset>\nIn the help text, {commands} is replaced with the list of commands."
+	if len(msg.Args) < 2 {
+		return NewResponse(msg).Text(usage).Build(), nil
+	}
+
+	name := strings.ToLower(msg.Args[0])
+	if name != contentWelcome && name != contentHelp {
+		return NewResponse(msg).Text(usage).Build(), nil
+	}
+
+	if len(msg.Args) == 2 && strings.EqualFold(msg.Args[1], "reset") {
+		if err := b.storage.Delete(ctx, bucketContent, name); err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("failed to delete %s content: %w", name, err)
+		}
+
+		log.Printf("Audit: admin %s reset the %s text", msg.Username, name)
+
+		return NewResponse(msg).Textf("✅ The %s text is back to the configured one.", name).Build(), nil
+	}
+
+	// The text is taken as typed, so line breaks survive
+	text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(msg.ArgsText), msg.Args[0]))
+	if text == "" {
+		text = strings.Join(msg.Args[1:], " ")
+	}
+
+	if err := b.storage.Put(ctx, bucketContent, name, text); err != nil {
+		return nil, fmt.Errorf("failed to save %s content: %w", name, err)
+	}
+
+	log.Printf("Audit: admin %s changed the %s text", msg.Username, name)
+
+	command := "start"
+	if name == contentHelp {
+		command = "help"
+	}
+
+	return NewResponse(msg).Textf("✅ The %s text has been updated. Use /%s to see it.", name, command).Build(), nil
+}
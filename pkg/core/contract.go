@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// contractTimeLayout formats times of contract details
+const contractTimeLayout = "2006-01-02 15:04:05 UTC"
+
+// handleContract shows full details of a contract of the user's account, "/contract <contract_id>".
+// Open contracts that can be sold get a Sell button.
+func (b *Bot) handleContract(ctx context.Context, msg *Message) (*Response, error) {
+	contractID, usage := parseContractID(msg, "/contract")
+	if usage != nil {
+		return usage, nil
+	}
+
+	// Deriv only returns contracts of the account the client is authorized with
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := client.GetContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	resp := NewResponse(msg).Text(formatContract(b.formatter(ctx, msg), info))
+
+	if !info.IsSold && info.Status == ContractStatusOpen && info.IsValidToSell {
+		resp.Keyboard([][]Button{{
+			{Text: "💸 Sell now", CallbackData: fmt.Sprintf("sell:%d", info.ContractID)},
+		}})
+	}
+
+	return resp.Build(), nil
+}
+
+// formatContract renders details of the contract, lines of values Deriv didn't return are left out
+func formatContract(f Formatter, info *ContractInfo) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "📄 Contract %d\n\n", info.ContractID)
+	fmt.Fprintf(&sb, "Symbol: %s\n", info.Symbol)
+	fmt.Fprintf(&sb, "Type: %s %s\n", f.ContractIcon(info.ContractType), info.ContractType)
+	fmt.Fprintf(&sb, "Status: %s\n", f.Outcome(info.Status, info.Profit))
+	if info.Longcode != "" {
+		fmt.Fprintf(&sb, "\n%s\n", info.Longcode)
+	}
+
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "Stake: %s\n", f.Money(info.BuyPrice, info.Currency))
+	if info.Payout > 0 {
+		fmt.Fprintf(&sb, "Payout: %s\n", f.Money(info.Payout, info.Currency))
+	}
+	if info.IsSold {
+		fmt.Fprintf(&sb, "Sold for: %s\n", f.Money(info.SellPrice, info.Currency))
+	}
+	fmt.Fprintf(&sb, "Profit: %s\n", f.SignedMoney(info.Profit, info.Currency))
+
+	sb.WriteString("\n")
+	if info.Barrier != "" {
+		fmt.Fprintf(&sb, "Barrier: %s\n", info.Barrier)
+	}
+	if info.EntrySpot != 0 {
+		fmt.Fprintf(&sb, "Entry spot: %s%s\n", f.Number(info.EntrySpot, 2), contractTime(" at ", info.EntryTime))
+	}
+	if info.ExitSpot != 0 {
+		fmt.Fprintf(&sb, "Exit spot: %s%s\n", f.Number(info.ExitSpot, 2), contractTime(" at ", info.ExitTime))
+	} else if info.CurrentSpot != 0 {
+		fmt.Fprintf(&sb, "Current spot: %s\n", f.Number(info.CurrentSpot, 2))
+	}
+
+	sb.WriteString("\n")
+	if !info.PurchaseTime.IsZero() {
+		fmt.Fprintf(&sb, "Purchased: %s\n", info.PurchaseTime.UTC().Format(contractTimeLayout))
+	}
+	if !info.ExpiryTime.IsZero() {
+		fmt.Fprintf(&sb, "Expiry: %s\n", info.ExpiryTime.UTC().Format(contractTimeLayout))
+	}
+	if info.TickCount > 0 {
+		fmt.Fprintf(&sb, "Ticks: %d of %d\n", info.TicksPassed, info.TickCount)
+	}
+	if info.IsSold && !info.SellTime.IsZero() {
+		fmt.Fprintf(&sb, "Closed: %s\n", info.SellTime.UTC().Format(contractTimeLayout))
+	} else if !info.IsSold && !info.ExpiryTime.IsZero() && info.TickCount == 0 {
+		fmt.Fprintf(&sb, "Expires in %s\n", time.Until(info.ExpiryTime).Round(time.Second))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// contractTime renders a time of the contract after the prefix, empty when it's unknown
+func contractTime(prefix string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return prefix + t.UTC().Format("15:04:05 UTC")
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ContractLimits describes trading constraints of a contract type on a symbol
+type ContractLimits struct {
+	Symbol       string
+	ContractType string // Deriv contract type, e.g. CALL
+	Category     string // Deriv contract category, e.g. callput
+	Currency     string
+	MinStake     float64
+	MaxStake     float64
+	MinDuration  string // Minimal contract duration, e.g. 1t
+	MaxDuration  string // Maximal contract duration, e.g. 365d
+	Barriers     int    // Number of barriers, 0 for contracts without one
+	Barrier      string // Default barrier, e.g. +0.1, empty when there is none
+
+	StartType     string        // spot for contracts starting right away, forward for forward-starting ones
+	ForwardStarts []StartWindow // Sessions forward-starting contracts may start in
+}
+
+// StartWindow is a period a forward-starting contract may start in
+type StartWindow struct {
+	Open  time.Time
+	Close time.Time
+}
+
+// contractCategoryNames maps Deriv contract categories to names used in chat messages
+var contractCategoryNames = map[string]string{
+	"callput":      "rise/fall",
+	"touchnotouch": "touch/no touch",
+	"digits":       "digits",
+	"multiplier":   "multipliers",
+	"accumulator":  "accumulators",
+}
+
+// categoryName returns a human-readable name of a contract category
+func categoryName(category string) string {
+	if name, ok := contractCategoryNames[category]; ok {
+		return name
+	}
+	return category
+}
+
+// contractLimits returns constraints of contracts available for the symbol, cached for the configured time,
+// so the buy flow doesn't wait for contracts_for on every trade
+func (b *Bot) contractLimits(ctx context.Context, symbol string) ([]ContractLimits, error) {
+	limits, err := b.limits.Do(ctx, symbol, func(ctx context.Context) ([]ContractLimits, error) {
+		return b.derivClient.GetContractLimits(ctx, symbol)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract limits: %w", err)
+	}
+	return limits, nil
+}
+
+// contractLimit returns constraints of the contract type on the symbol, nil when it isn't offered
+func contractLimit(limits []ContractLimits, contractType string) *ContractLimits {
+	for i := range limits {
+		if limits[i].ContractType == contractType {
+			return &limits[i]
+		}
+	}
+	return nil
+}
+
+// stakeAllowed reports whether the stake is within bounds of the contract
+func (l *ContractLimits) stakeAllowed(amount float64) bool {
+	return (l.MinStake <= 0 || amount >= l.MinStake) && (l.MaxStake <= 0 || amount <= l.MaxStake)
+}
+
+// validateStake checks the stake against limits of the given contract types on the symbol.
+// It returns a user-facing message when the stake can't be used, or an empty string when it's valid.
+func (b *Bot) validateStake(ctx context.Context, f Formatter, symbol string, amount float64, contractTypes ...string) (string, error) {
+	limits, err := b.contractLimits(ctx, symbol)
+	if err != nil {
+		return "", err
+	}
+
+	for _, contractType := range contractTypes {
+		limit := contractLimit(limits, contractType)
+		if limit == nil {
+			return fmt.Sprintf("❌ %s contracts are not available for %s", contractType, symbol), nil
+		}
+
+		if limit.MinStake > 0 && amount < limit.MinStake {
+			return fmt.Sprintf("❌ Minimum stake for %s %s is %s",
+				symbol, categoryName(limit.Category), f.Money(limit.MinStake, limit.Currency)), nil
+		}
+
+		if limit.MaxStake > 0 && amount > limit.MaxStake {
+			return fmt.Sprintf("❌ Maximum stake for %s %s is %s",
+				symbol, categoryName(limit.Category), f.Money(limit.MaxStake, limit.Currency)), nil
+		}
+	}
+
+	return "", nil
+}
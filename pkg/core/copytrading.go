@@ -0,0 +1,333 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bucketCopying is the storage bucket holding tokens of traders each user copies, tokens make it sensitive
+const bucketCopying = "copying"
+
+// CopyTrader is a trader whose trades the account copies
+type CopyTrader struct {
+	LoginID    string
+	Token      string // Read-only token of the trader the copying was started with
+	MinStake   float64
+	MaxStake   float64
+	Assets     []string
+	TradeTypes []string
+}
+
+// CopyTradingList holds traders the account copies and accounts copying it
+type CopyTradingList struct {
+	Traders []CopyTrader
+	Copiers []string // Login IDs of accounts copying this one
+}
+
+// CopyOptions limits which trades of a trader are copied, zero values copy every trade
+type CopyOptions struct {
+	MaxStake float64
+	Assets   []string
+}
+
+// CopyStats is the track record of a trader offering their trades for copying
+type CopyStats struct {
+	TraderID          string
+	ActiveSince       time.Time
+	Copiers           int
+	TotalTrades       int
+	ProfitableTrades  float64 // Share of profitable trades in percent
+	AvgProfit         float64 // Average profit of winning trades in percent
+	AvgLoss           float64 // Average loss of losing trades in percent
+	AvgDuration       time.Duration
+	Last12Months      float64 // Net change in equity over 12 months in percent
+	PerformanceChance float64 // Deriv's probability of the trader's performance
+}
+
+// copyUsage lists the subcommands of /copy
+const copyUsage = "❌ Usage:\n" +
+	"/copy list - Traders you copy and accounts copying you\n" +
+	"/copy start <trader_token> [max_stake] [symbol...] - Copy trades of a trader\n" +
+	"/copy stop <trader_token> - Stop copying a trader\n" +
+	"/copy stats <trader_loginid> - Track record of a trader"
+
+// handleCopy manages copy trading of the user's own account, "/copy list|start|stop|stats ..."
+func (b *Bot) handleCopy(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) == 0 {
+		return NewResponse(msg).Text(copyUsage).Build(), nil
+	}
+
+	// Copying changes what the account trades, so it's never done on the shared account
+	token, err := b.userToken(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, ErrNotConnected
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	args := msg.Args[1:]
+	switch strings.ToLower(msg.Args[0]) {
+	case "list":
+		return b.copyList(ctx, msg, client)
+	case "start":
+		return b.copyStart(ctx, msg, client, args)
+	case "stop":
+		if len(args) != 1 {
+			return NewResponse(msg).Text(copyUsage).Build(), nil
+		}
+
+		if err := client.StopCopying(ctx, args[0]); err != nil {
+			return nil, err
+		}
+
+		if err := b.trackCopying(ctx, msg.Username, args[0], false); err != nil {
+			log.Printf("Failed to forget copy session of %s: %v", msg.Username, err)
+		}
+
+		return withoutToken(msg, NewResponse(msg).Text("⏹ Stopped copying the trader. Contracts copied so far stay open.")), nil
+	case "stats":
+		if len(args) != 1 {
+			return NewResponse(msg).Text(copyUsage).Build(), nil
+		}
+
+		stats, err := client.GetCopyStatistics(ctx, strings.ToUpper(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		return NewResponse(msg).Text(formatCopyStats(b.formatter(ctx, msg), stats)).Build(), nil
+	default:
+		return NewResponse(msg).Text(copyUsage).Build(), nil
+	}
+}
+
+// copyList shows traders the account copies and who copies it
+func (b *Bot) copyList(ctx context.Context, msg *Message, client DerivClient) (*Response, error) {
+	list, err := client.GetCopyTradingList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f := b.formatter(ctx, msg)
+	currency := client.Currency()
+
+	var sb strings.Builder
+	sb.WriteString("👥 Copy trading\n\n")
+
+	if len(list.Traders) == 0 {
+		sb.WriteString("You don't copy any trader. Start with /copy start <trader_token>.\n")
+	} else {
+		sb.WriteString("Copying:\n")
+		for _, trader := range list.Traders {
+			fmt.Fprintf(&sb, "• %s", trader.LoginID)
+			if trader.MaxStake > 0 {
+				fmt.Fprintf(&sb, ", stakes up to %s", f.Money(trader.MaxStake, currency))
+			}
+			if len(trader.Assets) > 0 {
+				fmt.Fprintf(&sb, ", %s", strings.Join(trader.Assets, ", "))
+			}
+			if len(trader.TradeTypes) > 0 {
+				fmt.Fprintf(&sb, ", %s", strings.Join(trader.TradeTypes, "/"))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(list.Copiers) > 0 {
+		fmt.Fprintf(&sb, "\nCopied by: %s\n", strings.Join(list.Copiers, ", "))
+	}
+
+	return NewResponse(msg).Text(strings.TrimRight(sb.String(), "\n")).Build(), nil
+}
+
+// copyStart starts copying a trader, "/copy start <trader_token> [max_stake] [symbol...]"
+func (b *Bot) copyStart(ctx context.Context, msg *Message, client DerivClient, args []string) (*Response, error) {
+	if len(args) == 0 {
+		return NewResponse(msg).Text(copyUsage).Build(), nil
+	}
+
+	if paused, reason := b.watchdog.Paused(); paused {
+		return NewResponse(msg).Textf("🛑 Trading is paused (%s), copying can't start now.", reason).Build(), nil
+	}
+
+	token, rest := args[0], args[1:]
+
+	var opts CopyOptions
+	if len(rest) > 0 {
+		if stake, err := strconv.ParseFloat(rest[0], 64); err == nil {
+			if stake <= 0 {
+				return NewResponse(msg).Text("❌ The maximum stake must be positive").Build(), nil
+			}
+			opts.MaxStake = stake
+			rest = rest[1:]
+		}
+	}
+
+	for _, arg := range rest {
+		matches := b.resolver.Resolve(arg)
+		if len(matches) > 1 {
+			return NewResponse(msg).Textf("❌ Symbol %q is ambiguous: %s", arg, strings.Join(matches, ", ")).Build(), nil
+		}
+		opts.Assets = append(opts.Assets, matches[0])
+	}
+
+	// Copied trades can't be held to the per-trade limit one by one, so it caps what is copied instead
+	if b.cfg.Risk.MaxStake > 0 {
+		limit, err := b.risk.limit(ctx, b.cfg.Risk.MaxStake, client.Currency())
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.MaxStake == 0 || opts.MaxStake > limit {
+			opts.MaxStake = limit
+		}
+	}
+
+	if err := client.StartCopying(ctx, token, opts); err != nil {
+		return nil, err
+	}
+
+	// Copying goes on at Deriv without the bot, the session is kept so the kill switch can stop it
+	if err := b.trackCopying(ctx, msg.Username, token, true); err != nil {
+		log.Printf("Failed to keep copy session of %s: %v", msg.Username, err)
+	}
+
+	f := b.formatter(ctx, msg)
+
+	var sb strings.Builder
+	sb.WriteString("▶️ Copying the trader's new trades")
+	if opts.MaxStake > 0 {
+		fmt.Fprintf(&sb, " with stakes up to %s", f.Money(opts.MaxStake, client.Currency()))
+	}
+	if len(opts.Assets) > 0 {
+		fmt.Fprintf(&sb, " on %s", strings.Join(opts.Assets, ", "))
+	}
+	sb.WriteString(".\nCopied contracts are bought on your account by Deriv, outside the bot's limits. Stop with /copy stop <trader_token>.")
+
+	return withoutToken(msg, NewResponse(msg).Text(sb.String())), nil
+}
+
+// trackCopying adds or removes the trader token to copy sessions of the user
+func (b *Bot) trackCopying(ctx context.Context, username, token string, active bool) error {
+	b.copying.Lock()
+	defer b.copying.Unlock()
+
+	var tokens []string
+	if err := b.storage.Get(ctx, bucketCopying, username, &tokens); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	var kept []string
+	for _, t := range tokens {
+		if t != token {
+			kept = append(kept, t)
+		}
+	}
+	if active {
+		kept = append(kept, token)
+	}
+
+	if len(kept) == 0 {
+		if err := b.storage.Delete(ctx, bucketCopying, username); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	return b.storage.Put(ctx, bucketCopying, username, kept)
+}
+
+// stopAllCopying stops copy sessions of all users started with /copy start,
+// it returns the number of stopped sessions and users whose sessions couldn't be stopped
+func (b *Bot) stopAllCopying(ctx context.Context) (int, []string) {
+	b.copying.Lock()
+	defer b.copying.Unlock()
+
+	usernames, err := b.storage.Keys(ctx, bucketCopying)
+	if err != nil {
+		log.Printf("Failed to list copy sessions: %v", err)
+		return 0, nil
+	}
+
+	var stopped int
+	var failed []string
+
+	for _, username := range usernames {
+		var tokens []string
+		if err := b.storage.Get(ctx, bucketCopying, username, &tokens); err != nil {
+			log.Printf("Failed to load copy sessions of %s: %v", username, err)
+			failed = append(failed, username)
+			continue
+		}
+
+		client, err := b.clientFor(ctx, username)
+		if err != nil {
+			log.Printf("Failed to get client of %s: %v", username, err)
+			failed = append(failed, username)
+			continue
+		}
+
+		// Sessions that couldn't be stopped are kept, so the next kill switch tries them again
+		var left []string
+		for _, token := range tokens {
+			if err := client.StopCopying(ctx, token); err != nil {
+				log.Printf("Kill switch failed to stop copy trading of %s: %v", username, err)
+				left = append(left, token)
+				continue
+			}
+			stopped++
+		}
+
+		if len(left) > 0 {
+			failed = append(failed, username)
+			err = b.storage.Put(ctx, bucketCopying, username, left)
+		} else {
+			err = b.storage.Delete(ctx, bucketCopying, username)
+		}
+		if err != nil {
+			log.Printf("Failed to save copy sessions of %s: %v", username, err)
+		}
+	}
+
+	return stopped, failed
+}
+
+// withoutToken builds the response deleting the typed command, it carries the trader's token
+func withoutToken(msg *Message, rb *ResponseBuilder) *Response {
+	if msg.CallbackData == "" {
+		rb.Delete(msg.MessageID).NoReply()
+	}
+
+	return rb.Build()
+}
+
+// formatCopyStats renders the track record of a trader
+func formatCopyStats(f Formatter, stats *CopyStats) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "📊 Trader %s\n\n", stats.TraderID)
+	if !stats.ActiveSince.IsZero() {
+		fmt.Fprintf(&sb, "Active since: %s\n", stats.ActiveSince.UTC().Format(time.DateOnly))
+	}
+	fmt.Fprintf(&sb, "Copiers: %d\n", stats.Copiers)
+	fmt.Fprintf(&sb, "Trades: %d, %s%% profitable\n", stats.TotalTrades, f.Number(stats.ProfitableTrades, 1))
+	fmt.Fprintf(&sb, "Average win: %s, average loss: %s\n", f.Percent(stats.AvgProfit, 1), f.Percent(stats.AvgLoss, 1))
+	if stats.AvgDuration > 0 {
+		fmt.Fprintf(&sb, "Average holding time: %s\n", stats.AvgDuration)
+	}
+	fmt.Fprintf(&sb, "Last 12 months: %s\n", f.Percent(stats.Last12Months, 1))
+	fmt.Fprintf(&sb, "Performance probability: %s", f.Number(stats.PerformanceChance, 2))
+
+	return sb.String()
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// bucketCredentials is the storage bucket holding user Deriv tokens, it's encrypted at rest by the store
+const bucketCredentials = "credentials"
+
+// ErrNotConnected is returned when a user must link a Deriv account before using account commands
+var ErrNotConnected = errors.New("deriv account is not connected")
+
+// DerivClientPool provides Deriv clients authorized with user tokens
+type DerivClientPool interface {
+	Client(ctx context.Context, token string) (DerivClient, error)
+	Release(token string) error
+}
+
+// Credentials holds a user's own Deriv API token
+type Credentials struct {
+	Token    string          `json:"token"`
+	Accounts []LinkedAccount `json:"accounts,omitempty"` // All accounts authorized through OAuth
+	LastUsed time.Time       `json:"last_used,omitempty"`
+	Expired  bool            `json:"expired,omitempty"` // The token was dropped after inactivity
+}
+
+// userToken returns the Deriv token linked by the user or configured for them, empty when there is none.
+// Linked accounts idle for longer than the configured timeout are logged out, returning ErrSessionExpired.
+func (b *Bot) userToken(ctx context.Context, username string) (string, error) {
+	var creds Credentials
+	err := b.storage.Get(ctx, bucketCredentials, username, &creds)
+	switch {
+	case err == nil && creds.Expired:
+		return "", ErrSessionExpired
+	case err == nil && b.sessionIdle(&creds, time.Now()):
+		if err := b.expireSession(ctx, username, &creds); err != nil {
+			return "", err
+		}
+		return "", ErrSessionExpired
+	case err == nil:
+		b.touchSession(ctx, username, &creds)
+		return creds.Token, nil
+	case !errors.Is(err, ErrNotFound):
+		return "", fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	return b.cfg.UserTokens[username], nil
+}
+
+// clientFor returns the Deriv client for the user's own account,
+// falling back to the shared account unless own accounts are required
+func (b *Bot) clientFor(ctx context.Context, username string) (DerivClient, error) {
+	token, err := b.userToken(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == "" {
+		if b.cfg.RequireOwnAccount {
+			return nil, ErrNotConnected
+		}
+		return b.sharedClient(ctx, username)
+	}
+
+	client, err := b.pool.Client(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect user account: %w", err)
+	}
+
+	return client, nil
+}
+
+// accountGuard asks the user to link their account when a handler requires one
+func (b *Bot) accountGuard(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		resp, err := next(ctx, msg)
+		if errors.Is(err, ErrNotConnected) {
+			return NewResponse(msg).Text("🔗 Please connect your Deriv account first: /connect <api_token>").Build(), nil
+		}
+
+		if errors.Is(err, ErrSessionExpired) {
+			return NewResponse(msg).
+				Text("🔒 Your Deriv account was logged out after a period of inactivity. " +
+					"Connect it again with /connect, or use /disconnect to unlink it.").
+				Build(), nil
+		}
+
+		return resp, err
+	}
+}
+
+// handleConnect links the user's own Deriv account using an API token
+func (b *Bot) handleConnect(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) < 1 && b.cfg.PublicURL != "" {
+		return b.accountLinkResponse(msg)
+	}
+
+	if len(msg.Args) < 1 {
+		return NewResponse(msg).
+			Text("❌ Please provide your Deriv API token. Example: /connect <api_token>\n\n" +
+				"Create a token with Read and Trade scopes at https://app.deriv.com/account/api-token").
+			Build(), nil
+	}
+
+	token := msg.Args[0]
+
+	// The message contains the token, so it's removed from the chat regardless of the outcome
+	rb := NewResponse(msg).Delete(msg.MessageID).NoReply()
+
+	client, err := b.pool.Client(ctx, token)
+	if err != nil {
+		log.Printf("Failed to connect account of %s: %v", msg.Username, err)
+		return rb.Text("❌ Failed to connect your Deriv account. Please check the token and try again.").Build(), nil
+	}
+
+	balance, err := client.GetBalance(ctx)
+	if err != nil {
+		b.pool.Release(token)
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	var previous Credentials
+	if err := b.storage.Get(ctx, bucketCredentials, msg.Username, &previous); err != nil && !errors.Is(err, ErrNotFound) {
+		log.Printf("Failed to load previous credentials of %s: %v", msg.Username, err)
+	}
+
+	if err := b.storage.Put(ctx, bucketCredentials, msg.Username, &Credentials{Token: token, LastUsed: time.Now()}); err != nil {
+		b.pool.Release(token)
+		return nil, fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	if previous.Token != "" && previous.Token != token {
+		if err := b.pool.Release(previous.Token); err != nil {
+			log.Printf("Failed to release previous connection of %s: %v", msg.Username, err)
+		}
+	}
+
+	return rb.Textf("✅ Your Deriv account is connected. Balance: %s\n\nYour message with the token was deleted for safety.",
+		b.formatter(ctx, msg).Money(balance.Amount, balance.Currency)).Build(), nil
+}
+
+// handleDisconnect unlinks the user's own Deriv account
+func (b *Bot) handleDisconnect(ctx context.Context, msg *Message) (*Response, error) {
+	var creds Credentials
+	err := b.storage.Get(ctx, bucketCredentials, msg.Username, &creds)
+	if errors.Is(err, ErrNotFound) {
+		return NewResponse(msg).Text("ℹ️ You have no connected Deriv account.").Build(), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	if err := b.storage.Delete(ctx, bucketCredentials, msg.Username); err != nil {
+		return nil, fmt.Errorf("failed to delete credentials: %w", err)
+	}
+
+	if err := b.pool.Release(creds.Token); err != nil {
+		log.Printf("Failed to release connection of %s: %v", msg.Username, err)
+	}
+
+	return NewResponse(msg).Text("✅ Your Deriv account has been disconnected.").Build(), nil
+}
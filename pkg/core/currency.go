@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// exchangeRateTTL is how long fetched exchange rates are reused
+const exchangeRateTTL = 5 * time.Minute
+
+// currencyCodePattern matches Deriv currency codes, e.g. USD, EUR, BTC, eUSDT
+var currencyCodePattern = regexp.MustCompile(`^[a-zA-Z]{3,5}$`)
+
+// convert converts the amount between currencies using Deriv exchange rates
+func (b *Bot) convert(ctx context.Context, amount float64, from, to string) (float64, error) {
+	if strings.EqualFold(from, to) {
+		return amount, nil
+	}
+
+	rate, err := b.rates.Do(ctx, from+":"+to, func(ctx context.Context) (float64, error) {
+		return b.derivClient.GetExchangeRate(ctx, from, to)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get exchange rate %s/%s: %w", from, to, err)
+	}
+
+	return amount * rate, nil
+}
+
+// accountCurrency returns the currency of the user's account, failures are logged and the default currency is assumed
+func (b *Bot) accountCurrency(ctx context.Context, username string) string {
+	client, err := b.clientFor(ctx, username)
+	if err != nil {
+		log.Printf("Failed to get client of %s: %v", username, err)
+		return defaultCurrency
+	}
+	return client.Currency()
+}
+
+// handleCurrency shows or changes the user's display currency
+func (b *Bot) handleCurrency(ctx context.Context, msg *Message) (*Response, error) {
+	settings, err := b.getSettings(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Args) == 0 {
+		text := "💱 Display currency is not set. Use /currency <code> to show converted amounts, e.g. /currency EUR"
+		if settings.DisplayCurrency != "" {
+			text = fmt.Sprintf("💱 Display currency: %s\nUse /currency off to disable conversion.", settings.DisplayCurrency)
+		}
+
+		return NewResponse(msg).Text(text).Build(), nil
+	}
+
+	code := msg.Args[0]
+	if strings.EqualFold(code, "off") {
+		settings.DisplayCurrency = ""
+		if err := b.saveSettings(ctx, msg.Username, settings); err != nil {
+			return nil, err
+		}
+
+		return NewResponse(msg).Text("✅ Currency conversion disabled").Build(), nil
+	}
+
+	if !currencyCodePattern.MatchString(code) {
+		return NewResponse(msg).Text("❌ Invalid currency code. Example: /currency EUR").Build(), nil
+	}
+	code = strings.ToUpper(code)
+
+	// Make sure the currency is supported before saving it
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := client.GetBalance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	if _, err := b.convert(ctx, 1, balance.Currency, code); err != nil {
+		return NewResponse(msg).Textf("❌ Conversion from %s to %s is not supported", balance.Currency, code).Build(), nil
+	}
+
+	settings.DisplayCurrency = code
+	if err := b.saveSettings(ctx, msg.Username, settings); err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg).Textf("✅ Amounts will also be shown in %s", code).Build(), nil
+}
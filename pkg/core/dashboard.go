@@ -0,0 +1,188 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DashboardConfig holds configuration of live dashboards
+type DashboardConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // How often the dashboard message is updated
+	MaxDuration     time.Duration `mapstructure:"max_duration"`     // Dashboards stop by themselves after this time
+	Symbols         []string      `mapstructure:"symbols"`          // Watchlist, all configured symbols when empty
+}
+
+// dashboards tracks running dashboards by chat
+type dashboards struct {
+	mu      sync.Mutex
+	running map[int64]*dashboardRun
+}
+
+// dashboardRun is a running dashboard
+type dashboardRun struct {
+	cancel context.CancelFunc
+}
+
+// start registers a new dashboard for the chat, stopping the previous one
+func (d *dashboards) start(chatID int64, run *dashboardRun) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if prev, ok := d.running[chatID]; ok {
+		prev.cancel()
+	}
+	d.running[chatID] = run
+}
+
+// finish unregisters the dashboard unless it was already replaced by a newer one
+func (d *dashboards) finish(chatID int64, run *dashboardRun) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.running[chatID] == run {
+		delete(d.running, chatID)
+	}
+}
+
+// stop stops the dashboard of the chat, reports whether one was running
+func (d *dashboards) stop(chatID int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	run, ok := d.running[chatID]
+	if ok {
+		run.cancel()
+		delete(d.running, chatID)
+	}
+
+	return ok
+}
+
+// handleDashboard posts a dashboard message and keeps it up to date, "/dashboard stop" stops it
+func (b *Bot) handleDashboard(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) > 0 && msg.Args[0] == "stop" {
+		if !b.dashboards.stop(msg.ChatID) {
+			return NewResponse(msg).Text("ℹ️ No dashboard is running in this chat.").Build(), nil
+		}
+		// The dashboard message itself shows that it's stopped
+		return nil, nil
+	}
+
+	if b.cfg.Dashboard.RefreshInterval <= 0 {
+		return nil, fmt.Errorf("dashboard refresh interval is not configured")
+	}
+
+	username := msg.Username
+	chatID := msg.ChatID
+
+	// The first render happens right away, so errors are reported to the user
+	f := b.formatter(ctx, msg)
+	text := b.renderDashboard(ctx, f, username)
+
+	err := b.background.Go(func(bgCtx context.Context, notifier Notifier) {
+		notifier = b.userNotifier(bgCtx, notifier, username)
+
+		msgID, err := notifier.Send(bgCtx, dashboardResponse(chatID, 0, text, true))
+		if err != nil {
+			log.Printf("Failed to post dashboard for %s: %v", username, err)
+			return
+		}
+
+		subCtx, unsubscribe := b.subscribe(bgCtx, chatID, "dashboard", "/dashboard")
+		defer unsubscribe()
+
+		dashCtx, cancel := context.WithTimeout(subCtx, b.cfg.Dashboard.MaxDuration)
+		defer cancel()
+
+		run := &dashboardRun{cancel: cancel}
+		b.dashboards.start(chatID, run)
+		defer b.dashboards.finish(chatID, run)
+
+		ticker := time.NewTicker(b.cfg.Dashboard.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-dashCtx.Done():
+				// Render the final state without the Stop button
+				final := text + "\n\n⏹ Dashboard stopped. Use /dashboard to start it again."
+				if _, err := notifier.Send(context.WithoutCancel(dashCtx), dashboardResponse(chatID, msgID, final, false)); err != nil {
+					log.Printf("Failed to stop dashboard for %s: %v", username, err)
+				}
+				return
+			case <-ticker.C:
+				refreshCtx, cancelRefresh := context.WithTimeout(dashCtx, b.cfg.Dashboard.RefreshInterval)
+				text = b.renderDashboard(refreshCtx, f, username)
+				cancelRefresh()
+
+				if _, err := notifier.Send(dashCtx, dashboardResponse(chatID, msgID, text, true)); err != nil {
+					log.Printf("Failed to update dashboard for %s: %v", username, err)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start dashboard: %w", err)
+	}
+
+	return nil, nil
+}
+
+// dashboardResponse builds the dashboard message, editing msgID when it's set
+func dashboardResponse(chatID int64, msgID int, text string, running bool) *Response {
+	rb := NewNotification(chatID).Text(text).Edit(msgID)
+	if running {
+		rb.Keyboard([][]Button{{{Text: "⏹ Stop", CallbackData: "dashboard:stop"}}})
+	}
+
+	return rb.Build()
+}
+
+// renderDashboard summarizes balance, open contracts and watchlist prices of the user.
+// Sections that fail to load are marked unavailable, so one failure doesn't hide the rest.
+func (b *Bot) renderDashboard(ctx context.Context, f Formatter, username string) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "📊 Dashboard · updated %s UTC\n\n", time.Now().UTC().Format(time.TimeOnly))
+
+	client, err := b.clientFor(ctx, username)
+	if err != nil {
+		log.Printf("Dashboard account for %s is unavailable: %v", username, err)
+		sb.WriteString("💰 Balance: unavailable\n📈 Open contracts: unavailable\n")
+	} else {
+		if balance, err := client.GetBalance(ctx); err != nil {
+			log.Printf("Dashboard balance for %s is unavailable: %v", username, err)
+			sb.WriteString("💰 Balance: unavailable\n")
+		} else {
+			fmt.Fprintf(&sb, "💰 Balance: %s\n", f.Money(balance.Amount, balance.Currency))
+		}
+
+		if positions, err := client.GetOpenPositions(ctx); err != nil {
+			log.Printf("Dashboard positions for %s are unavailable: %v", username, err)
+			sb.WriteString("📈 Open contracts: unavailable\n")
+		} else {
+			fmt.Fprintf(&sb, "📈 Open contracts:\n%s\n", formatPositions(f, positions))
+		}
+	}
+
+	symbols := b.cfg.Dashboard.Symbols
+	if len(symbols) == 0 {
+		symbols = b.symbols
+	}
+
+	sb.WriteString("\n👀 Watchlist:\n")
+	for _, symbol := range symbols {
+		if price, err := b.getPrice(ctx, symbol); err != nil {
+			log.Printf("Dashboard price of %s is unavailable: %v", symbol, err)
+			fmt.Fprintf(&sb, "%s: unavailable\n", symbol)
+		} else {
+			fmt.Fprintf(&sb, "%s: %s\n", symbol, f.Number(price, 2))
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// fallbackDuration is used when neither the user nor the market defaults specify a duration
+var fallbackDuration = Duration{Value: 5, Unit: "t"}
+
+// marketTypes maps market types used in configuration to Deriv market codes
+var marketTypes = map[string]string{
+	"synthetics":  "synthetic_index",
+	"forex":       "forex",
+	"crypto":      "cryptocurrency",
+	"commodities": "commodities",
+	"indices":     "indices",
+}
+
+// ContractDefaults holds default contract parameters of a market type
+type ContractDefaults struct {
+	Duration string `mapstructure:"duration"` // e.g. 5t for 5 ticks or 15m for 15 minutes
+}
+
+// Duration is a contract duration in Deriv units: t(icks), s(econds), m(inutes), h(ours) or d(ays)
+type Duration struct {
+	Value int
+	Unit  string
+}
+
+// ParseDuration parses durations like 5t or 15m
+func ParseDuration(s string) (Duration, error) {
+	if len(s) < 2 {
+		return Duration{}, fmt.Errorf("invalid duration %q", s)
+	}
+
+	unit := s[len(s)-1:]
+	switch unit {
+	case "t", "s", "m", "h", "d":
+	default:
+		return Duration{}, fmt.Errorf("invalid duration unit in %q, expected one of t, s, m, h, d", s)
+	}
+
+	value, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || value <= 0 {
+		return Duration{}, fmt.Errorf("invalid duration %q", s)
+	}
+
+	return Duration{Value: value, Unit: unit}, nil
+}
+
+// String formats the duration the way ParseDuration accepts it
+func (d Duration) String() string {
+	return strconv.Itoa(d.Value) + d.Unit
+}
+
+// Span returns the duration as time, it reports false for tick durations
+func (d Duration) Span() (time.Duration, bool) {
+	units := map[string]time.Duration{
+		"s": time.Second,
+		"m": time.Minute,
+		"h": time.Hour,
+		"d": 24 * time.Hour,
+	}
+
+	unit, ok := units[d.Unit]
+	if !ok {
+		return 0, false
+	}
+
+	return time.Duration(d.Value) * unit, true
+}
+
+// parseContractDefaults validates configured defaults and indexes durations by Deriv market code
+func parseContractDefaults(defaults map[string]ContractDefaults) (map[string]Duration, error) {
+	durations := make(map[string]Duration, len(defaults))
+
+	for marketType, d := range defaults {
+		market, ok := marketTypes[marketType]
+		if !ok {
+			return nil, fmt.Errorf("unknown market type %q in contract defaults", marketType)
+		}
+
+		if d.Duration == "" {
+			continue
+		}
+
+		duration, err := ParseDuration(d.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default duration for %s: %w", marketType, err)
+		}
+
+		durations[market] = duration
+	}
+
+	return durations, nil
+}
+
+// defaultDuration returns the configured duration for the market of the symbol
+func (b *Bot) defaultDuration(ctx context.Context, symbol string) Duration {
+	if len(b.durations) == 0 {
+		return fallbackDuration
+	}
+
+	symbols, err := b.activeSymbols(ctx)
+	if err != nil {
+		log.Printf("Failed to look up market of %s: %v", symbol, err)
+		return fallbackDuration
+	}
+
+	for _, s := range symbols {
+		if s.Symbol != symbol {
+			continue
+		}
+
+		if duration, ok := b.durations[s.Market]; ok {
+			return duration
+		}
+		break
+	}
+
+	return fallbackDuration
+}
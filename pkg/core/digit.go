@@ -0,0 +1,130 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// digitUsage explains the arguments of /digit
+const digitUsage = "❌ Usage: /digit <symbol> <amount> <prediction> [ticks]\nThe prediction is a digit from 0 to 9, ticks from 1t to 10t. Example: /digit R_50 1 7 5t"
+
+// defaultDigitDuration is the duration of digit contracts when none is given
+var defaultDigitDuration = Duration{Value: 5, Unit: "t"}
+
+// digitContracts are last-digit contract types by callback name
+var digitContracts = map[string]string{
+	"match":  "DIGITMATCH",
+	"differ": "DIGITDIFF",
+	"over":   "DIGITOVER",
+	"under":  "DIGITUNDER",
+}
+
+// handleDigit trades last-digit contracts, "/digit <symbol> <amount> <prediction> [ticks]". The contract type is
+// chosen on buttons, which call the command again with it appended.
+func (b *Bot) handleDigit(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) < 3 {
+		return NewResponse(msg).Text(digitUsage).Build(), nil
+	}
+
+	stake, err := strconv.ParseFloat(msg.Args[1], 64)
+	if err != nil || stake <= 0 {
+		return NewResponse(msg).Text(digitUsage).Build(), nil
+	}
+
+	digit, err := strconv.Atoi(msg.Args[2])
+	if err != nil || digit < 0 || digit > 9 {
+		return NewResponse(msg).Text(digitUsage).Build(), nil
+	}
+
+	duration := defaultDigitDuration
+	var kind string
+	for _, arg := range msg.Args[3:] {
+		switch {
+		case kind == "" && digitContracts[strings.ToLower(arg)] != "":
+			kind = strings.ToLower(arg)
+		case isDuration(arg):
+			duration, _ = ParseDuration(arg)
+		default:
+			return NewResponse(msg).Text(digitUsage).Build(), nil
+		}
+	}
+
+	if duration.Unit != "t" || duration.Value < 1 || duration.Value > 10 {
+		return NewResponse(msg).Text(digitUsage).Build(), nil
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "digit:" + strings.Join(append([]string{symbol}, msg.Args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	if reason, err := b.tradingBlocked(ctx, symbol); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	f := b.formatter(ctx, msg)
+
+	// The stake is checked on every step, buttons of earlier messages skip the first one
+	contractTypes := []string{"DIGITMATCH", "DIGITDIFF"}
+	if kind != "" {
+		contractTypes = []string{digitContracts[kind]}
+	}
+	if reason, err := b.validateStake(ctx, f, symbol, stake, contractTypes...); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	if kind == "" {
+		callback := fmt.Sprintf("digit:%s:%s:%d:%s", symbol, strconv.FormatFloat(stake, 'f', -1, 64), digit, duration)
+		buttons := [][]Button{{
+			{Text: fmt.Sprintf("= %d Matches", digit), CallbackData: callback + ":match"},
+			{Text: fmt.Sprintf("≠ %d Differs", digit), CallbackData: callback + ":differ"},
+		}}
+
+		// No digit is over 9 or under 0
+		var row []Button
+		if digit < 9 {
+			row = append(row, Button{Text: fmt.Sprintf("> %d Over", digit), CallbackData: callback + ":over"})
+		}
+		if digit > 0 {
+			row = append(row, Button{Text: fmt.Sprintf("< %d Under", digit), CallbackData: callback + ":under"})
+		}
+		buttons = append(buttons, row)
+
+		return NewResponse(msg).
+			Textf("🔢 %s on the last digit of %s after %s, predicting %d. Choose the contract:",
+				f.Money(stake, b.accountCurrency(ctx, msg.Username)), symbol, duration, digit).
+			Keyboard(buttons).
+			Build(), nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &TradeRequest{
+		Symbol:       symbol,
+		Amount:       stake,
+		ContractType: digitContracts[kind],
+		Duration:     duration,
+		Barrier:      strconv.Itoa(digit),
+	}
+
+	if stop, err := b.checkTrade(ctx, msg, client, req); err != nil || stop != nil {
+		return stop, err
+	}
+
+	if b.cfg.TradeConfirmTimeout > 0 {
+		return b.confirmTradePrompt(ctx, msg, client, req, nil)
+	}
+
+	return b.executeTrade(ctx, msg, client, req, nil)
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Kinds of Deriv API errors with dedicated handling
+var (
+	ErrInvalidToken        = errors.New("invalid token")
+	ErrMarketClosed        = errors.New("market is closed")
+	ErrContractValidation  = errors.New("contract validation failed")
+	ErrRateLimit           = errors.New("rate limit exceeded")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrInvalidSymbol       = errors.New("invalid symbol")
+)
+
+// DerivError is an error reported by Deriv API
+type DerivError struct {
+	Kind    error  // One of the Err* kinds above, nil for unclassified errors
+	Code    string // Deriv error code, e.g. MarketIsClosed
+	Message string // Message from Deriv
+}
+
+// Error implements error interface
+func (e *DerivError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap allows matching the error kind with errors.Is
+func (e *DerivError) Unwrap() error {
+	return e.Kind
+}
+
+// errorMessageKeys maps error kinds to user-facing messages in the catalog
+var errorMessageKeys = map[error]string{
+	ErrInvalidToken:        "error.invalid_token",
+	ErrMarketClosed:        "error.market_closed",
+	ErrContractValidation:  "error.contract_validation",
+	ErrRateLimit:           "error.rate_limit",
+	ErrInsufficientBalance: "error.insufficient_balance",
+	ErrInvalidSymbol:       "error.invalid_symbol",
+}
+
+// friendlyErrors replies with a human-readable explanation and next steps for known Deriv errors
+func (b *Bot) friendlyErrors(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		resp, err := next(ctx, msg)
+		if err == nil {
+			return resp, nil
+		}
+
+		if errors.Is(err, errDuplicateTrade) {
+			log.Printf("Ignored repeated trade button %q of %s", msg.CallbackData, msg.Username)
+			return NewResponse(msg).Text("ℹ️ This trade was already placed, the button works only once.").Build(), nil
+		}
+
+		var derivErr *DerivError
+		if !errors.As(err, &derivErr) || derivErr.Kind == nil {
+			return resp, err
+		}
+
+		log.Printf("Command %q from %s failed: %v", commandName(msg), msg.Username, err)
+
+		return NewResponse(msg).Text(translate(msg.LanguageCode, errorMessageKeys[derivErr.Kind], derivErr.Message)).Build(), nil
+	}
+}
@@ -0,0 +1,241 @@
+package core
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// EventType identifies events published on the bot's event bus
+type EventType string
+
+const (
+	EventTick         EventType = "tick"          // New price of a symbol
+	EventCandleClose  EventType = "candle_close"  // Candle of a symbol and granularity completed
+	EventTradeOpened  EventType = "trade_opened"  // Trade placed through the bot was journaled
+	EventTradeSettled EventType = "trade_settled" // Journaled trade got its outcome
+)
+
+// feedRetryDelay is the pause before a tick subscription that ended is opened again
+const feedRetryDelay = 5 * time.Second
+
+// Event is a normalized event of market data or trading
+type Event struct {
+	Type        EventType
+	Symbol      string
+	Time        time.Time
+	Price       HistoricalDataPoint // Tick price, or OHLC of the closed candle
+	Granularity int                 // Candle size in seconds of candle close events
+	Trade       *TradeRecord        // Trade of trade events
+}
+
+// EventFilter selects events delivered to a subscriber, empty fields match any value.
+// Tick and candle subscribers must name a symbol, it's what opens the market data feed.
+type EventFilter struct {
+	Type        EventType
+	Symbol      string
+	Granularity int
+}
+
+// matches reports whether the event passes the filter
+func (f EventFilter) matches(event Event) bool {
+	return (f.Type == "" || f.Type == event.Type) &&
+		(f.Symbol == "" || f.Symbol == event.Symbol) &&
+		(f.Granularity == 0 || f.Granularity == event.Granularity)
+}
+
+// EventHandler receives events, it's called synchronously by the publisher and must not block
+type EventHandler func(event Event)
+
+// eventSubscription is a registered handler with its filter
+type eventSubscription struct {
+	filter  EventFilter
+	handler EventHandler
+}
+
+// eventBus delivers events to subscribers. Market data of a symbol comes from a single tick subscription
+// shared by all its subscribers, opened with the first one and closed with the last.
+type eventBus struct {
+	mu     sync.Mutex
+	watch  func(ctx context.Context, symbol string) (<-chan HistoricalDataPoint, error)
+	nextID int
+	subs   map[int]eventSubscription
+	feeds  map[string]*marketFeed
+}
+
+// marketFeed turns ticks of a symbol into tick and candle close events
+type marketFeed struct {
+	cancel  context.CancelFunc
+	users   int                    // Subscriptions relying on the feed
+	candles map[int]*candleBuilder // Candles being built by granularity
+}
+
+// candleBuilder accumulates ticks of the current candle
+type candleBuilder struct {
+	start  int64 // Open time of the candle, 0 before the first tick
+	candle HistoricalDataPoint
+}
+
+// newEventBus creates a bus opening tick subscriptions with watch
+func newEventBus(watch func(ctx context.Context, symbol string) (<-chan HistoricalDataPoint, error)) *eventBus {
+	return &eventBus{
+		watch: watch,
+		subs:  make(map[int]eventSubscription),
+		feeds: make(map[string]*marketFeed),
+	}
+}
+
+// Subscribe registers the handler for events matching the filter and returns the function removing it
+func (bus *eventBus) Subscribe(filter EventFilter, handler EventHandler) func() {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	id := bus.nextID
+	bus.nextID++
+	bus.subs[id] = eventSubscription{filter: filter, handler: handler}
+
+	marketData := filter.Symbol != "" && (filter.Type == EventTick || filter.Type == EventCandleClose)
+	if marketData {
+		bus.acquireFeed(filter)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			bus.mu.Lock()
+			defer bus.mu.Unlock()
+
+			delete(bus.subs, id)
+			if marketData {
+				bus.releaseFeed(filter.Symbol)
+			}
+		})
+	}
+}
+
+// Publish delivers the event to matching subscribers
+func (bus *eventBus) Publish(event Event) {
+	bus.mu.Lock()
+	handlers := make([]EventHandler, 0, len(bus.subs))
+	for _, sub := range bus.subs {
+		if sub.filter.matches(event) {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	bus.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// Close stops all market data feeds
+func (bus *eventBus) Close() {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	for symbol, feed := range bus.feeds {
+		feed.cancel()
+		delete(bus.feeds, symbol)
+	}
+}
+
+// acquireFeed starts the feed of the symbol if needed, it must be called with the lock held
+func (bus *eventBus) acquireFeed(filter EventFilter) {
+	feed, ok := bus.feeds[filter.Symbol]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		feed = &marketFeed{cancel: cancel, candles: make(map[int]*candleBuilder)}
+		bus.feeds[filter.Symbol] = feed
+		go bus.runFeed(ctx, filter.Symbol, feed)
+	}
+
+	feed.users++
+	if filter.Type == EventCandleClose && filter.Granularity > 0 && feed.candles[filter.Granularity] == nil {
+		feed.candles[filter.Granularity] = &candleBuilder{}
+	}
+}
+
+// releaseFeed stops the feed of the symbol when nobody uses it anymore, it must be called with the lock held
+func (bus *eventBus) releaseFeed(symbol string) {
+	feed, ok := bus.feeds[symbol]
+	if !ok {
+		return
+	}
+
+	feed.users--
+	if feed.users <= 0 {
+		feed.cancel()
+		delete(bus.feeds, symbol)
+	}
+}
+
+// runFeed publishes events for ticks of the symbol, reopening the subscription when it ends
+func (bus *eventBus) runFeed(ctx context.Context, symbol string, feed *marketFeed) {
+	for ctx.Err() == nil {
+		ticks, err := bus.watch(ctx, symbol)
+		if err != nil {
+			log.Printf("Failed to watch ticks of %s: %v", symbol, err)
+		} else {
+			for tick := range ticks {
+				bus.Publish(Event{Type: EventTick, Symbol: symbol, Time: time.Unix(tick.Timestamp, 0), Price: tick})
+				for _, event := range bus.closeCandles(symbol, feed, tick) {
+					bus.Publish(event)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(feedRetryDelay):
+		}
+	}
+}
+
+// closeCandles adds the tick to candles of the feed and returns events of candles it completed
+func (bus *eventBus) closeCandles(symbol string, feed *marketFeed, tick HistoricalDataPoint) []Event {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	var events []Event
+	for granularity, builder := range feed.candles {
+		start := tick.Timestamp - tick.Timestamp%int64(granularity)
+
+		if builder.start != 0 && start > builder.start {
+			events = append(events, Event{
+				Type:        EventCandleClose,
+				Symbol:      symbol,
+				Time:        time.Unix(builder.start+int64(granularity), 0),
+				Price:       builder.candle,
+				Granularity: granularity,
+			})
+		}
+
+		if start != builder.start {
+			builder.start = start
+			builder.candle = HistoricalDataPoint{Timestamp: start, Open: tick.Price, High: tick.Price, Low: tick.Price}
+		}
+
+		builder.candle.High = max(builder.candle.High, tick.Price)
+		builder.candle.Low = min(builder.candle.Low, tick.Price)
+		builder.candle.Close = tick.Price
+		builder.candle.Price = tick.Price
+	}
+
+	return events
+}
+
+// observeTradeMetrics records execution quality of trades published on the bus
+func (b *Bot) observeTradeMetrics(event Event) {
+	switch event.Type {
+	case EventTradeOpened:
+		if event.Trade.LatencyMs > 0 {
+			b.metrics.Observe(metricTradeLatency, float64(event.Trade.LatencyMs))
+		}
+	case EventTradeSettled:
+		if slippage, ok := event.Trade.Slippage(); ok {
+			b.metrics.Observe(metricTradeSlippage, slippage)
+		}
+	}
+}
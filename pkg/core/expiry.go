@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ExpiryAlertConfig holds configuration of notifications sent shortly before contracts expire
+type ExpiryAlertConfig struct {
+	Lead        time.Duration `mapstructure:"lead"`         // How long before expiry time-based contracts are reported, tick contracts are reported one tick before
+	MaxDuration time.Duration `mapstructure:"max_duration"` // Only contracts not longer than this are watched
+}
+
+// watchExpiry notifies the user shortly before the contract expires, if the user enabled expiry alerts
+func (b *Bot) watchExpiry(ctx context.Context, msg *Message, client DerivClient, symbol string, result *TradeResult) {
+	settings, err := b.getSettings(ctx, msg.Username)
+	if err != nil {
+		log.Printf("Failed to check expiry alerts of %s: %v", msg.Username, err)
+		return
+	}
+
+	if !settings.ExpiryAlerts {
+		return
+	}
+
+	chatID := msg.ChatID
+	username := msg.Username
+	lead := b.cfg.ExpiryAlerts.Lead
+	f := b.formatter(ctx, msg)
+
+	err = b.background.Go(func(ctx context.Context, notifier Notifier) {
+		ctx, cancel := context.WithTimeout(ctx, b.cfg.ExpiryAlerts.MaxDuration)
+		defer cancel()
+
+		updates, err := client.WatchContract(ctx, result.ContractID)
+		if err != nil {
+			log.Printf("Failed to watch contract %d: %v", result.ContractID, err)
+			return
+		}
+
+		for info := range updates {
+			if info.IsSold || info.Status != ContractStatusOpen {
+				return
+			}
+
+			// Long contracts aren't worth a countdown
+			if info.TickCount == 0 && !info.ExpiryTime.IsZero() && info.ExpiryTime.Sub(result.PurchaseTime) > b.cfg.ExpiryAlerts.MaxDuration {
+				return
+			}
+
+			remaining, ok := expiringSoon(info, lead)
+			if !ok {
+				continue
+			}
+
+			resp := NewNotification(chatID).Text(formatExpiryAlert(f, symbol, info, remaining)).Build()
+			if _, err := b.notify(ctx, notifier, username, NotifyAlerts, resp); err != nil {
+				log.Printf("Failed to send expiry alert for contract %d: %v", result.ContractID, err)
+			}
+
+			return
+		}
+	})
+	if err != nil {
+		log.Printf("Failed to watch contract %d: %v", result.ContractID, err)
+	}
+}
+
+// expiringSoon reports whether the contract is about to expire and describes the remaining time
+func expiringSoon(info *ContractInfo, lead time.Duration) (string, bool) {
+	if info.TickCount > 0 {
+		left := info.TickCount - info.TicksPassed
+		return "1 tick", left == 1
+	}
+
+	if info.ExpiryTime.IsZero() {
+		return "", false
+	}
+
+	left := time.Until(info.ExpiryTime)
+
+	return fmt.Sprintf("%ds", int(left.Round(time.Second).Seconds())), left > 0 && left <= lead
+}
+
+// formatExpiryAlert describes the contract state right before expiry
+func formatExpiryAlert(f Formatter, symbol string, info *ContractInfo, remaining string) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "⏳ Contract %d on %s expires in %s\n", info.ContractID, symbol, remaining)
+
+	move := info.CurrentSpot - info.EntrySpot
+	arrow := "▲"
+	if move < 0 {
+		arrow = "▼"
+	}
+	fmt.Fprintf(&sb, "Spot %s vs entry %s (%s %s)\n", f.Number(info.CurrentSpot, 2), f.Number(info.EntrySpot, 2), arrow, f.Signed(move, 2))
+
+	if info.IsValidToSell {
+		sb.WriteString("You can still sell it back early.")
+	} else {
+		sb.WriteString("This contract can't be sold back early.")
+	}
+
+	return sb.String()
+}
+
+// handleExpiryAlerts toggles notifications before contract expiry, "/expiryalerts on|off"
+func (b *Bot) handleExpiryAlerts(ctx context.Context, msg *Message) (*Response, error) {
+	settings, err := b.getSettings(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rb := NewResponse(msg)
+
+	if len(msg.Args) == 0 {
+		state := "off"
+		if settings.ExpiryAlerts {
+			state = "on"
+		}
+		return rb.Textf("⏳ Expiry alerts are %s. Use /expiryalerts on|off to change.", state).Build(), nil
+	}
+
+	switch strings.ToLower(msg.Args[0]) {
+	case "on":
+		settings.ExpiryAlerts = true
+		rb.Text("✅ You'll be notified shortly before your short-duration contracts expire")
+	case "off":
+		settings.ExpiryAlerts = false
+		rb.Text("✅ Expiry alerts disabled")
+	default:
+		return rb.Text("❌ Usage: /expiryalerts on|off").Build(), nil
+	}
+
+	if err := b.saveSettings(ctx, msg.Username, settings); err != nil {
+		return nil, err
+	}
+
+	return rb.Build(), nil
+}
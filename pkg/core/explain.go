@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// handleExplain asks the LLM to narrate why a settled contract won or lost, "/explain <contract_id>"
+func (b *Bot) handleExplain(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) < 1 {
+		return NewResponse(msg).Text("❌ Please provide a contract ID. Example: /explain 123456789\nContract IDs are shown in trade confirmations.").Build(), nil
+	}
+
+	contractID, err := strconv.ParseInt(msg.Args[0], 10, 64)
+	if err != nil || contractID <= 0 {
+		return NewResponse(msg).Text("❌ Invalid contract ID, it should be a number").Build(), nil
+	}
+
+	// Deriv only returns contracts of the account the client is authorized with
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := client.GetContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	if !info.IsSold {
+		return NewResponse(msg).Textf("⏳ Contract %d is still open, it can be explained once it settles.", contractID).Build(), nil
+	}
+
+	candles, err := b.derivClient.GetHistoricalData(ctx, explainCandlesRequest(info))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get historical data: %w", err)
+	}
+
+	explanation, err := b.llmClient.ProcessText(ctx, explainPrompt(info, candles))
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain contract: %w", err)
+	}
+
+	f := b.formatter(ctx, msg)
+	return NewResponse(msg).Textf("🧠 Contract %d on %s: %s %s\n\n%s", contractID, info.Symbol, f.Outcome(info.Status, info.Profit), f.SignedMoney(info.Profit, info.Currency), explanation).Build(), nil
+}
+
+// explainCandlesRequest picks candles covering the market around the contract
+func explainCandlesRequest(info *ContractInfo) HistoricalDataRequest {
+	// Candles are requested relative to now, so older contracts get coarser candles
+	if time.Since(info.EntryTime) < 50*time.Minute {
+		return HistoricalDataRequest{
+			Symbol:   info.Symbol,
+			Interval: IntervalHour,
+			Style:    StyleCandles,
+			Count:    60,
+		}
+	}
+
+	return dayCandlesRequest(info.Symbol)
+}
+
+// explainPrompt describes the contract and the market around it for the LLM
+func explainPrompt(info *ContractInfo, candles []HistoricalDataPoint) string {
+	var sb strings.Builder
+
+	sb.WriteString("Explain to a less-experienced trader why this Deriv contract won or lost. " +
+		"Compare the price movement with the barrier or entry spot and comment on the volatility in the candles around the contract. " +
+		"Use plain language, at most six sentences, and don't give financial advice.\n\n")
+
+	fmt.Fprintf(&sb, "Contract: %s\n", info.Longcode)
+	fmt.Fprintf(&sb, "Symbol: %s, type: %s\n", info.Symbol, info.ContractType)
+	fmt.Fprintf(&sb, "Stake: %.2f %s, payout: %.2f, profit: %+.2f, status: %s\n", info.BuyPrice, info.Currency, info.Payout, info.Profit, info.Status)
+	fmt.Fprintf(&sb, "Entry spot: %v at %s\n", info.EntrySpot, info.EntryTime.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Exit spot: %v at %s\n", info.ExitSpot, info.ExitTime.UTC().Format(time.RFC3339))
+	if info.Barrier != "" {
+		fmt.Fprintf(&sb, "Barrier: %s\n", info.Barrier)
+	}
+
+	if len(candles) > 0 {
+		sb.WriteString("\nCandles (time UTC, open, high, low, close):\n")
+		for _, c := range candles {
+			fmt.Fprintf(&sb, "%s %v %v %v %v\n", time.Unix(c.Timestamp, 0).UTC().Format(time.TimeOnly), c.Open, c.High, c.Low, c.Close)
+		}
+	}
+
+	return sb.String()
+}
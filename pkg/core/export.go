@@ -0,0 +1,222 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxExportRows is the most ticks or candles Deriv returns in one history request
+const maxExportRows = 5000
+
+// exportGranularities are candle sizes in seconds accepted by /data, "ticks" exports raw ticks
+var exportGranularities = map[string]int{
+	"1m":  60,
+	"2m":  120,
+	"3m":  180,
+	"5m":  300,
+	"10m": 600,
+	"15m": 900,
+	"30m": 1800,
+	"1h":  3600,
+	"2h":  7200,
+	"4h":  14400,
+	"8h":  28800,
+	"1d":  86400,
+}
+
+// exportRequest builds the history request of /data, it reports false for unknown intervals
+func exportRequest(symbol, interval string, count int) (HistoricalDataRequest, bool) {
+	if interval == "ticks" {
+		return HistoricalDataRequest{
+			Symbol:   symbol,
+			Interval: IntervalDay,
+			Style:    StyleTicks,
+			Count:    count,
+		}, true
+	}
+
+	granularity, ok := exportGranularities[interval]
+	if !ok {
+		return HistoricalDataRequest{}, false
+	}
+
+	// The history interval bounds how far back candles go, so the shortest one covering all of them is used
+	span := time.Duration(count*granularity) * time.Second
+	historyInterval := IntervalMonth
+	switch {
+	case span <= time.Hour:
+		historyInterval = IntervalHour
+	case span <= 24*time.Hour:
+		historyInterval = IntervalDay
+	case span <= 7*24*time.Hour:
+		historyInterval = IntervalWeek
+	}
+
+	return HistoricalDataRequest{
+		Symbol:      symbol,
+		Interval:    historyInterval,
+		Style:       StyleCandles,
+		Count:       count,
+		Granularity: granularity,
+	}, true
+}
+
+// exportCSV writes ticks or candles as CSV with a header row
+func exportCSV(data []HistoricalDataPoint, style DataStyle) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	price := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	header := []string{"epoch", "time", "open", "high", "low", "close"}
+	if style == StyleTicks {
+		header = []string{"epoch", "time", "price"}
+	}
+	if err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, point := range data {
+		row := []string{
+			strconv.FormatInt(point.Timestamp, 10),
+			time.Unix(point.Timestamp, 0).UTC().Format(time.RFC3339),
+		}
+
+		if style == StyleTicks {
+			row = append(row, price(point.Price))
+		} else {
+			row = append(row, price(point.Open), price(point.High), price(point.Low), price(point.Close))
+		}
+
+		if err := w.Write(row); err != nil {
+			return nil, fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// replayRecording exports recorded ticks of a symbol and day as a CSV document
+func (b *Bot) replayRecording(msg *Message) (*Response, error) {
+	if b.recorder == nil {
+		return NewResponse(msg).Text("❌ Tick recording is not enabled on this bot").Build(), nil
+	}
+
+	if len(msg.Args) != 3 {
+		return NewResponse(msg).Textf("❌ Usage: /data replay <symbol> <YYYY-MM-DD>. Recorded symbols: %s", strings.Join(b.recorder.Symbols(), ", ")).Build(), nil
+	}
+
+	symbol := strings.ToUpper(msg.Args[1])
+	if !b.recordsSymbol(symbol) {
+		return NewResponse(msg).Textf("❌ %s isn't recorded. Recorded symbols: %s", symbol, strings.Join(b.recorder.Symbols(), ", ")).Build(), nil
+	}
+
+	day, err := time.Parse(time.DateOnly, msg.Args[2])
+	if err != nil {
+		return NewResponse(msg).Text("❌ Please give the day as YYYY-MM-DD, days are in UTC").Build(), nil
+	}
+
+	ticks, err := b.recorder.Replay(symbol, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay recording: %w", err)
+	}
+
+	if len(ticks) == 0 {
+		return NewResponse(msg).Textf("❌ No ticks of %s were recorded on %s", symbol, day.Format(time.DateOnly)).Build(), nil
+	}
+
+	content, err := exportCSV(ticks, StyleTicks)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg).
+		Textf("📼 %s recorded on %s: %d ticks", symbol, day.Format(time.DateOnly), len(ticks)).
+		Document(&Document{
+			Name: fmt.Sprintf("%s_ticks_%s.csv", symbol, day.Format("20060102")),
+			Data: content,
+		}).
+		Build(), nil
+}
+
+// handleData exports raw ticks or candles as a CSV document, "/data <symbol> <interval> <count> [csv]".
+// "/data replay <symbol> <YYYY-MM-DD>" exports ticks the bot recorded on the day.
+func (b *Bot) handleData(ctx context.Context, msg *Message) (*Response, error) {
+	rb := NewResponse(msg)
+
+	if len(msg.Args) > 0 && strings.EqualFold(msg.Args[0], "replay") {
+		return b.replayRecording(msg)
+	}
+
+	usage := fmt.Sprintf("❌ Usage: /data <symbol> <interval> <count> [csv], e.g. /data R_50 1m 500 csv. "+
+		"Intervals: ticks, 1m, 2m, 3m, 5m, 10m, 15m, 30m, 1h, 2h, 4h, 8h, 1d; up to %d rows.", maxExportRows)
+	if len(msg.Args) < 3 || len(msg.Args) > 4 {
+		return rb.Text(usage).Build(), nil
+	}
+
+	if len(msg.Args) == 4 && !strings.EqualFold(msg.Args[3], "csv") {
+		return rb.Text("❌ Only csv exports are supported").Build(), nil
+	}
+
+	count, err := strconv.Atoi(msg.Args[2])
+	if err != nil || count <= 0 || count > maxExportRows {
+		return rb.Text(usage).Build(), nil
+	}
+
+	interval := strings.ToLower(msg.Args[1])
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "data:" + strings.Join(append([]string{symbol}, msg.Args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	req, ok := exportRequest(symbol, interval, count)
+	if !ok {
+		return rb.Text(usage).Build(), nil
+	}
+
+	ctx, progress := b.startProgress(ctx, msg, fmt.Sprintf("Exporting %d %s rows of %s", count, interval, symbol))
+
+	data, err := b.derivClient.GetHistoricalData(ctx, req)
+	if err != nil {
+		return progress.finish(b, msg, nil, fmt.Errorf("failed to get historical data: %w", err))
+	}
+	progress.Step(1, 2)
+
+	if len(data) == 0 {
+		rb.Textf("❌ No %s data available for %s", interval, symbol)
+		return progress.finish(b, msg, rb.Build(), nil)
+	}
+
+	content, err := exportCSV(data, req.Style)
+	if err != nil {
+		return progress.finish(b, msg, nil, err)
+	}
+
+	rb.Document(&Document{
+		Name: fmt.Sprintf("%s_%s_%s.csv", symbol, interval, time.Now().UTC().Format("20060102_150405")),
+		Data: content,
+	})
+
+	text := fmt.Sprintf("📦 %s %s: %d rows", symbol, interval, len(data))
+	if len(data) < count {
+		text += fmt.Sprintf(" of %d requested, older data isn't available in one request", count)
+	}
+	rb.Text(text)
+
+	return progress.finish(b, msg, rb.Build(), nil)
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// exposureGroup sums open contracts sharing a symbol or contract type
+type exposureGroup struct {
+	Contracts int
+	Stake     float64
+	Payout    float64
+}
+
+// add counts the position in the group
+func (g *exposureGroup) add(p PositionInfo) {
+	g.Contracts++
+	g.Stake += p.Stake
+	g.Payout += p.Payout
+}
+
+// handleExposure summarizes the risk of the user's open contracts, taken from the live portfolio of the account
+func (b *Bot) handleExposure(ctx context.Context, msg *Message) (*Response, error) {
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := client.GetOpenPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open positions: %w", err)
+	}
+
+	if len(positions) == 0 {
+		return NewResponse(msg).Text("🛡 No open contracts, nothing is at risk.").Build(), nil
+	}
+
+	var total exposureGroup
+	var openEnded int
+	bySymbol := make(map[string]*exposureGroup)
+	byType := make(map[string]*exposureGroup)
+
+	for _, p := range positions {
+		total.add(p)
+
+		if bySymbol[p.Symbol] == nil {
+			bySymbol[p.Symbol] = &exposureGroup{}
+		}
+		bySymbol[p.Symbol].add(p)
+
+		if byType[p.ContractType] == nil {
+			byType[p.ContractType] = &exposureGroup{}
+		}
+		byType[p.ContractType].add(p)
+
+		// Multipliers have no fixed payout
+		if p.Payout == 0 {
+			openEnded++
+		}
+	}
+
+	f := b.formatter(ctx, msg)
+	currency := positions[0].Currency
+	if currency == "" {
+		currency = client.Currency()
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🛡 Exposure\n\n")
+	fmt.Fprintf(&sb, "Open contracts: %d\n", total.Contracts)
+	fmt.Fprintf(&sb, "Stake at risk: %s\n", f.Money(total.Stake, currency))
+	// A contract can't lose more than its stake
+	fmt.Fprintf(&sb, "Max possible loss: %s\n", f.Money(total.Stake, currency))
+	fmt.Fprintf(&sb, "Potential payout: %s\n", f.Money(total.Payout, currency))
+	if openEnded > 0 {
+		fmt.Fprintf(&sb, "(%d contract(s) without a fixed payout not included)\n", openEnded)
+	}
+
+	sb.WriteString("\nBy symbol:\n")
+	writeExposureGroups(&sb, f, currency, bySymbol)
+
+	sb.WriteString("\nBy contract type:\n")
+	writeExposureGroups(&sb, f, currency, byType)
+
+	return NewResponse(msg).Text(strings.TrimSuffix(sb.String(), "\n")).Build(), nil
+}
+
+// writeExposureGroups lists groups by their stake, the largest first
+func writeExposureGroups(sb *strings.Builder, f Formatter, currency string, groups map[string]*exposureGroup) {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if groups[names[i]].Stake != groups[names[j]].Stake {
+			return groups[names[i]].Stake > groups[names[j]].Stake
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		g := groups[name]
+		fmt.Fprintf(sb, "• %s: %d, stake %s, payout %s\n", name, g.Contracts, f.Money(g.Stake, currency), f.Money(g.Payout, currency))
+	}
+}
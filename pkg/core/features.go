@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Feature flags gating risky or experimental functionality
+const (
+	FeatureAutoTrading       = "auto_trading"
+	FeatureLLMTradeProposals = "llm_trade_proposals" // The LLM may propose trades in answers to free-form questions
+	FeatureWebhookIntake     = "webhook_intake"
+)
+
+// knownFeatures lists all supported feature flags, all of them are disabled by default
+var knownFeatures = []string{
+	FeatureAutoTrading,
+	FeatureLLMTradeProposals,
+	FeatureWebhookIntake,
+}
+
+// featureCommands are commands refused while the feature flag they belong to is disabled
+var featureCommands = map[string]string{
+	"order":    FeatureAutoTrading,
+	"copy":     FeatureAutoTrading,
+	"strategy": FeatureAutoTrading,
+	"webhook":  FeatureWebhookIntake,
+}
+
+// FeatureFlags holds the runtime state of feature flags
+type FeatureFlags struct {
+	mu    sync.RWMutex
+	flags map[string]bool
+}
+
+// NewFeatureFlags creates feature flags initialized from configuration
+func NewFeatureFlags(initial map[string]bool) (*FeatureFlags, error) {
+	flags := make(map[string]bool, len(knownFeatures))
+	for _, name := range knownFeatures {
+		flags[name] = false
+	}
+
+	for name, enabled := range initial {
+		if _, ok := flags[name]; !ok {
+			return nil, fmt.Errorf("unknown feature flag: %s", name)
+		}
+		flags[name] = enabled
+	}
+
+	return &FeatureFlags{flags: flags}, nil
+}
+
+// Enabled reports whether the feature is enabled
+func (f *FeatureFlags) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.flags[name]
+}
+
+// Set changes the state of a feature at runtime
+func (f *FeatureFlags) Set(name string, enabled bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.flags[name]; !ok {
+		return fmt.Errorf("unknown feature flag: %s", name)
+	}
+
+	f.flags[name] = enabled
+
+	return nil
+}
+
+// List returns feature names sorted alphabetically with their state
+func (f *FeatureFlags) List() ([]string, map[string]bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	names := make([]string, 0, len(f.flags))
+	state := make(map[string]bool, len(f.flags))
+	for name, enabled := range f.flags {
+		names = append(names, name)
+		state[name] = enabled
+	}
+	sort.Strings(names)
+
+	return names, state
+}
+
+// featureGate rejects commands gated by a disabled feature flag
+func (b *Bot) featureGate(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		feature, gated := featureCommands[msg.Command]
+		if gated && !b.features.Enabled(feature) {
+			return NewResponse(msg).Textf("🚧 This feature (%s) is currently disabled.", feature).Build(), nil
+		}
+
+		return next(ctx, msg)
+	}
+}
+
+// handleFeature lists feature flags or toggles one of them, admin only
+func (b *Bot) handleFeature(ctx context.Context, msg *Message) (*Response, error) {
+	if !b.isAdmin(msg.Username) {
+		return NewResponse(msg).Text("⚠️ This command is available to admins only.").Build(), nil
+	}
+
+	if len(msg.Args) == 0 {
+		names, state := b.features.List()
+
+		var text strings.Builder
+		text.WriteString("🚩 Feature flags:\n")
+		for _, name := range names {
+			status := "off"
+			if state[name] {
+				status = "on"
+			}
+			text.WriteString(fmt.Sprintf("\n%s: %s", name, status))
+		}
+
+		return NewResponse(msg).Text(text.String()).Build(), nil
+	}
+
+	if len(msg.Args) < 2 || (msg.Args[1] != "on" && msg.Args[1] != "off") {
+		return NewResponse(msg).Text("❌ Usage: /feature <name> on|off").Build(), nil
+	}
+
+	name, enabled := msg.Args[0], msg.Args[1] == "on"
+	if err := b.features.Set(name, enabled); err != nil {
+		return NewResponse(msg).Textf("❌ %v", err).Build(), nil
+	}
+
+	return NewResponse(msg).Textf("✅ Feature %s is now %s", name, msg.Args[1]).Build(), nil
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFeatureGate(t *testing.T) {
+	tests := []struct {
+		command string
+		feature string
+	}{
+		{command: "order", feature: FeatureAutoTrading},
+		{command: "copy", feature: FeatureAutoTrading},
+		{command: "strategy", feature: FeatureAutoTrading},
+		{command: "webhook", feature: FeatureWebhookIntake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			flags, err := NewFeatureFlags(map[string]bool{tt.feature: false})
+			if err != nil {
+				t.Fatalf("NewFeatureFlags() error = %v", err)
+			}
+
+			b := &Bot{features: flags}
+
+			var called bool
+			handler := b.featureGate(func(context.Context, *Message) (*Response, error) {
+				called = true
+				return NewResponse(&Message{}).Text("done").Build(), nil
+			})
+
+			msg := &Message{Command: tt.command, ChatID: 1, Username: "alice"}
+
+			resp, err := handler(context.Background(), msg)
+			if err != nil {
+				t.Fatalf("handler() error = %v", err)
+			}
+			if called {
+				t.Fatalf("/%s ran with %s disabled", tt.command, tt.feature)
+			}
+			if !strings.Contains(resp.Text, tt.feature) {
+				t.Errorf("response = %q, want it to name %s", resp.Text, tt.feature)
+			}
+
+			if err := flags.Set(tt.feature, true); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			if _, err := handler(context.Background(), msg); err != nil {
+				t.Fatalf("handler() error = %v", err)
+			}
+			if !called {
+				t.Errorf("/%s was refused with %s enabled", tt.command, tt.feature)
+			}
+		})
+	}
+}
+
+func TestFeatureGateUngatedCommand(t *testing.T) {
+	flags, err := NewFeatureFlags(nil)
+	if err != nil {
+		t.Fatalf("NewFeatureFlags() error = %v", err)
+	}
+
+	b := &Bot{features: flags}
+
+	var called bool
+	handler := b.featureGate(func(context.Context, *Message) (*Response, error) {
+		called = true
+		return nil, nil
+	})
+
+	if _, err := handler(context.Background(), &Message{Command: "price"}); err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if !called {
+		t.Error("/price was refused, it isn't gated by any feature")
+	}
+}
+
+func TestFeatureCommandsAreKnown(t *testing.T) {
+	for command, feature := range featureCommands {
+		flags, _ := NewFeatureFlags(nil)
+		if err := flags.Set(feature, true); err != nil {
+			t.Errorf("/%s is gated by unknown feature %s", command, feature)
+		}
+	}
+}
+
+// proposingLLM answers every question with a trade proposal, whatever it was told
+type proposingLLM struct{}
+
+func (proposingLLM) ProcessText(context.Context, string) (string, error) {
+	return "", nil
+}
+
+func (proposingLLM) ProcessWithFunctions(context.Context, string, MarketDataProvider, []LLMFunction) (string, error) {
+	return "R_50 trends up over the last day.\nBuy CALL on R_50 for 5 ticks with a 10 USD stake.\nVolatility is low.", nil
+}
+
+func TestAnswerTextStripsTradeProposals(t *testing.T) {
+	flags, err := NewFeatureFlags(nil)
+	if err != nil {
+		t.Fatalf("NewFeatureFlags() error = %v", err)
+	}
+
+	b := &Bot{features: flags, llmClient: proposingLLM{}}
+	msg := &Message{Command: llmQueryCommand, ChatID: 1, Username: "alice"}
+
+	resp, err := b.answerText(context.Background(), msg, "what about R_50?")
+	if err != nil {
+		t.Fatalf("answerText() error = %v", err)
+	}
+	if strings.Contains(resp.Text, "Buy CALL") {
+		t.Errorf("answer = %q, want the trade proposal dropped with %s disabled", resp.Text, FeatureLLMTradeProposals)
+	}
+	if !strings.Contains(resp.Text, "trends up") || !strings.Contains(resp.Text, "Volatility is low") {
+		t.Errorf("answer = %q, want the market analysis kept", resp.Text)
+	}
+
+	if err := flags.Set(FeatureLLMTradeProposals, true); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	resp, err = b.answerText(context.Background(), msg, "what about R_50?")
+	if err != nil {
+		t.Fatalf("answerText() error = %v", err)
+	}
+	if !strings.Contains(resp.Text, "Buy CALL") {
+		t.Errorf("answer = %q, want the trade proposal with %s enabled", resp.Text, FeatureLLMTradeProposals)
+	}
+}
@@ -0,0 +1,286 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// defaultCurrency is assumed for amounts when the account currency can't be determined
+const defaultCurrency = "USD"
+
+// currencyDecimals are decimal places of currencies that don't use two
+var currencyDecimals = map[string]int{
+	"JPY":  0,
+	"BTC":  8,
+	"ETH":  8,
+	"LTC":  8,
+	"BCH":  8,
+	"USDC": 2,
+	"UST":  2,
+}
+
+// currencySymbols are shown instead of currency codes
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"AUD": "A$",
+}
+
+// numberFormat holds separators and conventions of a locale
+type numberFormat struct {
+	group       string // Thousands separator
+	decimal     string
+	symbolFirst bool // Currency symbol goes before the amount
+}
+
+// localeFormats are number formats by language, the region is ignored
+var localeFormats = map[string]numberFormat{
+	"en": {group: ",", decimal: ".", symbolFirst: true},
+	"de": {group: ".", decimal: ","},
+	"es": {group: ".", decimal: ","},
+	"it": {group: ".", decimal: ","},
+	"pt": {group: ".", decimal: ","},
+	"nl": {group: ".", decimal: ","},
+	"id": {group: ".", decimal: ","},
+	"tr": {group: ".", decimal: ","},
+	"fr": {group: " ", decimal: ","},
+	"ru": {group: " ", decimal: ","},
+	"uk": {group: " ", decimal: ","},
+	"pl": {group: " ", decimal: ","},
+	"cs": {group: " ", decimal: ","},
+	"sv": {group: " ", decimal: ","},
+	"zh": {group: ",", decimal: ".", symbolFirst: true},
+	"ja": {group: ",", decimal: ".", symbolFirst: true},
+	"th": {group: ",", decimal: ".", symbolFirst: true},
+	"vi": {group: ".", decimal: ","},
+}
+
+// Formatter formats numbers and amounts following the conventions of a locale,
+// and icons of trades following the theme of the deployment
+type Formatter struct {
+	format  numberFormat
+	theme   *Theme           // Default icons when nil
+	display *displayCurrency // Amounts are only shown in their own currency when nil
+}
+
+// displayCurrency converts amounts a formatter shows into the display currency chosen by the user
+type displayCurrency struct {
+	code    string
+	convert func(amount float64, from string) (float64, error)
+}
+
+// NewFormatter creates a formatter for the IETF language tag, e.g. de or pt-br, falling back to English
+func NewFormatter(locale string) Formatter {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(locale, "_", "-")), "-")
+
+	format, ok := localeFormats[lang]
+	if !ok {
+		format = localeFormats["en"]
+	}
+
+	return Formatter{format: format}
+}
+
+// supportedLocale reports whether numbers have a dedicated format for the locale
+func supportedLocale(locale string) bool {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(locale, "_", "-")), "-")
+	_, ok := localeFormats[lang]
+	return ok
+}
+
+// formatter returns the formatter of the user sending the message
+func (b *Bot) formatter(ctx context.Context, msg *Message) Formatter {
+	return b.formatterFor(ctx, msg.Username, msg.LanguageCode)
+}
+
+// formatterFor returns the formatter for the locale chosen by the user, or for their Telegram language
+func (b *Bot) formatterFor(ctx context.Context, username, languageCode string) Formatter {
+	settings, err := b.getSettings(ctx, username)
+	if err != nil {
+		log.Printf("Failed to load settings of %s: %v", username, err)
+		return NewFormatter(languageCode).WithTheme(b.theme)
+	}
+
+	locale := languageCode
+	if settings.Locale != "" {
+		locale = settings.Locale
+	}
+
+	f := NewFormatter(locale).WithTheme(b.theme)
+	if code := settings.DisplayCurrency; code != "" {
+		f.display = &displayCurrency{
+			code: code,
+			convert: func(amount float64, from string) (float64, error) {
+				return b.convert(ctx, amount, from, code)
+			},
+		}
+	}
+
+	return f
+}
+
+// WithTheme returns the formatter using icons of the theme
+func (f Formatter) WithTheme(theme *Theme) Formatter {
+	f.theme = theme
+	return f
+}
+
+// Number formats the value with thousands separators and the given decimal places
+func (f Formatter) Number(value float64, decimals int) string {
+	text := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+
+	whole, fraction, _ := strings.Cut(text, ".")
+
+	var sb strings.Builder
+	if value < 0 && strings.Trim(text, "0.") != "" {
+		sb.WriteString("-")
+	}
+
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			sb.WriteString(f.format.group)
+		}
+		sb.WriteRune(digit)
+	}
+
+	if fraction != "" {
+		sb.WriteString(f.format.decimal)
+		sb.WriteString(fraction)
+	}
+
+	return sb.String()
+}
+
+// Signed formats the value like Number, always with a sign
+func (f Formatter) Signed(value float64, decimals int) string {
+	text := f.Number(value, decimals)
+	if strings.HasPrefix(text, "-") {
+		return text
+	}
+	return "+" + text
+}
+
+// Percent formats a signed percentage
+func (f Formatter) Percent(value float64, decimals int) string {
+	return f.Signed(value, decimals) + "%"
+}
+
+// withoutConversion returns the formatter showing amounts in their own currency only, e.g. for button labels
+func (f Formatter) withoutConversion() Formatter {
+	f.display = nil
+	return f
+}
+
+// Money formats the amount in the currency with its decimal places and symbol, e.g. $1,234.50 or 1.234,50 €.
+// With a display currency set the converted amount follows, e.g. $12.40 (~11,50 €).
+func (f Formatter) Money(amount float64, currency string) string {
+	text := f.money(f.Number(amount, moneyDecimals(currency)), currency)
+
+	if converted, ok := f.convert(amount, currency); ok {
+		return fmt.Sprintf("%s (~%s)", text, f.withoutConversion().Money(converted, f.display.code))
+	}
+	return text
+}
+
+// SignedMoney formats the amount like Money, always with a sign, e.g. +$12.50 or +$12.50 (~+11,50 €)
+func (f Formatter) SignedMoney(amount float64, currency string) string {
+	text := f.withoutConversion().signedMoney(amount, currency)
+
+	if converted, ok := f.convert(amount, currency); ok {
+		return fmt.Sprintf("%s (~%s)", text, f.withoutConversion().signedMoney(converted, f.display.code))
+	}
+	return text
+}
+
+// signedMoney formats the amount like Money with a sign
+func (f Formatter) signedMoney(amount float64, currency string) string {
+	text := f.Money(math.Abs(amount), currency)
+
+	// Amounts rounding to zero have no sign in Number
+	if strings.HasPrefix(f.Number(amount, moneyDecimals(currency)), "-") {
+		return "-" + text
+	}
+	return "+" + text
+}
+
+// convert converts the amount into the display currency, it reports false when there is none,
+// it's the currency of the amount or the conversion failed. Failures are logged.
+func (f Formatter) convert(amount float64, currency string) (float64, bool) {
+	if f.display == nil || strings.EqualFold(f.display.code, currency) {
+		return 0, false
+	}
+
+	converted, err := f.display.convert(amount, currency)
+	if err != nil {
+		log.Printf("Failed to convert %s to %s: %v", currency, f.display.code, err)
+		return 0, false
+	}
+
+	return converted, true
+}
+
+// money adds the currency symbol or code to the formatted number
+func (f Formatter) money(number, currency string) string {
+	currency = strings.ToUpper(currency)
+
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		return number + " " + currency
+	}
+
+	if f.format.symbolFirst {
+		if strings.HasPrefix(number, "-") {
+			return "-" + symbol + strings.TrimPrefix(number, "-")
+		}
+		return symbol + number
+	}
+
+	return number + " " + symbol
+}
+
+// moneyDecimals returns decimal places of the currency
+func moneyDecimals(currency string) int {
+	if decimals, ok := currencyDecimals[strings.ToUpper(currency)]; ok {
+		return decimals
+	}
+	return 2
+}
+
+// handleLocale shows or changes the locale used to format numbers, "/locale <code>" or "/locale auto"
+func (b *Bot) handleLocale(ctx context.Context, msg *Message) (*Response, error) {
+	settings, err := b.getSettings(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Args) == 0 {
+		locale := settings.Locale
+		if locale == "" {
+			locale = "auto, following your Telegram language"
+		}
+		return NewResponse(msg).Textf("🌐 Number format: %s\nExample: %s\nUse /locale <code>, e.g. /locale de, or /locale auto to change it.",
+			locale, b.formatter(ctx, msg).Money(1234.5, defaultCurrency)).Build(), nil
+	}
+
+	locale := strings.ToLower(msg.Args[0])
+	switch {
+	case locale == "auto":
+		settings.Locale = ""
+	case supportedLocale(locale):
+		settings.Locale = locale
+	default:
+		return NewResponse(msg).Text("❌ Unsupported locale. Example: /locale de").Build(), nil
+	}
+
+	if err := b.saveSettings(ctx, msg.Username, settings); err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg).Textf("✅ Amounts will look like %s", b.formatter(ctx, msg).Money(1234.5, defaultCurrency)).Build(), nil
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatterDisplayCurrency(t *testing.T) {
+	f := NewFormatter("en")
+	f.display = &displayCurrency{
+		code: "EUR",
+		convert: func(amount float64, from string) (float64, error) {
+			if from != "USD" {
+				return 0, errors.New("no rate")
+			}
+			return amount * 0.5, nil
+		},
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "money", got: f.Money(12.4, "USD"), want: "$12.40 (~€6.20)"},
+		{name: "signed profit", got: f.SignedMoney(3, "USD"), want: "+$3.00 (~+€1.50)"},
+		{name: "signed loss", got: f.SignedMoney(-3, "USD"), want: "-$3.00 (~-€1.50)"},
+		{name: "same currency", got: f.Money(5, "EUR"), want: "€5.00"},
+		{name: "failed conversion", got: f.Money(5, "GBP"), want: "£5.00"},
+		{name: "without conversion", got: f.withoutConversion().Money(12.4, "USD"), want: "$12.40"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
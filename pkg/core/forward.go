@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// startArgPrefix marks the start time of a forward-starting contract in /buy arguments, e.g. start=+10m
+const startArgPrefix = "start="
+
+// splitStart separates the start=+<duration> argument from the other /buy arguments
+func splitStart(args []string) (rest []string, start string) {
+	for _, arg := range args {
+		if strings.HasPrefix(strings.ToLower(arg), startArgPrefix) {
+			start = arg[len(startArgPrefix):]
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	return rest, start
+}
+
+// parseStart returns the start time of a forward-starting contract given relative to now, e.g. +10m
+func parseStart(value string, now time.Time) (time.Time, error) {
+	if !strings.HasPrefix(value, "+") {
+		return time.Time{}, fmt.Errorf("start time %q must be relative, e.g. +10m", value)
+	}
+
+	offset, err := ParseDuration(value[1:])
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	span, ok := offset.Span()
+	if !ok {
+		return time.Time{}, fmt.Errorf("start time %q can't be given in ticks", value)
+	}
+
+	return now.Add(span).Truncate(time.Second), nil
+}
+
+// validateForwardStart checks that the contract types can start at the given time on the symbol.
+// It returns a user-facing message when they can't, or an empty string when the start is valid.
+func (b *Bot) validateForwardStart(ctx context.Context, symbol string, start time.Time, duration Duration, contractTypes ...string) (string, error) {
+	if _, ok := duration.Span(); !ok {
+		return "❌ Forward-starting contracts need a duration in s, m, h or d, e.g. 5m", nil
+	}
+
+	limits, err := b.contractLimits(ctx, symbol)
+	if err != nil {
+		return "", err
+	}
+
+	for _, contractType := range contractTypes {
+		var limit *ContractLimits
+		for i := range limits {
+			if limits[i].ContractType == contractType && limits[i].StartType == "forward" {
+				limit = &limits[i]
+				break
+			}
+		}
+
+		if limit == nil {
+			return fmt.Sprintf("❌ Forward-starting contracts are not offered for %s", symbol), nil
+		}
+
+		if !startAllowed(limit.ForwardStarts, start) {
+			return fmt.Sprintf("❌ Contracts on %s can't start at %s. %s",
+				symbol, start.UTC().Format("2006-01-02 15:04 UTC"), describeStartWindows(limit.ForwardStarts)), nil
+		}
+	}
+
+	return "", nil
+}
+
+// startAllowed reports whether the start time falls into one of the windows
+func startAllowed(windows []StartWindow, start time.Time) bool {
+	for _, window := range windows {
+		if !start.Before(window.Open) && !start.After(window.Close) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// describeStartWindows lists upcoming start windows for error messages
+func describeStartWindows(windows []StartWindow) string {
+	const maxListed = 3
+
+	var parts []string
+	for _, window := range windows {
+		if window.Close.Before(time.Now()) {
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%s–%s", window.Open.UTC().Format("Jan 2 15:04"), window.Close.UTC().Format("15:04")))
+		if len(parts) == maxListed {
+			break
+		}
+	}
+
+	if len(parts) == 0 {
+		return "No start times are available at the moment."
+	}
+
+	return "Allowed start times (UTC): " + strings.Join(parts, ", ")
+}
+
+// formatCallbackDuration adds the start time of forward-starting contracts to the duration in trade callbacks,
+// e.g. 5m@1735689600
+func formatCallbackDuration(duration Duration, start time.Time) string {
+	if start.IsZero() {
+		return duration.String()
+	}
+
+	return duration.String() + "@" + strconv.FormatInt(start.Unix(), 10)
+}
+
+// parseCallbackDuration reads the duration and optional start time written by formatCallbackDuration
+func parseCallbackDuration(value string) (Duration, time.Time, error) {
+	durationPart, startPart, forward := strings.Cut(value, "@")
+
+	duration, err := ParseDuration(durationPart)
+	if err != nil {
+		return Duration{}, time.Time{}, err
+	}
+
+	if !forward {
+		return duration, time.Time{}, nil
+	}
+
+	epoch, err := strconv.ParseInt(startPart, 10, 64)
+	if err != nil {
+		return Duration{}, time.Time{}, fmt.Errorf("invalid start time %q: %w", startPart, err)
+	}
+
+	return duration, time.Unix(epoch, 0), nil
+}
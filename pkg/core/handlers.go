@@ -3,22 +3,19 @@ package core
 import (
 	"context"
 	"fmt"
+	"log"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/kirill/deriv-teletrader/pkg/chart"
 )
 
 // Basic command handlers
 func (b *Bot) handleStart(ctx context.Context, msg *Message) (*Response, error) {
-	text := `👋 Welcome to Deriv Trading Bot!
-
-Use /help to see available commands.`
-	return &Response{
-		Text:             text,
-		ReplyToMessageID: msg.MessageID,
-		ChatID:           msg.ChatID,
-	}, nil
+	return NewResponse(msg).Text(b.content(ctx, contentWelcome, defaultWelcome)).Build(), nil
 }
 
 func (b *Bot) handleHelp(ctx context.Context, msg *Message) (*Response, error) {
@@ -27,70 +24,165 @@ func (b *Bot) handleHelp(ctx context.Context, msg *Message) (*Response, error) {
 /symbols - List available trading symbols
 /balance - Show account balance
 /price <symbol> - Get current price for a symbol
-/buy <symbol> <amount> - Place a trade (Up/Down)
-/position - Show current positions
+/chart <symbol> - Price chart for the last hour
+/compare <symbol> <symbol> [...] - 24h change and range of symbols side by side
+/mtf <symbol> - Change and range over 1h, 24h, 7d and 30d
+/order buy <symbol> <amount> [duration] [up|down] when price <op> <value> - Place a trade once the price meets a condition
+/orders - List pending orders
+/cancelorder <id> - Cancel a pending order
+/strategy list|start|stop|tune - Run built-in strategies and tune their parameters
+/alert <symbol> <indicator>(<period>,<timeframe>) <op> <value> - Alert on an indicator, e.g. /alert R_50 rsi(14,5m) < 25
+/markets - Browse symbols by market
+/buy <symbol> [amount] [duration] [#tags] [start=+10m] [sl=<amount>] [tp=<amount>] - Place a trade (Up/Down), e.g. 5t or 15m
+/proposal <symbol> <amount> [duration] <up|down> - Preview price and payout without buying
+/stakes [amounts|reset] - Stakes offered when /buy has no amount
+/position - Show open positions with P&L, sell and details buttons
+/history [n] - Latest account transactions with paging
+/profits [today|week|month] - Win rate, stakes, payouts and net P&L of closed contracts
+/exposure - Stake at risk and potential payout by symbol and contract type
+/track <contract_id> - Follow a contract until it settles
+/contract <contract_id> - Show full details of a contract
+/sell [contract_id] - Sell a contract back before expiry, without an ID pick from open ones
+/tp <contract_id> <amount|percent%|off> - Sell automatically once the profit reaches a target
+/trail <contract_id> <distance|off> - Sell once the profit falls by a distance from its peak
+/hedge <contract_id> [max_loss] - Propose an opposite contract capping the loss of an open one
+/basket buy <symbols> <amount> [up|down] - Trade several symbols at once
+/touch <symbol> <stake> [duration] [barrier] - Touch/No touch or Higher/Lower than a barrier
+/digit <symbol> <amount> <prediction> [ticks] - Last digit matches, differs, over or under
+/mult <symbol> <stake> <multiplier> [up|down] [sl=<amount>] [tp=<amount>] - Multiplier contract, e.g. /mult R_50 10 x100 sl=5
+/portfolio - P&L of your trades and baskets
+/pnl [by-tag] - Realized P&L, optionally per trade tag
+/stats [symbol] [from] [to] - Win rate, profit factor, streaks and execution quality of your trades
+/status - Trading availability and response times of Deriv, the assistant and Telegram
+/explain <contract_id> - Learn why a trade won or lost
+/learn [contract_type] [question] - How contract types work
+/size <symbol> <risk%> <stop_distance> [x<multiplier>] - Stake for a multiplier stop-loss
+/teamportfolio [join|leave] - Shared positions of a group chat
+/weekly [badges on|off] - Report of the last 7 days with badges
+/watch <symbol> [every 5m|on 0.3%] - Price updates at most every interval or on moves, /watch stop [symbol]
+/ticks <symbol> [n] - Latest ticks with changes and last digits
+/data <symbol> <interval> <count> [csv] - Export ticks or candles as CSV
+/data replay <symbol> <YYYY-MM-DD> - Export ticks recorded by the bot on a day
+/simulate <symbol> <stake> <duration> [up|down] - Replay a trade on recent prices
+/locale [code|auto] - Number format for amounts
+/plain on|off - Screen-reader friendly output without emojis
+/notifications [category on|off] - Choose which notifications you get
+/webhook [set <url>|test|off] - Post your trade events to your own URL
+/dashboard - Live summary of balance, positions and prices
+/expiryalerts on|off - Notify shortly before contracts expire
+/currency <code> - Show amounts converted to a display currency
+/connect <token> - Trade with your own Deriv account
+/disconnect - Unlink your Deriv account
+/account - Show your accounts and switch between demo and real
+/copy list|start|stop|stats - Copy trades of another Deriv trader on your account
+/wipe me - Delete your stored data, /wipe restore brings it back for a while
 
 Example:
 1. /buy R_50 10.50
 2. Select Up ⬆️ or Down ⬇️`
-	return &Response{
-		Text:             text,
-		ReplyToMessageID: msg.MessageID,
-		ChatID:           msg.ChatID,
-	}, nil
+
+	text += b.macroHelp()
+
+	if len(b.customCommands) > 0 {
+		names := make([]string, 0, len(b.customCommands))
+		for name := range b.customCommands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		text += "\n\nPlugin commands:\n"
+		for _, name := range names {
+			text += fmt.Sprintf("\n/%s - %s", name, b.customCommands[name])
+		}
+	}
+
+	// Operators may replace the help with their own text, keeping the list of commands where {commands} is
+	if custom := b.content(ctx, contentHelp, ""); custom != "" {
+		text = strings.ReplaceAll(custom, commandsPlaceholder, text)
+	}
+
+	return NewResponse(msg).Text(text).Build(), nil
 }
 
 func (b *Bot) handleSymbols(ctx context.Context, msg *Message) (*Response, error) {
-	symbols := strings.Join(b.symbols, "\n")
-	text := fmt.Sprintf("Available symbols:\n\n%s", symbols)
-	return &Response{
-		Text:             text,
-		ReplyToMessageID: msg.MessageID,
-		ChatID:           msg.ChatID,
-	}, nil
+	names := b.symbolNames(ctx)
+
+	labels := make([]string, len(b.symbols))
+	for i, symbol := range b.symbols {
+		labels[i] = symbolLabel(names, symbol)
+	}
+
+	text := fmt.Sprintf("Available symbols:\n\n%s", strings.Join(labels, "\n"))
+	return NewResponse(msg).Text(text).Build(), nil
 }
 
 func (b *Bot) handleBalance(ctx context.Context, msg *Message) (*Response, error) {
-	balance, err := b.derivClient.GetBalance(ctx)
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := client.GetBalance(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
-	return &Response{
-		Text:             fmt.Sprintf("💰 Balance: %.2f %s", balance.Amount, balance.Currency),
-		ReplyToMessageID: msg.MessageID,
-		ChatID:           msg.ChatID,
-	}, nil
+	return NewResponse(msg).Textf("💰 Balance: %s\n👤 Account: %s",
+		b.formatter(ctx, msg).Money(balance.Amount, balance.Currency), client.ActiveAccount().Label()).Build(), nil
 }
 
 func (b *Bot) handlePrice(ctx context.Context, msg *Message) (*Response, error) {
 	if len(msg.Args) < 1 {
-		return &Response{
-			Text:             "❌ Please provide a symbol. Example: /price R_50",
-			ReplyToMessageID: msg.MessageID,
-			ChatID:           msg.ChatID,
-		}, nil
+		return NewResponse(msg).Text("❌ Please provide a symbol. Example: /price R_50").Build(), nil
 	}
 
-	symbol := msg.Args[0]
-	price, err := b.derivClient.GetPrice(ctx, symbol)
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "price:" + symbol
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	// Candles don't depend on the tick, so both are fetched at once
+	var (
+		wg         sync.WaitGroup
+		candles    []HistoricalDataPoint
+		historyErr error
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		candles, historyErr = b.cachedCandles(ctx, dayCandlesRequest(symbol))
+	}()
+
+	price, err := b.getPrice(ctx, symbol)
+	wg.Wait()
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to get price: %w", err)
 	}
 
-	return &Response{
-		Text:             fmt.Sprintf("💹 %s price: %.2f", symbol, price),
-		ReplyToMessageID: msg.MessageID,
-		ChatID:           msg.ChatID,
-	}, nil
+	f := b.formatter(ctx, msg)
+	text := fmt.Sprintf("💹 %s: %s", symbolLabel(b.symbolNames(ctx), symbol), f.Number(price, 2))
+
+	// The price is still useful without statistics
+	if historyErr != nil {
+		log.Printf("Failed to get 24h candles for %s: %v", symbol, historyErr)
+	} else if stats, ok := dayStats(candles, price); ok {
+		text += "\n" + formatDayStats(f, stats)
+	}
+
+	if line := b.benchmarkLine(ctx, f, symbol, price); line != "" {
+		text += "\n" + line
+	}
+
+	return NewResponse(msg).Text(text).Build(), nil
 }
 
 func (b *Bot) handleBuy(ctx context.Context, msg *Message) (*Response, error) {
-	// If there's callback data, handle the direction selection
-	if msg.CallbackData != "" {
+	// If there's trade callback data, handle the direction selection
+	if strings.HasPrefix(msg.CallbackData, "trade:") {
 		data := ParseCallbackData(msg.CallbackData)
-		if data["action"] != "trade" {
-			return nil, fmt.Errorf("invalid callback action: %s", data["action"])
-		}
 
 		symbol := data["symbol"]
 		amount, err := strconv.ParseFloat(data["amount"], 64)
@@ -98,67 +190,173 @@ func (b *Bot) handleBuy(ctx context.Context, msg *Message) (*Response, error) {
 			return nil, fmt.Errorf("invalid amount in callback: %w", err)
 		}
 
+		// Callback data is "trade:<symbol>:<amount>:<duration>:[<limits>:]<tags>:<up|down>" with comma separated
+		// limits and tags, the duration of forward-starting contracts carries the start time.
+		// Older buttons have no tags or duration
+		duration := fallbackDuration
+		var startAt time.Time
+		var limits TradeLimits
+		var tags []string
+		parts := strings.Split(msg.CallbackData, ":")
+		if len(parts) >= 5 {
+			if duration, startAt, err = parseCallbackDuration(parts[3]); err != nil {
+				return nil, fmt.Errorf("invalid duration in callback: %w", err)
+			}
+		}
+		if len(parts) == 7 {
+			if _, limits, err = splitLimits(strings.Split(parts[4], ",")); err != nil {
+				return nil, fmt.Errorf("invalid limits in callback: %w", err)
+			}
+		}
+		if len(parts) >= 6 && parts[len(parts)-2] != "" {
+			tags = strings.Split(parts[len(parts)-2], ",")
+		}
+
 		direction := "CALL"
 		if strings.HasSuffix(msg.CallbackData, ":down") {
 			direction = "PUT"
 		}
 
-		if err := b.derivClient.PlaceTrade(ctx, symbol, amount, direction); err != nil {
-			return nil, fmt.Errorf("failed to place trade: %w", err)
+		req := &TradeRequest{
+			Symbol:       symbol,
+			Amount:       amount,
+			ContractType: direction,
+			Duration:     duration,
+			StartAt:      startAt,
+			Limits:       limits,
 		}
 
-		directionEmoji := "⬆️"
-		if direction == "PUT" {
-			directionEmoji = "⬇️"
+		return b.tradeDirection(ctx, msg, req, tags)
+	}
+
+	// Initial /buy command handling, #tags, start=+<duration>, sl=<amount> and tp=<amount> can be placed anywhere after the command
+	args, tags, err := splitTags(msg.Args)
+	if err != nil {
+		return NewResponse(msg).Text("❌ Invalid tag. Tags may contain letters, digits, _ and -, e.g. #breakout").Build(), nil
+	}
+
+	args, startArg := splitStart(args)
+
+	args, limits, err := splitLimits(args)
+	if err != nil {
+		return NewResponse(msg).Text("❌ Invalid stop-loss or take-profit. Give them as positive amounts, e.g. sl=5 tp=10").Build(), nil
+	}
+
+	var startAt time.Time
+	if startArg != "" {
+		if startAt, err = parseStart(startArg, time.Now()); err != nil {
+			return NewResponse(msg).Text("❌ Invalid start time. Give it relative to now, e.g. start=+10m").Build(), nil
+		}
+	}
+
+	if len(args) == 0 {
+		return NewResponse(msg).Text("❌ Please provide symbol and amount, optionally a duration and #tags. Example: /buy R_50 10.50 5t #breakout").Build(), nil
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, args[0], func(symbol string) string {
+		callback := "buy:" + strings.Join(append([]string{symbol}, args[1:]...), ":")
+		if startArg != "" {
+			callback += ":" + startArgPrefix + startArg
+		}
+		for _, arg := range limits.args() {
+			callback += ":" + arg
 		}
+		if len(tags) > 0 {
+			callback += ":#" + strings.Join(tags, ":#")
+		}
+		return callback
+	})
+	if choice != nil {
+		return choice, nil
+	}
 
-		return &Response{
-			Text:             fmt.Sprintf("✅ %s Trade placed for %s: $%.2f", directionEmoji, symbol, amount),
-			ReplyToMessageID: msg.MessageID,
-			ChatID:           msg.ChatID,
-		}, nil
+	if reason, err := b.tradingBlocked(ctx, symbol); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
 	}
 
-	// Initial /buy command handling
-	if len(msg.Args) < 2 {
-		return &Response{
-			Text:             "❌ Please provide symbol and amount. Example: /buy R_50 10.50",
-			ReplyToMessageID: msg.MessageID,
-			ChatID:           msg.ChatID,
-		}, nil
+	// Without an amount the stake is picked from a keyboard
+	if stakeOmitted(args) {
+		return b.stakePicker(ctx, msg, symbol, args[1:], startArg, limits, tags)
 	}
 
-	symbol := msg.Args[0]
-	amount, err := strconv.ParseFloat(msg.Args[1], 64)
+	amount, err := strconv.ParseFloat(args[1], 64)
 	if err != nil {
-		return &Response{
-			Text:             "❌ Invalid amount format. Please provide a number.",
-			ReplyToMessageID: msg.MessageID,
-			ChatID:           msg.ChatID,
-		}, nil
+		return NewResponse(msg).Text("❌ Invalid amount format. Please provide a number.").Build(), nil
 	}
 
-	// Get historical data for the last hour
-	historyReq := HistoricalDataRequest{
-		Symbol:   symbol,
-		Interval: IntervalHour,
-		Style:    StyleCandles,
-		Count:    60, // 1 minute candles for the last hour
+	var duration Duration
+	if len(args) > 2 {
+		if duration, err = ParseDuration(args[2]); err != nil {
+			return NewResponse(msg).Text("❌ Invalid duration. Use a number with t (ticks), s, m, h or d, e.g. 5t or 15m.").Build(), nil
+		}
+	}
+
+	if duration.Value == 0 {
+		duration = b.defaultDuration(ctx, symbol)
+	}
+
+	// Up/Down contracts can't lose more than the stake, a stop-loss at the stake would never trigger
+	if limits.StopLoss >= amount {
+		return NewResponse(msg).Text("❌ The stop-loss must be below the stake.").Build(), nil
+	}
+
+	if prompt, err := b.cooldownPrompt(ctx, msg); err != nil || prompt != nil {
+		return prompt, err
+	}
+
+	// Validate stake before offering the trade, Up/Down use CALL/PUT contracts
+	f := b.formatter(ctx, msg)
+	if reason, err := b.validateStake(ctx, f, symbol, amount, "CALL", "PUT"); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	if !startAt.IsZero() {
+		if reason, err := b.validateForwardStart(ctx, symbol, startAt, duration, "CALL", "PUT"); err != nil {
+			return nil, err
+		} else if reason != "" {
+			return NewResponse(msg).Text(reason).Build(), nil
+		}
 	}
 
-	data, err := b.derivClient.GetHistoricalData(ctx, historyReq)
+	// Get historical data for the last hour
+	data, err := b.cachedCandles(ctx, chartCandlesRequest(symbol))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get historical data: %w", err)
 	}
 
 	// Generate price chart
-	chartPath, err := chart.GeneratePriceChart(data, symbol)
+	chartPath, err := chart.GeneratePriceChart(data, symbol, b.chartLevels(ctx, msg.Username, symbol), b.chartWatermark(msg.Username))
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate chart: %w", err)
 	}
 
 	// Create callback data with trade details
-	callbackBase := fmt.Sprintf("trade:%s:%.2f", symbol, amount)
+	callbackBase := fmt.Sprintf("trade:%s:%s:%s", symbol, strconv.FormatFloat(amount, 'f', -1, 64), formatCallbackDuration(duration, startAt))
+	if limits.IsSet() {
+		callbackBase += ":" + strings.Join(limits.args(), ",")
+	}
+	callbackBase += ":" + strings.Join(tags, ",")
+
+	// Trades that don't fit into callback data wait on the server, the buttons carry their ID
+	if len(callbackBase)+len(":down") > maxCallbackData {
+		id := b.pendingTrades.put(msg.ChatID, &pendingTrade{
+			username: msg.Username,
+			req: &TradeRequest{
+				Symbol:   symbol,
+				Amount:   amount,
+				Duration: duration,
+				StartAt:  startAt,
+				Limits:   limits,
+			},
+			tags:    tags,
+			expires: time.Now().Add(directionChoiceTimeout),
+		})
+		callbackBase = "direction:" + strconv.FormatInt(id, 10)
+	}
 
 	// Create Up/Down buttons
 	buttons := [][]Button{
@@ -168,23 +366,143 @@ func (b *Bot) handleBuy(ctx context.Context, msg *Message) (*Response, error) {
 		},
 	}
 
-	return &Response{
-		Text:             fmt.Sprintf("🎯 Place a trade for %s: $%.2f\nSelect direction:", symbol, amount),
-		ReplyToMessageID: msg.MessageID,
-		ChatID:           msg.ChatID,
-		Buttons:          buttons,
-		PhotoPath:        chartPath,
-	}, nil
+	currency := b.accountCurrency(ctx, msg.Username)
+	prompt := fmt.Sprintf("🎯 Place a trade for %s: %s for %s", symbol, f.Money(amount, currency), duration)
+	if !startAt.IsZero() {
+		prompt += ", starting at " + startAt.UTC().Format("15:04:05 UTC")
+	}
+	if limits.StopLoss > 0 {
+		prompt += ", stop-loss " + f.Money(limits.StopLoss, currency)
+	}
+	if limits.TakeProfit > 0 {
+		prompt += ", take-profit " + f.Money(limits.TakeProfit, currency)
+	}
+	if len(tags) > 0 {
+		prompt += " " + formatTags(tags)
+	}
+
+	return NewResponse(msg).Text(prompt + "\nSelect direction:").Keyboard(buttons).Photo(chartPath).Build(), nil
+}
+
+// tradeDirection places the Up/Down trade picked on the buttons of /buy, asking for confirmation first when it's enabled
+func (b *Bot) tradeDirection(ctx context.Context, msg *Message, req *TradeRequest, tags []string) (*Response, error) {
+	if !req.StartAt.IsZero() && !req.StartAt.After(time.Now()) {
+		return NewResponse(msg).Text("❌ The start time of this trade has passed. Please use /buy again.").Build(), nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if stop, err := b.checkTrade(ctx, msg, client, req); err != nil || stop != nil {
+		return stop, err
+	}
+
+	if b.cfg.TradeConfirmTimeout > 0 {
+		return b.confirmTradePrompt(ctx, msg, client, req, tags)
+	}
+
+	return b.executeTrade(ctx, msg, client, req, tags)
+}
+
+// checkTrade returns the response refusing the trade when the user is cooling down after losses, trading of
+// the symbol is blocked, too many of its contracts are open or a risk limit is reached, or nil when it may be placed.
+// Buttons of earlier messages can still be clicked, so every purchase is checked right before it's placed.
+func (b *Bot) checkTrade(ctx context.Context, msg *Message, client DerivClient, req *TradeRequest) (*Response, error) {
+	if prompt, err := b.cooldownPrompt(ctx, msg); err != nil || prompt != nil {
+		return prompt, err
+	}
+
+	reason, err := b.tradingBlocked(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason == "" {
+		if reason, err = b.openContractsLimited(ctx, client, req.Symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	if reason == "" {
+		if reason, err = b.riskLimited(ctx, msg, client, req.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	return nil, nil
+}
+
+// executeTrade places the trade, records it and follows it until expiry, returning the receipt
+func (b *Bot) executeTrade(ctx context.Context, msg *Message, client DerivClient, req *TradeRequest, tags []string) (*Response, error) {
+	result, err := b.placeTrade(ctx, msg, client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place trade: %w", err)
+	}
+
+	if err := b.recordTrade(ctx, msg.Username, req.Symbol, req.ContractType, req.Barrier, tags, result); err != nil {
+		log.Printf("Failed to record trade %d: %v", result.ContractID, err)
+	}
+
+	// Multipliers don't expire and Deriv closes them at their limits
+	if req.IsMultiplier() {
+		return multReceipt(msg, b.formatter(ctx, msg), req, result), nil
+	}
+
+	b.watchExpiry(ctx, msg, client, req.Symbol, result)
+	b.watchLimits(ctx, msg, client, result, req.Limits)
+
+	return tradeReceipt(msg, b.formatter(ctx, msg), req, result, tags), nil
 }
 
 func (b *Bot) handlePosition(ctx context.Context, msg *Message) (*Response, error) {
-	position, err := b.derivClient.GetPosition(ctx)
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := client.GetOpenPositions(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get position: %w", err)
 	}
-	return &Response{
-		Text:             fmt.Sprintf("📊 Current positions:\n\n%s", position),
-		ReplyToMessageID: msg.MessageID,
-		ChatID:           msg.ChatID,
-	}, nil
+
+	var buttons [][]Button
+	for _, position := range positions {
+		row := []Button{{Text: fmt.Sprintf("🔎 %d", position.ContractID), CallbackData: fmt.Sprintf("track:%d", position.ContractID)}}
+		if position.IsValidToSell {
+			row = append(row, Button{Text: "💸 Sell", CallbackData: fmt.Sprintf("sell:%d", position.ContractID)})
+		}
+		buttons = append(buttons, row)
+	}
+
+	return NewResponse(msg).
+		Textf("📊 Current positions:\n\n%s", formatPositions(b.formatter(ctx, msg), positions)).
+		Keyboard(buttons).
+		Build(), nil
+}
+
+// formatPositions lists open positions one per line
+func formatPositions(f Formatter, positions []PositionInfo) string {
+	if len(positions) == 0 {
+		return "No open positions"
+	}
+
+	var sb strings.Builder
+	for _, p := range positions {
+		fmt.Fprintf(&sb, "%s %d %s %s, stake %s", f.ContractIcon(p.ContractType), p.ContractID, p.Symbol, p.ContractType, f.Money(p.Stake, p.Currency))
+		if p.Valued {
+			fmt.Fprintf(&sb, ", P&L %s", f.SignedMoney(p.Profit, p.Currency))
+		}
+		if !p.ExpiryTime.IsZero() {
+			fmt.Fprintf(&sb, ", expires in %s", time.Until(p.ExpiryTime).Round(time.Second))
+		}
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
 }
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minHedgeTime is the least time left on a contract for a hedge to be proposed, shorter hedges can't be placed in time
+const minHedgeTime = 15 * time.Second
+
+// oppositeContracts maps Up/Down contract types to the type winning when they lose
+var oppositeContracts = map[string]string{
+	"CALL": "PUT",
+	"PUT":  "CALL",
+}
+
+// handleHedge proposes an opposite contract expiring with an open one, "/hedge <contract_id> [max_loss]".
+// Without max_loss the hedge locks in the smallest loss possible, either way the trade goes through confirmation.
+func (b *Bot) handleHedge(ctx context.Context, msg *Message) (*Response, error) {
+	usage := NewResponse(msg).Text("❌ Usage: /hedge <contract_id> [max_loss]\nExample: /hedge 123456 2").Build()
+	if len(msg.Args) < 1 || len(msg.Args) > 2 {
+		return usage, nil
+	}
+
+	contractID, err := strconv.ParseInt(msg.Args[0], 10, 64)
+	if err != nil || contractID <= 0 {
+		return usage, nil
+	}
+
+	maxLoss := -1.0
+	if len(msg.Args) == 2 {
+		if maxLoss, err = strconv.ParseFloat(msg.Args[1], 64); err != nil || maxLoss < 0 {
+			return NewResponse(msg).Text("❌ Invalid maximum loss, it should be a positive amount.").Build(), nil
+		}
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := client.GetContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	if info.IsSold {
+		return NewResponse(msg).Textf("ℹ️ Contract %d has already settled.", contractID).Build(), nil
+	}
+
+	opposite, ok := oppositeContracts[info.ContractType]
+	if !ok {
+		return NewResponse(msg).Text("❌ Only Up/Down contracts can be hedged.").Build(), nil
+	}
+
+	duration, ok := remainingDuration(info, time.Now())
+	if !ok {
+		return NewResponse(msg).Textf("❌ Contract %d expires too soon to be hedged.", contractID).Build(), nil
+	}
+
+	f := b.formatter(ctx, msg)
+
+	if maxLoss >= info.BuyPrice {
+		return NewResponse(msg).Textf("ℹ️ Contract %d can't lose more than its stake of %s, no hedge is needed.",
+			contractID, f.Money(info.BuyPrice, info.Currency)).Build(), nil
+	}
+
+	// The payout ratio barely depends on the stake, so a quote at the stake of the contract sizes the hedge
+	req := &TradeRequest{
+		Symbol:       info.Symbol,
+		Amount:       info.BuyPrice,
+		ContractType: opposite,
+		Duration:     duration,
+	}
+
+	quote, err := client.GetQuote(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	ratio := payoutRatio(quote)
+
+	var stake float64
+	if maxLoss < 0 {
+		stake = lockStake(info.Payout, ratio)
+	} else if stake, err = hedgeStake(info.BuyPrice, info.Payout, ratio, maxLoss); errors.Is(err, errHedgeImpossible) {
+		floor := info.BuyPrice - lockStake(info.Payout, ratio)*(ratio-1)
+		return NewResponse(msg).Textf("❌ A hedge can't cap the loss of contract %d at %s, the smallest loss it can lock in is %s.",
+			contractID, f.Money(maxLoss, info.Currency), f.Money(math.Max(floor, 0), info.Currency)).Build(), nil
+	}
+
+	scale := math.Pow(10, float64(moneyDecimals(info.Currency)))
+	req.Amount = math.Ceil(stake*scale) / scale
+
+	if reason, err := b.validateStake(ctx, f, req.Symbol, req.Amount, req.ContractType); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	prompt, err := b.confirmTradePrompt(ctx, msg, client, req, []string{"hedge"})
+	if err != nil {
+		return nil, err
+	}
+
+	contractWins, hedgeWins := hedgeOutcomes(info.BuyPrice, info.Payout, req.Amount, ratio)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🛡 Hedge of contract %d (%s %s)\n", contractID, info.Symbol, info.ContractType)
+	fmt.Fprintf(&sb, "If the contract wins: %s\n", f.SignedMoney(contractWins, info.Currency))
+	fmt.Fprintf(&sb, "If the hedge wins: %s\n\n", f.SignedMoney(hedgeWins, info.Currency))
+	prompt.Text = sb.String() + prompt.Text
+
+	return prompt, nil
+}
+
+// remainingDuration returns the duration of a contract expiring together with the open one,
+// it reports false when too little is left to place it
+func remainingDuration(info *ContractInfo, now time.Time) (Duration, bool) {
+	if info.TickCount > 0 {
+		left := info.TickCount - info.TicksPassed
+		return Duration{Value: left, Unit: "t"}, left > 0
+	}
+
+	left := info.ExpiryTime.Sub(now)
+	if left < minHedgeTime {
+		return Duration{}, false
+	}
+
+	return Duration{Value: int(left.Seconds()), Unit: "s"}, true
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page sizes of /history
+const (
+	defaultHistoryPage = 10
+	maxHistoryPage     = 50
+)
+
+// Transaction is an entry of the account statement
+type Transaction struct {
+	ID           int64
+	Time         time.Time
+	Action       string // buy, sell, deposit, withdrawal, escrow, adjustment, virtual_credit or transfer
+	Amount       float64
+	BalanceAfter float64
+	ContractID   int64  // Zero for transactions not related to a contract
+	Longcode     string // Description of the contract or the transfer
+}
+
+// historyActions names statement actions in chat messages, sells of contracts include payouts at expiry
+var historyActions = map[string]string{
+	"buy":            "🟥 Buy",
+	"sell":           "🟩 Sell/payout",
+	"deposit":        "⬇️ Deposit",
+	"withdrawal":     "⬆️ Withdrawal",
+	"transfer":       "🔁 Transfer",
+	"virtual_credit": "🎁 Virtual credit",
+}
+
+// handleHistory lists the latest transactions of the account, "/history [n]". Prev and Next buttons page
+// through older ones by editing the message, their callback data is "history:<n>:<offset>".
+func (b *Bot) handleHistory(ctx context.Context, msg *Message) (*Response, error) {
+	rb := NewResponse(msg)
+
+	size := defaultHistoryPage
+	if len(msg.Args) > 0 {
+		n, err := strconv.Atoi(msg.Args[0])
+		if err != nil || n <= 0 || n > maxHistoryPage {
+			return rb.Textf("❌ Usage: /history [n], n is between 1 and %d", maxHistoryPage).Build(), nil
+		}
+		size = n
+	}
+
+	var offset int
+	if len(msg.Args) > 1 {
+		n, err := strconv.Atoi(msg.Args[1])
+		if err != nil || n < 0 {
+			return rb.Text("❌ Invalid page").Build(), nil
+		}
+		offset = n
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	// One more transaction tells whether there is a next page
+	transactions, err := client.GetStatement(ctx, size+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(transactions) > size
+	if more {
+		transactions = transactions[:size]
+	}
+
+	// Pages replace each other in place
+	if msg.CallbackData != "" {
+		rb.Edit(msg.MessageID)
+	}
+
+	rb.Text(formatHistory(b.formatter(ctx, msg), client.Currency(), transactions, offset))
+
+	var row []Button
+	if offset > 0 {
+		row = append(row, Button{Text: "⬅️ Prev", CallbackData: fmt.Sprintf("history:%d:%d", size, max(offset-size, 0))})
+	}
+	if more {
+		row = append(row, Button{Text: "Next ➡️", CallbackData: fmt.Sprintf("history:%d:%d", size, offset+size)})
+	}
+	if len(row) > 0 {
+		rb.Keyboard([][]Button{row})
+	}
+
+	return rb.Build(), nil
+}
+
+// formatHistory lists transactions of a statement page
+func formatHistory(f Formatter, currency string, transactions []Transaction, offset int) string {
+	if len(transactions) == 0 {
+		if offset > 0 {
+			return "📜 No older transactions."
+		}
+		return "📜 No transactions yet."
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📜 Transactions %d-%d, newest first\n\n", offset+1, offset+len(transactions))
+
+	for _, tx := range transactions {
+		action, ok := historyActions[tx.Action]
+		if !ok {
+			action = tx.Action
+		}
+
+		fmt.Fprintf(&sb, "%s %s %s", tx.Time.UTC().Format("01-02 15:04"), action, f.SignedMoney(tx.Amount, currency))
+		if tx.ContractID != 0 {
+			fmt.Fprintf(&sb, " #%d", tx.ContractID)
+		}
+		fmt.Fprintf(&sb, "\n   balance %s\n", f.Money(tx.BalanceAfter, currency))
+	}
+
+	sb.WriteString("\nTimes are in UTC.")
+
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultLanguage is used when a message is missing in the user's language
+const defaultLanguage = "en"
+
+// catalog holds chat messages by language and key
+var catalog = map[string]map[string]string{
+	"en": {
+		"error.invalid_token":        "🔑 Your Deriv API token is invalid or has expired.\n\nCreate a new token with Read and Trade scopes and link it again with /connect.",
+		"error.market_closed":        "🕒 This market is closed right now.\n\nSynthetic indices such as R_50 trade 24/7, or try again when the market opens.",
+		"error.contract_validation":  "⚠️ Deriv rejected the contract: %s\n\nCheck the stake and duration, then try again.",
+		"error.rate_limit":           "🐢 Too many requests to Deriv.\n\nPlease wait a minute and try again.",
+		"error.insufficient_balance": "💸 Your balance is too low for this trade.\n\nCheck /balance and lower the stake.",
+		"error.invalid_symbol":       "❓ Deriv doesn't know this symbol.\n\nUse /symbols to see the available ones.",
+	},
+	"ru": {
+		"error.invalid_token":        "🔑 Ваш токен Deriv API недействителен или истёк.\n\nСоздайте новый токен с правами Read и Trade и подключите его снова через /connect.",
+		"error.market_closed":        "🕒 Этот рынок сейчас закрыт.\n\nСинтетические индексы, например R_50, торгуются круглосуточно, или попробуйте позже, когда рынок откроется.",
+		"error.contract_validation":  "⚠️ Deriv отклонил контракт: %s\n\nПроверьте ставку и длительность и попробуйте снова.",
+		"error.rate_limit":           "🐢 Слишком много запросов к Deriv.\n\nПодождите минуту и попробуйте снова.",
+		"error.insufficient_balance": "💸 Недостаточно средств для этой сделки.\n\nПроверьте /balance и уменьшите ставку.",
+		"error.invalid_symbol":       "❓ Deriv не знает этот символ.\n\nСписок доступных символов: /symbols.",
+		"market.synthetic_index":     "Синтетические индексы",
+		"market.forex":               "Форекс",
+		"market.indices":             "Фондовые индексы",
+		"market.cryptocurrency":      "Криптовалюты",
+		"market.commodities":         "Сырьевые товары",
+	},
+	"es": {
+		"error.invalid_token":        "🔑 Tu token de Deriv API no es válido o ha caducado.\n\nCrea un nuevo token con permisos Read y Trade y vuelve a vincularlo con /connect.",
+		"error.market_closed":        "🕒 Este mercado está cerrado ahora mismo.\n\nLos índices sintéticos como R_50 operan 24/7, o inténtalo de nuevo cuando abra el mercado.",
+		"error.contract_validation":  "⚠️ Deriv rechazó el contrato: %s\n\nRevisa el importe y la duración e inténtalo de nuevo.",
+		"error.rate_limit":           "🐢 Demasiadas solicitudes a Deriv.\n\nEspera un minuto e inténtalo de nuevo.",
+		"error.insufficient_balance": "💸 Tu saldo es insuficiente para esta operación.\n\nRevisa /balance y reduce el importe.",
+		"error.invalid_symbol":       "❓ Deriv no reconoce este símbolo.\n\nUsa /symbols para ver los disponibles.",
+		"market.synthetic_index":     "Índices sintéticos",
+		"market.forex":               "Forex",
+		"market.indices":             "Índices bursátiles",
+		"market.cryptocurrency":      "Criptomonedas",
+		"market.commodities":         "Materias primas",
+	},
+}
+
+// translate returns the message in the user's language, formatted with args when the message expects them
+func translate(lang, key string, args ...any) string {
+	// Telegram language codes may include a region, e.g. pt-br
+	lang = strings.ToLower(strings.SplitN(lang, "-", 2)[0])
+
+	text, ok := catalog[lang][key]
+	if !ok {
+		text, ok = catalog[defaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if strings.Contains(text, "%") {
+		return fmt.Sprintf(text, args...)
+	}
+
+	return text
+}
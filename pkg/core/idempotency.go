@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// tradeKeyTTL is how long a button is remembered as having placed its trade, Telegram redelivers callbacks within seconds
+const tradeKeyTTL = 10 * time.Minute
+
+// errDuplicateTrade is returned when a button that already placed its trade is pressed or delivered again
+var errDuplicateTrade = errors.New("trade of this button was already placed")
+
+// tradeKeys remembers idempotency keys of trades placed from buttons
+type tradeKeys struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// claim records the key and reports whether it wasn't seen within the TTL, forgetting expired keys
+func (k *tradeKeys) claim(key string, now time.Time) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for seen, at := range k.seen {
+		if now.Sub(at) > tradeKeyTTL {
+			delete(k.seen, seen)
+		}
+	}
+
+	if _, ok := k.seen[key]; ok {
+		return false
+	}
+	k.seen[key] = now
+
+	return true
+}
+
+// release forgets the key, so the button can place its trade again
+func (k *tradeKeys) release(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	delete(k.seen, key)
+}
+
+// tradeIdempotencyKey identifies a trade placed from a button: the message carrying the button, the button itself
+// and the contract, as a basket button places a contract per symbol. Commands typed by the user have no key.
+func tradeIdempotencyKey(msg *Message, req *TradeRequest) (string, bool) {
+	if msg.CallbackData == "" {
+		return "", false
+	}
+
+	return fmt.Sprintf("%d/%d/%s/%s/%s", msg.ChatID, msg.MessageID, msg.CallbackData, req.Symbol, req.ContractType), true
+}
+
+// placeTrade buys the contract, at most once per button even when Telegram delivers a callback twice
+// or the user double-taps it. A trade Deriv refused can be retried with the same button.
+func (b *Bot) placeTrade(ctx context.Context, msg *Message, client DerivClient, req *TradeRequest) (*TradeResult, error) {
+	key, ok := tradeIdempotencyKey(msg, req)
+	if ok && !b.tradeKeys.claim(key, time.Now()) {
+		return nil, errDuplicateTrade
+	}
+
+	result, err := client.PlaceTrade(ctx, req)
+
+	// Other failures, like a timeout, may have bought the contract anyway, so the key stays
+	var derivErr *DerivError
+	if ok && errors.As(err, &derivErr) {
+		b.tradeKeys.release(key)
+	}
+
+	return result, err
+}
@@ -0,0 +1,29 @@
+package core
+
+import (
+	"time"
+)
+
+// IdleConfig holds settings of the power-saving mode used when nobody is using the bot
+type IdleConfig struct {
+	After time.Duration `mapstructure:"after"` // Time without user messages before the bot idles, 0 disables idling
+}
+
+// touch records user activity, waking the bot up from idling
+func (b *Bot) touch() {
+	b.lastActivity.Store(time.Now().UnixNano())
+}
+
+// Idle reports whether no user has messaged the bot for a while and no background job such as
+// a dashboard or contract tracking is running, so polling and non-essential work can slow down
+func (b *Bot) Idle() bool {
+	if b.cfg.Idle.After <= 0 {
+		return false
+	}
+
+	if b.background.running.Load() > 0 {
+		return false
+	}
+
+	return time.Since(time.Unix(0, b.lastActivity.Load())) >= b.cfg.Idle.After
+}
@@ -0,0 +1,291 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Journal persists trades placed through the bot and looks them up by user, symbol and placement time
+type Journal interface {
+	// Save stores the trade, replacing the one of the same user with the same contract ID
+	Save(ctx context.Context, record *TradeRecord) error
+	// Get returns the trade of the user with the contract ID, or ErrNotFound
+	Get(ctx context.Context, username string, contractID int64) (*TradeRecord, error)
+	Delete(ctx context.Context, username string, contractID int64) error
+	// Query returns trades passing the filter ordered by placement time
+	Query(ctx context.Context, filter *JournalFilter) ([]*TradeRecord, error)
+	// Usernames returns users having trades in the journal
+	Usernames(ctx context.Context) ([]string, error)
+}
+
+// SetJournal keeps the trade journal in the journal instead of the data store
+func (b *Bot) SetJournal(journal Journal) {
+	b.journal = journal
+}
+
+// JournalFilter selects trades of the journal, zero fields match everything
+type JournalFilter struct {
+	Username string
+	Symbol   string
+	From     time.Time // Trades placed at or after it
+	To       time.Time // Trades placed before it
+	Open     bool      // Only trades that haven't settled yet
+}
+
+// match reports whether the trade passes the filter
+func (jf *JournalFilter) match(record *TradeRecord) bool {
+	switch {
+	case jf.Username != "" && record.Username != jf.Username:
+		return false
+	case jf.Symbol != "" && record.Symbol != jf.Symbol:
+		return false
+	case jf.Open && record.Settled():
+		return false
+	case !jf.From.IsZero() && record.PlacedAt.Before(jf.From):
+		return false
+	case !jf.To.IsZero() && !record.PlacedAt.Before(jf.To):
+		return false
+	}
+	return true
+}
+
+// describe names the filter in chat messages, e.g. "R_50, Jan 2 – Jan 9"
+func (jf *JournalFilter) describe() string {
+	var parts []string
+	if jf.Symbol != "" {
+		parts = append(parts, jf.Symbol)
+	}
+
+	switch {
+	case !jf.From.IsZero() && !jf.To.IsZero():
+		parts = append(parts, jf.From.Format("Jan 2")+" – "+jf.To.AddDate(0, 0, -1).Format("Jan 2"))
+	case !jf.From.IsZero():
+		parts = append(parts, "since "+jf.From.Format("Jan 2"))
+	case !jf.To.IsZero():
+		parts = append(parts, "until "+jf.To.AddDate(0, 0, -1).Format("Jan 2"))
+	}
+
+	if len(parts) == 0 {
+		return "all trades"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// tradeKey builds the data store key of a trade, keys are prefixed by username for per-user lookups
+func tradeKey(username string, contractID int64) string {
+	return username + "/" + strconv.FormatInt(contractID, 10)
+}
+
+// storageJournal keeps the trade journal in a bucket of the data store, it's used unless SetJournal is called.
+// Queries load the whole bucket, a journal with indexes suits long trading histories better.
+type storageJournal struct {
+	storage Storage
+}
+
+// Save stores the trade under its user and contract ID
+func (j *storageJournal) Save(ctx context.Context, record *TradeRecord) error {
+	return j.storage.Put(ctx, bucketTrades, tradeKey(record.Username, record.ContractID), record)
+}
+
+// Get loads the trade of the user with the contract ID
+func (j *storageJournal) Get(ctx context.Context, username string, contractID int64) (*TradeRecord, error) {
+	var record TradeRecord
+	if err := j.storage.Get(ctx, bucketTrades, tradeKey(username, contractID), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Delete removes the trade of the user with the contract ID
+func (j *storageJournal) Delete(ctx context.Context, username string, contractID int64) error {
+	return j.storage.Delete(ctx, bucketTrades, tradeKey(username, contractID))
+}
+
+// Query loads the trades of the bucket passing the filter
+func (j *storageJournal) Query(ctx context.Context, filter *JournalFilter) ([]*TradeRecord, error) {
+	keys, err := j.storage.Keys(ctx, bucketTrades)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trades: %w", err)
+	}
+
+	var records []*TradeRecord
+	for _, key := range keys {
+		if filter.Username != "" && !strings.HasPrefix(key, filter.Username+"/") {
+			continue
+		}
+
+		var record TradeRecord
+		if err := j.storage.Get(ctx, bucketTrades, key, &record); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to load trade %s: %w", key, err)
+		}
+
+		if filter.match(&record) {
+			records = append(records, &record)
+		}
+	}
+
+	sort.Slice(records, func(i, k int) bool {
+		return records[i].PlacedAt.Before(records[k].PlacedAt)
+	})
+
+	return records, nil
+}
+
+// Usernames returns users having trades in the bucket
+func (j *storageJournal) Usernames(ctx context.Context) ([]string, error) {
+	keys, err := j.storage.Keys(ctx, bucketTrades)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trades: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var usernames []string
+	for _, key := range keys {
+		if username, _, ok := strings.Cut(key, "/"); ok && !seen[username] {
+			seen[username] = true
+			usernames = append(usernames, username)
+		}
+	}
+	sort.Strings(usernames)
+
+	return usernames, nil
+}
+
+// MoveJournal moves trades kept in the data store to the journal, so switching to another journal keeps
+// the trading history. Each trade is deleted from the store once the journal has it, so it's safe to run
+// on every start. It returns the number of moved trades.
+func MoveJournal(ctx context.Context, storage Storage, journal Journal) (int, error) {
+	from := &storageJournal{storage: storage}
+
+	records, err := from.Query(ctx, &JournalFilter{})
+	if err != nil {
+		return 0, err
+	}
+
+	for i, record := range records {
+		if err := journal.Save(ctx, record); err != nil {
+			return i, fmt.Errorf("failed to move trade %d of %s: %w", record.ContractID, record.Username, err)
+		}
+
+		if err := from.Delete(ctx, record.Username, record.ContractID); err != nil && !errors.Is(err, ErrNotFound) {
+			return i, fmt.Errorf("failed to delete moved trade %d of %s: %w", record.ContractID, record.Username, err)
+		}
+	}
+
+	return len(records), nil
+}
+
+// journalStats summarizes results of settled trades
+type journalStats struct {
+	Trades      int
+	Wins        int
+	GrossWin    float64 // Sum of profits of won trades
+	GrossLoss   float64 // Sum of losses of lost trades, positive
+	LongestWin  int     // Most wins in a row
+	LongestLoss int     // Most losses in a row
+	Current     int     // Current streak, positive for wins and negative for losses
+}
+
+// summarizeJournal computes results of settled trades ordered by placement, open ones are skipped
+func summarizeJournal(records []*TradeRecord) *journalStats {
+	stats := &journalStats{}
+	for _, record := range records {
+		if !record.Settled() {
+			continue
+		}
+
+		stats.Trades++
+
+		if record.Profit > 0 {
+			stats.Wins++
+			stats.GrossWin += record.Profit
+			stats.Current = max(stats.Current, 0) + 1
+			stats.LongestWin = max(stats.LongestWin, stats.Current)
+		} else {
+			stats.GrossLoss -= record.Profit
+			stats.Current = min(stats.Current, 0) - 1
+			stats.LongestLoss = max(stats.LongestLoss, -stats.Current)
+		}
+	}
+
+	return stats
+}
+
+// formatJournalStats renders win rate, average win and loss, profit factor and streaks
+func formatJournalStats(f Formatter, currency string, stats *journalStats) string {
+	losses := stats.Trades - stats.Wins
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Trades: %d (%d won, %d lost)\n", stats.Trades, stats.Wins, losses)
+	fmt.Fprintf(&sb, "Win rate: %s%%\n", f.Number(float64(stats.Wins)/float64(stats.Trades)*100, 1))
+	fmt.Fprintf(&sb, "Net P&L: %s\n", f.SignedMoney(stats.GrossWin-stats.GrossLoss, currency))
+
+	if stats.Wins > 0 {
+		fmt.Fprintf(&sb, "Average win: %s\n", f.SignedMoney(stats.GrossWin/float64(stats.Wins), currency))
+	}
+	if losses > 0 {
+		fmt.Fprintf(&sb, "Average loss: %s\n", f.SignedMoney(-stats.GrossLoss/float64(losses), currency))
+	}
+
+	if stats.GrossLoss > 0 {
+		fmt.Fprintf(&sb, "Profit factor: %s\n", f.Number(stats.GrossWin/stats.GrossLoss, 2))
+	} else {
+		sb.WriteString("Profit factor: no losses\n")
+	}
+
+	fmt.Fprintf(&sb, "Longest streaks: %d wins, %d losses\n", stats.LongestWin, stats.LongestLoss)
+	switch {
+	case stats.Current > 0:
+		fmt.Fprintf(&sb, "Current streak: %d wins\n", stats.Current)
+	case stats.Current < 0:
+		fmt.Fprintf(&sb, "Current streak: %d losses\n", -stats.Current)
+	}
+
+	return sb.String()
+}
+
+// parseJournalFilter reads "[symbol] [from] [to]" with dates as YYYY-MM-DD, both days included
+func (b *Bot) parseJournalFilter(args []string) (*JournalFilter, error) {
+	filter := &JournalFilter{}
+
+	var dates []time.Time
+	for _, arg := range args {
+		if day, err := time.Parse(time.DateOnly, arg); err == nil {
+			dates = append(dates, day)
+			continue
+		}
+
+		if filter.Symbol != "" {
+			return nil, fmt.Errorf("only one symbol can be given")
+		}
+
+		matches := b.resolver.Resolve(arg)
+		if len(matches) != 1 {
+			return nil, fmt.Errorf("symbol %q is unknown or ambiguous", arg)
+		}
+		filter.Symbol = matches[0]
+	}
+
+	switch len(dates) {
+	case 0:
+	case 1:
+		filter.From = dates[0]
+	case 2:
+		filter.From, filter.To = dates[0], dates[1].AddDate(0, 0, 1)
+		if !filter.From.Before(filter.To) {
+			return nil, fmt.Errorf("the start date must not be after the end date")
+		}
+	default:
+		return nil, fmt.Errorf("at most two dates can be given")
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMoveJournalKeepsQueries(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	storage := newMemStorage()
+	from := &storageJournal{storage: storage}
+	for _, record := range []*TradeRecord{
+		{ContractID: 3, Username: "alice", Symbol: "R_50", Status: "won", PlacedAt: day.Add(2 * time.Hour)},
+		{ContractID: 1, Username: "alice", Symbol: "R_50", Status: ContractStatusOpen, PlacedAt: day.Add(time.Hour)},
+		{ContractID: 2, Username: "alice", Symbol: "R_100", Status: "lost", PlacedAt: day.Add(time.Hour)},
+		{ContractID: 4, Username: "bob", Symbol: "R_50", Status: "won", PlacedAt: day.Add(-time.Hour)},
+	} {
+		if err := from.Save(ctx, record); err != nil {
+			t.Fatalf("failed to save trade %d: %v", record.ContractID, err)
+		}
+	}
+
+	to := &storageJournal{storage: newMemStorage()}
+	moved, err := MoveJournal(ctx, storage, to)
+	if err != nil {
+		t.Fatalf("failed to move the journal: %v", err)
+	}
+	if moved != 4 {
+		t.Errorf("moved = %d, want 4", moved)
+	}
+	if keys, _ := storage.Keys(ctx, bucketTrades); len(keys) != 0 {
+		t.Errorf("store keeps trades %v after they were moved", keys)
+	}
+
+	records, err := to.Query(ctx, &JournalFilter{Username: "alice", Symbol: "R_50", From: day, To: day.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("failed to query the journal: %v", err)
+	}
+	if len(records) != 2 || records[0].ContractID != 1 || records[1].ContractID != 3 {
+		t.Errorf("R_50 trades of alice = %+v, want #1 and #3 by placement time", records)
+	}
+
+	open, err := to.Query(ctx, &JournalFilter{Open: true})
+	if err != nil {
+		t.Fatalf("failed to query open trades: %v", err)
+	}
+	if len(open) != 1 || open[0].ContractID != 1 {
+		t.Errorf("open trades = %+v, want #1", open)
+	}
+
+	usernames, err := to.Usernames(ctx)
+	if err != nil {
+		t.Fatalf("failed to list traders: %v", err)
+	}
+	if len(usernames) != 2 || usernames[0] != "alice" || usernames[1] != "bob" {
+		t.Errorf("usernames = %v, want alice and bob", usernames)
+	}
+
+	if _, err := to.Get(ctx, "bob", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("trade #1 of bob: err = %v, want ErrNotFound", err)
+	}
+}
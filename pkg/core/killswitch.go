@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// handleKillSwitch stops everything the bot does on its own and pauses trading until /resume, admin only.
+// "/killswitch sellall" also sells open contracts of all users placed through the bot.
+func (b *Bot) handleKillSwitch(ctx context.Context, msg *Message) (*Response, error) {
+	if !b.isAdmin(msg.Username) {
+		return NewResponse(msg).Text("⚠️ This command is available to admins only.").Build(), nil
+	}
+
+	sellAll := len(msg.Args) > 0 && strings.EqualFold(msg.Args[0], "sellall")
+	if len(msg.Args) > 0 && !sellAll {
+		return NewResponse(msg).Text("❌ Usage: /killswitch [sellall]").Build(), nil
+	}
+
+	// Trading is paused first, so no new position is opened while jobs are stopped
+	reason := "kill switch activated by " + msg.Username
+	if !b.watchdog.pause(reason) {
+		log.Printf("Kill switch activated by %s while trading was already paused", msg.Username)
+	}
+
+	// Strategies are marked stopped, so they aren't resumed when the bot restarts,
+	// and pending orders are removed, so they don't fire on stale conditions after /resume
+	halted := b.haltStrategies(ctx, "")
+	cleared := b.clearOrders(ctx)
+	canceled := b.background.cancelAll()
+	// Copy trading runs at Deriv, it keeps buying contracts on users' accounts until it's stopped there
+	copying, copyFailed := b.stopAllCopying(ctx)
+
+	log.Printf("Kill switch activated by %s: %d jobs canceled, %d strategies stopped, %d orders cleared, %d copy sessions stopped",
+		msg.Username, canceled, halted, cleared, copying)
+
+	if notifier := b.background.currentNotifier(); notifier != nil {
+		b.alertAdmins(ctx, notifier, fmt.Sprintf("🚨 Kill switch activated by %s. Use /resume to allow trading again.", msg.Username))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🚨 Kill switch activated\n\n")
+	sb.WriteString("Trading commands are disabled until /resume.\n")
+	fmt.Fprintf(&sb, "Background jobs canceled: %d\n", canceled)
+	fmt.Fprintf(&sb, "Strategies stopped: %d\n", halted)
+	fmt.Fprintf(&sb, "Conditional orders cleared: %d\n", cleared)
+	fmt.Fprintf(&sb, "Copy trading stopped: %d\n", copying)
+	if len(copyFailed) > 0 {
+		fmt.Fprintf(&sb, "Failed to stop copy trading of: %s\n", strings.Join(copyFailed, ", "))
+	}
+
+	if sellAll {
+		sold, failed, err := b.sellAllOpen(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&sb, "Open contracts sold: %d\n", sold)
+		if len(failed) > 0 {
+			fmt.Fprintf(&sb, "Failed to sell: %s\n", strings.Join(failed, ", "))
+		}
+		sb.WriteString("Only contracts placed through the bot are sold.")
+	}
+
+	return NewResponse(msg).Text(sb.String()).Build(), nil
+}
+
+// sellAllOpen sells open contracts of all users in the trade journal,
+// it returns the number of sold contracts and descriptions of ones that couldn't be sold
+func (b *Bot) sellAllOpen(ctx context.Context) (int, []string, error) {
+	records, err := b.journal.Query(ctx, &JournalFilter{Open: true})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load open trades: %w", err)
+	}
+
+	var sold int
+	var failed []string
+
+	for _, record := range records {
+		client, err := b.clientFor(ctx, record.Username)
+		if err != nil {
+			log.Printf("Failed to get client of %s: %v", record.Username, err)
+			failed = append(failed, fmt.Sprintf("%d (%s)", record.ContractID, record.Username))
+			continue
+		}
+
+		// Contracts may have expired since they were last settled
+		info, err := client.GetContract(ctx, record.ContractID)
+		if err == nil && info.IsSold {
+			continue
+		}
+		if err == nil && !info.IsValidToSell {
+			failed = append(failed, fmt.Sprintf("%d (%s, can't be sold before expiry)", record.ContractID, record.Username))
+			continue
+		}
+
+		if _, err := client.SellContract(ctx, record.ContractID); err != nil {
+			log.Printf("Kill switch failed to sell contract %d of %s: %v", record.ContractID, record.Username, err)
+			failed = append(failed, fmt.Sprintf("%d (%s)", record.ContractID, record.Username))
+			continue
+		}
+
+		sold++
+	}
+
+	return sold, failed, nil
+}
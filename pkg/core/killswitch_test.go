@@ -0,0 +1,93 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// copyingClient is a Deriv account that records copy sessions started and stopped on it
+type copyingClient struct {
+	DerivClient
+	copying map[string]bool
+}
+
+func (c *copyingClient) StartCopying(_ context.Context, traderToken string, _ CopyOptions) error {
+	c.copying[traderToken] = true
+	return nil
+}
+
+func (c *copyingClient) StopCopying(_ context.Context, traderToken string) error {
+	delete(c.copying, traderToken)
+	return nil
+}
+
+func (c *copyingClient) Currency() string {
+	return "USD"
+}
+
+// clientsByToken hands out a fixed client for every user token
+type clientsByToken map[string]DerivClient
+
+func (p clientsByToken) Client(_ context.Context, token string) (DerivClient, error) {
+	return p[token], nil
+}
+
+func (p clientsByToken) Release(string) error {
+	return nil
+}
+
+func TestKillSwitchStopsCopyTrading(t *testing.T) {
+	ctx := context.Background()
+
+	alice := &copyingClient{copying: make(map[string]bool)}
+	bob := &copyingClient{copying: make(map[string]bool)}
+
+	b := &Bot{
+		cfg:        &Config{UserTokens: map[string]string{"alice": "alice-token", "bob": "bob-token"}},
+		pool:       clientsByToken{"alice-token": alice, "bob-token": bob},
+		storage:    newMemStorage(),
+		admins:     map[string]struct{}{"admin": {}},
+		watchdog:   &Watchdog{},
+		strategies: strategyRunners{running: make(map[string]*strategyRunner)},
+	}
+
+	for _, start := range []struct {
+		username string
+		trader   string
+	}{
+		{username: "alice", trader: "trader1"},
+		{username: "alice", trader: "trader2"},
+		{username: "bob", trader: "trader1"},
+	} {
+		msg := &Message{Command: "copy", Args: []string{"start", start.trader}, ChatID: 1, Username: start.username}
+		if _, err := b.handleCopy(ctx, msg); err != nil {
+			t.Fatalf("/copy start %s of %s: error = %v", start.trader, start.username, err)
+		}
+	}
+
+	// A session stopped by the user is no longer the kill switch's business
+	stop := &Message{Command: "copy", Args: []string{"stop", "trader2"}, ChatID: 1, Username: "alice"}
+	if _, err := b.handleCopy(ctx, stop); err != nil {
+		t.Fatalf("/copy stop: error = %v", err)
+	}
+	alice.copying["trader2"] = true
+
+	resp, err := b.handleKillSwitch(ctx, &Message{Command: "killswitch", ChatID: 1, Username: "admin"})
+	if err != nil {
+		t.Fatalf("handleKillSwitch() error = %v", err)
+	}
+
+	if !strings.Contains(resp.Text, "Copy trading stopped: 2") {
+		t.Errorf("kill switch reply = %q, want 2 copy sessions stopped", resp.Text)
+	}
+	if alice.copying["trader1"] || bob.copying["trader1"] {
+		t.Errorf("copy sessions still running after the kill switch: alice %v, bob %v", alice.copying, bob.copying)
+	}
+	if !alice.copying["trader2"] {
+		t.Error("the kill switch stopped a session alice had already stopped")
+	}
+	if keys, _ := b.storage.Keys(ctx, bucketCopying); len(keys) != 0 {
+		t.Errorf("copy sessions of %v are still kept after they were stopped", keys)
+	}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"strings"
+)
+
+// learnContent holds explanations of contract types, one file per type named after it
+//
+//go:embed learn/*.txt
+var learnContent embed.FS
+
+// learnTopics lists contract types in the order they are offered by /learn
+var learnTopics = []string{"rise_fall", "touch", "digits", "multipliers", "accumulators"}
+
+// learnAliases maps contract type names users may type to topics
+var learnAliases = map[string]string{
+	"risefall":    "rise_fall",
+	"rise":        "rise_fall",
+	"fall":        "rise_fall",
+	"updown":      "rise_fall",
+	"call":        "rise_fall",
+	"put":         "rise_fall",
+	"notouch":     "touch",
+	"no_touch":    "touch",
+	"onetouch":    "touch",
+	"digit":       "digits",
+	"matches":     "digits",
+	"differs":     "digits",
+	"evenodd":     "digits",
+	"overunder":   "digits",
+	"multiplier":  "multipliers",
+	"mult":        "multipliers",
+	"accumulator": "accumulators",
+	"accu":        "accumulators",
+}
+
+// learnTopic returns the title and text of a topic
+func learnTopic(topic string) (title, text string, err error) {
+	data, err := learnContent.ReadFile("learn/" + topic + ".txt")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read topic %s: %w", topic, err)
+	}
+
+	title, text, _ = strings.Cut(strings.TrimSpace(string(data)), "\n")
+
+	return title, text, nil
+}
+
+// resolveLearnTopic returns the topic matching user input, or an empty string when there's none
+func resolveLearnTopic(input string) string {
+	key := strings.ToLower(strings.NewReplacer("-", "_", "/", "").Replace(input))
+	if alias, ok := learnAliases[key]; ok {
+		return alias
+	}
+
+	for _, topic := range learnTopics {
+		if key == topic {
+			return topic
+		}
+	}
+
+	return ""
+}
+
+// handleLearn explains contract types, "/learn <type> <question>" lets the LLM answer a question about it
+func (b *Bot) handleLearn(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) == 0 {
+		var buttons [][]Button
+		for _, topic := range learnTopics {
+			title, _, err := learnTopic(topic)
+			if err != nil {
+				return nil, err
+			}
+			buttons = append(buttons, []Button{{Text: title, CallbackData: "learn:" + topic}})
+		}
+		return NewResponse(msg).Text("📚 Which contract type would you like to learn about?").Keyboard(buttons).Build(), nil
+	}
+
+	topic := resolveLearnTopic(msg.Args[0])
+	if topic == "" {
+		return NewResponse(msg).Textf("❌ Unknown contract type. Available: %s", strings.Join(learnTopics, ", ")).Build(), nil
+	}
+
+	title, text, err := learnTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	question := strings.Join(msg.Args[1:], " ")
+	if question == "" {
+		return NewResponse(msg).Textf("%s\n\n%s\n\nAsk a follow-up question with /learn %s <question>", title, text, topic).Build(), nil
+	}
+
+	answer, err := b.llmClient.ProcessText(ctx, learnPrompt(title, text, question))
+	if err != nil {
+		return nil, fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	return NewResponse(msg).Textf("%s\n\n%s", title, answer).Build(), nil
+}
+
+// learnPrompt asks the LLM to answer a question grounded in the topic explanation
+func learnPrompt(title, text, question string) string {
+	var sb strings.Builder
+
+	sb.WriteString("You are teaching a beginner trader on Deriv how a contract type works. ")
+	sb.WriteString("Answer the question using the reference below, add a short numeric example if it helps. ")
+	sb.WriteString("Do not give trading advice or predict prices. Keep it under 150 words and don't use Markdown.\n\n")
+	fmt.Fprintf(&sb, "Reference: %s\n%s\n\n", title, text)
+	fmt.Fprintf(&sb, "Question: %s\n", question)
+
+	return sb.String()
+}
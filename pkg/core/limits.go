@@ -0,0 +1,358 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Limit order arguments of /buy and /mult, e.g. sl=5 tp=10
+const (
+	stopLossArgPrefix   = "sl="
+	takeProfitArgPrefix = "tp="
+)
+
+// TradeLimits close a contract once its loss or profit reaches an amount, zero values are not set
+type TradeLimits struct {
+	StopLoss   float64
+	TakeProfit float64
+	Trail      float64 // Retrace of the profit from its peak that closes the contract
+}
+
+// IsSet reports whether any limit is set
+func (l TradeLimits) IsSet() bool {
+	return l.StopLoss > 0 || l.TakeProfit > 0 || l.Trail > 0
+}
+
+// args returns the limits as command arguments
+func (l TradeLimits) args() []string {
+	var args []string
+	if l.StopLoss > 0 {
+		args = append(args, stopLossArgPrefix+strconv.FormatFloat(l.StopLoss, 'f', -1, 64))
+	}
+	if l.TakeProfit > 0 {
+		args = append(args, takeProfitArgPrefix+strconv.FormatFloat(l.TakeProfit, 'f', -1, 64))
+	}
+	return args
+}
+
+// limitWatch is a running watch of limits of an open contract
+type limitWatch struct {
+	limits TradeLimits
+	cancel context.CancelFunc
+}
+
+// limitWatches holds limits watched on open contracts by contract ID, a contract has one watch at a time
+type limitWatches struct {
+	mu      sync.Mutex
+	running map[int64]*limitWatch
+}
+
+// replace registers the watch of the contract and stops the one it replaces
+func (w *limitWatches) replace(contractID int64, watch *limitWatch) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if previous, ok := w.running[contractID]; ok {
+		previous.cancel()
+	}
+	w.running[contractID] = watch
+}
+
+// remove forgets the watch of the contract unless it was replaced already
+func (w *limitWatches) remove(contractID int64, watch *limitWatch) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running[contractID] == watch {
+		delete(w.running, contractID)
+	}
+}
+
+// stop stops the watch of the contract, if any
+func (w *limitWatches) stop(contractID int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if watch, ok := w.running[contractID]; ok {
+		watch.cancel()
+		delete(w.running, contractID)
+	}
+}
+
+// current returns limits watched on the contract, zero when there is no watch
+func (w *limitWatches) current(contractID int64) TradeLimits {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if watch, ok := w.running[contractID]; ok {
+		return watch.limits
+	}
+	return TradeLimits{}
+}
+
+// reached returns the name of the limit the profit of a contract has reached, or an empty string.
+// The peak is the highest profit seen while the limits are watched.
+func (l TradeLimits) reached(profit, peak float64) string {
+	switch {
+	case l.StopLoss > 0 && -profit >= l.StopLoss:
+		return "Stop-loss"
+	case l.TakeProfit > 0 && profit >= l.TakeProfit:
+		return "Take-profit"
+	case l.Trail > 0 && peak-profit >= l.Trail:
+		return "Trailing stop"
+	default:
+		return ""
+	}
+}
+
+// splitLimits separates sl= and tp= arguments from the rest
+func splitLimits(args []string) (rest []string, limits TradeLimits, err error) {
+	for _, arg := range args {
+		lower := strings.ToLower(arg)
+
+		var value *float64
+		switch {
+		case strings.HasPrefix(lower, stopLossArgPrefix):
+			value = &limits.StopLoss
+		case strings.HasPrefix(lower, takeProfitArgPrefix):
+			value = &limits.TakeProfit
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+
+		amount, err := strconv.ParseFloat(lower[len(stopLossArgPrefix):], 64)
+		if err != nil || amount <= 0 {
+			return nil, TradeLimits{}, fmt.Errorf("invalid limit %q", arg)
+		}
+		*value = amount
+	}
+
+	return rest, limits, nil
+}
+
+// writeLimits adds the limits of a contract to a receipt
+func writeLimits(sb *strings.Builder, f Formatter, limits TradeLimits, currency string) {
+	if limits.StopLoss > 0 {
+		fmt.Fprintf(sb, "Stop-loss: %s\n", f.Money(limits.StopLoss, currency))
+	}
+	if limits.TakeProfit > 0 {
+		fmt.Fprintf(sb, "Take-profit: %s\n", f.Money(limits.TakeProfit, currency))
+	}
+	if limits.Trail > 0 {
+		fmt.Fprintf(sb, "Trailing stop: %s below the peak profit\n", f.Money(limits.Trail, currency))
+	}
+}
+
+// watchLimits sells the contract once its profit reaches one of the limits, for contract types without native
+// limit orders. Limits are enforced while the bot runs, the contract runs to expiry when the bot is stopped.
+func (b *Bot) watchLimits(ctx context.Context, msg *Message, client DerivClient, result *TradeResult, limits TradeLimits) {
+	if !limits.IsSet() {
+		return
+	}
+
+	username := msg.Username
+	chatID := msg.ChatID
+	f := b.formatter(ctx, msg)
+
+	err := b.background.Go(func(ctx context.Context, notifier Notifier) {
+		notifier = b.userNotifier(ctx, notifier, username)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// Limits changed with /tp replace the watch
+		watch := &limitWatch{limits: limits, cancel: cancel}
+		b.limitWatches.replace(result.ContractID, watch)
+		defer b.limitWatches.remove(result.ContractID, watch)
+
+		updates, err := client.WatchContract(ctx, result.ContractID)
+		if err != nil {
+			log.Printf("Failed to watch limits of contract %d: %v", result.ContractID, err)
+			return
+		}
+
+		peak := math.Inf(-1)
+		for info := range updates {
+			if info.IsSold || info.Status != ContractStatusOpen {
+				return
+			}
+
+			peak = math.Max(peak, info.Profit)
+
+			limit := limits.reached(info.Profit, peak)
+			if limit == "" || !info.IsValidToSell {
+				continue
+			}
+
+			// Selling is a trading action of the user, it mustn't interleave with the user's commands
+			unlock := b.trading.lock(username)
+			sold, err := client.SellContract(ctx, result.ContractID)
+			unlock()
+
+			if err != nil {
+				// The contract may have settled meanwhile, the next update tells
+				log.Printf("Failed to sell contract %d at its %s: %v", result.ContractID, strings.ToLower(limit), err)
+				continue
+			}
+
+			if _, err := b.settleUser(ctx, username); err != nil {
+				log.Printf("Failed to settle trades of %s: %v", username, err)
+			}
+
+			profit := sold.SoldFor - result.BuyPrice
+			text := fmt.Sprintf("🛑 %s reached, contract %d on %s sold for %s (%s %s)", limit, result.ContractID, info.Symbol,
+				f.Money(sold.SoldFor, result.Currency), f.ProfitIcon(profit), f.SignedMoney(profit, result.Currency))
+			if limit == "Trailing stop" {
+				text += fmt.Sprintf("\nThe profit retraced %s from its peak of %s.",
+					f.Money(peak-info.Profit, result.Currency), f.SignedMoney(peak, result.Currency))
+			}
+
+			b.notify(ctx, notifier, username, NotifySettlements, NewNotification(chatID).Text(text).Build())
+
+			return
+		}
+	})
+	if err != nil {
+		log.Printf("Failed to watch limits of contract %d: %v", result.ContractID, err)
+	}
+}
+
+// handleTakeProfit sets or removes the take-profit of an open contract placed by the bot,
+// "/tp <contract_id> <amount|percent%|off>", a percentage is of the stake
+func (b *Bot) handleTakeProfit(ctx context.Context, msg *Message) (*Response, error) {
+	usage := NewResponse(msg).Text("❌ Usage: /tp <contract_id> <amount|percent%|off>\nExample: /tp 123456 50%").Build()
+	if len(msg.Args) != 2 {
+		return usage, nil
+	}
+
+	contractID, err := strconv.ParseInt(msg.Args[0], 10, 64)
+	if err != nil {
+		return usage, nil
+	}
+
+	record, reply, err := b.openRecord(ctx, msg, contractID)
+	if err != nil || reply != nil {
+		return reply, err
+	}
+
+	limits := b.limitWatches.current(contractID)
+
+	arg := strings.ToLower(msg.Args[1])
+	if arg == "off" {
+		limits.TakeProfit = 0
+	} else if limits.TakeProfit, err = parseTakeProfit(arg, record.Stake); err != nil {
+		return usage, nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !limits.IsSet() {
+		b.limitWatches.stop(contractID)
+		return NewResponse(msg).Textf("✅ Take-profit of contract %d removed.", contractID).Build(), nil
+	}
+
+	currency := client.Currency()
+	b.watchLimits(ctx, msg, client, &TradeResult{ContractID: contractID, BuyPrice: record.Stake, Currency: currency}, limits)
+
+	if limits.TakeProfit == 0 {
+		return NewResponse(msg).Textf("✅ Take-profit of contract %d removed, its stop-loss stays.", contractID).Build(), nil
+	}
+
+	f := b.formatter(ctx, msg)
+
+	return NewResponse(msg).Textf("🎯 Contract %d is sold once its profit reaches %s. The take-profit is enforced while the bot runs.",
+		contractID, f.SignedMoney(limits.TakeProfit, currency)).Build(), nil
+}
+
+// parseTakeProfit reads a take-profit as an amount or a percentage of the stake, e.g. 5 or 50%
+func parseTakeProfit(arg string, stake float64) (float64, error) {
+	percent, isPercent := strings.CutSuffix(arg, "%")
+
+	value, err := strconv.ParseFloat(percent, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid take-profit %q", arg)
+	}
+
+	if isPercent {
+		return stake * value / 100, nil
+	}
+
+	return value, nil
+}
+
+// openRecord loads an open contract the user placed through the bot, a reply is returned instead when there's none
+func (b *Bot) openRecord(ctx context.Context, msg *Message, contractID int64) (*TradeRecord, *Response, error) {
+	record, err := b.journal.Get(ctx, msg.Username, contractID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, NewResponse(msg).Textf("❌ Contract %d wasn't placed through the bot.", contractID).Build(), nil
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to load trade: %w", err)
+	}
+
+	if record.Settled() {
+		return nil, NewResponse(msg).Textf("ℹ️ Contract %d has already settled.", contractID).Build(), nil
+	}
+
+	return record, nil, nil
+}
+
+// handleTrail sets or removes a trailing stop of an open contract placed by the bot, "/trail <contract_id> <distance|off>".
+// The contract is sold once its profit falls by the distance from the highest profit seen since.
+func (b *Bot) handleTrail(ctx context.Context, msg *Message) (*Response, error) {
+	usage := NewResponse(msg).Text("❌ Usage: /trail <contract_id> <distance|off>\nExample: /trail 123456 2").Build()
+	if len(msg.Args) != 2 {
+		return usage, nil
+	}
+
+	contractID, err := strconv.ParseInt(msg.Args[0], 10, 64)
+	if err != nil {
+		return usage, nil
+	}
+
+	record, reply, err := b.openRecord(ctx, msg, contractID)
+	if err != nil || reply != nil {
+		return reply, err
+	}
+
+	limits := b.limitWatches.current(contractID)
+
+	arg := strings.ToLower(msg.Args[1])
+	if arg == "off" {
+		limits.Trail = 0
+	} else if limits.Trail, err = strconv.ParseFloat(arg, 64); err != nil || limits.Trail <= 0 {
+		return usage, nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !limits.IsSet() {
+		b.limitWatches.stop(contractID)
+		return NewResponse(msg).Textf("✅ Trailing stop of contract %d removed.", contractID).Build(), nil
+	}
+
+	// Replacing the watch starts tracking the peak anew, so the distance applies from the current profit
+	currency := client.Currency()
+	b.watchLimits(ctx, msg, client, &TradeResult{ContractID: contractID, BuyPrice: record.Stake, Currency: currency}, limits)
+
+	if limits.Trail == 0 {
+		return NewResponse(msg).Textf("✅ Trailing stop of contract %d removed, its other limits stay.", contractID).Build(), nil
+	}
+
+	f := b.formatter(ctx, msg)
+
+	return NewResponse(msg).Textf("🪜 Contract %d is sold once its profit falls %s below its peak. The trailing stop is enforced while the bot runs.",
+		contractID, f.Money(limits.Trail, currency)).Build(), nil
+}
@@ -1,6 +1,38 @@
 package core
 
-import "context"
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// noTradeProposalsInstruction keeps answers to market analysis while LLM trade proposals are disabled
+const noTradeProposalsInstruction = "\n\nDescribe the market data only. Don't propose trades: no directions, " +
+	"entries, stakes, durations, stop-losses or take-profits to act on."
+
+// tradeProposalPattern matches lines of an answer telling the user to place a trade
+var tradeProposalPattern = regexp.MustCompile(`(?i)(\b(buy|sell|go long|go short|open a (long|short)?\s*position|` +
+	`place a trade|stake|entry point|stop[- ]loss|take[- ]profit)\b|/(buy|mult|touch|digit|basket|order)\b)`)
+
+// withoutTradeProposals drops lines of an LLM answer proposing trades, the model isn't trusted to leave them out
+// just because it was told to. The answer is returned unchanged when it proposes nothing
+func withoutTradeProposals(answer string) (string, bool) {
+	var kept []string
+	var dropped bool
+	for _, line := range strings.Split(answer, "\n") {
+		if tradeProposalPattern.MatchString(line) {
+			dropped = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	if !dropped {
+		return answer, false
+	}
+
+	return strings.TrimSpace(strings.Join(kept, "\n")), true
+}
 
 // Available functions for LLM
 var MarketDataFunctions = []LLMFunction{
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Rough token accounting of free-form questions answered with market data tools
+const (
+	llmCharsPerToken   = 4   // Characters of English text per token
+	llmPromptTokens    = 600 // System prompt and tool descriptions
+	llmTokensPerPoint  = 15  // A price point with its timestamp as returned by a tool
+	llmDefaultPoints   = 10  // Points fetched when the question gives no count
+	llmMaxPoints       = 1000
+	llmAgentIterations = 3 // Each agent step sends the conversation so far again
+)
+
+// llmQueryCommand confirms or cancels a heavy question, its buttons call it
+const llmQueryCommand = "llmquery"
+
+// LLMCostConfig holds settings of cost previews of heavy free-form questions
+type LLMCostConfig struct {
+	ConfirmAbove    int     `mapstructure:"confirm_above"`     // Estimated tokens above which users confirm the question, 0 disables it
+	PricePerMillion float64 `mapstructure:"price_per_million"` // USD per million tokens, shows estimates in dollars when set
+}
+
+// pendingQueries holds heavy questions waiting for confirmation by username
+type pendingQueries struct {
+	mu      sync.Mutex
+	queries map[string]string
+}
+
+// put stores the question of the user, replacing an earlier one
+func (p *pendingQueries) put(username, text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.queries[username] = text
+}
+
+// take removes and returns the question of the user
+func (p *pendingQueries) take(username string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	text, ok := p.queries[username]
+	delete(p.queries, username)
+
+	return text, ok
+}
+
+// estimateLLMTokens estimates tokens a free-form question uses: its text, the prompt, and price history fetched
+// for every mentioned symbol, sent again on each agent step
+func (b *Bot) estimateLLMTokens(text string) int {
+	symbols := 0
+	points := llmDefaultPoints
+
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !(r == '_' || r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z')
+	}) {
+		if b.isKnownSymbol(strings.ToUpper(word)) {
+			symbols++
+			continue
+		}
+
+		// The largest number is taken as the history window, e.g. "last 500 candles"
+		if n, err := strconv.Atoi(word); err == nil && n > points {
+			points = min(n, llmMaxPoints)
+		}
+	}
+
+	symbols = max(symbols, 1)
+
+	return (llmPromptTokens + len(text)/llmCharsPerToken + symbols*points*llmTokensPerPoint) * llmAgentIterations
+}
+
+// isKnownSymbol reports whether the symbol is one of the configured ones
+func (b *Bot) isKnownSymbol(symbol string) bool {
+	for _, s := range b.symbols {
+		if strings.EqualFold(s, symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// llmCostPrompt asks to confirm a question estimated to use more tokens than configured, or returns nil
+func (b *Bot) llmCostPrompt(msg *Message, text string) *Response {
+	limit := b.cfg.LLMCost.ConfirmAbove
+	if limit <= 0 {
+		return nil
+	}
+
+	tokens := b.estimateLLMTokens(text)
+	if tokens <= limit {
+		return nil
+	}
+
+	b.pendingQueries.put(msg.Username, text)
+
+	estimate := fmt.Sprintf("about %d tokens", tokens)
+	if price := b.cfg.LLMCost.PricePerMillion; price > 0 {
+		estimate += fmt.Sprintf(" (~$%.2f)", float64(tokens)*price/1_000_000)
+	}
+
+	return NewResponse(msg).
+		Textf("💰 This analysis will use %s, above the limit of %d for running without confirmation.\n"+
+			"Narrow the history window or the number of symbols to make it cheaper. Run it anyway?", estimate, limit).
+		Keyboard([][]Button{{
+			{Text: "✅ Run", CallbackData: llmQueryCommand + ":run"},
+			{Text: "✖️ Cancel", CallbackData: llmQueryCommand + ":cancel"},
+		}}).
+		Build()
+}
+
+// handleLLMQuery runs or drops the question waiting for confirmation, "/llmquery run|cancel"
+func (b *Bot) handleLLMQuery(ctx context.Context, msg *Message) (*Response, error) {
+	text, ok := b.pendingQueries.take(msg.Username)
+	if !ok {
+		return NewResponse(msg).Text("ℹ️ No question is waiting for confirmation.").Build(), nil
+	}
+
+	if len(msg.Args) == 0 || msg.Args[0] != "run" {
+		return NewResponse(msg).Text("✖️ Canceled.").Build(), nil
+	}
+
+	return b.answerText(ctx, msg, text)
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// MacroConfig defines a quick command running several other commands in order
+type MacroConfig struct {
+	Description string   `mapstructure:"description"` // Shown in /help
+	Commands    []string `mapstructure:"commands"`    // Commands with arguments, e.g. "/price R_50"
+}
+
+// macroStep is a single command of a macro
+type macroStep struct {
+	command string
+	args    []string
+}
+
+// String returns the step as it's written in the config
+func (s macroStep) String() string {
+	return strings.TrimSpace("/" + s.command + " " + strings.Join(s.args, " "))
+}
+
+// parseMacro parses commands of a macro into steps
+func parseMacro(name string, cfg MacroConfig) ([]macroStep, error) {
+	if len(cfg.Commands) == 0 {
+		return nil, fmt.Errorf("macro %s has no commands", name)
+	}
+
+	steps := make([]macroStep, 0, len(cfg.Commands))
+	for _, line := range cfg.Commands {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") || len(fields[0]) == 1 {
+			return nil, fmt.Errorf("macro %s: invalid command %q, commands start with /", name, line)
+		}
+
+		steps = append(steps, macroStep{
+			command: strings.ToLower(strings.TrimPrefix(fields[0], "/")),
+			args:    fields[1:],
+		})
+	}
+
+	return steps, nil
+}
+
+// registerMacros adds configured macros as commands, they can't override built-in commands
+func (b *Bot) registerMacros(macros map[string]MacroConfig) error {
+	for name, cfg := range macros {
+		name = strings.ToLower(name)
+		if _, exists := b.commandHandlers[name]; exists {
+			return fmt.Errorf("macro %s conflicts with a built-in command", name)
+		}
+
+		steps, err := parseMacro(name, cfg)
+		if err != nil {
+			return err
+		}
+
+		b.commandHandlers[name] = b.macroHandler(steps)
+		b.macros[name] = cfg.Description
+	}
+
+	return nil
+}
+
+// macroHandler returns a handler running the steps one by one, each step replies with its own message
+func (b *Bot) macroHandler(steps []macroStep) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		// Steps are checked before running any, so a macro is never executed halfway
+		for _, step := range steps {
+			if _, isMacro := b.macros[step.command]; isMacro {
+				return nil, fmt.Errorf("macro step %s can't run another macro", step)
+			}
+			if _, exists := b.commandHandlers[step.command]; !exists {
+				return nil, fmt.Errorf("macro step %s refers to an unknown command", step)
+			}
+		}
+
+		err := b.background.Go(func(bgCtx context.Context, notifier Notifier) {
+			for _, step := range steps {
+				if bgCtx.Err() != nil {
+					return
+				}
+
+				resp := b.runMacroStep(bgCtx, msg, step)
+				if resp == nil {
+					continue
+				}
+
+				if _, err := notifier.Send(bgCtx, resp); err != nil {
+					log.Printf("Failed to send result of macro step %s to %s: %v", step, msg.Username, err)
+				}
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to run macro: %w", err)
+		}
+
+		return nil, nil
+	}
+}
+
+// runMacroStep runs a step with the same middlewares as a command sent by the user
+func (b *Bot) runMacroStep(ctx context.Context, msg *Message, step macroStep) *Response {
+	stepMsg := *msg
+	stepMsg.Command = step.command
+	stepMsg.Args = step.args
+	stepMsg.CallbackData = ""
+
+	handler := b.commandHandlers[step.command]
+
+	resp, err := chain(handler, b.middlewares...)(ctx, &stepMsg)
+	if err != nil {
+		log.Printf("Macro step %s from %s failed: %v", step, msg.Username, err)
+
+		return NewResponse(msg).Textf("❌ %s failed, please try it on its own.", step).Build()
+	}
+
+	return resp
+}
+
+// macroHelp lists configured macros for /help
+func (b *Bot) macroHelp() string {
+	if len(b.macros) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(b.macros))
+	for name := range b.macros {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	text := "\n\nQuick commands:\n"
+	for _, name := range names {
+		text += fmt.Sprintf("\n/%s - %s", name, b.macros[name])
+	}
+
+	return text
+}
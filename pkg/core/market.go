@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/kirill/deriv-teletrader/pkg/types"
 )
@@ -16,6 +17,79 @@ type MarketDataProvider interface {
 	GetAvailableSymbols(ctx context.Context) ([]string, error)
 }
 
+// getPrice retrieves current price for a symbol, identical concurrent requests share one upstream call
+func (b *Bot) getPrice(ctx context.Context, symbol string) (float64, error) {
+	return b.prices.Do(ctx, symbol, func(ctx context.Context) (float64, error) {
+		return b.derivClient.GetPrice(ctx, symbol)
+	})
+}
+
+// DayStats summarizes price movement over the last 24 hours
+type DayStats struct {
+	Open      float64
+	High      float64
+	Low       float64
+	Change    float64 // Current price minus the open
+	ChangePct float64
+}
+
+// chartCandlesRequest requests minute candles of the last hour, as drawn on price charts
+func chartCandlesRequest(symbol string) HistoricalDataRequest {
+	return HistoricalDataRequest{
+		Symbol:   symbol,
+		Interval: IntervalHour,
+		Style:    StyleCandles,
+		Count:    60, // 1 minute candles for the last hour
+	}
+}
+
+// dayCandlesRequest requests hourly candles covering the last 24 hours
+func dayCandlesRequest(symbol string) HistoricalDataRequest {
+	return HistoricalDataRequest{
+		Symbol:      symbol,
+		Interval:    IntervalDay,
+		Style:       StyleCandles,
+		Count:       24,
+		Granularity: 3600,
+	}
+}
+
+// dayStats computes 24h statistics from hourly candles and the current price
+func dayStats(candles []HistoricalDataPoint, price float64) (*DayStats, bool) {
+	if len(candles) == 0 {
+		return nil, false
+	}
+
+	stats := &DayStats{
+		Open: candles[0].Open,
+		High: price,
+		Low:  price,
+	}
+
+	for _, candle := range candles {
+		stats.High = max(stats.High, candle.High)
+		stats.Low = min(stats.Low, candle.Low)
+	}
+
+	stats.Change = price - stats.Open
+	if stats.Open != 0 {
+		stats.ChangePct = stats.Change / stats.Open * 100
+	}
+
+	return stats, true
+}
+
+// formatDayStats renders 24h statistics as a compact card
+func formatDayStats(f Formatter, stats *DayStats) string {
+	arrow := "▲"
+	if stats.Change < 0 {
+		arrow = "▼"
+	}
+
+	return fmt.Sprintf("24h: %s %s (%s)\nOpen %s · High %s · Low %s",
+		arrow, f.Signed(stats.Change, 2), f.Percent(stats.ChangePct, 2), f.Number(stats.Open, 2), f.Number(stats.High, 2), f.Number(stats.Low, 2))
+}
+
 // Re-export types for backward compatibility
 type (
 	TimeInterval          = types.TimeInterval
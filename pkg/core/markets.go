@@ -0,0 +1,210 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/chart"
+)
+
+// activeSymbolsTTL is how long the list of symbols offered by Deriv is cached
+const activeSymbolsTTL = 10 * time.Minute
+
+// maxMarketSymbols limits the number of symbol buttons shown for a market
+const maxMarketSymbols = 60
+
+// browserStakes are stakes offered by the Trade action of the symbol browser
+var browserStakes = []string{"1", "5", "10", "25"}
+
+// SymbolInfo describes a symbol offered by Deriv
+type SymbolInfo struct {
+	Symbol        string
+	DisplayName   string
+	Market        string // Market code, e.g. synthetic_index or forex
+	MarketName    string
+	SubmarketName string
+	IsOpen        bool
+}
+
+// activeSymbols returns symbols offered by Deriv, cached for a while since they rarely change
+func (b *Bot) activeSymbols(ctx context.Context) ([]SymbolInfo, error) {
+	return b.active.Do(ctx, "", func(ctx context.Context) ([]SymbolInfo, error) {
+		return b.derivClient.GetActiveSymbols(ctx)
+	})
+}
+
+// handleMarkets lets users browse symbols by market with inline buttons:
+// "/markets" lists markets, "markets:<market>" lists its symbols,
+// "markets:<market>:<symbol>" shows actions and "markets:<market>:<symbol>:trade" offers stakes
+func (b *Bot) handleMarkets(ctx context.Context, msg *Message) (*Response, error) {
+	symbols, err := b.activeSymbols(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active symbols: %w", err)
+	}
+
+	rb := NewResponse(msg)
+
+	// Navigating with buttons updates the same message
+	if msg.CallbackData != "" {
+		rb.Edit(msg.MessageID)
+	}
+
+	switch len(msg.Args) {
+	case 0:
+		marketsMenu(rb, symbols, msg.LanguageCode)
+	case 1:
+		marketMenu(rb, symbols, msg.Args[0], msg.LanguageCode)
+	default:
+		var info *SymbolInfo
+		for i := range symbols {
+			if symbols[i].Market == msg.Args[0] && symbols[i].Symbol == msg.Args[1] {
+				info = &symbols[i]
+				break
+			}
+		}
+
+		if info == nil {
+			return rb.Text("❌ This symbol is no longer offered. Use /markets to browse again.").Build(), nil
+		}
+
+		if len(msg.Args) > 2 && msg.Args[2] == "trade" {
+			stakeMenu(rb, info)
+		} else {
+			symbolMenu(rb, info, msg.LanguageCode)
+		}
+	}
+
+	return rb.Build(), nil
+}
+
+// marketsMenu lists markets with at least one symbol, their names are shown in the user's language
+func marketsMenu(rb *ResponseBuilder, symbols []SymbolInfo, lang string) {
+	names := make(map[string]string)
+	for _, s := range symbols {
+		names[s.Market] = marketName(lang, s.Market, s.MarketName)
+	}
+
+	markets := make([]string, 0, len(names))
+	for market := range names {
+		markets = append(markets, market)
+	}
+	sort.Slice(markets, func(i, j int) bool {
+		return names[markets[i]] < names[markets[j]]
+	})
+
+	var buttons [][]Button
+	for i, market := range markets {
+		if i%2 == 0 {
+			buttons = append(buttons, nil)
+		}
+		row := len(buttons) - 1
+		buttons[row] = append(buttons[row], Button{Text: names[market], CallbackData: "markets:" + market})
+	}
+
+	rb.Text("🗂 Choose a market:").Keyboard(buttons)
+}
+
+// marketMenu lists symbols of the market
+func marketMenu(rb *ResponseBuilder, symbols []SymbolInfo, market, lang string) {
+	var found []SymbolInfo
+	for _, s := range symbols {
+		if s.Market == market {
+			found = append(found, s)
+		}
+	}
+
+	if len(found) == 0 {
+		rb.Text("❌ This market is no longer offered. Use /markets to browse again.")
+		return
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].DisplayName < found[j].DisplayName
+	})
+
+	text := fmt.Sprintf("🗂 %s: choose a symbol", marketName(lang, market, found[0].MarketName))
+	if len(found) > maxMarketSymbols {
+		text += fmt.Sprintf(" (first %d of %d shown)", maxMarketSymbols, len(found))
+		found = found[:maxMarketSymbols]
+	}
+
+	var buttons [][]Button
+	for i, s := range found {
+		if i%2 == 0 {
+			buttons = append(buttons, nil)
+		}
+
+		label := fmt.Sprintf("%s (%s)", s.DisplayName, s.Symbol)
+		if !s.IsOpen {
+			label += " 🔒"
+		}
+
+		row := len(buttons) - 1
+		buttons[row] = append(buttons[row], Button{Text: label, CallbackData: fmt.Sprintf("markets:%s:%s", market, s.Symbol)})
+	}
+
+	buttons = append(buttons, []Button{{Text: "⬅️ Markets", CallbackData: "markets"}})
+	rb.Text(text).Keyboard(buttons)
+}
+
+// symbolMenu shows actions available for the symbol
+func symbolMenu(rb *ResponseBuilder, info *SymbolInfo, lang string) {
+	state := "🟢 Open"
+	if !info.IsOpen {
+		state = "🔒 Closed"
+	}
+
+	market := marketName(lang, info.Market, info.MarketName)
+	rb.Textf("%s (%s)\n%s · %s\n%s", info.DisplayName, info.Symbol, market, info.SubmarketName, state)
+	rb.Keyboard([][]Button{
+		{
+			{Text: "💹 Price", CallbackData: "price:" + info.Symbol},
+			{Text: "📈 Chart", CallbackData: "chart:" + info.Symbol},
+			{Text: "🎯 Trade", CallbackData: fmt.Sprintf("markets:%s:%s:trade", info.Market, info.Symbol)},
+		},
+		{{Text: "⬅️ " + market, CallbackData: "markets:" + info.Market}},
+	})
+}
+
+// stakeMenu offers stakes for trading the symbol
+func stakeMenu(rb *ResponseBuilder, info *SymbolInfo) {
+	rb.Textf("🎯 Trade %s: choose a stake", info.DisplayName)
+
+	var row []Button
+	for _, stake := range browserStakes {
+		row = append(row, Button{Text: "$" + stake, CallbackData: fmt.Sprintf("buy:%s:%s", info.Symbol, stake)})
+	}
+
+	rb.Keyboard([][]Button{
+		row,
+		{{Text: "⬅️ " + info.DisplayName, CallbackData: fmt.Sprintf("markets:%s:%s", info.Market, info.Symbol)}},
+	})
+}
+
+// handleChart sends a price chart of the symbol for the last hour
+func (b *Bot) handleChart(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) < 1 {
+		return NewResponse(msg).Text("❌ Please provide a symbol. Example: /chart R_50").Build(), nil
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "chart:" + symbol
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	data, err := b.cachedCandles(ctx, chartCandlesRequest(symbol))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get historical data: %w", err)
+	}
+
+	chartPath, err := chart.GeneratePriceChart(data, symbol, b.chartLevels(ctx, msg.Username, symbol), b.chartWatermark(msg.Username))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate chart: %w", err)
+	}
+
+	return NewResponse(msg).Textf("📈 %s, last hour", symbol).Photo(chartPath).Build(), nil
+}
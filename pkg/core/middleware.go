@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// Middleware wraps a command handler with cross-cutting behavior
+type Middleware func(next CommandHandler) CommandHandler
+
+// chain applies middlewares to the handler, the first middleware is the outermost one
+func chain(handler CommandHandler, middlewares ...Middleware) CommandHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// commandTimeout limits the time a handler may run, so a hung upstream call can't stall message processing
+// or keep the trade lock of a user. Commands placing or selling contracts get a longer deadline, and as a
+// purchase may already be executed when it's up, the user is told to check before buying again.
+func (b *Bot) commandTimeout(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		timeout := b.timeoutFor(msg.Command)
+		if timeout <= 0 {
+			return next(ctx, msg)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		type result struct {
+			resp *Response
+			err  error
+		}
+
+		done := make(chan result, 1)
+		go func() {
+			resp, err := next(ctx, msg)
+			done <- result{resp: resp, err: err}
+		}()
+
+		select {
+		case res := <-done:
+			return res.resp, res.err
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ctx.Err()
+			}
+
+			log.Printf("Command %q from %s timed out after %s", commandName(msg), msg.Username, timeout)
+
+			if serializedCommands[msg.Command] {
+				return NewResponse(msg).Text("⏱ Deriv took too long to confirm the trade, it may still have gone through. " +
+					"Check /portfolio before trying again.").Build(), nil
+			}
+
+			return NewResponse(msg).Text("⏱ Sorry, this took too long to complete. Please try again later.").Build(), nil
+		}
+	}
+}
+
+// timeoutFor returns the timeout configured for the command
+func (b *Bot) timeoutFor(command string) time.Duration {
+	// Confirmed questions are answered like free-form text
+	if command == "" || command == llmQueryCommand {
+		command = textCommand
+	}
+
+	if timeout, ok := b.cfg.CommandTimeouts[command]; ok {
+		return timeout
+	}
+
+	if serializedCommands[command] && b.cfg.TradeTimeout > 0 {
+		return b.cfg.TradeTimeout
+	}
+
+	return b.cfg.CommandTimeout
+}
+
+// commandName returns the command name used in logs
+func commandName(msg *Message) string {
+	if msg.Command == "" {
+		return textCommand
+	}
+	return msg.Command
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHungTradeReleasesTradeLock(t *testing.T) {
+	b := &Bot{
+		cfg:     &Config{CommandTimeout: time.Second, TradeTimeout: 50 * time.Millisecond},
+		trading: userLocks{locks: make(map[string]*sync.Mutex)},
+	}
+
+	if got := b.timeoutFor("buy"); got != 50*time.Millisecond {
+		t.Errorf("timeout of /buy = %s, want the trade timeout", got)
+	}
+	if got := b.timeoutFor("price"); got != time.Second {
+		t.Errorf("timeout of /price = %s, want the command timeout", got)
+	}
+
+	hung := true
+	handler := chain(func(ctx context.Context, msg *Message) (*Response, error) {
+		if hung {
+			hung = false
+			// A Deriv call that never answers returns once its context is done
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return NewResponse(msg).Text("✅ Bought").Build(), nil
+	}, b.commandTimeout, b.serializeTrades)
+
+	msg := &Message{Command: "buy", Username: "alice"}
+
+	resp, err := handler(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("hung trade: error = %v", err)
+	}
+	if !strings.Contains(resp.Text, "may still have gone through") {
+		t.Errorf("hung trade reply = %q, want a warning that it may have been placed", resp.Text)
+	}
+
+	done := make(chan *Response, 1)
+	go func() {
+		resp, _ := handler(context.Background(), msg)
+		done <- resp
+	}()
+
+	select {
+	case resp := <-done:
+		if resp == nil || resp.Text != "✅ Bought" {
+			t.Errorf("next trade reply = %+v, want it placed", resp)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the next trade of the user is still waiting for the lock of the hung one")
+	}
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// multUsage explains the arguments of /mult
+const multUsage = "❌ Usage: /mult <symbol> <stake> <multiplier> [up|down] [sl=<amount>] [tp=<amount>]\n" +
+	"Example: /mult R_50 10 x100 up sl=5 tp=20"
+
+// handleMult places a multiplier contract, "/mult <symbol> <stake> <multiplier> [up|down] [sl=<amount>] [tp=<amount>]".
+// Without a direction Up and Down buttons are offered, they call the command again with the direction added.
+func (b *Bot) handleMult(ctx context.Context, msg *Message) (*Response, error) {
+	req, direction, ok := parseMultArgs(msg.Args)
+	if !ok {
+		return NewResponse(msg).Text(multUsage).Build(), nil
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, req.Symbol, func(symbol string) string {
+		return "mult:" + strings.Join(append([]string{symbol}, msg.Args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+	req.Symbol = symbol
+
+	if reason, err := b.tradingBlocked(ctx, req.Symbol); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	// Multiplier stakes have their own limits, without a direction the stake must fit both of them
+	f := b.formatter(ctx, msg)
+	contractTypes := []string{req.ContractType}
+	if direction == "" {
+		contractTypes = []string{"MULTUP", "MULTDOWN"}
+	}
+	if reason, err := b.validateStake(ctx, f, req.Symbol, req.Amount, contractTypes...); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	if direction == "" {
+		callback := "mult:" + strings.Join(append([]string{req.Symbol}, msg.Args[1:]...), ":")
+		if len(callback)+len(":down") > maxCallbackData {
+			return NewResponse(msg).Text("❌ These arguments don't fit into direction buttons. Please add up or down to the command, e.g.\n" +
+				"/mult " + strings.Join(append([]string{req.Symbol}, msg.Args[1:]...), " ") + " up").Build(), nil
+		}
+
+		return NewResponse(msg).
+			Textf("✖️ %s x%d on %s, which direction?", f.Money(req.Amount, b.accountCurrency(ctx, msg.Username)), req.Multiplier, req.Symbol).
+			Keyboard([][]Button{{
+				{Text: "⬆️ Up", CallbackData: callback + ":up"},
+				{Text: "⬇️ Down", CallbackData: callback + ":down"},
+			}}).
+			Build(), nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if stop, err := b.checkTrade(ctx, msg, client, req); err != nil || stop != nil {
+		return stop, err
+	}
+
+	if b.cfg.TradeConfirmTimeout > 0 {
+		return b.confirmTradePrompt(ctx, msg, client, req, nil)
+	}
+
+	return b.executeTrade(ctx, msg, client, req, nil)
+}
+
+// parseMultArgs reads arguments of /mult, direction is empty when it's not given
+func parseMultArgs(args []string) (req *TradeRequest, direction string, ok bool) {
+	args, limits, err := splitLimits(args)
+	if err != nil || len(args) < 3 {
+		return nil, "", false
+	}
+
+	stake, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || stake <= 0 {
+		return nil, "", false
+	}
+
+	multiplier, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(args[2]), "x"))
+	if err != nil || multiplier <= 0 {
+		return nil, "", false
+	}
+
+	req = &TradeRequest{
+		Symbol:     args[0],
+		Amount:     stake,
+		Multiplier: multiplier,
+		Limits:     limits,
+	}
+
+	switch len(args) {
+	case 3:
+	case 4:
+		direction = strings.ToLower(args[3])
+		if direction != "up" && direction != "down" {
+			return nil, "", false
+		}
+	default:
+		return nil, "", false
+	}
+
+	req.ContractType = "MULTUP"
+	if direction == "down" {
+		req.ContractType = "MULTDOWN"
+	}
+
+	return req, direction, true
+}
+
+// multReceipt builds the card confirming a purchased multiplier contract
+func multReceipt(msg *Message, f Formatter, req *TradeRequest, result *TradeResult) *Response {
+	var sb strings.Builder
+	sb.WriteString("🧾 Trade receipt\n\n")
+	fmt.Fprintf(&sb, "Contract: %d\n", result.ContractID)
+	fmt.Fprintf(&sb, "Symbol: %s %s\n", req.Symbol, contractLabel(f, req))
+	fmt.Fprintf(&sb, "Stake: %s\n", f.Money(result.BuyPrice, result.Currency))
+	writeLimits(&sb, f, req.Limits, result.Currency)
+	sb.WriteString("Runs until sold or closed by its limits\n")
+
+	id := strconv.FormatInt(result.ContractID, 10)
+
+	return NewResponse(msg).Text(sb.String()).Keyboard([][]Button{{
+		{Text: "📡 Track", CallbackData: "track:" + id},
+		{Text: "💸 Sell now", CallbackData: "sell:" + id},
+		{Text: "📈 Chart", CallbackData: "chart:" + req.Symbol},
+	}}).Build()
+}
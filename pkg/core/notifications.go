@@ -0,0 +1,146 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// NotificationCategory groups messages the bot sends on its own, users can turn each category off
+type NotificationCategory string
+
+const (
+	NotifySettlements NotificationCategory = "settlements" // Outcomes of settled trades
+	NotifyAlerts      NotificationCategory = "alerts"      // Alerts such as upcoming contract expiry
+	NotifyDigests     NotificationCategory = "digests"     // Periodic reports
+	NotifyBroadcasts  NotificationCategory = "broadcasts"  // Announcements of bot admins
+	NotifySignals     NotificationCategory = "signals"     // Trade ideas of the signal engine
+	NotifyActivity    NotificationCategory = "activity"    // Account activity outside the bot
+)
+
+// notificationCategories lists categories in the order they are shown by /notifications, with their labels
+var notificationCategories = []struct {
+	Category NotificationCategory
+	Label    string
+}{
+	{NotifySettlements, "Trade settlements"},
+	{NotifyAlerts, "Alerts"},
+	{NotifyDigests, "Digests"},
+	{NotifyBroadcasts, "Admin broadcasts"},
+	{NotifySignals, "Signal engine"},
+	{NotifyActivity, "Account activity outside the bot"},
+}
+
+// validNotificationCategory reports whether the category is known
+func validNotificationCategory(category NotificationCategory) bool {
+	for _, c := range notificationCategories {
+		if c.Category == category {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationsEnabled reports whether the user receives notifications of the category
+func (s *UserSettings) notificationsEnabled(category NotificationCategory) bool {
+	return !slices.Contains(s.MutedNotifications, string(category))
+}
+
+// setNotifications turns notifications of the category on or off
+func (s *UserSettings) setNotifications(category NotificationCategory, enabled bool) {
+	s.MutedNotifications = slices.DeleteFunc(s.MutedNotifications, func(muted string) bool {
+		return muted == string(category)
+	})
+
+	if !enabled {
+		s.MutedNotifications = append(s.MutedNotifications, string(category))
+	}
+}
+
+// notify delivers a message the bot sends on its own, unless the user turned its category off.
+// The message is queued in the store first, when Telegram can't be reached it's delivered after recovery.
+// It returns the ID of the sent message, 0 when the notification was skipped or queued for a retry.
+func (b *Bot) notify(ctx context.Context, notifier Notifier, username string, category NotificationCategory, resp *Response) (int, error) {
+	settings, err := b.getSettings(ctx, username)
+	if err != nil {
+		// Failing open, a lost alert is worse than an unwanted one
+		log.Printf("Failed to check notification settings of %s: %v", username, err)
+	} else if !settings.notificationsEnabled(category) {
+		return 0, nil
+	} else if settings.PlainText {
+		resp = plainResponse(resp)
+	}
+
+	key, err := b.enqueue(ctx, username, category, resp)
+	if err != nil {
+		// Without the store the notification can still be sent once
+		log.Printf("Failed to queue %s notification for %s: %v", category, username, err)
+		return notifier.Send(ctx, resp)
+	}
+
+	msgID, _, err := b.deliver(ctx, notifier, key)
+	if err != nil {
+		log.Printf("Queued %s notification for %s for a retry: %v", category, username, err)
+		return 0, nil
+	}
+
+	return msgID, nil
+}
+
+// handleNotifications shows notification categories with toggle buttons, "/notifications <category> on|off" changes one
+func (b *Bot) handleNotifications(ctx context.Context, msg *Message) (*Response, error) {
+	settings, err := b.getSettings(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rb := NewResponse(msg)
+
+	// Toggling with buttons updates the same message
+	if msg.CallbackData != "" {
+		rb.Edit(msg.MessageID)
+	}
+
+	if len(msg.Args) > 0 {
+		category := NotificationCategory(strings.ToLower(msg.Args[0]))
+		if len(msg.Args) != 2 || !validNotificationCategory(category) {
+			return rb.Text("❌ Usage: /notifications <category> on|off, e.g. /notifications digests off").Build(), nil
+		}
+
+		switch strings.ToLower(msg.Args[1]) {
+		case "on":
+			settings.setNotifications(category, true)
+		case "off":
+			settings.setNotifications(category, false)
+		case "toggle":
+			settings.setNotifications(category, !settings.notificationsEnabled(category))
+		default:
+			return rb.Text("❌ Usage: /notifications <category> on|off, e.g. /notifications digests off").Build(), nil
+		}
+
+		if err := b.saveSettings(ctx, msg.Username, settings); err != nil {
+			return nil, err
+		}
+	}
+
+	var sb strings.Builder
+	var buttons [][]Button
+	sb.WriteString("🔔 Notifications\n\n")
+	for _, c := range notificationCategories {
+		state := "✅"
+		if !settings.notificationsEnabled(c.Category) {
+			state = "🔕"
+		}
+
+		fmt.Fprintf(&sb, "%s %s (%s)\n", state, c.Label, c.Category)
+		buttons = append(buttons, []Button{{
+			Text:         fmt.Sprintf("%s %s", state, c.Label),
+			CallbackData: fmt.Sprintf("notifications:%s:toggle", c.Category),
+		}})
+	}
+	sb.WriteString("\nTap a category to turn it on or off.")
+
+	return rb.Text(sb.String()).Keyboard(buttons).Build(), nil
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// accountLinkTTL is how long an OAuth login link stays valid
+const accountLinkTTL = 10 * time.Minute
+
+// ErrInvalidLinkState is returned when an OAuth callback doesn't match a pending login link
+var ErrInvalidLinkState = errors.New("invalid or expired login link")
+
+// LinkedAccount is a Deriv account authorized through OAuth
+type LinkedAccount struct {
+	LoginID  string `json:"login_id"`
+	Token    string `json:"token"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// pendingLink is an OAuth login started by a user
+type pendingLink struct {
+	username string
+	expires  time.Time
+}
+
+// accountLinks tracks pending OAuth logins by state
+type accountLinks struct {
+	mu      sync.Mutex
+	pending map[string]pendingLink
+}
+
+// start registers a pending login for the user and returns its state
+func (l *accountLinks) start(username string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	state := hex.EncodeToString(buf)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for key, link := range l.pending {
+		if now.After(link.expires) {
+			delete(l.pending, key)
+		}
+	}
+
+	l.pending[state] = pendingLink{username: username, expires: now.Add(accountLinkTTL)}
+
+	return state, nil
+}
+
+// complete consumes the pending login and returns its user
+func (l *accountLinks) complete(state string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	link, ok := l.pending[state]
+	delete(l.pending, state)
+
+	if !ok || time.Now().After(link.expires) {
+		return "", false
+	}
+
+	return link.username, true
+}
+
+// accountLinkResponse replies to /connect with an OAuth login button
+func (b *Bot) accountLinkResponse(msg *Message) (*Response, error) {
+	state, err := b.links.start(msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	loginURL := b.cfg.PublicURL + "/oauth/start?state=" + url.QueryEscape(state)
+
+	return NewResponse(msg).
+		Text("🔐 Log in with Deriv to connect your account. The link is valid for 10 minutes.\n\n" +
+			"Alternatively, send /connect <api_token> with a token created at https://app.deriv.com/account/api-token").
+		Keyboard([][]Button{
+			{{Text: "Log in with Deriv", URL: loginURL}},
+		}).
+		Build(), nil
+}
+
+// CompleteAccountLink stores accounts authorized through the OAuth login identified by state
+func (b *Bot) CompleteAccountLink(ctx context.Context, state string, accounts []LinkedAccount) error {
+	username, ok := b.links.complete(state)
+	if !ok {
+		return ErrInvalidLinkState
+	}
+
+	if len(accounts) == 0 {
+		return fmt.Errorf("no accounts were authorized")
+	}
+
+	// The first account is the one the user logged in with
+	token := accounts[0].Token
+	if _, err := b.pool.Client(ctx, token); err != nil {
+		return fmt.Errorf("failed to connect account: %w", err)
+	}
+
+	creds := &Credentials{Token: token, Accounts: accounts, LastUsed: time.Now()}
+	if err := b.storage.Put(ctx, bucketCredentials, username, creds); err != nil {
+		b.pool.Release(token)
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	return nil
+}
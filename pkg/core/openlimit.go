@@ -0,0 +1,54 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxOpenContracts returns how many contracts a user may hold open on the symbol, 0 means no limit
+func (b *Bot) maxOpenContracts(symbol string) int {
+	// Keys of config maps are lowercased when loaded
+	for key, limit := range b.cfg.MaxOpenContractsBySymbol {
+		if strings.EqualFold(key, symbol) {
+			return limit
+		}
+	}
+	return b.cfg.MaxOpenContracts
+}
+
+// openContractsLimited returns the reason buying the symbols would exceed the limit of open contracts per symbol,
+// or an empty string when it's allowed. Open contracts are taken from the live portfolio of the account,
+// so contracts bought outside the bot count too. Symbols may repeat, each occurrence is a contract to buy.
+func (b *Bot) openContractsLimited(ctx context.Context, client DerivClient, symbols ...string) (string, error) {
+	buying := make(map[string]int, len(symbols))
+	limited := false
+	for _, symbol := range symbols {
+		buying[symbol]++
+		limited = limited || b.maxOpenContracts(symbol) > 0
+	}
+
+	if !limited {
+		return "", nil
+	}
+
+	contracts, err := client.GetOpenPositions(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to check open contracts: %w", err)
+	}
+
+	open := make(map[string]int)
+	for _, contract := range contracts {
+		open[contract.Symbol]++
+	}
+
+	for _, symbol := range symbols {
+		limit := b.maxOpenContracts(symbol)
+		if limit > 0 && open[symbol]+buying[symbol] > limit {
+			return fmt.Sprintf("⚠️ You already have %d open contracts on %s, the limit is %d at a time. "+
+				"Wait for one to settle or sell it first.", open[symbol], symbol, limit), nil
+		}
+	}
+
+	return "", nil
+}
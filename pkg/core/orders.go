@@ -0,0 +1,570 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucketOrders is the storage bucket holding conditional orders of users
+const bucketOrders = "orders"
+
+const (
+	maxOrdersPerUser = 20               // Orders a user may have at once
+	orderTimeout     = 30 * time.Second // Bounds placing the trade of a triggered order
+)
+
+// orderUsage explains the syntax of /order
+const orderUsage = "❌ Usage: /order buy <symbol> <amount> [duration] [up|down] when price <op> <value>\n" +
+	"Example: /order buy R_50 10 5t up when price > 1234.5"
+
+// ConditionalOrder places an Up/Down trade once the price of the symbol meets the condition
+type ConditionalOrder struct {
+	ID           int       `json:"id"`
+	Symbol       string    `json:"symbol"`
+	ContractType string    `json:"contract_type"`
+	Amount       float64   `json:"amount"`
+	Duration     string    `json:"duration"` // As accepted by /buy, e.g. 5t
+	Operator     string    `json:"operator"`
+	Price        float64   `json:"price"`
+	ChatID       int64     `json:"chat_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// String renders the order, e.g. "buy R_50 Up 10 for 5t when price > 1234.5"
+func (o *ConditionalOrder) String() string {
+	direction := "Up"
+	if o.ContractType == "PUT" {
+		direction = "Down"
+	}
+
+	return fmt.Sprintf("buy %s %s %s for %s when price %s %s", o.Symbol, direction,
+		strconv.FormatFloat(o.Amount, 'f', -1, 64), o.Duration, o.Operator, strconv.FormatFloat(o.Price, 'f', -1, 64))
+}
+
+// met reports whether the price satisfies the condition
+func (o *ConditionalOrder) met(price float64) bool {
+	switch o.Operator {
+	case "<":
+		return price < o.Price
+	case "<=":
+		return price <= o.Price
+	case ">":
+		return price > o.Price
+	case ">=":
+		return price >= o.Price
+	}
+	return false
+}
+
+// orderFeed holds orders of users waiting on ticks of a symbol
+type orderFeed struct {
+	orders      map[string][]ConditionalOrder // Orders on the symbol by username
+	unsubscribe func()
+}
+
+// orderWatcher keeps conditional orders in memory and evaluates them on ticks in a single worker
+type orderWatcher struct {
+	mu      sync.Mutex
+	feeds   map[string]*orderFeed
+	prices  map[string]float64 // Latest prices of symbols the worker hasn't evaluated yet
+	wake    chan struct{}      // Signals the worker that prices are waiting
+	storage sync.Mutex         // Serializes changes of users' order lists
+}
+
+// triggeredOrder is an order of a user whose condition a price met
+type triggeredOrder struct {
+	username string
+	order    ConditionalOrder
+}
+
+// getOrders loads conditional orders of the user
+func (b *Bot) getOrders(ctx context.Context, username string) ([]ConditionalOrder, error) {
+	var orders []ConditionalOrder
+
+	err := b.storage.Get(ctx, bucketOrders, username, &orders)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// saveOrders stores conditional orders of the user, removing the entry when there are none left
+func (b *Bot) saveOrders(ctx context.Context, username string, orders []ConditionalOrder) error {
+	if len(orders) == 0 {
+		if err := b.storage.Delete(ctx, bucketOrders, username); err != nil && !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("failed to delete orders: %w", err)
+		}
+		return nil
+	}
+
+	if err := b.storage.Put(ctx, bucketOrders, username, orders); err != nil {
+		return fmt.Errorf("failed to save orders: %w", err)
+	}
+
+	return nil
+}
+
+// watchOrder starts evaluating the order on ticks of its symbol
+func (b *Bot) watchOrder(username string, order *ConditionalOrder) {
+	b.orders.mu.Lock()
+	defer b.orders.mu.Unlock()
+
+	feed, ok := b.orders.feeds[order.Symbol]
+	if !ok {
+		feed = &orderFeed{orders: make(map[string][]ConditionalOrder)}
+		b.orders.feeds[order.Symbol] = feed
+		feed.unsubscribe = b.events.Subscribe(EventFilter{Type: EventTick, Symbol: order.Symbol}, b.onOrderTick)
+	}
+
+	feed.orders[username] = append(feed.orders[username], *order)
+}
+
+// unwatchOrder stops evaluating the order, closing the feed of its symbol when no orders are left
+func (b *Bot) unwatchOrder(username string, order *ConditionalOrder) {
+	b.orders.mu.Lock()
+	defer b.orders.mu.Unlock()
+
+	feed, ok := b.orders.feeds[order.Symbol]
+	if !ok {
+		return
+	}
+
+	feed.orders[username] = slices.DeleteFunc(feed.orders[username], func(o ConditionalOrder) bool {
+		return o.ID == order.ID
+	})
+	if len(feed.orders[username]) == 0 {
+		delete(feed.orders, username)
+	}
+
+	if len(feed.orders) == 0 {
+		feed.unsubscribe()
+		delete(b.orders.feeds, order.Symbol)
+		delete(b.orders.prices, order.Symbol)
+	}
+}
+
+// watchingOrder reports whether the order is still evaluated, it isn't once cancelled or wiped
+func (b *Bot) watchingOrder(username string, order *ConditionalOrder) bool {
+	b.orders.mu.Lock()
+	defer b.orders.mu.Unlock()
+
+	feed, ok := b.orders.feeds[order.Symbol]
+	if !ok {
+		return false
+	}
+
+	return slices.ContainsFunc(feed.orders[username], func(o ConditionalOrder) bool {
+		return o.ID == order.ID
+	})
+}
+
+// onOrderTick hands the price to the order worker, a newer tick replaces a price the worker hasn't reached yet
+func (b *Bot) onOrderTick(event Event) {
+	b.orders.mu.Lock()
+	if _, ok := b.orders.feeds[event.Symbol]; ok {
+		b.orders.prices[event.Symbol] = event.Price.Price
+	}
+	b.orders.mu.Unlock()
+
+	select {
+	case b.orders.wake <- struct{}{}:
+	default:
+	}
+}
+
+// triggeredOrders takes prices waiting for the worker and returns orders whose condition they meet
+func (b *Bot) triggeredOrders() (triggered []triggeredOrder, prices map[string]float64) {
+	b.orders.mu.Lock()
+	defer b.orders.mu.Unlock()
+
+	prices = b.orders.prices
+	b.orders.prices = make(map[string]float64)
+
+	for symbol, price := range prices {
+		feed, ok := b.orders.feeds[symbol]
+		if !ok {
+			continue
+		}
+
+		for username, orders := range feed.orders {
+			for _, order := range orders {
+				if order.met(price) {
+					triggered = append(triggered, triggeredOrder{username: username, order: order})
+				}
+			}
+		}
+	}
+
+	return triggered, prices
+}
+
+// ordersDeferred reports whether triggered orders wait for later ticks instead of being placed now,
+// they do while auto trading is disabled, trading is paused or the Deriv connection is degraded
+func (b *Bot) ordersDeferred() bool {
+	if !b.features.Enabled(FeatureAutoTrading) {
+		return true
+	}
+	if paused, _ := b.watchdog.Paused(); paused {
+		return true
+	}
+	reason, _ := b.watchdog.Degraded()
+	return reason != ""
+}
+
+// checkOrders places trades of orders whose condition the latest prices meet. Orders are removed once they are
+// placed or dropped, the user is told either way. While trading is paused they keep waiting.
+func (b *Bot) checkOrders(ctx context.Context, notifier Notifier) {
+	triggered, prices := b.triggeredOrders()
+	if len(triggered) == 0 {
+		return
+	}
+
+	// Later ticks trigger them again once trading resumes
+	if b.ordersDeferred() {
+		return
+	}
+
+	for _, t := range triggered {
+		b.triggerOrder(ctx, notifier, t.username, &t.order, prices[t.order.Symbol])
+	}
+}
+
+// triggerOrder places the trade of the order, removes it and reports the outcome to the user
+func (b *Bot) triggerOrder(ctx context.Context, notifier Notifier, username string, order *ConditionalOrder, price float64) {
+	ctx, cancel := context.WithTimeout(ctx, orderTimeout)
+	defer cancel()
+
+	text, placed, ok := b.placeOrder(ctx, username, order, price)
+	if !ok {
+		return
+	}
+
+	if _, err := b.removeOrder(ctx, username, order.ID); err != nil {
+		log.Printf("Failed to remove order #%d of %s: %v", order.ID, username, err)
+	}
+	b.unwatchOrder(username, order)
+
+	category := NotifySettlements
+	if !placed {
+		category = NotifyAlerts
+	}
+
+	notifier = b.userNotifier(ctx, notifier, username)
+	if _, err := b.notify(ctx, notifier, username, category, NewNotification(order.ChatID).Text(text).Build()); err != nil {
+		log.Printf("Failed to report order #%d of %s: %v", order.ID, username, err)
+	}
+}
+
+// placeOrder places the trade of a triggered order with the checks of /buy and returns the message for the user
+// and whether the trade was placed. ok is false when the order was cancelled meanwhile and is left alone.
+func (b *Bot) placeOrder(ctx context.Context, username string, order *ConditionalOrder, price float64) (text string, placed, ok bool) {
+	header := fmt.Sprintf("⚡️ Order #%d triggered at %s: %s\n\n", order.ID, strconv.FormatFloat(price, 'f', -1, 64), order)
+
+	duration, err := ParseDuration(order.Duration)
+	if err != nil {
+		return header + "❌ Not placed and removed, the order has an invalid duration.", false, true
+	}
+
+	unlock := b.trading.lock(username)
+	defer unlock()
+
+	if !b.watchingOrder(username, order) {
+		return "", false, false
+	}
+
+	client, err := b.clientFor(ctx, username)
+	if err != nil {
+		log.Printf("Failed to get client of %s for order #%d: %v", username, order.ID, err)
+		return header + "❌ Not placed and removed, your account couldn't be reached.", false, true
+	}
+
+	msg := &Message{Username: username, ChatID: order.ChatID}
+	req := &TradeRequest{
+		Symbol:       order.Symbol,
+		Amount:       order.Amount,
+		ContractType: order.ContractType,
+		Duration:     duration,
+	}
+
+	if stop, err := b.checkTrade(ctx, msg, client, req); err != nil {
+		log.Printf("Failed to check order #%d of %s: %v", order.ID, username, err)
+		return header + "❌ Not placed and removed, the trade couldn't be checked against your limits.", false, true
+	} else if stop != nil {
+		return header + "Not placed and removed: " + stop.Text, false, true
+	}
+
+	receipt, err := b.executeTrade(ctx, msg, client, req, nil)
+	if err != nil {
+		log.Printf("Failed to place order #%d of %s: %v", order.ID, username, err)
+		return header + "❌ Not placed and removed, Deriv refused the trade.", false, true
+	}
+
+	return header + receipt.Text, true, true
+}
+
+// removeOrder removes the order with the ID from the user's stored orders and returns it, nil when it isn't there
+func (b *Bot) removeOrder(ctx context.Context, username string, id int) (*ConditionalOrder, error) {
+	b.orders.storage.Lock()
+	defer b.orders.storage.Unlock()
+
+	orders, err := b.getOrders(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		if orders[i].ID != id {
+			continue
+		}
+
+		removed := orders[i]
+		if err := b.saveOrders(ctx, username, append(orders[:i:i], orders[i+1:]...)); err != nil {
+			return nil, err
+		}
+
+		return &removed, nil
+	}
+
+	return nil, nil
+}
+
+// clearOrders removes pending conditional orders of all users and returns their number
+func (b *Bot) clearOrders(ctx context.Context) int {
+	b.orders.storage.Lock()
+	defer b.orders.storage.Unlock()
+
+	usernames, err := b.storage.Keys(ctx, bucketOrders)
+	if err != nil {
+		log.Printf("Failed to list orders: %v", err)
+		return 0
+	}
+
+	var cleared int
+	for _, username := range usernames {
+		orders, err := b.getOrders(ctx, username)
+		if err == nil {
+			err = b.saveOrders(ctx, username, nil)
+		}
+		if err != nil {
+			log.Printf("Failed to clear orders of %s: %v", username, err)
+			continue
+		}
+
+		for i := range orders {
+			b.unwatchOrder(username, &orders[i])
+		}
+		cleared += len(orders)
+	}
+
+	return cleared
+}
+
+// runOrders loads conditional orders of all users and evaluates them on ticks until the bot stops,
+// it's a service of the bot
+func (b *Bot) runOrders(ctx context.Context, notifier Notifier) {
+	usernames, err := b.storage.Keys(ctx, bucketOrders)
+	if err != nil {
+		log.Printf("Failed to list orders: %v", err)
+	}
+
+	for _, username := range usernames {
+		orders, err := b.getOrders(ctx, username)
+		if err != nil {
+			log.Printf("Failed to load orders of %s: %v", username, err)
+			continue
+		}
+
+		for i := range orders {
+			b.watchOrder(username, &orders[i])
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.orders.wake:
+			b.checkOrders(ctx, notifier)
+		}
+	}
+}
+
+// parseOrder parses "buy <symbol> <amount> [duration] [up|down] when price <op> <value>" into an order with the
+// symbol as given, the duration is left empty when it's omitted
+func parseOrder(args []string) (*ConditionalOrder, error) {
+	when := -1
+	for i, arg := range args {
+		if strings.EqualFold(arg, "when") {
+			when = i
+			break
+		}
+	}
+
+	if when < 0 || len(args[when+1:]) != 3 || !strings.EqualFold(args[when+1], "price") {
+		return nil, fmt.Errorf("the condition should look like: when price > 1234.5")
+	}
+
+	trade := args[:when]
+	if len(trade) < 3 || len(trade) > 5 || !strings.EqualFold(trade[0], "buy") {
+		return nil, fmt.Errorf("the trade should look like: buy R_50 10 5t up")
+	}
+
+	order := &ConditionalOrder{Symbol: trade[1], ContractType: "CALL"}
+
+	amount, err := strconv.ParseFloat(trade[2], 64)
+	if err != nil || amount <= 0 {
+		return nil, fmt.Errorf("invalid amount %s", trade[2])
+	}
+	order.Amount = amount
+
+	for _, arg := range trade[3:] {
+		switch strings.ToLower(arg) {
+		case "up":
+			order.ContractType = "CALL"
+		case "down":
+			order.ContractType = "PUT"
+		default:
+			duration, err := ParseDuration(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration %s, e.g. 5t or 15m", arg)
+			}
+			order.Duration = duration.String()
+		}
+	}
+
+	switch op := args[when+2]; op {
+	case "<", "<=", ">", ">=":
+		order.Operator = op
+	default:
+		return nil, fmt.Errorf("unknown operator %s, use <, <=, > or >=", op)
+	}
+
+	if order.Price, err = strconv.ParseFloat(args[when+3], 64); err != nil {
+		return nil, fmt.Errorf("invalid price %s", args[when+3])
+	}
+
+	return order, nil
+}
+
+// handleOrder stores a conditional order, "/order buy <symbol> <amount> [duration] [up|down] when price <op> <value>"
+func (b *Bot) handleOrder(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) == 0 {
+		return NewResponse(msg).Text(orderUsage).Build(), nil
+	}
+
+	order, err := parseOrder(msg.Args)
+	if err != nil {
+		return NewResponse(msg).Textf("❌ %v.\n\n%s", err, orderUsage).Build(), nil
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, order.Symbol, func(symbol string) string {
+		args := append([]string{msg.Args[0], symbol}, msg.Args[2:]...)
+		return "order:" + strings.Join(args, ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+	order.Symbol = symbol
+
+	if reason, err := b.tradingBlocked(ctx, symbol); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	f := b.formatter(ctx, msg)
+	if reason, err := b.validateStake(ctx, f, symbol, order.Amount, order.ContractType); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	if order.Duration == "" {
+		order.Duration = b.defaultDuration(ctx, symbol).String()
+	}
+
+	b.orders.storage.Lock()
+	defer b.orders.storage.Unlock()
+
+	orders, err := b.getOrders(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orders) >= maxOrdersPerUser {
+		return NewResponse(msg).Textf("❌ You can have at most %d orders, cancel one with /cancelorder <id>", maxOrdersPerUser).Build(), nil
+	}
+
+	order.ChatID = msg.ChatID
+	order.CreatedAt = time.Now()
+	for _, existing := range orders {
+		order.ID = max(order.ID, existing.ID)
+	}
+	order.ID++
+
+	if err := b.saveOrders(ctx, msg.Username, append(orders, *order)); err != nil {
+		return nil, err
+	}
+	b.watchOrder(msg.Username, order)
+
+	return NewResponse(msg).Textf("✅ Order #%d set: %s\n"+
+		"It's checked on every tick and placed once, with the same limits as /buy. Orders are evaluated while the bot runs "+
+		"and wait while trading is paused.",
+		order.ID, order).Build(), nil
+}
+
+// handleOrders lists conditional orders of the user
+func (b *Bot) handleOrders(ctx context.Context, msg *Message) (*Response, error) {
+	orders, err := b.getOrders(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(orders) == 0 {
+		return NewResponse(msg).Text("📋 No pending orders. Set one with /order buy <symbol> <amount> when price <op> <value>").Build(), nil
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+
+	var sb strings.Builder
+	sb.WriteString("📋 Your pending orders:\n")
+	for _, order := range orders {
+		fmt.Fprintf(&sb, "#%d %s\n", order.ID, &order)
+	}
+	sb.WriteString("\nCancel one with /cancelorder <id>")
+
+	return NewResponse(msg).Text(sb.String()).Build(), nil
+}
+
+// handleCancelOrder removes a conditional order of the user, "/cancelorder <id>"
+func (b *Bot) handleCancelOrder(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) != 1 {
+		return NewResponse(msg).Text("❌ Usage: /cancelorder <id>").Build(), nil
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(msg.Args[0], "#"))
+	if err != nil {
+		return NewResponse(msg).Text("❌ Usage: /cancelorder <id>").Build(), nil
+	}
+
+	removed, err := b.removeOrder(ctx, msg.Username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if removed == nil {
+		return NewResponse(msg).Textf("❌ Order #%d not found, it may have been placed already", id).Build(), nil
+	}
+	b.unwatchOrder(msg.Username, removed)
+
+	return NewResponse(msg).Textf("✅ Order #%d cancelled", id).Build(), nil
+}
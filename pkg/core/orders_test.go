@@ -0,0 +1,59 @@
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOrderTicksTriggerLatestPrice(t *testing.T) {
+	b := &Bot{
+		events: newEventBus(func(context.Context, string) (<-chan HistoricalDataPoint, error) {
+			return make(chan HistoricalDataPoint), nil
+		}),
+		orders: orderWatcher{feeds: make(map[string]*orderFeed), prices: make(map[string]float64), wake: make(chan struct{}, 1)},
+	}
+
+	above := &ConditionalOrder{ID: 1, Symbol: "R_50", Operator: ">", Price: 100}
+	below := &ConditionalOrder{ID: 2, Symbol: "R_50", Operator: "<", Price: 90}
+	b.watchOrder("alice", above)
+	b.watchOrder("bob", below)
+
+	// Ticks the worker hasn't reached yet are replaced by newer ones
+	b.onOrderTick(Event{Type: EventTick, Symbol: "R_50", Price: HistoricalDataPoint{Price: 80}})
+	b.onOrderTick(Event{Type: EventTick, Symbol: "R_50", Price: HistoricalDataPoint{Price: 101}})
+	b.onOrderTick(Event{Type: EventTick, Symbol: "R_100", Price: HistoricalDataPoint{Price: 101}})
+
+	select {
+	case <-b.orders.wake:
+	default:
+		t.Fatal("the worker wasn't woken up")
+	}
+
+	triggered, prices := b.triggeredOrders()
+	if len(triggered) != 1 || triggered[0].username != "alice" || triggered[0].order.ID != above.ID {
+		t.Fatalf("triggered = %+v, want order #1 of alice", triggered)
+	}
+	if prices["R_50"] != 101 {
+		t.Errorf("price of R_50 = %v, want 101", prices["R_50"])
+	}
+	if _, ok := prices["R_100"]; ok {
+		t.Error("price of R_100 is kept, no order waits on it")
+	}
+
+	if triggered, _ := b.triggeredOrders(); len(triggered) != 0 {
+		t.Errorf("triggered = %+v after the prices were taken, want none", triggered)
+	}
+
+	b.unwatchOrder("alice", above)
+	if b.watchingOrder("alice", above) {
+		t.Error("order #1 is watched after it was removed")
+	}
+	if !b.watchingOrder("bob", below) {
+		t.Error("order #2 isn't watched, only #1 was removed")
+	}
+
+	b.unwatchOrder("bob", below)
+	if len(b.orders.feeds) != 0 {
+		t.Errorf("feeds = %v, want none without orders", b.orders.feeds)
+	}
+}
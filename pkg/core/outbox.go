@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// bucketOutbox is the storage bucket holding notifications until Telegram accepts them, keyed by queue time
+const bucketOutbox = "outbox"
+
+// Delivery of queued notifications
+const (
+	outboxRetryInterval = 15 * time.Second // Time between delivery attempts of queued notifications
+	outboxMaxAge        = 24 * time.Hour   // Notifications not delivered within this time are dropped
+)
+
+// outboxEntry is a notification waiting for delivery
+type outboxEntry struct {
+	Username string               `json:"username"`
+	Category NotificationCategory `json:"category"`
+	Response *Response            `json:"response"`
+	QueuedAt time.Time            `json:"queued_at"`
+	Attempts int                  `json:"attempts"`
+}
+
+// outboxKey orders entries by queue time, the user keeps keys of entries queued at the same moment apart
+func outboxKey(queuedAt time.Time, username string) string {
+	return fmt.Sprintf("%020d/%s", queuedAt.UnixNano(), username)
+}
+
+// enqueue persists the notification before it's sent, so it survives failed deliveries and restarts
+func (b *Bot) enqueue(ctx context.Context, username string, category NotificationCategory, resp *Response) (string, error) {
+	entry := &outboxEntry{
+		Username: username,
+		Category: category,
+		Response: resp,
+		QueuedAt: time.Now(),
+	}
+
+	key := outboxKey(entry.QueuedAt, username)
+	if err := b.storage.Put(ctx, bucketOutbox, key, entry); err != nil {
+		return "", fmt.Errorf("failed to queue notification: %w", err)
+	}
+
+	return key, nil
+}
+
+// deliver sends the queued notification and removes it from the outbox once Telegram accepted it.
+// It reports false when the entry was already delivered by someone else.
+func (b *Bot) deliver(ctx context.Context, notifier Notifier, key string) (int, bool, error) {
+	b.outbox.Lock()
+	defer b.outbox.Unlock()
+
+	var entry outboxEntry
+	err := b.storage.Get(ctx, bucketOutbox, key, &entry)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("failed to load queued notification: %w", err)
+	}
+
+	msgID, sendErr := notifier.Send(ctx, entry.Response)
+	if sendErr != nil {
+		entry.Attempts++
+		if err := b.storage.Put(ctx, bucketOutbox, key, &entry); err != nil {
+			log.Printf("Failed to save delivery attempt of notification %s: %v", key, err)
+		}
+		return 0, true, fmt.Errorf("failed to send notification: %w", sendErr)
+	}
+
+	if err := b.storage.Delete(ctx, bucketOutbox, key); err != nil {
+		// The notification may be sent again after a restart, which is better than losing it
+		log.Printf("Failed to remove delivered notification %s: %v", key, err)
+	}
+
+	return msgID, true, nil
+}
+
+// runOutbox retries queued notifications until they're delivered or too old to be useful
+func (b *Bot) runOutbox(ctx context.Context, notifier Notifier) {
+	ticker := time.NewTicker(outboxRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		keys, err := b.storage.Keys(ctx, bucketOutbox)
+		if err != nil {
+			log.Printf("Failed to list queued notifications: %v", err)
+			continue
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var entry outboxEntry
+			if err := b.storage.Get(ctx, bucketOutbox, key, &entry); err != nil {
+				continue
+			}
+
+			if time.Since(entry.QueuedAt) > outboxMaxAge {
+				log.Printf("Dropping %s notification for %s after %d failed attempts", entry.Category, entry.Username, entry.Attempts)
+				if err := b.storage.Delete(ctx, bucketOutbox, key); err != nil {
+					log.Printf("Failed to drop notification %s: %v", key, err)
+				}
+				continue
+			}
+
+			if _, _, err := b.deliver(ctx, notifier, key); err != nil {
+				log.Printf("Failed to deliver queued %s notification for %s: %v", entry.Category, entry.Username, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"errors"
+	"math"
+)
+
+// errHedgeImpossible is returned when no hedge stake caps the loss at the requested level
+var errHedgeImpossible = errors.New("no hedge stake caps the loss at this level")
+
+// payoutRatio returns the payout per unit of stake of the quote, e.g. 1.95 for a 95% return
+func payoutRatio(quote *Quote) float64 {
+	if quote.AskPrice <= 0 {
+		return 0
+	}
+	return quote.Payout / quote.AskPrice
+}
+
+// hedgeOutcomes returns the net result of holding a contract and its opposite when either of them wins.
+// The contract was bought for stake with the payout, the hedge costs hedgeStake and pays hedgeStake * ratio.
+func hedgeOutcomes(stake, payout, hedgeStake, ratio float64) (contractWins, hedgeWins float64) {
+	return payout - stake - hedgeStake, hedgeStake*ratio - stake - hedgeStake
+}
+
+// lockStake returns the hedge stake making both outcomes equal, the smallest loss an opposite contract can lock in
+func lockStake(payout, ratio float64) float64 {
+	return payout / ratio
+}
+
+// hedgeStake returns the smallest stake of an opposite contract paying ratio per unit that caps the loss
+// of a contract bought for stake with the payout at maxLoss, whichever of them wins
+func hedgeStake(stake, payout, ratio, maxLoss float64) (float64, error) {
+	if ratio <= 1 {
+		return 0, errHedgeImpossible
+	}
+
+	// When the hedge wins: hedgeStake*(ratio-1) - stake >= -maxLoss
+	needed := math.Max((stake-maxLoss)/(ratio-1), 0)
+
+	// When the contract wins: payout - stake - hedgeStake >= -maxLoss
+	if needed > payout-stake+maxLoss {
+		return 0, errHedgeImpossible
+	}
+
+	return needed, nil
+}
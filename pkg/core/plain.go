@@ -0,0 +1,155 @@
+package core
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode"
+)
+
+// plainPhrases replace symbols carrying meaning with words, so screen readers announce them
+var plainPhrases = strings.NewReplacer(
+	"❌", "Error:",
+	"⚠️", "Warning:",
+	"🛑", "Stopped:",
+	"⬆️", "Up",
+	"⬇️", "Down",
+	"▲", "up",
+	"▼", "down",
+	"×", "x",
+)
+
+// plainText rewrites the text for screen readers: meaningful symbols become words, emojis and decorations are removed
+func plainText(text string) string {
+	text = plainPhrases.Replace(text)
+
+	text = strings.Map(func(r rune) rune {
+		if isDecoration(r) {
+			return -1
+		}
+		return r
+	}, text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// isDecoration reports whether the rune is an emoji or another pictograph without meaning in plain text
+func isDecoration(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Emoji and pictographs
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // Miscellaneous symbols and arrows
+		return true
+	case r == 0xFE0F || r == 0x200D: // Emoji presentation selector and joiner
+		return true
+	case r == '⏱' || r == '⏳' || r == '⌛':
+		return true
+	}
+	return unicode.Is(unicode.Variation_Selector, r)
+}
+
+// plainResponse returns a copy of the response in plain output mode
+func plainResponse(resp *Response) *Response {
+	if resp == nil {
+		return nil
+	}
+
+	plain := *resp
+	plain.Text = plainText(resp.Text)
+	plain.Preformatted = false
+
+	if len(resp.Buttons) > 0 {
+		plain.Buttons = make([][]Button, len(resp.Buttons))
+		for i, row := range resp.Buttons {
+			plain.Buttons[i] = make([]Button, len(row))
+			for j, button := range row {
+				button.Text = plainText(button.Text)
+				plain.Buttons[i][j] = button
+			}
+		}
+	}
+
+	return &plain
+}
+
+// plainOutput rewrites responses of users who turned the plain output mode on
+func (b *Bot) plainOutput(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		resp, err := next(ctx, msg)
+		if resp == nil || !b.plainMode(ctx, msg.Username) {
+			return resp, err
+		}
+		return plainResponse(resp), err
+	}
+}
+
+// plainMode reports whether the user reads messages in plain output mode, failures are logged as off
+func (b *Bot) plainMode(ctx context.Context, username string) bool {
+	settings, err := b.getSettings(ctx, username)
+	if err != nil {
+		log.Printf("Failed to load settings of %s: %v", username, err)
+		return false
+	}
+	return settings.PlainText
+}
+
+// plainNotifier sends messages in plain output mode
+type plainNotifier struct {
+	Notifier
+}
+
+// Send implements Notifier
+func (n plainNotifier) Send(ctx context.Context, resp *Response) (int, error) {
+	return n.Notifier.Send(ctx, plainResponse(resp))
+}
+
+// userNotifier returns the notifier delivering background messages of the user in their output mode
+func (b *Bot) userNotifier(ctx context.Context, notifier Notifier, username string) Notifier {
+	if b.plainMode(ctx, username) {
+		return plainNotifier{notifier}
+	}
+	return notifier
+}
+
+// handlePlain turns the plain output mode on or off, "/plain on|off"
+func (b *Bot) handlePlain(ctx context.Context, msg *Message) (*Response, error) {
+	settings, err := b.getSettings(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rb := NewResponse(msg)
+
+	if len(msg.Args) == 0 {
+		state := "off"
+		if settings.PlainText {
+			state = "on"
+		}
+		return rb.Text("♿ Plain output is " + state + ". It leaves out emojis and formatting, so messages read well with a screen reader.\n" +
+			"Use /plain on or /plain off to change it.").Build(), nil
+	}
+
+	switch strings.ToLower(msg.Args[0]) {
+	case "on":
+		settings.PlainText = true
+		rb.Text("Plain output is on. Messages will have no emojis or formatting.")
+	case "off":
+		settings.PlainText = false
+		rb.Text("✅ Plain output is off")
+	default:
+		return rb.Text("❌ Usage: /plain on|off").Build(), nil
+	}
+
+	if err := b.saveSettings(ctx, msg.Username, settings); err != nil {
+		return nil, err
+	}
+
+	return rb.Build(), nil
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// handlePortfolio summarizes the user's trades placed through the bot, including basket-level P&L
+func (b *Bot) handlePortfolio(ctx context.Context, msg *Message) (*Response, error) {
+	if _, err := b.settleUser(ctx, msg.Username); err != nil {
+		return nil, err
+	}
+
+	records, err := b.userTrades(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return NewResponse(msg).Text("💼 No trades yet. Place one with /buy or /basket.").Build(), nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	// Open trades are valued at their current profit
+	trades := make(map[int64]*TradeRecord, len(records))
+	var open, settled int
+	var realized, unrealized float64
+
+	for _, record := range records {
+		trades[record.ContractID] = record
+
+		if record.Settled() {
+			settled++
+			realized += record.Profit
+			continue
+		}
+
+		open++
+		info, err := client.GetContract(ctx, record.ContractID)
+		if err != nil {
+			log.Printf("Failed to value contract %d: %v", record.ContractID, err)
+			continue
+		}
+		record.Profit = info.Profit
+		unrealized += info.Profit
+	}
+
+	var sb strings.Builder
+	sb.WriteString("💼 Portfolio\n\n")
+	f := b.formatter(ctx, msg)
+	currency := client.Currency()
+	fmt.Fprintf(&sb, "Open trades: %d (P&L %s)\n", open, f.SignedMoney(unrealized, currency))
+	fmt.Fprintf(&sb, "Settled trades: %d (P&L %s)\n", settled, f.SignedMoney(realized, currency))
+
+	baskets, err := b.userBaskets(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(baskets) > 0 {
+		sb.WriteString("\n🧺 Baskets:\n")
+	}
+
+	for _, basket := range baskets {
+		var pnl float64
+		var openLegs int
+		for _, id := range basket.ContractIDs {
+			record, ok := trades[id]
+			if !ok {
+				continue
+			}
+			pnl += record.Profit
+			if !record.Settled() {
+				openLegs++
+			}
+		}
+
+		state := f.ProfitIcon(pnl) + " settled"
+		if openLegs > 0 {
+			state = fmt.Sprintf("%d open", openLegs)
+		}
+
+		fmt.Fprintf(&sb, "%s %s %d × %s: P&L %s (%s)\n",
+			basket.Name, f.ContractIcon(basket.Direction), len(basket.ContractIDs), f.Money(basket.Stake, currency), f.SignedMoney(pnl, currency), state)
+	}
+
+	return NewResponse(msg).Text(strings.TrimRight(sb.String(), "\n")).Build(), nil
+}
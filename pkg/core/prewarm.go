@@ -0,0 +1,76 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+)
+
+// prewarmTimeout bounds fetching candles of one symbol ahead of requests
+const prewarmTimeout = 30 * time.Second
+
+// cachedCandles returns candles of the request, reusing ones fetched within the candle cache TTL
+func (b *Bot) cachedCandles(ctx context.Context, req HistoricalDataRequest) ([]HistoricalDataPoint, error) {
+	return b.candles.Do(ctx, fmt.Sprintf("%v", req), func(ctx context.Context) ([]HistoricalDataPoint, error) {
+		return b.derivClient.GetHistoricalData(ctx, req)
+	})
+}
+
+// runPrewarm fetches candles of watched symbols on start and then periodically, so the first /chart, /buy or
+// /price of a symbol doesn't wait for Deriv. It's a service of the bot.
+func (b *Bot) runPrewarm(ctx context.Context, _ Notifier) {
+	ticker := time.NewTicker(b.cfg.PrewarmInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, symbol := range b.watchedSymbols() {
+			b.prewarmSymbol(ctx, symbol)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prewarmSymbol replaces cached candles of the symbol with fresh ones
+func (b *Bot) prewarmSymbol(ctx context.Context, symbol string) {
+	ctx, cancel := context.WithTimeout(ctx, prewarmTimeout)
+	defer cancel()
+
+	for _, req := range []HistoricalDataRequest{chartCandlesRequest(symbol), dayCandlesRequest(symbol)} {
+		b.candles.forget(fmt.Sprintf("%v", req))
+		if _, err := b.cachedCandles(ctx, req); err != nil {
+			log.Printf("Failed to pre-warm candles of %s: %v", symbol, err)
+			return
+		}
+	}
+}
+
+// watchedSymbols returns symbols of the dashboard watchlist and of users' alerts and orders, in sorted order
+func (b *Bot) watchedSymbols() []string {
+	symbols := slices.Clone(b.cfg.Dashboard.Symbols)
+	if len(symbols) == 0 {
+		symbols = slices.Clone(b.symbols)
+	}
+
+	b.alerts.mu.Lock()
+	for stream := range b.alerts.streams {
+		symbols = append(symbols, stream.Symbol)
+	}
+	b.alerts.mu.Unlock()
+
+	b.orders.mu.Lock()
+	for symbol := range b.orders.feeds {
+		symbols = append(symbols, symbol)
+	}
+	b.orders.mu.Unlock()
+
+	slices.Sort(symbols)
+
+	return slices.Compact(symbols)
+}
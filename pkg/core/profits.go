@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ClosedContract is a sold or expired contract of the account's profit table
+type ClosedContract struct {
+	ContractID   int64
+	Symbol       string
+	ContractType string
+	BuyPrice     float64
+	SellPrice    float64 // Payout received when the contract was sold or expired
+	PurchaseTime time.Time
+	SellTime     time.Time
+}
+
+// Profit returns the net result of the contract
+func (c *ClosedContract) Profit() float64 {
+	return c.SellPrice - c.BuyPrice
+}
+
+// profitPeriods are the periods accepted by /profits
+var profitPeriods = []string{"today", "week", "month"}
+
+// profitPeriodStart returns the start of the period, today begins at midnight UTC
+func profitPeriodStart(period string, now time.Time) (time.Time, bool) {
+	switch period {
+	case "today":
+		return now.UTC().Truncate(24 * time.Hour), true
+	case "week":
+		return now.Add(-reportPeriod), true
+	case "month":
+		return now.AddDate(0, 0, -30), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// handleProfits summarizes contracts closed over a period, "/profits [today|week|month]"
+func (b *Bot) handleProfits(ctx context.Context, msg *Message) (*Response, error) {
+	period := "today"
+	if len(msg.Args) > 0 {
+		period = strings.ToLower(msg.Args[0])
+	}
+
+	now := time.Now()
+	from, ok := profitPeriodStart(period, now)
+	if len(msg.Args) > 1 || !ok {
+		return NewResponse(msg).Textf("❌ Usage: /profits [%s]", strings.Join(profitPeriods, "|")).Build(), nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := client.GetProfitTable(ctx, from, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg).Text(formatProfits(b.formatter(ctx, msg), client.Currency(), period, contracts)).Build(), nil
+}
+
+// formatProfits writes win rate, stakes, payouts and net result of closed contracts
+func formatProfits(f Formatter, currency, period string, contracts []ClosedContract) string {
+	if len(contracts) == 0 {
+		return fmt.Sprintf("💹 No contracts closed %s.", profitPeriodLabel(period))
+	}
+
+	var wins int
+	var staked, paid float64
+	for i := range contracts {
+		staked += contracts[i].BuyPrice
+		paid += contracts[i].SellPrice
+		if contracts[i].Profit() > 0 {
+			wins++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "💹 Profits %s\n\n", profitPeriodLabel(period))
+	fmt.Fprintf(&sb, "Contracts: %d\n", len(contracts))
+	fmt.Fprintf(&sb, "Win rate: %.0f%% (%d won)\n", float64(wins)/float64(len(contracts))*100, wins)
+	fmt.Fprintf(&sb, "Total stake: %s\n", f.Money(staked, currency))
+	fmt.Fprintf(&sb, "Total payout: %s\n", f.Money(paid, currency))
+	fmt.Fprintf(&sb, "Net P&L: %s", f.SignedMoney(paid-staked, currency))
+
+	return sb.String()
+}
+
+// profitPeriodLabel names the period in chat messages
+func profitPeriodLabel(period string) string {
+	switch period {
+	case "week":
+		return "in the last 7 days"
+	case "month":
+		return "in the last 30 days"
+	default:
+		return "today (UTC)"
+	}
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// progressDelay is how long a command runs before it shows progress, fast commands don't show any
+	progressDelay = 2 * time.Second
+	// progressEditInterval limits how often the progress message is edited, Telegram throttles frequent edits
+	progressEditInterval = 2 * time.Second
+	progressBarWidth     = 10
+)
+
+// Progress receives completion of a long-running command, handlers opt in by calling startProgress
+// and passing the reporter to the slow operation
+type Progress interface {
+	// Step reports that done of total units of work completed, it's safe for concurrent use
+	Step(done, total int)
+}
+
+// progressRuns keeps cancel functions of commands showing progress, so their Cancel buttons can stop them
+type progressRuns struct {
+	mu     sync.Mutex
+	nextID int
+	runs   map[string]*progressMessage
+}
+
+func (pr *progressRuns) add(p *progressMessage) string {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.nextID++
+	id := strconv.Itoa(pr.nextID)
+	pr.runs[id] = p
+
+	return id
+}
+
+func (pr *progressRuns) remove(id string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	delete(pr.runs, id)
+}
+
+func (pr *progressRuns) get(id string) (*progressMessage, bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	p, ok := pr.runs[id]
+	return p, ok
+}
+
+// progressMessage is a message edited with the percent complete of a command and a Cancel button
+type progressMessage struct {
+	ctx      context.Context
+	notifier Notifier
+	chatID   int64
+	username string
+	title    string
+	id       string
+	cancel   context.CancelFunc
+	timer    *time.Timer
+
+	mu        sync.Mutex
+	shown     bool // The command ran for progressDelay already
+	messageID int
+	done      int
+	total     int
+	percent   int
+	edited    time.Time
+	canceled  bool
+	finished  bool
+}
+
+// startProgress prepares a progress message of the command, it's sent once the command runs longer than
+// progressDelay. The returned context is canceled by the Cancel button, finish must be called with the result.
+func (b *Bot) startProgress(ctx context.Context, msg *Message, title string) (context.Context, *progressMessage) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	p := &progressMessage{
+		ctx:      ctx,
+		notifier: b.background.currentNotifier(),
+		chatID:   msg.ChatID,
+		username: msg.Username,
+		title:    title,
+		cancel:   cancel,
+		percent:  -1,
+	}
+	p.id = b.progress.add(p)
+
+	p.timer = time.AfterFunc(progressDelay, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.shown = true
+		p.update()
+	})
+
+	return ctx, p
+}
+
+// Step implements Progress
+func (p *progressMessage) Step(done, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done, p.total = done, total
+	if p.shown && time.Since(p.edited) >= progressEditInterval {
+		p.update()
+	}
+}
+
+// update sends or edits the progress message if the percent complete changed, p.mu must be held
+func (p *progressMessage) update() {
+	percent := 0
+	if p.total > 0 {
+		percent = min(100, p.done*100/p.total)
+	}
+
+	if p.notifier == nil || p.canceled || p.finished || percent <= p.percent {
+		return
+	}
+
+	rb := &ResponseBuilder{resp: Response{ChatID: p.chatID}}
+	rb.Text(formatProgress(p.title, percent)).Keyboard([][]Button{{{Text: "✖️ Cancel", CallbackData: "cancelprogress:" + p.id}}}).Silent()
+	if p.messageID != 0 {
+		rb.Edit(p.messageID)
+	}
+
+	messageID, err := p.notifier.Send(p.ctx, rb.Build())
+	if err != nil {
+		log.Printf("Failed to show progress of %s in chat %d: %v", p.title, p.chatID, err)
+		return
+	}
+
+	p.messageID, p.percent, p.edited = messageID, percent, time.Now()
+}
+
+// finish stops tracking the command and removes the progress message along with the response.
+// A command canceled by the user gets the progress message replaced with a note instead of its error.
+func (p *progressMessage) finish(b *Bot, msg *Message, resp *Response, err error) (*Response, error) {
+	b.progress.remove(p.id)
+	p.timer.Stop()
+	p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.finished = true
+
+	if p.canceled {
+		rb := NewResponse(msg).Textf("🛑 %s canceled", p.title)
+		if p.messageID != 0 {
+			rb.Edit(p.messageID)
+		}
+		return rb.Build(), nil
+	}
+
+	if p.messageID != 0 {
+		if resp != nil && resp.DeleteMessageID == 0 {
+			resp.DeleteMessageID = p.messageID
+		} else {
+			// The progress message can't go away along with the response, drop its Cancel button at least
+			done := NewNotification(p.chatID).Textf("⌛ %s stopped", p.title).Edit(p.messageID).Build()
+			if _, err := p.notifier.Send(context.WithoutCancel(p.ctx), done); err != nil {
+				log.Printf("Failed to update progress of %s in chat %d: %v", p.title, p.chatID, err)
+			}
+		}
+	}
+
+	return resp, err
+}
+
+// formatProgress renders the percent complete as a bar
+func formatProgress(title string, percent int) string {
+	filled := percent * progressBarWidth / 100
+	bar := make([]rune, 0, progressBarWidth)
+	for i := range progressBarWidth {
+		if i < filled {
+			bar = append(bar, '▓')
+		} else {
+			bar = append(bar, '░')
+		}
+	}
+
+	return fmt.Sprintf("⏳ %s\n%s %d%%", title, string(bar), percent)
+}
+
+// handleCancelProgress stops a command showing progress, "/cancelprogress <id>" from its Cancel button
+func (b *Bot) handleCancelProgress(_ context.Context, msg *Message) (*Response, error) {
+	rb := NewResponse(msg)
+	if msg.CallbackData != "" {
+		rb.Edit(msg.MessageID)
+	}
+
+	if len(msg.Args) != 1 {
+		return rb.Text("❌ Usage: /cancelprogress <id>").Build(), nil
+	}
+
+	p, ok := b.progress.get(msg.Args[0])
+	if !ok || p.username != msg.Username {
+		return rb.Text("ℹ️ This command already finished").Build(), nil
+	}
+
+	p.mu.Lock()
+	p.canceled = true
+	p.mu.Unlock()
+
+	p.cancel()
+
+	return rb.Textf("🛑 Canceling %s...", p.title).Build(), nil
+}
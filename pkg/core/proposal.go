@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// proposalValidity is how long the "Buy now" button of a /proposal preview buys the quoted price
+const proposalValidity = 30 * time.Second
+
+// handleProposal quotes an Up/Down contract without buying it, "/proposal <symbol> <amount> [duration] <up|down>".
+// The "Buy now" button buys the quoted proposal, so the price doesn't move between the preview and the purchase.
+func (b *Bot) handleProposal(ctx context.Context, msg *Message) (*Response, error) {
+	usage := NewResponse(msg).Text("❌ Usage: /proposal <symbol> <amount> [duration] <up|down>\nExample: /proposal R_50 10 5t up").Build()
+
+	args := msg.Args
+	if len(args) < 3 || len(args) > 4 {
+		return usage, nil
+	}
+
+	var contractType string
+	switch strings.ToLower(args[len(args)-1]) {
+	case "up":
+		contractType = "CALL"
+	case "down":
+		contractType = "PUT"
+	default:
+		return usage, nil
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, args[0], func(symbol string) string {
+		return "proposal:" + strings.Join(append([]string{symbol}, args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	amount, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || amount <= 0 {
+		return NewResponse(msg).Text("❌ Invalid amount format. Please provide a number.").Build(), nil
+	}
+
+	var duration Duration
+	if len(args) == 4 {
+		if duration, err = ParseDuration(args[2]); err != nil {
+			return NewResponse(msg).Text("❌ Invalid duration. Use a number with t (ticks), s, m, h or d, e.g. 5t or 15m.").Build(), nil
+		}
+	}
+
+	if duration.Value == 0 {
+		duration = b.defaultDuration(ctx, symbol)
+	}
+
+	f := b.formatter(ctx, msg)
+	if reason, err := b.validateStake(ctx, f, symbol, amount, contractType); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &TradeRequest{
+		Symbol:       symbol,
+		Amount:       amount,
+		ContractType: contractType,
+		Duration:     duration,
+	}
+
+	quote, err := client.GetQuote(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	req.Quote = quote
+	id := b.pendingTrades.put(msg.ChatID, &pendingTrade{
+		username: msg.Username,
+		account:  client.ActiveAccount().LoginID,
+		req:      req,
+		expires:  time.Now().Add(proposalValidity),
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🧮 %s %s, %s\n\n", symbolLabel(b.symbolNames(ctx), symbol), contractLabel(f, req), duration)
+	fmt.Fprintf(&sb, "Ask price: %s\n", f.Money(quote.AskPrice, quote.Currency))
+	fmt.Fprintf(&sb, "Payout: %s\n", f.Money(quote.Payout, quote.Currency))
+	if quote.AskPrice > 0 {
+		fmt.Fprintf(&sb, "Return: %s\n", f.Percent((quote.Payout-quote.AskPrice)/quote.AskPrice*100, 1))
+	}
+	if quote.Longcode != "" {
+		fmt.Fprintf(&sb, "\n%s\n", quote.Longcode)
+	}
+	fmt.Fprintf(&sb, "\nBuy now keeps this price for %s.", proposalValidity)
+
+	return NewResponse(msg).Text(sb.String()).Keyboard([][]Button{{
+		{Text: "💸 Buy now", CallbackData: "confirmtrade:" + strconv.FormatInt(id, 10) + ":yes"},
+	}}).Build(), nil
+}
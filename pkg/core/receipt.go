@@ -0,0 +1,272 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Live contract tracking started from trade receipts
+const (
+	trackRefreshInterval = 3 * time.Second // Minimum time between edits of the tracking message
+	trackMaxDuration     = time.Hour       // Tracking stops by itself after this time
+)
+
+// tradeReceipt builds the card confirming a purchased contract, with follow-up actions
+func tradeReceipt(msg *Message, f Formatter, req *TradeRequest, result *TradeResult, tags []string) *Response {
+	var sb strings.Builder
+	sb.WriteString("🧾 Trade receipt\n\n")
+	fmt.Fprintf(&sb, "Contract: %d\n", result.ContractID)
+	if result.Account.LoginID != "" {
+		fmt.Fprintf(&sb, "Account: %s\n", result.Account.Label())
+	}
+	fmt.Fprintf(&sb, "Symbol: %s %s\n", req.Symbol, contractLabel(f, req))
+	fmt.Fprintf(&sb, "Stake: %s\n", f.Money(result.BuyPrice, result.Currency))
+	fmt.Fprintf(&sb, "Payout: %s\n", f.Money(result.Payout, result.Currency))
+	start := result.PurchaseTime
+	if !req.StartAt.IsZero() {
+		start = req.StartAt
+		fmt.Fprintf(&sb, "Starts: %s\n", start.UTC().Format("15:04:05 UTC"))
+	}
+	fmt.Fprintf(&sb, "Expiry: %s\n", receiptExpiry(start, req.Duration))
+	writeLimits(&sb, f, req.Limits, result.Currency)
+	if len(tags) > 0 {
+		fmt.Fprintf(&sb, "Tags: %s\n", formatTags(tags))
+	}
+
+	id := strconv.FormatInt(result.ContractID, 10)
+
+	return NewResponse(msg).Text(sb.String()).Keyboard([][]Button{{
+		{Text: "📡 Track", CallbackData: "track:" + id},
+		{Text: "💸 Sell now", CallbackData: "sell:" + id},
+		{Text: "📈 Chart", CallbackData: "chart:" + req.Symbol},
+	}}).Build()
+}
+
+// receiptExpiry describes when a contract starting at the given time expires
+func receiptExpiry(start time.Time, duration Duration) string {
+	span, ok := duration.Span()
+	if !ok {
+		return fmt.Sprintf("after %d ticks", duration.Value)
+	}
+
+	layout := "15:04:05 UTC"
+	if span >= 24*time.Hour {
+		layout = "2006-01-02 15:04 UTC"
+	}
+
+	return start.Add(span).UTC().Format(layout)
+}
+
+// parseContractID reads the contract ID argument of a command
+func parseContractID(msg *Message, usage string) (int64, *Response) {
+	if len(msg.Args) > 0 {
+		if id, err := strconv.ParseInt(msg.Args[0], 10, 64); err == nil && id > 0 {
+			return id, nil
+		}
+	}
+
+	return 0, NewResponse(msg).Textf("❌ Please provide a contract ID. Example: %s 123456789", usage).Build()
+}
+
+// handleTrack posts the state of a contract and keeps it up to date until it settles, "/track <contract_id>"
+func (b *Bot) handleTrack(ctx context.Context, msg *Message) (*Response, error) {
+	contractID, usage := parseContractID(msg, "/track")
+	if usage != nil {
+		return usage, nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	f := b.formatter(ctx, msg)
+
+	// The first state is fetched right away, so errors are reported to the user
+	info, err := client.GetContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	if info.IsSold {
+		return trackResponse(f, msg.ChatID, 0, info), nil
+	}
+
+	username := msg.Username
+	chatID := msg.ChatID
+
+	err = b.background.Go(func(bgCtx context.Context, notifier Notifier) {
+		notifier = b.userNotifier(bgCtx, notifier, username)
+
+		msgID, err := notifier.Send(bgCtx, trackResponse(f, chatID, 0, info))
+		if err != nil {
+			log.Printf("Failed to post tracking of contract %d for %s: %v", contractID, username, err)
+			return
+		}
+
+		subCtx, unsubscribe := b.subscribe(bgCtx, chatID, fmt.Sprintf("tracking of contract %d", contractID), fmt.Sprintf("/track %d", contractID))
+		defer unsubscribe()
+
+		trackCtx, cancel := context.WithTimeout(subCtx, trackMaxDuration)
+		defer cancel()
+
+		updates, err := client.WatchContract(trackCtx, contractID)
+		if err != nil {
+			log.Printf("Failed to watch contract %d: %v", contractID, err)
+			return
+		}
+
+		var edited time.Time
+		for update := range updates {
+			info = update
+			settled := info.IsSold || info.Status != ContractStatusOpen
+
+			// Telegram limits how often a message can be edited, the final state is always shown
+			if !settled && time.Since(edited) < trackRefreshInterval {
+				continue
+			}
+
+			if _, err := notifier.Send(trackCtx, trackResponse(f, chatID, msgID, info)); err != nil {
+				log.Printf("Failed to update tracking of contract %d: %v", contractID, err)
+
+				// The outcome is sent as a new message once Telegram is reachable again
+				if settled {
+					b.notify(context.WithoutCancel(trackCtx), notifier, username, NotifySettlements, trackResponse(f, chatID, 0, info))
+				}
+			}
+			edited = time.Now()
+
+			if settled {
+				return
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start tracking: %w", err)
+	}
+
+	return nil, nil
+}
+
+// trackResponse renders the contract state, editing msgID when it's set
+func trackResponse(f Formatter, chatID int64, msgID int, info *ContractInfo) *Response {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "📡 Contract %d on %s\n\n", info.ContractID, info.Symbol)
+	fmt.Fprintf(&sb, "Status: %s\n", f.Outcome(info.Status, info.Profit))
+	fmt.Fprintf(&sb, "Entry: %s, spot: %s\n", f.Number(info.EntrySpot, 2), f.Number(info.CurrentSpot, 2))
+	fmt.Fprintf(&sb, "Stake: %s, payout: %s\n", f.Money(info.BuyPrice, info.Currency), f.Money(info.Payout, info.Currency))
+	fmt.Fprintf(&sb, "Profit: %s\n", f.SignedMoney(info.Profit, info.Currency))
+
+	open := !info.IsSold && info.Status == ContractStatusOpen
+	if open {
+		if info.TickCount > 0 {
+			fmt.Fprintf(&sb, "Ticks: %d of %d\n", info.TicksPassed, info.TickCount)
+		} else if !info.ExpiryTime.IsZero() {
+			fmt.Fprintf(&sb, "Expires in %s\n", time.Until(info.ExpiryTime).Round(time.Second))
+		}
+	}
+
+	rb := NewNotification(chatID).Text(sb.String()).Edit(msgID)
+	if open && info.IsValidToSell {
+		rb.Keyboard([][]Button{{
+			{Text: "💸 Sell now", CallbackData: fmt.Sprintf("sell:%d", info.ContractID)},
+		}})
+	}
+
+	return rb.Build()
+}
+
+// sellPicker lists open contracts of the account with buttons selling them
+func (b *Bot) sellPicker(ctx context.Context, msg *Message, client DerivClient) (*Response, error) {
+	contracts, err := client.GetOpenPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contracts) == 0 {
+		return NewResponse(msg).Text("💼 No open contracts to sell.").Build(), nil
+	}
+
+	f := b.formatter(ctx, msg)
+
+	var buttons [][]Button
+	for _, contract := range contracts {
+		if contract.Valued && !contract.IsValidToSell {
+			continue
+		}
+
+		buttons = append(buttons, []Button{{
+			Text: fmt.Sprintf("Sell %s %s, %s (%d)", contract.Symbol, contract.ContractType,
+				f.Money(contract.Stake, contract.Currency), contract.ContractID),
+			CallbackData: fmt.Sprintf("sell:%d", contract.ContractID),
+		}})
+	}
+
+	if len(buttons) == 0 {
+		return NewResponse(msg).Text("💼 None of the open contracts can be sold right now.").Build(), nil
+	}
+
+	return NewResponse(msg).Text("💼 Which contract do you want to sell at the current price?").Keyboard(buttons).Build(), nil
+}
+
+// handleSell sells an open contract back at the market price, "/sell <contract_id>", "/sell" lists open contracts to pick from
+func (b *Bot) handleSell(ctx context.Context, msg *Message) (*Response, error) {
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Args) == 0 {
+		return b.sellPicker(ctx, msg, client)
+	}
+
+	contractID, usage := parseContractID(msg, "/sell")
+	if usage != nil {
+		return usage, nil
+	}
+
+	// The stake is known for trades placed through the bot, other contracts are looked up before they're sold
+	var stake float64
+	record, err := b.journal.Get(ctx, msg.Username, contractID)
+	switch {
+	case err == nil:
+		stake = record.Stake
+	case !errors.Is(err, ErrNotFound):
+		log.Printf("Failed to load trade %d: %v", contractID, err)
+	}
+
+	if stake == 0 {
+		if info, err := client.GetContract(ctx, contractID); err != nil {
+			log.Printf("Failed to get buy price of contract %d: %v", contractID, err)
+		} else {
+			stake = info.BuyPrice
+		}
+	}
+
+	result, err := client.SellContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sell contract: %w", err)
+	}
+
+	// The sale shows up in the journal and counts towards the loss streak right away
+	if _, err := b.settleUser(ctx, msg.Username); err != nil {
+		log.Printf("Failed to settle trades of %s: %v", msg.Username, err)
+	}
+
+	f := b.formatter(ctx, msg)
+	text := fmt.Sprintf("💸 Contract %d sold for %s", contractID, f.Money(result.SoldFor, client.Currency()))
+	if stake > 0 {
+		text += fmt.Sprintf(" (profit %s)", f.SignedMoney(result.SoldFor-stake, client.Currency()))
+	}
+	if result.BalanceAfter > 0 {
+		text += fmt.Sprintf("\nBalance: %s", f.Money(result.BalanceAfter, client.Currency()))
+	}
+
+	return NewResponse(msg).Text(text).Build(), nil
+}
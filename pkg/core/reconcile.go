@@ -0,0 +1,259 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// maxReconcileLines is how many discrepancies of a kind the summary lists
+	maxReconcileLines = 10
+	// profitTolerance is the difference of profits still considered equal, it absorbs rounding of amounts
+	profitTolerance = 0.005
+)
+
+// ReconciliationConfig holds settings of the nightly reconciliation of the trade journal against Deriv
+type ReconciliationConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	At      time.Duration `mapstructure:"at"`      // Time after midnight UTC the previous day is reconciled
+	ChatID  int64         `mapstructure:"chat_id"` // Admin channel receiving summaries, admins' chats when unset
+}
+
+// reconciliation compares trades of one Deriv account placed in a day with its profit table
+type reconciliation struct {
+	Account   string
+	Trades    int               // Journaled trades placed in the day
+	Matched   int               // Trades whose outcome agrees with Deriv
+	Missed    []*TradeRecord    // Closed at Deriv but still open in the journal
+	Mismatch  []*TradeRecord    // Settled with a different profit than Deriv reports
+	Unknown   []*TradeRecord    // Settled in the journal but not closed at Deriv
+	Outside   []*ClosedContract // Closed at Deriv but never placed through the bot
+	profitsOf map[int64]float64 // Deriv profits of mismatched trades by contract ID
+	err       error
+}
+
+// OK reports whether the account has no discrepancies
+func (r *reconciliation) OK() bool {
+	return r.err == nil && len(r.Missed)+len(r.Mismatch)+len(r.Unknown)+len(r.Outside) == 0
+}
+
+// reconcileAccount matches journal records against closed contracts of the same account
+func reconcileAccount(account string, records []*TradeRecord, contracts []ClosedContract) *reconciliation {
+	rec := &reconciliation{Account: account, Trades: len(records), profitsOf: make(map[int64]float64)}
+
+	closed := make(map[int64]*ClosedContract, len(contracts))
+	for i := range contracts {
+		closed[contracts[i].ContractID] = &contracts[i]
+	}
+
+	for _, record := range records {
+		contract, ok := closed[record.ContractID]
+		delete(closed, record.ContractID)
+
+		switch {
+		case !ok && record.Settled():
+			rec.Unknown = append(rec.Unknown, record)
+		case !ok:
+			// Still open at Deriv as well, e.g. a multiplier running over midnight
+			rec.Matched++
+		case !record.Settled():
+			rec.Missed = append(rec.Missed, record)
+		case math.Abs(record.Profit-contract.Profit()) > profitTolerance:
+			rec.Mismatch = append(rec.Mismatch, record)
+			rec.profitsOf[record.ContractID] = contract.Profit()
+		default:
+			rec.Matched++
+		}
+	}
+
+	for _, contract := range closed {
+		rec.Outside = append(rec.Outside, contract)
+	}
+	slices.SortFunc(rec.Outside, func(a, b *ClosedContract) int {
+		return a.PurchaseTime.Compare(b.PurchaseTime)
+	})
+
+	return rec
+}
+
+// reconcileDay reconciles trades placed through the bot in the UTC day starting at day with Deriv,
+// trades are grouped by the account they were placed on
+func (b *Bot) reconcileDay(ctx context.Context, day time.Time) ([]*reconciliation, error) {
+	usernames, err := b.journal.Usernames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list traders: %w", err)
+	}
+
+	end := day.Add(24 * time.Hour)
+	clients := make(map[string]DerivClient)
+	records := make(map[string][]*TradeRecord)
+	var failed []*reconciliation
+
+	for _, username := range usernames {
+		client, err := b.clientFor(ctx, username)
+		if err != nil {
+			failed = append(failed, &reconciliation{Account: "account of " + username, err: err})
+			continue
+		}
+
+		account := client.ActiveAccount().Label()
+		clients[account] = client
+
+		trades, err := b.journal.Query(ctx, &JournalFilter{Username: username, From: day, To: end})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load trades of %s: %w", username, err)
+		}
+
+		records[account] = append(records[account], trades...)
+	}
+
+	// The shared account is checked for trades placed outside the bot even when nobody traded
+	if !b.cfg.RequireOwnAccount {
+		clients[b.derivClient.ActiveAccount().Label()] = b.derivClient
+	}
+
+	results := failed
+	for account, client := range clients {
+		contracts, err := client.GetProfitTable(ctx, day, end)
+		if err != nil {
+			results = append(results, &reconciliation{Account: account, err: fmt.Errorf("failed to get profit table: %w", err)})
+			continue
+		}
+
+		results = append(results, reconcileAccount(account, records[account], contracts))
+	}
+
+	slices.SortFunc(results, func(a, b *reconciliation) int {
+		return strings.Compare(a.Account, b.Account)
+	})
+
+	return results, nil
+}
+
+// formatReconciliation writes the summary of a day posted to admins
+func formatReconciliation(f Formatter, day time.Time, results []*reconciliation) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🧾 Reconciliation of %s (UTC)\n", day.Format(time.DateOnly))
+
+	if len(results) == 0 {
+		sb.WriteString("\nNo accounts to reconcile.")
+		return sb.String()
+	}
+
+	for _, rec := range results {
+		fmt.Fprintf(&sb, "\n%s\n", rec.Account)
+
+		if rec.err != nil {
+			fmt.Fprintf(&sb, "❌ Not reconciled: %v\n", rec.err)
+			continue
+		}
+
+		if rec.OK() {
+			fmt.Fprintf(&sb, "✅ All %d trades match Deriv\n", rec.Trades)
+			continue
+		}
+
+		fmt.Fprintf(&sb, "%d trades, %d match Deriv\n", rec.Trades, rec.Matched)
+
+		writeReconcileLines(&sb, "⚠️ Missed settlements", rec.Missed, func(r *TradeRecord) string {
+			return fmt.Sprintf("#%d %s %s of %s", r.ContractID, r.Symbol, r.ContractType, r.Username)
+		})
+		writeReconcileLines(&sb, "⚠️ Profit differs", rec.Mismatch, func(r *TradeRecord) string {
+			return fmt.Sprintf("#%d of %s: journal %s, Deriv %s", r.ContractID, r.Username,
+				f.Signed(r.Profit, 2), f.Signed(rec.profitsOf[r.ContractID], 2))
+		})
+		writeReconcileLines(&sb, "⚠️ Not closed at Deriv", rec.Unknown, func(r *TradeRecord) string {
+			return fmt.Sprintf("#%d %s %s of %s, journaled as %s", r.ContractID, r.Symbol, r.ContractType, r.Username, r.Status)
+		})
+		writeReconcileLines(&sb, "⚠️ Placed outside the bot", rec.Outside, func(c *ClosedContract) string {
+			return fmt.Sprintf("#%d %s %s at %s, P&L %s", c.ContractID, c.Symbol, c.ContractType,
+				c.PurchaseTime.UTC().Format("15:04"), f.Signed(c.Profit(), 2))
+		})
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// writeReconcileLines lists up to maxReconcileLines discrepancies of a kind
+func writeReconcileLines[T any](sb *strings.Builder, title string, items []T, line func(T) string) {
+	if len(items) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "%s: %d\n", title, len(items))
+	for _, item := range items[:min(len(items), maxReconcileLines)] {
+		fmt.Fprintf(sb, "  %s\n", line(item))
+	}
+	if len(items) > maxReconcileLines {
+		fmt.Fprintf(sb, "  … and %d more\n", len(items)-maxReconcileLines)
+	}
+}
+
+// runReconciliation reconciles the previous UTC day every night and posts the summary to admins
+func (b *Bot) runReconciliation(ctx context.Context, notifier Notifier) {
+	for {
+		now := time.Now().UTC()
+		next := now.Truncate(24 * time.Hour).Add(b.cfg.Reconciliation.At)
+		if !next.After(now) {
+			next = next.Add(24 * time.Hour)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		day := next.Truncate(24 * time.Hour).Add(-24 * time.Hour)
+
+		results, err := b.reconcileDay(ctx, day)
+		if err != nil {
+			log.Printf("Failed to reconcile trades of %s: %v", day.Format(time.DateOnly), err)
+			continue
+		}
+
+		b.postReconciliation(ctx, notifier, formatReconciliation(NewFormatter("").WithTheme(b.theme), day, results))
+	}
+}
+
+// postReconciliation sends the summary to the admin channel or, without one, to admins
+func (b *Bot) postReconciliation(ctx context.Context, notifier Notifier, text string) {
+	if b.cfg.Reconciliation.ChatID == 0 {
+		b.alertAdmins(ctx, notifier, text)
+		return
+	}
+
+	if _, err := notifier.Send(ctx, NewNotification(b.cfg.Reconciliation.ChatID).Text(text).Build()); err != nil {
+		log.Printf("Failed to post reconciliation: %v", err)
+	}
+}
+
+// handleReconcile reconciles a day on demand, "/reconcile [YYYY-MM-DD]", yesterday by default
+func (b *Bot) handleReconcile(ctx context.Context, msg *Message) (*Response, error) {
+	if !b.isAdmin(msg.Username) {
+		return NewResponse(msg).Text("⚠️ This command is available to admins only.").Build(), nil
+	}
+
+	day := time.Now().UTC().Truncate(24 * time.Hour).Add(-24 * time.Hour)
+	if len(msg.Args) == 1 {
+		parsed, err := time.Parse(time.DateOnly, msg.Args[0])
+		if err != nil {
+			return NewResponse(msg).Text("❌ Usage: /reconcile [YYYY-MM-DD]").Build(), nil
+		}
+		day = parsed
+	} else if len(msg.Args) > 1 {
+		return NewResponse(msg).Text("❌ Usage: /reconcile [YYYY-MM-DD]").Build(), nil
+	}
+
+	results, err := b.reconcileDay(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg).Text(formatReconciliation(b.formatter(ctx, msg), day, results)).Build(), nil
+}
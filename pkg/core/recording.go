@@ -0,0 +1,101 @@
+package core
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+)
+
+const (
+	recordingBuffer        = 1024          // Ticks waiting to be written before new ones are dropped
+	recordingFlushInterval = time.Minute   // How often recorded ticks are made readable
+	recordingCleanupPeriod = 6 * time.Hour // How often files past the retention period are removed
+)
+
+// TickRecorder archives ticks of symbols into daily files and reads them back
+type TickRecorder interface {
+	// Symbols returns the symbols whose ticks are recorded
+	Symbols() []string
+	Record(symbol string, tick HistoricalDataPoint) error
+	Flush() error
+	// Cleanup removes files past the retention period and returns their number
+	Cleanup(now time.Time) (int, error)
+	// Replay returns ticks of the symbol recorded on the UTC day of the given time
+	Replay(symbol string, day time.Time) ([]HistoricalDataPoint, error)
+	Close() error
+}
+
+// recordedTick is a tick waiting to be written
+type recordedTick struct {
+	symbol string
+	tick   HistoricalDataPoint
+}
+
+// SetTickRecorder enables recording ticks of the recorder's symbols while the bot runs
+func (b *Bot) SetTickRecorder(recorder TickRecorder) {
+	b.recorder = recorder
+}
+
+// recordsSymbol reports whether ticks of the symbol are recorded
+func (b *Bot) recordsSymbol(symbol string) bool {
+	return b.recorder != nil && slices.Contains(b.recorder.Symbols(), symbol)
+}
+
+// runRecording writes ticks of recorded symbols published on the event bus, it's a service of the bot
+func (b *Bot) runRecording(ctx context.Context, _ Notifier) {
+	ticks := make(chan recordedTick, recordingBuffer)
+
+	for _, symbol := range b.recorder.Symbols() {
+		unsubscribe := b.events.Subscribe(EventFilter{Type: EventTick, Symbol: symbol}, func(event Event) {
+			select {
+			case ticks <- recordedTick{symbol: event.Symbol, tick: event.Price}:
+			default:
+				log.Printf("Tick recording is falling behind, dropped a tick of %s", event.Symbol)
+			}
+		})
+		defer unsubscribe()
+	}
+
+	defer func() {
+		if err := b.recorder.Close(); err != nil {
+			log.Printf("Failed to close tick recordings: %v", err)
+		}
+	}()
+
+	flush := time.NewTicker(recordingFlushInterval)
+	defer flush.Stop()
+
+	cleanup := time.NewTicker(recordingCleanupPeriod)
+	defer cleanup.Stop()
+
+	b.cleanupRecordings()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticks:
+			if err := b.recorder.Record(t.symbol, t.tick); err != nil {
+				log.Printf("Failed to record tick of %s: %v", t.symbol, err)
+			}
+		case <-flush.C:
+			if err := b.recorder.Flush(); err != nil {
+				log.Printf("Failed to flush tick recordings: %v", err)
+			}
+		case <-cleanup.C:
+			b.cleanupRecordings()
+		}
+	}
+}
+
+// cleanupRecordings removes recordings past the retention period
+func (b *Bot) cleanupRecordings() {
+	removed, err := b.recorder.Cleanup(time.Now())
+	if err != nil {
+		log.Printf("Failed to remove old tick recordings: %v", err)
+	}
+	if removed > 0 {
+		log.Printf("Removed %d tick recordings past the retention period", removed)
+	}
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reportPeriod is the period covered by the weekly report
+const reportPeriod = 7 * 24 * time.Hour
+
+// weeklyStats summarizes settled trades of a week
+type weeklyStats struct {
+	Trades        int
+	Wins          int
+	Profit        float64
+	BestDay       string // Day with the highest profit, e.g. Mon Jan 2
+	BestDayProfit float64
+	TradingDays   int
+	WinStreak     int // Longest run of consecutive wins
+	Tagged        int // Trades journaled with tags
+	MinStake      float64
+	MaxStake      float64
+
+	// Cool-downs after loss streaks, only counted when loss streak nudges are enabled
+	Cooldowns          int
+	CooldownsRespected int
+}
+
+// weeklyReport computes stats of trades settled since the given time, records must be ordered by placement time
+func weeklyReport(records []*TradeRecord, since time.Time, streaks LossStreakConfig) *weeklyStats {
+	stats := &weeklyStats{}
+	days := make(map[string]float64)
+
+	var winRun, lossRun int
+	var lastLoss time.Time
+
+	for _, record := range records {
+		if !record.Settled() || record.PlacedAt.Before(since) {
+			continue
+		}
+
+		// A trade placed right after the loss streak threshold was reached shows whether the cool-down was kept
+		if streaks.Threshold > 0 && lossRun >= streaks.Threshold {
+			stats.Cooldowns++
+			if record.PlacedAt.Sub(lastLoss) >= streaks.Cooldown {
+				stats.CooldownsRespected++
+			}
+		}
+
+		stats.Trades++
+		stats.Profit += record.Profit
+		days[record.PlacedAt.UTC().Format(time.DateOnly)] += record.Profit
+
+		if len(record.Tags) > 0 {
+			stats.Tagged++
+		}
+
+		if stats.MinStake == 0 || record.Stake < stats.MinStake {
+			stats.MinStake = record.Stake
+		}
+		stats.MaxStake = max(stats.MaxStake, record.Stake)
+
+		if record.Profit > 0 {
+			stats.Wins++
+			winRun++
+			lossRun = 0
+			stats.WinStreak = max(stats.WinStreak, winRun)
+		} else {
+			winRun = 0
+			lossRun++
+			lastLoss = record.SettledAt
+		}
+	}
+
+	stats.TradingDays = len(days)
+
+	// Ties go to the earlier day, so the report doesn't change between runs
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	var best string
+	for _, date := range dates {
+		if best == "" || days[date] > stats.BestDayProfit {
+			best, stats.BestDayProfit = date, days[date]
+		}
+	}
+
+	if day, err := time.Parse(time.DateOnly, best); err == nil {
+		stats.BestDay = day.Format("Mon Jan 2")
+	}
+
+	return stats
+}
+
+// badge is an achievement shown in the weekly report
+type badge struct {
+	Emoji string
+	Name  string
+	Hint  string
+}
+
+// weeklyBadges returns badges earned with the week's trading
+func weeklyBadges(stats *weeklyStats) []badge {
+	var badges []badge
+
+	if stats.Profit > 0 {
+		badges = append(badges, badge{"📈", "Green week", "finished the week in profit"})
+	}
+	if stats.WinStreak >= 3 {
+		badges = append(badges, badge{"🔥", "Hot streak", fmt.Sprintf("%d wins in a row", stats.WinStreak)})
+	}
+	if stats.TradingDays >= 5 {
+		badges = append(badges, badge{"🗓️", "Regular", fmt.Sprintf("traded on %d days", stats.TradingDays)})
+	}
+	if stats.Trades >= 5 && stats.Tagged == stats.Trades {
+		badges = append(badges, badge{"📓", "Journaler", "every trade tagged with its setup"})
+	}
+	if stats.Trades >= 5 && stats.MaxStake <= 2*stats.MinStake {
+		badges = append(badges, badge{"⚖️", "Steady sizing", "largest stake within twice the smallest"})
+	}
+	if stats.Cooldowns > 0 && stats.CooldownsRespected == stats.Cooldowns {
+		badges = append(badges, badge{"🧘", "Cool head", "took a break after every losing streak"})
+	}
+
+	return badges
+}
+
+// handleWeekly shows the report of the last 7 days, "/weekly badges on|off" shows or hides badges
+func (b *Bot) handleWeekly(ctx context.Context, msg *Message) (*Response, error) {
+	rb := NewResponse(msg)
+
+	settings, err := b.getSettings(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Args) > 0 {
+		if len(msg.Args) != 2 || !strings.EqualFold(msg.Args[0], "badges") {
+			return rb.Text("❌ Usage: /weekly [badges on|off]").Build(), nil
+		}
+
+		switch strings.ToLower(msg.Args[1]) {
+		case "on":
+			settings.HideBadges = false
+			rb.Text("✅ Badges will be shown in weekly reports")
+		case "off":
+			settings.HideBadges = true
+			rb.Text("✅ Badges hidden from weekly reports")
+		default:
+			return rb.Text("❌ Usage: /weekly [badges on|off]").Build(), nil
+		}
+
+		if err := b.saveSettings(ctx, msg.Username, settings); err != nil {
+			return nil, err
+		}
+
+		return rb.Build(), nil
+	}
+
+	if _, err := b.settleUser(ctx, msg.Username); err != nil {
+		return nil, err
+	}
+
+	records, err := b.userTrades(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := weeklyReport(records, time.Now().Add(-reportPeriod), b.cfg.LossStreak)
+	if stats.Trades == 0 {
+		return rb.Text("🗓️ No settled trades in the last 7 days.").Build(), nil
+	}
+
+	f := b.formatter(ctx, msg)
+	currency := b.accountCurrency(ctx, msg.Username)
+
+	var sb strings.Builder
+	sb.WriteString("🗓️ Weekly report\n\n")
+	fmt.Fprintf(&sb, "Trades: %d, won %d (%s%%)\n", stats.Trades, stats.Wins, f.Number(float64(stats.Wins)/float64(stats.Trades)*100, 0))
+	fmt.Fprintf(&sb, "P&L: %s\n", f.SignedMoney(stats.Profit, currency))
+	fmt.Fprintf(&sb, "Best day: %s (%s)\n", stats.BestDay, f.SignedMoney(stats.BestDayProfit, currency))
+	fmt.Fprintf(&sb, "Longest win streak: %d\n", stats.WinStreak)
+	fmt.Fprintf(&sb, "Journaled trades: %d of %d\n", stats.Tagged, stats.Trades)
+	if stats.Cooldowns > 0 {
+		fmt.Fprintf(&sb, "Cool-downs respected: %d of %d\n", stats.CooldownsRespected, stats.Cooldowns)
+	}
+
+	if !settings.HideBadges {
+		if badges := weeklyBadges(stats); len(badges) > 0 {
+			sb.WriteString("\nBadges:\n")
+			for _, badge := range badges {
+				fmt.Fprintf(&sb, "%s %s: %s\n", badge.Emoji, badge.Name, badge.Hint)
+			}
+			sb.WriteString("\nUse /weekly badges off to hide badges.")
+		}
+	}
+
+	return rb.Text(sb.String()).Build(), nil
+}
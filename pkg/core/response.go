@@ -0,0 +1,92 @@
+package core
+
+import "fmt"
+
+// ResponseBuilder builds a response to a message step by step, e.g.
+//
+//	NewResponse(msg).Text("📈 Pick a symbol").Keyboard(buttons).Build()
+//
+// New capabilities of responses get a method here, so handlers don't depend on the layout of Response.
+type ResponseBuilder struct {
+	resp Response
+}
+
+// NewResponse starts a response replying to the message in its chat
+func NewResponse(msg *Message) *ResponseBuilder {
+	return &ResponseBuilder{resp: Response{
+		ReplyToMessageID: msg.MessageID,
+		ChatID:           msg.ChatID,
+	}}
+}
+
+// NewNotification starts a message the bot sends to the chat on its own, e.g. an alert
+func NewNotification(chatID int64) *ResponseBuilder {
+	return &ResponseBuilder{resp: Response{ChatID: chatID}}
+}
+
+// Text sets the text of the response, it's the caption of photos and documents
+func (rb *ResponseBuilder) Text(text string) *ResponseBuilder {
+	rb.resp.Text = text
+	return rb
+}
+
+// Textf sets the text of the response formatted like fmt.Sprintf
+func (rb *ResponseBuilder) Textf(format string, args ...any) *ResponseBuilder {
+	rb.resp.Text = fmt.Sprintf(format, args...)
+	return rb
+}
+
+// Keyboard sets inline buttons of the response in rows
+func (rb *ResponseBuilder) Keyboard(buttons [][]Button) *ResponseBuilder {
+	rb.resp.Buttons = buttons
+	return rb
+}
+
+// Photo attaches the image file at the path
+func (rb *ResponseBuilder) Photo(path string) *ResponseBuilder {
+	rb.resp.PhotoPath = path
+	return rb
+}
+
+// Document attaches the file
+func (rb *ResponseBuilder) Document(doc *Document) *ResponseBuilder {
+	rb.resp.Document = doc
+	return rb
+}
+
+// Preformatted shows the text in a monospace block
+func (rb *ResponseBuilder) Preformatted() *ResponseBuilder {
+	rb.resp.Preformatted = true
+	return rb
+}
+
+// Edit replaces the text and buttons of the message instead of sending a new one
+func (rb *ResponseBuilder) Edit(messageID int) *ResponseBuilder {
+	rb.resp.EditMessageID = messageID
+	rb.resp.ReplyToMessageID = 0
+	return rb
+}
+
+// Delete removes the message from the chat, e.g. one containing secrets
+func (rb *ResponseBuilder) Delete(messageID int) *ResponseBuilder {
+	rb.resp.DeleteMessageID = messageID
+	return rb
+}
+
+// NoReply sends the response as a standalone message rather than a reply
+func (rb *ResponseBuilder) NoReply() *ResponseBuilder {
+	rb.resp.ReplyToMessageID = 0
+	return rb
+}
+
+// Silent delivers the response without a notification sound
+func (rb *ResponseBuilder) Silent() *ResponseBuilder {
+	rb.resp.Silent = true
+	return rb
+}
+
+// Build returns the response
+func (rb *ResponseBuilder) Build() *Response {
+	resp := rb.resp
+	return &resp
+}
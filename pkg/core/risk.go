@@ -0,0 +1,254 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucketDailyPnL is the storage bucket holding realized P&L of users on the current day
+const bucketDailyPnL = "daily_pnl"
+
+// riskEventTimeout bounds saving a settled trade to the daily P&L
+const riskEventTimeout = 5 * time.Second
+
+// RiskConfig holds limits enforced on every trade of a user, zero values disable them
+type RiskConfig struct {
+	MaxDailyLoss     float64 `mapstructure:"max_daily_loss"`     // Realized loss in a UTC day after which new trades are refused
+	MaxStake         float64 `mapstructure:"max_stake"`          // Largest stake of a single contract
+	MaxOpenPositions int     `mapstructure:"max_open_positions"` // Open contracts across all symbols
+	Currency         string  `mapstructure:"currency"`           // Currency of the amounts above, USD by default
+}
+
+// currency returns the currency the limits are given in
+func (c *RiskConfig) currency() string {
+	if c.Currency == "" {
+		return defaultCurrency
+	}
+	return strings.ToUpper(c.Currency)
+}
+
+// converter converts an amount between currencies
+type converter func(ctx context.Context, amount float64, from, to string) (float64, error)
+
+// DailyPnL is the realized P&L of a user in one account currency in a UTC day
+type DailyPnL struct {
+	Profit    float64 `json:"profit"`
+	Trades    int     `json:"trades"`
+	Contracts []int64 `json:"contracts,omitempty"` // Trades counted in the day, so none is counted twice
+}
+
+// dailyPnLs is the realized P&L of a user in a UTC day by account currency, amounts of accounts
+// in different currencies are never added up
+type dailyPnLs struct {
+	Day        string               `json:"day"` // YYYY-MM-DD
+	Currencies map[string]*DailyPnL `json:"currencies"`
+}
+
+// RiskManager tracks realized P&L of users per day and checks trades against the configured limits.
+// Limits are converted into the currency of the account they're checked for, so they mean the same
+// amount of money on fiat and crypto accounts.
+type RiskManager struct {
+	mu      sync.Mutex // Serializes updates of daily P&L
+	cfg     RiskConfig
+	storage Storage
+	convert converter
+}
+
+// newRiskManager creates a risk manager keeping daily P&L in the storage
+func newRiskManager(cfg RiskConfig, storage Storage, convert converter) *RiskManager {
+	return &RiskManager{cfg: cfg, storage: storage, convert: convert}
+}
+
+// limit converts an amount of the limits into the currency of an account
+func (r *RiskManager) limit(ctx context.Context, amount float64, currency string) (float64, error) {
+	converted, err := r.convert(ctx, amount, r.cfg.currency(), currency)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert risk limit to %s: %w", currency, err)
+	}
+	return converted, nil
+}
+
+// day returns the daily P&L of the user for the day of now, it has no currencies when nothing settled yet that day
+func (r *RiskManager) day(ctx context.Context, username string, now time.Time) (*dailyPnLs, error) {
+	day := now.UTC().Format(time.DateOnly)
+
+	var pnls dailyPnLs
+	err := r.storage.Get(ctx, bucketDailyPnL, username, &pnls)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load daily P&L: %w", err)
+	}
+
+	if pnls.Day != day || pnls.Currencies == nil {
+		pnls = dailyPnLs{Day: day, Currencies: make(map[string]*DailyPnL)}
+	}
+
+	return &pnls, nil
+}
+
+// today returns the daily P&L of the user in the currency for the day of now, it's empty when nothing settled yet
+func (r *RiskManager) today(ctx context.Context, username, currency string, now time.Time) (*DailyPnL, error) {
+	pnls, err := r.day(ctx, username, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if pnl, ok := pnls.Currencies[currency]; ok {
+		return pnl, nil
+	}
+
+	return &DailyPnL{}, nil
+}
+
+// recordSettlement adds the outcome of a settled trade to the P&L of its day, a trade already counted is ignored
+func (r *RiskManager) recordSettlement(ctx context.Context, record *TradeRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pnls, err := r.day(ctx, record.Username, record.SettledAt)
+	if err != nil {
+		return err
+	}
+
+	pnl, ok := pnls.Currencies[record.Currency]
+	if !ok {
+		pnl = &DailyPnL{}
+		pnls.Currencies[record.Currency] = pnl
+	}
+
+	if slices.Contains(pnl.Contracts, record.ContractID) {
+		return nil
+	}
+
+	pnl.Profit += record.Profit
+	pnl.Trades++
+	pnl.Contracts = append(pnl.Contracts, record.ContractID)
+
+	if err := r.storage.Put(ctx, bucketDailyPnL, record.Username, pnls); err != nil {
+		return fmt.Errorf("failed to save daily P&L: %w", err)
+	}
+
+	return nil
+}
+
+// check returns the reason buying contracts with the stakes breaks a limit, or an empty string when it's allowed
+func (r *RiskManager) check(ctx context.Context, f Formatter, currency, username string, client DerivClient, stakes ...float64) (string, error) {
+	if r.cfg.MaxStake > 0 {
+		limit, err := r.limit(ctx, r.cfg.MaxStake, currency)
+		if err != nil {
+			return "", err
+		}
+
+		for _, stake := range stakes {
+			if stake > limit {
+				return fmt.Sprintf("⚠️ A stake of %s is above the limit of %s per trade.",
+					f.Money(stake, currency), f.Money(limit, currency)), nil
+			}
+		}
+	}
+
+	if r.cfg.MaxDailyLoss > 0 {
+		limit, err := r.limit(ctx, r.cfg.MaxDailyLoss, currency)
+		if err != nil {
+			return "", err
+		}
+
+		pnl, err := r.today(ctx, username, currency, time.Now())
+		if err != nil {
+			return "", err
+		}
+
+		if -pnl.Profit >= limit {
+			return fmt.Sprintf("🛑 You've lost %s today, reaching the daily loss limit of %s. Trading is available again from 00:00 UTC.",
+				f.Money(-pnl.Profit, currency), f.Money(limit, currency)), nil
+		}
+	}
+
+	if limit := r.cfg.MaxOpenPositions; limit > 0 {
+		positions, err := client.GetOpenPositions(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to check open positions: %w", err)
+		}
+
+		if len(positions)+len(stakes) > limit {
+			return fmt.Sprintf("⚠️ You have %d open positions, the limit is %d at a time. "+
+				"Wait for one to settle or sell it first with /sell <contract_id>.\n\n%s",
+				len(positions), limit, formatPositions(f, positions)), nil
+		}
+	}
+
+	return "", nil
+}
+
+// riskLimited returns the reason buying contracts with the stakes breaks a risk limit of the user,
+// or an empty string when it's allowed. Trades are settled first, so the daily loss is up to date.
+func (b *Bot) riskLimited(ctx context.Context, msg *Message, client DerivClient, stakes ...float64) (string, error) {
+	if b.cfg.Risk.MaxDailyLoss > 0 {
+		if _, err := b.settleUser(ctx, msg.Username); err != nil {
+			return "", err
+		}
+	}
+
+	return b.risk.check(ctx, b.formatter(ctx, msg), client.Currency(), msg.Username, client, stakes...)
+}
+
+// recordDailyPnL keeps the realized P&L of the day up to date as trades settle
+func (b *Bot) recordDailyPnL(event Event) {
+	if event.Trade == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), riskEventTimeout)
+	defer cancel()
+
+	if err := b.risk.recordSettlement(ctx, event.Trade); err != nil {
+		log.Printf("Failed to record P&L of trade %d: %v", event.Trade.ContractID, err)
+	}
+}
+
+// describeRisk writes the risk limits of the user and their state in each account currency for the support view
+func (b *Bot) describeRisk(ctx context.Context, sb *strings.Builder, f Formatter, username string) error {
+	cfg := b.cfg.Risk
+	if cfg.MaxDailyLoss <= 0 && cfg.MaxStake <= 0 && cfg.MaxOpenPositions <= 0 {
+		return nil
+	}
+
+	pnls, err := b.risk.day(ctx, username, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if len(pnls.Currencies) == 0 {
+		sb.WriteString("Realized today: no settled trades")
+		if cfg.MaxDailyLoss > 0 {
+			fmt.Fprintf(sb, ", daily loss limit %s", f.Money(cfg.MaxDailyLoss, cfg.currency()))
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, currency := range slices.Sorted(maps.Keys(pnls.Currencies)) {
+		pnl := pnls.Currencies[currency]
+
+		fmt.Fprintf(sb, "Realized today: %s over %d trades", f.SignedMoney(pnl.Profit, currency), pnl.Trades)
+		if cfg.MaxDailyLoss > 0 {
+			limit, err := b.risk.limit(ctx, cfg.MaxDailyLoss, currency)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(sb, ", daily loss limit %s", f.Money(limit, currency))
+			if -pnl.Profit >= limit {
+				sb.WriteString(", 🛑 reached")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	return nil
+}
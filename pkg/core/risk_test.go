@@ -0,0 +1,171 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// usdRates converts amounts from USD at fixed rates
+func usdRates(_ context.Context, amount float64, from, to string) (float64, error) {
+	rates := map[string]float64{"USD": 1, "EUR": 0.9, "BTC": 0.00002}
+
+	rate, ok := rates[to]
+	if from != "USD" || !ok {
+		return 0, fmt.Errorf("no rate %s/%s", from, to)
+	}
+	return amount * rate, nil
+}
+
+func TestRecordSettlementCountsTradeOnce(t *testing.T) {
+	ctx := context.Background()
+	risk := newRiskManager(RiskConfig{MaxDailyLoss: 20}, newMemStorage(), usdRates)
+	settledAt := time.Now()
+
+	loss := &TradeRecord{ContractID: 1, Username: "alice", Currency: "USD", Profit: -10, SettledAt: settledAt}
+	win := &TradeRecord{ContractID: 2, Username: "alice", Currency: "USD", Profit: 4, SettledAt: settledAt}
+
+	// Racing settlements publish the same trade more than once
+	var wg sync.WaitGroup
+	for range 5 {
+		for _, record := range []*TradeRecord{loss, win} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := risk.recordSettlement(ctx, record); err != nil {
+					t.Errorf("recordSettlement() error = %v", err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	pnl, err := risk.today(ctx, "alice", "USD", settledAt)
+	if err != nil {
+		t.Fatalf("today() error = %v", err)
+	}
+
+	if pnl.Profit != -6 || pnl.Trades != 2 {
+		t.Errorf("daily P&L = %v over %d trades, want -6 over 2 trades", pnl.Profit, pnl.Trades)
+	}
+
+	reason, err := risk.check(ctx, NewFormatter(""), "USD", "alice", nil, 1)
+	if err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	if reason != "" {
+		t.Errorf("check() refused a trade at a loss of 6 with a limit of 20: %s", reason)
+	}
+}
+
+func TestRecordSettlementStartsNewDay(t *testing.T) {
+	ctx := context.Background()
+	risk := newRiskManager(RiskConfig{}, newMemStorage(), usdRates)
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	if err := risk.recordSettlement(ctx, &TradeRecord{ContractID: 1, Username: "alice", Currency: "USD", Profit: -5, SettledAt: yesterday}); err != nil {
+		t.Fatalf("recordSettlement() error = %v", err)
+	}
+
+	pnl, err := risk.today(ctx, "alice", "USD", time.Now())
+	if err != nil {
+		t.Fatalf("today() error = %v", err)
+	}
+
+	if pnl.Profit != 0 || pnl.Trades != 0 || len(pnl.Contracts) != 0 {
+		t.Errorf("today's P&L = %+v, want it empty", pnl)
+	}
+}
+
+func TestRecordSettlementKeepsCurrenciesApart(t *testing.T) {
+	ctx := context.Background()
+	risk := newRiskManager(RiskConfig{MaxDailyLoss: 20}, newMemStorage(), usdRates)
+	now := time.Now()
+
+	records := []*TradeRecord{
+		{ContractID: 1, Username: "alice", Currency: "USD", Profit: -15, SettledAt: now},
+		{ContractID: 2, Username: "alice", Currency: "BTC", Profit: -0.001, SettledAt: now},
+	}
+	for _, record := range records {
+		if err := risk.recordSettlement(ctx, record); err != nil {
+			t.Fatalf("recordSettlement() error = %v", err)
+		}
+	}
+
+	pnl, err := risk.today(ctx, "alice", "USD", now)
+	if err != nil {
+		t.Fatalf("today() error = %v", err)
+	}
+	if pnl.Profit != -15 || pnl.Trades != 1 {
+		t.Errorf("USD P&L = %v over %d trades, want -15 over 1 trade", pnl.Profit, pnl.Trades)
+	}
+
+	if err := risk.recordSettlement(ctx, &TradeRecord{ContractID: 3, Username: "alice", Currency: "BTC", Profit: -10, SettledAt: now}); err != nil {
+		t.Fatalf("recordSettlement() error = %v", err)
+	}
+
+	reason, err := risk.check(ctx, NewFormatter(""), "USD", "alice", nil, 1)
+	if err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	if reason != "" {
+		t.Errorf("check() counted BTC losses towards the USD limit: %s", reason)
+	}
+}
+
+func TestCheckConvertsLimitsToAccountCurrency(t *testing.T) {
+	ctx := context.Background()
+	risk := newRiskManager(RiskConfig{MaxDailyLoss: 100, MaxStake: 50}, newMemStorage(), usdRates)
+	f := NewFormatter("")
+
+	// 50 USD is 0.001 BTC, a stake of 50 BTC must not pass as being within 50 of the limit
+	reason, err := risk.check(ctx, f, "BTC", "alice", nil, 50)
+	if err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	if !strings.Contains(reason, "above the limit") {
+		t.Errorf("check() = %q, want a stake of 50 BTC refused", reason)
+	}
+
+	reason, err = risk.check(ctx, f, "BTC", "alice", nil, 0.0005)
+	if err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	if reason != "" {
+		t.Errorf("check() refused a stake of 0.0005 BTC below the converted limit: %s", reason)
+	}
+
+	// 100 USD is 0.002 BTC
+	now := time.Now()
+	if err := risk.recordSettlement(ctx, &TradeRecord{ContractID: 1, Username: "alice", Currency: "BTC", Profit: -0.0025, SettledAt: now}); err != nil {
+		t.Fatalf("recordSettlement() error = %v", err)
+	}
+
+	reason, err = risk.check(ctx, f, "BTC", "alice", nil, 0.0001)
+	if err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	if !strings.Contains(reason, "daily loss limit") {
+		t.Errorf("check() = %q, want the trade refused after losing more than the converted daily limit", reason)
+	}
+
+	// The same loss in EUR is far below 90 EUR
+	if err := risk.recordSettlement(ctx, &TradeRecord{ContractID: 2, Username: "alice", Currency: "EUR", Profit: -80, SettledAt: now}); err != nil {
+		t.Fatalf("recordSettlement() error = %v", err)
+	}
+
+	reason, err = risk.check(ctx, f, "EUR", "alice", nil, 40)
+	if err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	if reason != "" {
+		t.Errorf("check() refused an EUR trade within the converted limits: %s", reason)
+	}
+
+	if _, err := risk.check(ctx, f, "XYZ", "alice", nil, 1); err == nil {
+		t.Error("check() allowed a trade whose limits couldn't be converted")
+	}
+}
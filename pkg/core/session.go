@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrSessionExpired is returned when a linked Deriv account was logged out after inactivity
+var ErrSessionExpired = errors.New("deriv session expired")
+
+// Timing of inactivity logouts of linked accounts
+const (
+	sessionTouchInterval = time.Minute // Minimum time between saves of the last use of an account
+	sessionCheckInterval = time.Minute // Maximum time between checks for idle accounts
+)
+
+// sessionIdle reports whether the linked account wasn't used for longer than the configured timeout
+func (b *Bot) sessionIdle(creds *Credentials, now time.Time) bool {
+	timeout := b.cfg.AccountIdleTimeout
+	return timeout > 0 && !creds.LastUsed.IsZero() && now.Sub(creds.LastUsed) >= timeout
+}
+
+// touchSession records the use of the linked account, saving at most once per sessionTouchInterval
+func (b *Bot) touchSession(ctx context.Context, username string, creds *Credentials) {
+	if b.cfg.AccountIdleTimeout <= 0 || time.Since(creds.LastUsed) < sessionTouchInterval {
+		return
+	}
+
+	creds.LastUsed = time.Now()
+	if err := b.storage.Put(ctx, bucketCredentials, username, creds); err != nil {
+		log.Printf("Failed to save last use of the account of %s: %v", username, err)
+	}
+}
+
+// expireSession drops the token of the linked account and disconnects it, the user has to /connect again
+func (b *Bot) expireSession(ctx context.Context, username string, creds *Credentials) error {
+	token := creds.Token
+
+	creds.Token = ""
+	creds.Accounts = nil
+	creds.Expired = true
+	if err := b.storage.Put(ctx, bucketCredentials, username, creds); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	if err := b.pool.Release(token); err != nil {
+		log.Printf("Failed to release connection of %s: %v", username, err)
+	}
+
+	log.Printf("Logged out the Deriv account of %s after inactivity", username)
+
+	return nil
+}
+
+// runSessionExpiry periodically logs out linked accounts that weren't used for longer than the configured timeout,
+// so their connections don't stay authorized between uses
+func (b *Bot) runSessionExpiry(ctx context.Context, _ Notifier) {
+	ticker := time.NewTicker(min(b.cfg.AccountIdleTimeout, sessionCheckInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		usernames, err := b.storage.Keys(ctx, bucketCredentials)
+		if err != nil {
+			log.Printf("Failed to list linked accounts: %v", err)
+			continue
+		}
+
+		now := time.Now()
+		for _, username := range usernames {
+			var creds Credentials
+			if err := b.storage.Get(ctx, bucketCredentials, username, &creds); err != nil {
+				log.Printf("Failed to load credentials of %s: %v", username, err)
+				continue
+			}
+
+			switch {
+			case creds.Expired:
+			case creds.LastUsed.IsZero():
+				// Accounts linked before logouts were enabled start counting now
+				creds.LastUsed = now
+				if err := b.storage.Put(ctx, bucketCredentials, username, &creds); err != nil {
+					log.Printf("Failed to save last use of the account of %s: %v", username, err)
+				}
+			case b.sessionIdle(&creds, now):
+				if err := b.expireSession(ctx, username, &creds); err != nil {
+					log.Printf("Failed to log out the account of %s: %v", username, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// bucketSettings is the storage bucket holding per-user settings
+const bucketSettings = "settings"
+
+// UserSettings holds preferences of a single user
+type UserSettings struct {
+	DisplayCurrency string    `json:"display_currency,omitempty"` // Currency used to show converted amounts
+	ExpiryAlerts    bool      `json:"expiry_alerts,omitempty"`    // Notify shortly before contracts expire
+	Locale          string    `json:"locale,omitempty"`           // Number format, the Telegram language is used when empty
+	StakePresets    []float64 `json:"stake_presets,omitempty"`    // Stakes offered by /buy without an amount
+
+	MutedNotifications []string `json:"muted_notifications,omitempty"` // Notification categories turned off
+	HideBadges         bool     `json:"hide_badges,omitempty"`         // Leave badges out of weekly reports
+	PlainText          bool     `json:"plain_text,omitempty"`          // Screen-reader friendly output without emojis
+
+	Account string `json:"account,omitempty"` // Login ID of the shared account picked with /account, the main one when empty
+}
+
+// getSettings loads user settings, returning defaults for new users
+func (b *Bot) getSettings(ctx context.Context, username string) (*UserSettings, error) {
+	var settings UserSettings
+
+	err := b.storage.Get(ctx, bucketSettings, username, &settings)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// saveSettings persists user settings
+func (b *Bot) saveSettings(ctx context.Context, username string, settings *UserSettings) error {
+	if err := b.storage.Put(ctx, bucketSettings, username, settings); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionSummaryTimeout bounds sending session summaries on shutdown
+const sessionSummaryTimeout = 30 * time.Second
+
+// sessionChats remembers private chats of users who used the bot since it started
+type sessionChats struct {
+	mu    sync.Mutex
+	chats map[string]int64
+}
+
+// add remembers the user's private chat
+func (s *sessionChats) add(username string, chatID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chats[username] = chatID
+}
+
+// all returns private chats of all users by username
+func (s *sessionChats) all() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chats := make(map[string]int64, len(s.chats))
+	for username, chatID := range s.chats {
+		chats[username] = chatID
+	}
+
+	return chats
+}
+
+// ShutdownRequested returns a channel closed when an admin asks the bot to shut down with /shutdown
+func (b *Bot) ShutdownRequested() <-chan struct{} {
+	return b.shutdown
+}
+
+// handleShutdown stops the bot after sending users their session summaries, admin only
+func (b *Bot) handleShutdown(_ context.Context, msg *Message) (*Response, error) {
+	if !b.isAdmin(msg.Username) {
+		return NewResponse(msg).Text("⚠️ This command is available to admins only.").Build(), nil
+	}
+
+	log.Printf("Shutdown requested by %s", msg.Username)
+	b.shutdownOnce.Do(func() { close(b.shutdown) })
+
+	return NewResponse(msg).Text("🛑 Shutting down. Users active in this session get their summary before the bot exits.").Build(), nil
+}
+
+// sendSessionSummaries sends each user active since the bot started a summary of their trading in the session
+func (b *Bot) sendSessionSummaries(notifier Notifier, started time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionSummaryTimeout)
+	defer cancel()
+
+	for username, chatID := range b.sessions.all() {
+		summary, err := b.sessionSummary(ctx, username, started)
+		if err != nil {
+			log.Printf("Failed to prepare session summary of %s: %v", username, err)
+			continue
+		}
+
+		if _, err := b.notify(ctx, notifier, username, NotifyDigests, NewNotification(chatID).Text(summary).Build()); err != nil {
+			log.Printf("Failed to send session summary to %s: %v", username, err)
+		}
+	}
+}
+
+// sessionSummary renders trades placed and settled since the bot started and positions left open
+func (b *Bot) sessionSummary(ctx context.Context, username string, started time.Time) (string, error) {
+	if _, err := b.settleUser(ctx, username); err != nil {
+		log.Printf("Failed to settle trades of %s: %v", username, err)
+	}
+
+	records, err := b.userTrades(ctx, username)
+	if err != nil {
+		return "", err
+	}
+
+	var placed, settled, open int
+	var profit float64
+	for _, record := range records {
+		if !record.PlacedAt.Before(started) {
+			placed++
+		}
+
+		switch {
+		case !record.Settled():
+			open++
+		case !record.SettledAt.Before(started):
+			settled++
+			profit += record.Profit
+		}
+	}
+
+	f := b.formatterFor(ctx, username, "")
+	currency := b.accountCurrency(ctx, username)
+
+	var sb strings.Builder
+	sb.WriteString("👋 The bot is shutting down, here is your session summary\n\n")
+	fmt.Fprintf(&sb, "Session: since %s UTC\n", started.UTC().Format("2006-01-02 15:04"))
+	fmt.Fprintf(&sb, "Trades placed: %d\n", placed)
+	fmt.Fprintf(&sb, "P&L of %d settled trades: %s %s\n", settled, f.ProfitIcon(profit), f.SignedMoney(profit, currency))
+	fmt.Fprintf(&sb, "Open positions remaining: %d\n", open)
+	if open > 0 {
+		sb.WriteString("\nOpen contracts keep running on Deriv and settle on their own. Check them with /portfolio.")
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,233 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSimulatedTicks limits tick durations of simulations, longer ones don't fit into the last hour of ticks
+const maxSimulatedTicks = 100
+
+// simulationWindow is the start and end of a replayed trade
+type simulationWindow struct {
+	Start, End  time.Time
+	Entry, Exit float64
+	FromCandles bool // Entry and exit are taken from candles, so they are approximate
+	Ticks       int
+}
+
+// handleSimulate replays the latest historical window of the duration and tells whether a trade
+// placed at its start would have won, "/simulate <symbol> <stake> <duration> [up|down]"
+func (b *Bot) handleSimulate(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) < 3 {
+		return NewResponse(msg).Text("❌ Please provide symbol, stake and duration, optionally up or down. Example: /simulate R_50 10 5m up").Build(), nil
+	}
+
+	stake, err := strconv.ParseFloat(msg.Args[1], 64)
+	if err != nil || stake <= 0 {
+		return NewResponse(msg).Text("❌ Invalid stake. Please provide a positive number.").Build(), nil
+	}
+
+	duration, err := ParseDuration(msg.Args[2])
+	if err != nil {
+		return NewResponse(msg).Text("❌ Invalid duration. Use a number with t (ticks), s, m, h or d, e.g. 5t or 15m.").Build(), nil
+	}
+
+	var direction string
+	if len(msg.Args) > 3 {
+		direction = strings.ToLower(msg.Args[3])
+		if direction != "up" && direction != "down" {
+			return NewResponse(msg).Text("❌ Direction must be up or down").Build(), nil
+		}
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "simulate:" + strings.Join(append([]string{symbol}, msg.Args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	req, ok := simulationRequest(symbol, duration)
+	if !ok {
+		return NewResponse(msg).Textf("❌ Simulations support up to %d ticks or 15 days", maxSimulatedTicks).Build(), nil
+	}
+
+	data, err := b.derivClient.GetHistoricalData(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get historical data: %w", err)
+	}
+
+	window, ok := replayWindow(data, duration, req.Granularity)
+	if !ok {
+		return NewResponse(msg).Textf("❌ Not enough price history of %s to replay %s", symbol, duration).Build(), nil
+	}
+
+	payoutRatio, err := b.payoutRatio(ctx, msg.Username, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg).Text(formatSimulation(b.formatter(ctx, msg), b.accountCurrency(ctx, msg.Username), symbol, stake, direction, window, payoutRatio)).Build(), nil
+}
+
+// simulationRequest builds the history request covering the latest window of the duration,
+// it reports false for durations too long to replay
+func simulationRequest(symbol string, duration Duration) (HistoricalDataRequest, bool) {
+	span, ok := duration.Span()
+	if !ok {
+		if duration.Value > maxSimulatedTicks {
+			return HistoricalDataRequest{}, false
+		}
+
+		// Contracts start at the tick following the purchase, so one more tick is needed
+		return HistoricalDataRequest{
+			Symbol:   symbol,
+			Interval: IntervalHour,
+			Style:    StyleTicks,
+			Count:    duration.Value + 1,
+		}, true
+	}
+
+	// The history interval must cover the window and one more candle
+	var interval TimeInterval
+	granularity := 60
+	switch {
+	case span <= 30*time.Minute:
+		interval = IntervalHour
+	case span <= 12*time.Hour:
+		interval = IntervalDay
+	case span <= 3*24*time.Hour:
+		interval, granularity = IntervalWeek, 3600
+	case span <= 15*24*time.Hour:
+		interval, granularity = IntervalMonth, 3600
+	default:
+		return HistoricalDataRequest{}, false
+	}
+
+	return HistoricalDataRequest{
+		Symbol:      symbol,
+		Interval:    interval,
+		Style:       StyleCandles,
+		Count:       windowCandles(span, granularity) + 1,
+		Granularity: granularity,
+	}, true
+}
+
+// windowCandles returns the number of candles of the granularity in seconds covering the span
+func windowCandles(span time.Duration, granularity int) int {
+	size := time.Duration(granularity) * time.Second
+	return int((span + size - 1) / size)
+}
+
+// replayWindow picks entry and exit prices of the latest window of the duration from history
+func replayWindow(data []HistoricalDataPoint, duration Duration, granularity int) (*simulationWindow, bool) {
+	span, isTime := duration.Span()
+	if !isTime {
+		if len(data) < duration.Value+1 {
+			return nil, false
+		}
+
+		entry := data[len(data)-1-duration.Value]
+		exit := data[len(data)-1]
+
+		return &simulationWindow{
+			Start: time.Unix(entry.Timestamp, 0),
+			End:   time.Unix(exit.Timestamp, 0),
+			Entry: entry.Price,
+			Exit:  exit.Price,
+			Ticks: duration.Value,
+		}, true
+	}
+
+	candles := windowCandles(span, granularity)
+	if len(data) < candles+1 {
+		return nil, false
+	}
+
+	// The latest candle is still forming, its close is the current price
+	first := data[len(data)-1-candles]
+	last := data[len(data)-1]
+
+	return &simulationWindow{
+		Start:       time.Unix(first.Timestamp, 0),
+		End:         time.Unix(last.Timestamp, 0).Add(time.Duration(granularity) * time.Second),
+		Entry:       first.Open,
+		Exit:        last.Close,
+		FromCandles: true,
+	}, true
+}
+
+// payoutRatio returns the average payout to stake ratio of the user's trades on the symbol, 0 when there are none
+func (b *Bot) payoutRatio(ctx context.Context, username, symbol string) (float64, error) {
+	records, err := b.userTrades(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+
+	var stake, payout float64
+	for _, record := range records {
+		if record.Symbol == symbol && record.Stake > 0 {
+			stake += record.Stake
+			payout += record.Payout
+		}
+	}
+
+	if stake == 0 {
+		return 0, nil
+	}
+
+	return payout / stake, nil
+}
+
+// formatSimulation describes the replayed window and the outcome of Up and Down trades, or only of the given direction
+func formatSimulation(f Formatter, currency, symbol string, stake float64, direction string, window *simulationWindow, payoutRatio float64) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "🧪 Simulation on %s, no real money involved\n\n", symbol)
+	layout := "15:04:05"
+	if window.End.Sub(window.Start) >= 24*time.Hour {
+		layout = "2006-01-02 15:04"
+	}
+	fmt.Fprintf(&sb, "Window: %s – %s UTC\n", window.Start.UTC().Format(layout), window.End.UTC().Format(layout))
+	if window.Ticks > 0 {
+		fmt.Fprintf(&sb, "Ticks: %d\n", window.Ticks)
+	}
+	fmt.Fprintf(&sb, "Entry: %s, exit: %s (%s)\n\n", f.Number(window.Entry, 2), f.Number(window.Exit, 2), f.Signed(window.Exit-window.Entry, 2))
+
+	directions := []string{"up", "down"}
+	if direction != "" {
+		directions = []string{direction}
+	}
+
+	for _, dir := range directions {
+		// Rise/fall contracts lose when the exit equals the entry
+		won := dir == "up" && window.Exit > window.Entry || dir == "down" && window.Exit < window.Entry
+
+		label := "⬆️ Up"
+		if dir == "down" {
+			label = "⬇️ Down"
+		}
+
+		switch {
+		case !won:
+			fmt.Fprintf(&sb, "%s: ❌ lost, %s\n", label, f.SignedMoney(-stake, currency))
+		case payoutRatio > 0:
+			fmt.Fprintf(&sb, "%s: ✅ won, about %s\n", label, f.SignedMoney(stake*payoutRatio-stake, currency))
+		default:
+			fmt.Fprintf(&sb, "%s: ✅ won\n", label)
+		}
+	}
+
+	if window.FromCandles {
+		sb.WriteString("\nPrices come from candles, so entry and exit are approximate.")
+	}
+	if payoutRatio > 0 {
+		sb.WriteString("\nWinnings are estimated from payouts of your past trades on this symbol.")
+	}
+
+	return sb.String()
+}
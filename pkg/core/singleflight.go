@@ -0,0 +1,92 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flightGroup coalesces concurrent calls with the same key into a single upstream call
+// and keeps the result for a short time, so bursts of identical requests are served at once
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	calls map[string]*flightCall[T]
+}
+
+// flightCall is an in-flight or recently completed call
+type flightCall[T any] struct {
+	done    chan struct{}
+	val     T
+	err     error
+	expires time.Time
+}
+
+// newFlightGroup creates a group caching successful results for ttl
+func newFlightGroup[T any](ttl time.Duration) *flightGroup[T] {
+	return &flightGroup[T]{
+		ttl:   ttl,
+		calls: make(map[string]*flightCall[T]),
+	}
+}
+
+// Do executes fn once for all concurrent callers with the same key and fans the result out
+func (g *flightGroup[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	g.mu.Lock()
+	c, ok := g.calls[key]
+	if ok && !c.expires.IsZero() && time.Now().After(c.expires) {
+		ok = false
+	}
+
+	if !ok {
+		c = &flightCall[T]{done: make(chan struct{})}
+		g.calls[key] = c
+		g.mu.Unlock()
+
+		go g.run(ctx, key, c, fn)
+	} else {
+		g.mu.Unlock()
+	}
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// forget drops the result kept for the key, so the next call fetches it again. Calls in flight are kept.
+func (g *flightGroup[T]) forget(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if c, ok := g.calls[key]; ok && !c.expires.IsZero() {
+		delete(g.calls, key)
+	}
+}
+
+// run performs the call and publishes its result
+func (g *flightGroup[T]) run(ctx context.Context, key string, c *flightCall[T], fn func(ctx context.Context) (T, error)) {
+	// The call must not be canceled when the caller that started it gives up waiting,
+	// but it keeps the deadline so a hung upstream call doesn't block the key forever
+	callCtx := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithDeadline(callCtx, deadline)
+		defer cancel()
+	}
+
+	c.val, c.err = fn(callCtx)
+
+	g.mu.Lock()
+	if c.err != nil || g.ttl <= 0 {
+		delete(g.calls, key)
+	} else {
+		c.expires = time.Now().Add(g.ttl)
+	}
+	g.mu.Unlock()
+
+	close(c.done)
+}
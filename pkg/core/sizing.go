@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// defaultSizingMultiplier is used by /size when no multiplier is given
+const defaultSizingMultiplier = 100
+
+// maxRiskPercent limits the share of balance /size accepts as risk
+const maxRiskPercent = 100
+
+// positionSize is a stake sized so the stop-loss loses a share of the balance
+type positionSize struct {
+	Balance    float64
+	Currency   string
+	RiskAmount float64 // Loss at the stop-loss
+	StopMove   float64 // Price move to the stop-loss, in percent
+	Multiplier int
+	Stake      float64
+}
+
+// sizePosition computes the stake of a multiplier contract losing riskAmount when the price moves
+// stopMove percent against it. It reports false when the stake would be lost before reaching the stop.
+func sizePosition(riskAmount, stopMove float64, multiplier int) (float64, bool) {
+	exposure := float64(multiplier) * stopMove / 100
+	if exposure <= 0 {
+		return 0, false
+	}
+
+	stake := riskAmount / exposure
+
+	// Multiplier contracts can't lose more than the stake
+	return stake, stake >= riskAmount
+}
+
+// floorMoney rounds the amount down to the precision of the currency, so a sized trade never risks more than asked.
+// The tiny offset keeps amounts like 0.0005 that floats store slightly below their value from losing a digit.
+func floorMoney(amount float64, currency string) float64 {
+	scale := math.Pow(10, float64(moneyDecimals(currency)))
+	return math.Floor(amount*scale+1e-6) / scale
+}
+
+// parseStopDistance reads the stop distance as price points, or as percent of the price with a % suffix
+func parseStopDistance(value string, price float64) (float64, error) {
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		move, err := strconv.ParseFloat(percent, 64)
+		if err != nil || move <= 0 {
+			return 0, fmt.Errorf("invalid stop distance %q", value)
+		}
+		return move, nil
+	}
+
+	points, err := strconv.ParseFloat(value, 64)
+	if err != nil || points <= 0 || price <= 0 {
+		return 0, fmt.Errorf("invalid stop distance %q", value)
+	}
+
+	return points / price * 100, nil
+}
+
+// handleSize suggests the stake of a multiplier contract whose stop-loss equals the given share of balance,
+// "/size <symbol> <risk%> <stop_distance> [x<multiplier>]"
+func (b *Bot) handleSize(ctx context.Context, msg *Message) (*Response, error) {
+	usage := "❌ Usage: /size <symbol> <risk%> <stop_distance> [x<multiplier>], e.g. /size R_50 1% 0.5% x100. " +
+		"The stop distance is in price points, or in percent of the price with %."
+	if len(msg.Args) < 3 || len(msg.Args) > 4 {
+		return NewResponse(msg).Text(usage).Build(), nil
+	}
+
+	risk, err := strconv.ParseFloat(strings.TrimSuffix(msg.Args[1], "%"), 64)
+	if err != nil || risk <= 0 || risk > maxRiskPercent {
+		return NewResponse(msg).Text("❌ Risk must be a percentage of your balance between 0 and 100, e.g. 1%").Build(), nil
+	}
+
+	multiplier := defaultSizingMultiplier
+	if len(msg.Args) == 4 {
+		multiplier, err = strconv.Atoi(strings.TrimPrefix(strings.ToLower(msg.Args[3]), "x"))
+		if err != nil || multiplier <= 0 {
+			return NewResponse(msg).Text(usage).Build(), nil
+		}
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "size:" + strings.Join(append([]string{symbol}, msg.Args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	price, err := b.getPrice(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price: %w", err)
+	}
+
+	stopMove, err := parseStopDistance(msg.Args[2], price)
+	if err != nil {
+		return NewResponse(msg).Text(usage).Build(), nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := client.GetBalance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	size := &positionSize{
+		Balance:    balance.Amount,
+		Currency:   balance.Currency,
+		RiskAmount: floorMoney(balance.Amount*risk/100, balance.Currency),
+		StopMove:   stopMove,
+		Multiplier: multiplier,
+	}
+
+	f := b.formatter(ctx, msg)
+
+	stake, ok := sizePosition(size.RiskAmount, size.StopMove, size.Multiplier)
+	if !ok {
+		return NewResponse(msg).Textf("❌ With x%d a %s%% move loses the whole stake before the stop is reached. "+
+			"Use a lower multiplier or a closer stop.", multiplier, f.Number(stopMove, 2)).Build(), nil
+	}
+	size.Stake = floorMoney(stake, size.Currency)
+
+	return NewResponse(msg).Text(formatPositionSize(f, symbol, price, size)).Keyboard([][]Button{{
+		{Text: "🎯 Trade this", CallbackData: fmt.Sprintf("mult:%s:%s:x%d:sl=%s", symbol,
+			strconv.FormatFloat(size.Stake, 'f', -1, 64), multiplier, strconv.FormatFloat(size.RiskAmount, 'f', -1, 64))},
+	}}).Build(), nil
+}
+
+// formatPositionSize renders the suggested trade parameters
+func formatPositionSize(f Formatter, symbol string, price float64, size *positionSize) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "📐 Position size for %s at %s\n\n", symbol, f.Number(price, 2))
+	fmt.Fprintf(&sb, "Balance: %s\n", f.Money(size.Balance, size.Currency))
+	fmt.Fprintf(&sb, "Risk: %s\n", f.Money(size.RiskAmount, size.Currency))
+	fmt.Fprintf(&sb, "Stop distance: %s%% of the price\n", f.Number(size.StopMove, 3))
+	fmt.Fprintf(&sb, "Multiplier: x%d\n\n", size.Multiplier)
+	fmt.Fprintf(&sb, "Suggested stake: %s\n", f.Money(size.Stake, size.Currency))
+	fmt.Fprintf(&sb, "Stop-loss: %s\n", f.Money(size.RiskAmount, size.Currency))
+
+	// Trade this falls back to the regular buy flow until the bot can place multiplier contracts
+	sb.WriteString("\nMultiplier contracts can't be placed by the bot yet, Trade this opens an Up/Down trade with the suggested stake.")
+
+	return sb.String()
+}
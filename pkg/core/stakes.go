@@ -0,0 +1,226 @@
+package core
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxStakePresets limits the stakes a user can configure, so the keyboard stays readable on phones
+const maxStakePresets = 6
+
+// customStake is the argument of /buy asking how to type an amount that isn't among the presets
+const customStake = "custom"
+
+// defaultStakePresets are offered to users that didn't configure their own
+var defaultStakePresets = []float64{0.35, 1, 5, 10}
+
+// stakeOmitted reports whether /buy arguments lack an amount, e.g. "/buy R_50" or "/buy R_50 5t"
+func stakeOmitted(args []string) bool {
+	if len(args) < 2 {
+		return true
+	}
+
+	if strings.EqualFold(args[1], customStake) {
+		return true
+	}
+
+	if _, err := strconv.ParseFloat(args[1], 64); err == nil {
+		return false
+	}
+
+	_, err := ParseDuration(args[1])
+	return len(args) == 2 && err == nil
+}
+
+// stakePresets returns stakes offered to the user
+func (b *Bot) stakePresets(ctx context.Context, username string) ([]float64, error) {
+	settings, err := b.getSettings(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(settings.StakePresets) == 0 {
+		return defaultStakePresets, nil
+	}
+
+	return settings.StakePresets, nil
+}
+
+// stakePicker offers preset stakes for a trade, each button repeats /buy with the amount filled in.
+// Rest holds /buy arguments after the symbol, an optional duration possibly preceded by "custom",
+// start is the start time of a forward-starting contract as given to /buy.
+func (b *Bot) stakePicker(ctx context.Context, msg *Message, symbol string, rest []string, start string, limits TradeLimits, tags []string) (*Response, error) {
+	custom := len(rest) > 0 && strings.EqualFold(rest[0], customStake)
+	if custom {
+		rest = rest[1:]
+	}
+
+	// Only the duration, start time and limits are expected here, stakeOmitted lets nothing else through
+	var extra []string
+	if len(rest) > 0 {
+		extra = append(extra, rest[0])
+	}
+	if start != "" {
+		extra = append(extra, startArgPrefix+start)
+	}
+	extra = append(extra, limits.args()...)
+
+	if custom {
+		example := append([]string{"/buy", symbol, "2.50"}, extra...)
+		if len(tags) > 0 {
+			example = append(example, formatTags(tags))
+		}
+
+		return NewResponse(msg).Text("✏️ Type the stake you'd like to trade, e.g.\n" + strings.Join(example, " ")).Build(), nil
+	}
+
+	presets, err := b.stakePresets(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	presets = b.allowedStakes(ctx, symbol, presets)
+
+	f := b.formatter(ctx, msg)
+	currency := b.accountCurrency(ctx, msg.Username)
+
+	var buttons [][]Button
+	for i, stake := range presets {
+		if i%3 == 0 {
+			buttons = append(buttons, nil)
+		}
+
+		args := append([]string{symbol, strconv.FormatFloat(stake, 'f', -1, 64)}, extra...)
+		callback, ok := buyCallback(args, tags)
+		if !ok {
+			return tagsTooLong(msg, symbol, extra, tags), nil
+		}
+
+		buttons[len(buttons)-1] = append(buttons[len(buttons)-1], Button{
+			Text:         f.withoutConversion().Money(stake, currency),
+			CallbackData: callback,
+		})
+	}
+
+	callback, ok := buyCallback(append([]string{symbol, customStake}, extra...), tags)
+	if !ok {
+		return tagsTooLong(msg, symbol, extra, tags), nil
+	}
+	buttons = append(buttons, []Button{{Text: "✏️ Custom", CallbackData: callback}})
+
+	return NewResponse(msg).Textf("💵 Choose the stake for %s:", symbol).Keyboard(buttons).Build(), nil
+}
+
+// buyCallback builds callback data repeating /buy with the arguments and tags, ok is false when it doesn't fit into the limit
+func buyCallback(args, tags []string) (callback string, ok bool) {
+	callback = "buy:" + strings.Join(args, ":")
+	for _, tag := range tags {
+		callback += ":#" + tag
+	}
+
+	return callback, len(callback) <= maxCallbackData
+}
+
+// tagsTooLong asks for the stake in the command when the tags don't fit into the stake buttons,
+// tags are never dropped from a trade
+func tagsTooLong(msg *Message, symbol string, extra, tags []string) *Response {
+	example := append([]string{"/buy", symbol, "2.50"}, extra...)
+	example = append(example, formatTags(tags))
+
+	return NewResponse(msg).Text("❌ Too many or too long tags for the stake buttons. Please give the stake in the command, e.g.\n" +
+		strings.Join(example, " ")).Build()
+}
+
+// handleStakes shows or changes the stakes offered by /buy without an amount, "/stakes 1 2 5" or "/stakes reset"
+func (b *Bot) handleStakes(ctx context.Context, msg *Message) (*Response, error) {
+	settings, err := b.getSettings(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	f := b.formatter(ctx, msg)
+
+	if len(msg.Args) == 0 {
+		presets, err := b.stakePresets(ctx, msg.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewResponse(msg).Textf("💵 Preset stakes: %s\nUse /stakes <amounts>, e.g. /stakes 0.5 2 5, or /stakes reset to change them.",
+			formatStakes(f, b.accountCurrency(ctx, msg.Username), presets)).Build(), nil
+	}
+
+	if len(msg.Args) == 1 && strings.EqualFold(msg.Args[0], "reset") {
+		settings.StakePresets = nil
+	} else {
+		if len(msg.Args) > maxStakePresets {
+			return NewResponse(msg).Textf("❌ Up to %d preset stakes are supported", maxStakePresets).Build(), nil
+		}
+
+		seen := make(map[float64]bool)
+		var presets []float64
+		for _, arg := range msg.Args {
+			stake, err := strconv.ParseFloat(arg, 64)
+			if err != nil || stake <= 0 {
+				return NewResponse(msg).Textf("❌ Invalid stake %s. Example: /stakes 0.5 2 5", arg).Build(), nil
+			}
+
+			if !seen[stake] {
+				seen[stake] = true
+				presets = append(presets, stake)
+			}
+		}
+
+		sort.Float64s(presets)
+		settings.StakePresets = presets
+	}
+
+	if err := b.saveSettings(ctx, msg.Username, settings); err != nil {
+		return nil, err
+	}
+
+	presets, err := b.stakePresets(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg).Textf("✅ Preset stakes: %s", formatStakes(f, b.accountCurrency(ctx, msg.Username), presets)).Build(), nil
+}
+
+// allowedStakes leaves out stakes Up/Down contracts on the symbol don't accept. The stakes are returned as is
+// when limits can't be loaded or none of them fits, validation of the trade explains the limits then.
+func (b *Bot) allowedStakes(ctx context.Context, symbol string, stakes []float64) []float64 {
+	limits, err := b.contractLimits(ctx, symbol)
+	if err != nil {
+		log.Printf("Failed to get contract limits of %s: %v", symbol, err)
+		return stakes
+	}
+
+	call, put := contractLimit(limits, "CALL"), contractLimit(limits, "PUT")
+
+	var allowed []float64
+	for _, stake := range stakes {
+		if (call == nil || call.stakeAllowed(stake)) && (put == nil || put.stakeAllowed(stake)) {
+			allowed = append(allowed, stake)
+		}
+	}
+
+	if len(allowed) == 0 {
+		return stakes
+	}
+
+	return allowed
+}
+
+// formatStakes lists stakes as amounts in the currency
+func formatStakes(f Formatter, currency string, stakes []float64) string {
+	parts := make([]string, len(stakes))
+	for i, stake := range stakes {
+		parts[i] = f.Money(stake, currency)
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kirill/deriv-teletrader/pkg/metrics"
+)
+
+// Execution quality metrics
+const (
+	metricTradeLatency  = "trade_latency_ms" // Quote to confirmed purchase
+	metricTradeSlippage = "trade_slippage"   // Adverse move of the entry spot from the quoted one
+)
+
+// statsTradeWindow is the number of the user's latest trades included in /stats
+const statsTradeWindow = 100
+
+// statsPercentiles are the percentiles shown by /stats
+var statsPercentiles = []float64{50, 90, 99}
+
+// Metrics returns the registry with metrics recorded by the bot
+func (b *Bot) Metrics() *metrics.Registry {
+	return b.metrics
+}
+
+// handleStats shows results from the trade journal and execution quality of the user's recent trades and
+// of all trades since the bot started, "/stats [symbol] [from] [to]" narrows the journal down.
+// "/stats commands" shows usage of commands instead.
+func (b *Bot) handleStats(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) == 1 && strings.EqualFold(msg.Args[0], "commands") {
+		return b.handleCommandStats(ctx, msg)
+	}
+
+	filter, err := b.parseJournalFilter(msg.Args)
+	if err != nil {
+		return NewResponse(msg).Textf("❌ %s.\nUsage: /stats [symbol] [from YYYY-MM-DD] [to YYYY-MM-DD]\nExample: /stats R_50 2024-01-01 2024-01-31", err).Build(), nil
+	}
+
+	if _, err := b.settleUser(ctx, msg.Username); err != nil {
+		return nil, err
+	}
+
+	filter.Username = msg.Username
+	records, err := b.journal.Query(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades: %w", err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📒 Journal, %s\n", filter.describe())
+	if journal := summarizeJournal(records); journal.Trades > 0 {
+		sb.WriteString(formatJournalStats(b.formatter(ctx, msg), b.accountCurrency(ctx, msg.Username), journal))
+	} else {
+		sb.WriteString("No settled trades.\n")
+	}
+
+	if len(records) > statsTradeWindow {
+		records = records[len(records)-statsTradeWindow:]
+	}
+
+	var latencies, slippages []float64
+	for _, record := range records {
+		if record.LatencyMs > 0 {
+			latencies = append(latencies, float64(record.LatencyMs))
+		}
+		if slippage, ok := record.Slippage(); ok {
+			slippages = append(slippages, slippage)
+		}
+	}
+
+	sb.WriteString("\n📐 Execution quality (p50 / p90 / p99)\n")
+
+	fmt.Fprintf(&sb, "\nYour last %d trades:\n", len(records))
+	sb.WriteString(formatPercentiles("Latency, ms", metrics.Percentiles(latencies, statsPercentiles...), "%.0f"))
+	sb.WriteString(formatPercentiles("Slippage", metrics.Percentiles(slippages, statsPercentiles...), "%+.4f"))
+
+	fmt.Fprintf(&sb, "\nAll trades since the bot started (%d):\n", b.metrics.Count(metricTradeLatency))
+	sb.WriteString(formatPercentiles("Latency, ms", b.metrics.Percentiles(metricTradeLatency, statsPercentiles...), "%.0f"))
+	sb.WriteString(formatPercentiles("Slippage", b.metrics.Percentiles(metricTradeSlippage, statsPercentiles...), "%+.4f"))
+
+	sb.WriteString("\nSlippage is how far the entry spot moved against you from the quote, it's known once trades settle.")
+
+	return NewResponse(msg).Text(sb.String()).Build(), nil
+}
+
+// formatPercentiles renders a line of percentile values, or a placeholder when there's no data
+func formatPercentiles(label string, values []float64, format string) string {
+	if len(values) == 0 {
+		return fmt.Sprintf("%s: no data\n", label)
+	}
+
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprintf(format, value)
+	}
+
+	return fmt.Sprintf("%s: %s\n", label, strings.Join(parts, " / "))
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/metrics"
+)
+
+// Latency metrics of the services the bot depends on
+const (
+	metricDerivLatency    = "deriv_latency_ms"    // Single Deriv API call, including retried attempts
+	metricLLMLatency      = "llm_latency_ms"      // Single request to the LLM
+	metricTelegramLatency = "telegram_latency_ms" // Single message sent or edited in Telegram
+)
+
+// statusPercentiles are the percentiles shown by /status
+var statusPercentiles = []float64{50, 95}
+
+// timedLLM records how long requests to the wrapped LLM client take
+type timedLLM struct {
+	LLMClient
+	metrics *metrics.Registry
+}
+
+// ProcessText implements LLMClient
+func (t timedLLM) ProcessText(ctx context.Context, input string) (string, error) {
+	defer t.observe(time.Now())
+	return t.LLMClient.ProcessText(ctx, input)
+}
+
+// ProcessWithFunctions implements LLMClient
+func (t timedLLM) ProcessWithFunctions(ctx context.Context, input string, provider MarketDataProvider, functions []LLMFunction) (string, error) {
+	defer t.observe(time.Now())
+	return t.LLMClient.ProcessWithFunctions(ctx, input, provider, functions)
+}
+
+// observe records latency of a request started at the given time
+func (t timedLLM) observe(start time.Time) {
+	t.metrics.Observe(metricLLMLatency, float64(time.Since(start).Milliseconds()))
+}
+
+// ObserveSendLatency records how long sending a message to Telegram took
+func (b *Bot) ObserveSendLatency(latency time.Duration) {
+	b.metrics.Observe(metricTelegramLatency, float64(latency.Milliseconds()))
+}
+
+// handleStatus shows whether trading is available and recent latencies of Deriv, the LLM and Telegram
+func (b *Bot) handleStatus(_ context.Context, msg *Message) (*Response, error) {
+	var sb strings.Builder
+	sb.WriteString("📶 Status\n\n")
+
+	if paused, reason := b.watchdog.Paused(); paused {
+		fmt.Fprintf(&sb, "Trading: paused, %s\n", reason)
+	} else if reason, _ := b.watchdog.Degraded(); reason != "" {
+		fmt.Fprintf(&sb, "Trading: unavailable until the connection recovers, %s\n", reason)
+	} else {
+		sb.WriteString("Trading: available\n")
+	}
+
+	sb.WriteString("\nLatency of recent calls (p50 / p95):\n")
+	sb.WriteString(b.formatLatency("Deriv API", metricDerivLatency))
+	sb.WriteString(b.formatLatency("Assistant (LLM)", metricLLMLatency))
+	sb.WriteString(b.formatLatency("Telegram", metricTelegramLatency))
+
+	sb.WriteString("\nIf the bot feels slow, the line with the highest p95 shows which service is to blame.")
+
+	return NewResponse(msg).Text(sb.String()).Build(), nil
+}
+
+// formatLatency renders percentiles of a latency metric with the number of calls it's based on
+func (b *Bot) formatLatency(label, name string) string {
+	values := b.metrics.Percentiles(name, statusPercentiles...)
+	if len(values) == 0 {
+		return fmt.Sprintf("%s: no data\n", label)
+	}
+
+	return fmt.Sprintf("%s: %.0f / %.0f ms (%d calls)\n", label, values[0], values[1], b.metrics.Count(name))
+}
@@ -0,0 +1,17 @@
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by storage when a key doesn't exist
+var ErrNotFound = errors.New("not found")
+
+// Storage defines the interface for persisting bot state as JSON values grouped in buckets
+type Storage interface {
+	Get(ctx context.Context, bucket, key string, v any) error
+	Put(ctx context.Context, bucket, key string, v any) error
+	Delete(ctx context.Context, bucket, key string) error
+	Keys(ctx context.Context, bucket string) ([]string, error)
+}
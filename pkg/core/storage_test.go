@@ -0,0 +1,70 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// memStorage keeps values JSON-encoded in memory, as the file store does on disk
+type memStorage struct {
+	mu      sync.Mutex
+	buckets map[string]map[string][]byte
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{buckets: make(map[string]map[string][]byte)}
+}
+
+func (s *memStorage) Get(_ context.Context, bucket, key string, v any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.buckets[bucket][key]
+	if !ok {
+		return ErrNotFound
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func (s *memStorage) Put(_ context.Context, bucket, key string, v any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if s.buckets[bucket] == nil {
+		s.buckets[bucket] = make(map[string][]byte)
+	}
+	s.buckets[bucket][key] = data
+
+	return nil
+}
+
+func (s *memStorage) Delete(_ context.Context, bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buckets[bucket][key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.buckets[bucket], key)
+
+	return nil
+}
+
+func (s *memStorage) Keys(_ context.Context, bucket string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.buckets[bucket]))
+	for key := range s.buckets[bucket] {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,860 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucketStrategies is the storage bucket holding strategies of users with their parameters
+const bucketStrategies = "strategies"
+
+const (
+	strategyTradeTimeout = 30 * time.Second // Bounds placing a trade of a strategy
+	strategyTickBuffer   = 16               // Ticks queued while a trade of the strategy is being placed
+)
+
+// strategyUsage lists the subcommands of /strategy
+const strategyUsage = "❌ Usage:\n" +
+	"/strategy list - Strategies with their state and parameters\n" +
+	"/strategy start <name> <symbol> - Trade a symbol with a strategy\n" +
+	"/strategy stop <name> - Stop a strategy\n" +
+	"/strategy tune <name> - Change parameters of a strategy"
+
+// strategyParam is a numeric parameter of a strategy tuned in steps between its bounds
+type strategyParam struct {
+	Name    string
+	Label   string
+	Default float64
+	Min     float64
+	Max     float64
+	Step    float64
+}
+
+// StrategySignal returns the contract type, CALL or PUT, to buy on the latest prices of the symbol,
+// or an empty string when there is no trade
+type StrategySignal func(ctx context.Context, symbol string, prices []float64, params map[string]float64) (string, error)
+
+// strategyDef is a strategy trading signals of the tick stream of a symbol
+type strategyDef struct {
+	Name        string
+	Description string
+	Params      []strategyParam
+	signal      StrategySignal
+	// window returns the number of latest prices the signal looks at
+	window func(params map[string]float64) int
+}
+
+// streakParams are parameters of strategies trading streaks of rising or falling ticks
+var streakParams = []strategyParam{
+	{Name: "stake", Label: "Stake", Default: 1, Min: 1, Max: 100, Step: 1},
+	{Name: "streak", Label: "Ticks in a row", Default: 3, Min: 2, Max: 10, Step: 1},
+	{Name: "duration", Label: "Duration, ticks", Default: 5, Min: 1, Max: 10, Step: 1},
+	{Name: "cooldown", Label: "Pause after a trade, s", Default: 60, Min: 0, Max: 600, Step: 30},
+	{Name: "max_trades", Label: "Trades per run", Default: 10, Min: 1, Max: 50, Step: 1},
+}
+
+// externalParams are parameters of strategies registered with RegisterStrategy
+var externalParams = []strategyParam{
+	{Name: "stake", Label: "Stake", Default: 1, Min: 1, Max: 100, Step: 1},
+	{Name: "ticks", Label: "Ticks per signal", Default: 5, Min: 2, Max: 50, Step: 1},
+	{Name: "duration", Label: "Duration, ticks", Default: 5, Min: 1, Max: 10, Step: 1},
+	{Name: "cooldown", Label: "Pause after a trade, s", Default: 60, Min: 0, Max: 600, Step: 30},
+	{Name: "max_trades", Label: "Trades per run", Default: 10, Min: 1, Max: 50, Step: 1},
+}
+
+// streakWindow is the window of streak strategies, a streak of n ticks takes n+1 prices
+func streakWindow(params map[string]float64) int {
+	return int(params["streak"]) + 1
+}
+
+// strategies are the built-in strategies users can run
+var strategies = []strategyDef{
+	{
+		Name:        "momentum",
+		Description: "Follows a streak of rising or falling ticks",
+		Params:      streakParams,
+		window:      streakWindow,
+		signal: func(_ context.Context, _ string, prices []float64, params map[string]float64) (string, error) {
+			switch tickStreak(prices, int(params["streak"])) {
+			case 1:
+				return "CALL", nil
+			case -1:
+				return "PUT", nil
+			}
+			return "", nil
+		},
+	},
+	{
+		Name:        "reversal",
+		Description: "Bets against a streak of rising or falling ticks",
+		Params:      streakParams,
+		window:      streakWindow,
+		signal: func(_ context.Context, _ string, prices []float64, params map[string]float64) (string, error) {
+			switch tickStreak(prices, int(params["streak"])) {
+			case 1:
+				return "PUT", nil
+			case -1:
+				return "CALL", nil
+			}
+			return "", nil
+		},
+	},
+}
+
+// tickStreak reports whether the last n changes of prices all rose (1) or all fell (-1), 0 otherwise
+func tickStreak(prices []float64, n int) int {
+	if n <= 0 || len(prices) < n+1 {
+		return 0
+	}
+
+	last := prices[len(prices)-n-1:]
+	rising, falling := true, true
+	for i := 1; i < len(last); i++ {
+		rising = rising && last[i] > last[i-1]
+		falling = falling && last[i] < last[i-1]
+	}
+
+	switch {
+	case rising:
+		return 1
+	case falling:
+		return -1
+	}
+	return 0
+}
+
+// strategyDefs returns the built-in strategies followed by the registered ones
+func (b *Bot) strategyDefs() []strategyDef {
+	return append(slices.Clip(strategies), b.extStrategies...)
+}
+
+// findStrategy returns the built-in or registered strategy with the name
+func (b *Bot) findStrategy(name string) (*strategyDef, bool) {
+	defs := b.strategyDefs()
+	for i := range defs {
+		if defs[i].Name == strings.ToLower(name) {
+			return &defs[i], true
+		}
+	}
+	return nil, false
+}
+
+// RegisterStrategy adds a strategy whose signals come from outside the bot, e.g. a plugin. It's run, tuned and
+// checked like the built-in ones, the signal gets as many latest prices as its "ticks" parameter.
+// Strategies must be registered before the bot starts.
+func (b *Bot) RegisterStrategy(name, description string, signal StrategySignal) error {
+	name = strings.ToLower(name)
+	if _, exists := b.findStrategy(name); exists {
+		return fmt.Errorf("strategy %s is already registered", name)
+	}
+
+	b.extStrategies = append(b.extStrategies, strategyDef{
+		Name:        name,
+		Description: description,
+		Params:      externalParams,
+		signal:      signal,
+		window: func(params map[string]float64) int {
+			return int(params["ticks"])
+		},
+	})
+
+	return nil
+}
+
+// defaultParams returns default values of the strategy's parameters
+func (d *strategyDef) defaultParams() map[string]float64 {
+	params := make(map[string]float64, len(d.Params))
+	for _, p := range d.Params {
+		params[p.Name] = p.Default
+	}
+	return params
+}
+
+// UserStrategy is a strategy of a user with its tuned parameters
+type UserStrategy struct {
+	Name    string             `json:"name"`
+	Symbol  string             `json:"symbol,omitempty"`
+	Params  map[string]float64 `json:"params"`
+	Running bool               `json:"running"`
+	ChatID  int64              `json:"chat_id"`
+}
+
+// strategyRunner is a running strategy, closing stop ends it once a trade in progress is placed
+type strategyRunner struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// strategyRunners keeps running strategies and parameters being tuned
+type strategyRunners struct {
+	mu      sync.Mutex
+	running map[string]*strategyRunner    // By username/strategy
+	drafts  map[string]map[string]float64 // Parameters tuned but not applied yet by username/strategy
+	storage sync.Mutex                    // Serializes changes of users' strategies
+}
+
+// strategyKey identifies a strategy of a user in runners and drafts
+func strategyKey(username, name string) string {
+	return username + "/" + name
+}
+
+// getStrategies loads strategies of the user, with parameters missing in storage set to their defaults
+func (b *Bot) getStrategies(ctx context.Context, username string) (map[string]*UserStrategy, error) {
+	var stored []UserStrategy
+
+	err := b.storage.Get(ctx, bucketStrategies, username, &stored)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load strategies: %w", err)
+	}
+
+	result := make(map[string]*UserStrategy, len(stored))
+	for i := range stored {
+		def, ok := b.findStrategy(stored[i].Name)
+		if !ok {
+			continue
+		}
+
+		params := def.defaultParams()
+		for name, value := range stored[i].Params {
+			if _, ok := params[name]; ok {
+				params[name] = value
+			}
+		}
+		stored[i].Params = params
+		result[def.Name] = &stored[i]
+	}
+
+	return result, nil
+}
+
+// userStrategy returns the user's strategy with the name, with default parameters when it was never set up
+func (b *Bot) userStrategy(ctx context.Context, username string, def *strategyDef) (*UserStrategy, map[string]*UserStrategy, error) {
+	all, err := b.getStrategies(ctx, username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s, ok := all[def.Name]; ok {
+		return s, all, nil
+	}
+
+	s := &UserStrategy{Name: def.Name, Params: def.defaultParams()}
+	all[def.Name] = s
+
+	return s, all, nil
+}
+
+// saveStrategies stores strategies of the user
+func (b *Bot) saveStrategies(ctx context.Context, username string, all map[string]*UserStrategy) error {
+	list := make([]UserStrategy, 0, len(all))
+	for _, s := range all {
+		list = append(list, *s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	if err := b.storage.Put(ctx, bucketStrategies, username, list); err != nil {
+		return fmt.Errorf("failed to save strategies: %w", err)
+	}
+
+	return nil
+}
+
+// startStrategy runs the strategy of the user as a background job
+func (b *Bot) startStrategy(username string, s UserStrategy) error {
+	runner := &strategyRunner{stop: make(chan struct{}), done: make(chan struct{})}
+
+	b.strategies.mu.Lock()
+	defer b.strategies.mu.Unlock()
+
+	key := strategyKey(username, s.Name)
+	if _, ok := b.strategies.running[key]; ok {
+		return nil
+	}
+
+	err := b.background.Go(func(ctx context.Context, notifier Notifier) {
+		b.runStrategy(ctx, notifier, username, s, runner)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start strategy %s: %w", s.Name, err)
+	}
+
+	b.strategies.running[key] = runner
+
+	return nil
+}
+
+// stopStrategy stops the strategy of the user and waits until a trade it's placing is done,
+// it reports false when the strategy wasn't running
+func (b *Bot) stopStrategy(username, name string) bool {
+	key := strategyKey(username, name)
+
+	b.strategies.mu.Lock()
+	runner, ok := b.strategies.running[key]
+	delete(b.strategies.running, key)
+	b.strategies.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	close(runner.stop)
+	<-runner.done
+
+	return true
+}
+
+// finishStrategy forgets the runner once it exits. A strategy that stopped on its own is marked as not running,
+// unless the user started it again meanwhile.
+func (b *Bot) finishStrategy(username, name string, runner *strategyRunner, stopped bool) {
+	key := strategyKey(username, name)
+
+	// Whoever removes the runner from the map owns its stop, so nobody waits for it while holding the storage lock
+	b.strategies.mu.Lock()
+	own := b.strategies.running[key] == runner
+	if own {
+		delete(b.strategies.running, key)
+	}
+	b.strategies.mu.Unlock()
+
+	if !own || !stopped {
+		return
+	}
+
+	b.strategies.storage.Lock()
+	defer b.strategies.storage.Unlock()
+
+	b.strategies.mu.Lock()
+	_, restarted := b.strategies.running[key]
+	b.strategies.mu.Unlock()
+	if restarted {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), strategyTradeTimeout)
+	defer cancel()
+
+	all, err := b.getStrategies(ctx, username)
+	if err != nil {
+		log.Printf("Failed to mark strategy %s of %s stopped: %v", name, username, err)
+		return
+	}
+
+	if s, ok := all[name]; ok && s.Running {
+		s.Running = false
+		if err := b.saveStrategies(ctx, username, all); err != nil {
+			log.Printf("Failed to mark strategy %s of %s stopped: %v", name, username, err)
+		}
+	}
+}
+
+// runStrategy trades signals of the strategy on ticks of its symbol until it's stopped, the bot shuts down
+// or the strategy placed its number of trades. A trade refused by the user's limits stops the strategy.
+func (b *Bot) runStrategy(ctx context.Context, notifier Notifier, username string, s UserStrategy, runner *strategyRunner) {
+	var stopped bool
+	defer func() {
+		b.finishStrategy(username, s.Name, runner, stopped)
+		close(runner.done)
+	}()
+
+	def, ok := b.findStrategy(s.Name)
+	if !ok {
+		return
+	}
+
+	prices := make(chan float64, strategyTickBuffer)
+	unsubscribe := b.events.Subscribe(EventFilter{Type: EventTick, Symbol: s.Symbol}, func(event Event) {
+		select {
+		case prices <- event.Price.Price:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	size := def.window(s.Params)
+	window := make([]float64, 0, size)
+	cooldown := time.Duration(s.Params["cooldown"]) * time.Second
+
+	var trades int
+	var resumeAt time.Time
+
+	for {
+		var price float64
+		select {
+		case <-ctx.Done():
+			return
+		case <-runner.stop:
+			return
+		case price = <-prices:
+		}
+
+		window = append(window, price)
+		if len(window) > size {
+			window = window[1:]
+		}
+
+		if len(window) < size || time.Now().Before(resumeAt) {
+			continue
+		}
+
+		contractType, err := def.signal(ctx, s.Symbol, window, s.Params)
+		if err != nil {
+			log.Printf("Failed to get signal of strategy %s of %s: %v", s.Name, username, err)
+			continue
+		}
+		if contractType == "" {
+			continue
+		}
+
+		// The next signal needs a window of its own
+		window = window[:0]
+
+		text, placed, stop := b.placeStrategyTrade(ctx, username, &s, contractType)
+		if placed {
+			trades++
+			resumeAt = time.Now().Add(cooldown)
+		}
+
+		if trades >= int(s.Params["max_trades"]) {
+			text += fmt.Sprintf("\n\n⏹ Strategy %s stopped after %d trades, start it again with /strategy start %s %s", s.Name, trades, s.Name, s.Symbol)
+			stop = true
+		} else if stop {
+			text += fmt.Sprintf("\n\n⏹ Strategy %s stopped, start it again with /strategy start %s %s", s.Name, s.Name, s.Symbol)
+		}
+
+		if text != "" {
+			resp := NewNotification(s.ChatID).Text(text).Build()
+			if _, err := b.notify(ctx, b.userNotifier(ctx, notifier, username), username, NotifySettlements, resp); err != nil {
+				log.Printf("Failed to report strategy %s of %s: %v", s.Name, username, err)
+			}
+		}
+
+		if stop {
+			stopped = true
+			return
+		}
+	}
+}
+
+// placeStrategyTrade places a trade of the strategy with the checks of /buy. It returns the message for the user,
+// whether the trade was placed and whether the strategy should stop. Signals while the connection is degraded
+// are skipped without a message.
+func (b *Bot) placeStrategyTrade(ctx context.Context, username string, s *UserStrategy, contractType string) (string, bool, bool) {
+	if !b.features.Enabled(FeatureAutoTrading) {
+		return fmt.Sprintf("🚧 Strategy %s didn't trade, automated trading (%s) is disabled.", s.Name, FeatureAutoTrading), false, true
+	}
+	if paused, reason := b.watchdog.Paused(); paused {
+		return fmt.Sprintf("🛑 Strategy %s didn't trade, trading is paused (%s).", s.Name, reason), false, true
+	}
+	if reason, _ := b.watchdog.Degraded(); reason != "" {
+		return "", false, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, strategyTradeTimeout)
+	defer cancel()
+
+	unlock := b.trading.lock(username)
+	defer unlock()
+
+	header := fmt.Sprintf("🤖 Strategy %s on %s\n\n", s.Name, s.Symbol)
+
+	client, err := b.clientFor(ctx, username)
+	if err != nil {
+		log.Printf("Failed to get client of %s for strategy %s: %v", username, s.Name, err)
+		return header + "❌ Not placed, your account couldn't be reached.", false, true
+	}
+
+	msg := &Message{Username: username, ChatID: s.ChatID}
+	req := &TradeRequest{
+		Symbol:       s.Symbol,
+		Amount:       s.Params["stake"],
+		ContractType: contractType,
+		Duration:     Duration{Value: int(s.Params["duration"]), Unit: "t"},
+	}
+
+	if stop, err := b.checkTrade(ctx, msg, client, req); err != nil {
+		log.Printf("Failed to check trade of strategy %s of %s: %v", s.Name, username, err)
+		return header + "❌ Not placed, the trade couldn't be checked against your limits.", false, true
+	} else if stop != nil {
+		return header + "Not placed: " + stop.Text, false, true
+	}
+
+	receipt, err := b.executeTrade(ctx, msg, client, req, []string{s.Name})
+	if err != nil {
+		log.Printf("Failed to place trade of strategy %s of %s: %v", s.Name, username, err)
+		return header + "❌ Not placed, Deriv refused the trade.", false, true
+	}
+
+	return header + receipt.Text, true, false
+}
+
+// runStrategies starts strategies users left running when the bot stopped, it's a service of the bot
+func (b *Bot) runStrategies(ctx context.Context, _ Notifier) {
+	usernames, err := b.storage.Keys(ctx, bucketStrategies)
+	if err != nil {
+		log.Printf("Failed to list strategies: %v", err)
+		return
+	}
+
+	for _, username := range usernames {
+		b.resumeStrategies(ctx, username)
+	}
+}
+
+// resumeStrategies starts strategies of the user marked as running
+func (b *Bot) resumeStrategies(ctx context.Context, username string) {
+	all, err := b.getStrategies(ctx, username)
+	if err != nil {
+		log.Printf("Failed to load strategies of %s: %v", username, err)
+		return
+	}
+
+	for _, s := range all {
+		if !s.Running {
+			continue
+		}
+
+		if err := b.startStrategy(username, *s); err != nil {
+			log.Printf("Failed to resume strategy %s of %s: %v", s.Name, username, err)
+		}
+	}
+}
+
+// haltStrategies stops running strategies of the user, or of all users when username is empty, and marks them
+// as stopped so they aren't resumed on restart. It returns the number of strategies stopped.
+func (b *Bot) haltStrategies(ctx context.Context, username string) int {
+	b.strategies.mu.Lock()
+	var keys []string
+	for key := range b.strategies.running {
+		if owner, _, _ := strings.Cut(key, "/"); username == "" || owner == username {
+			keys = append(keys, key)
+		}
+	}
+	b.strategies.mu.Unlock()
+
+	var halted int
+	for _, key := range keys {
+		owner, name, _ := strings.Cut(key, "/")
+		if !b.stopStrategy(owner, name) {
+			continue
+		}
+		halted++
+
+		b.strategies.storage.Lock()
+		all, err := b.getStrategies(ctx, owner)
+		if err == nil && all[name] != nil {
+			all[name].Running = false
+			err = b.saveStrategies(ctx, owner, all)
+		}
+		b.strategies.storage.Unlock()
+
+		if err != nil {
+			log.Printf("Failed to mark strategy %s of %s stopped: %v", name, owner, err)
+		}
+	}
+
+	return halted
+}
+
+// handleStrategy manages strategies of the user, "/strategy list|start|stop|tune ..."
+func (b *Bot) handleStrategy(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) == 0 || strings.EqualFold(msg.Args[0], "list") {
+		return b.strategyList(ctx, msg)
+	}
+
+	sub, args := strings.ToLower(msg.Args[0]), msg.Args[1:]
+	if len(args) == 0 || (sub != "start" && sub != "stop" && sub != "tune") {
+		return NewResponse(msg).Text(strategyUsage).Build(), nil
+	}
+
+	def, ok := b.findStrategy(args[0])
+	if !ok {
+		return NewResponse(msg).Textf("❌ Unknown strategy %s, see /strategy list", args[0]).Build(), nil
+	}
+
+	switch sub {
+	case "start":
+		if len(args) != 2 {
+			return NewResponse(msg).Text(strategyUsage).Build(), nil
+		}
+		return b.strategyStart(ctx, msg, def, args[1])
+	case "stop":
+		return b.strategyStop(ctx, msg, def)
+	default:
+		return b.strategyTune(ctx, msg, def, args[1:])
+	}
+}
+
+// strategyList shows the built-in and registered strategies with the user's state and parameters of each
+func (b *Bot) strategyList(ctx context.Context, msg *Message) (*Response, error) {
+	all, err := b.getStrategies(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	f := b.formatter(ctx, msg)
+
+	var sb strings.Builder
+	sb.WriteString("🤖 Strategies\n")
+	defs := b.strategyDefs()
+	for i := range defs {
+		def := &defs[i]
+
+		s, ok := all[def.Name]
+		if !ok {
+			s = &UserStrategy{Name: def.Name, Params: def.defaultParams()}
+		}
+
+		status := "stopped"
+		if s.Running {
+			status = "running on " + s.Symbol
+		}
+
+		fmt.Fprintf(&sb, "\n%s (%s)\n%s\n", def.Name, status, def.Description)
+		for _, p := range def.Params {
+			fmt.Fprintf(&sb, "  %s: %s\n", p.Label, f.Number(s.Params[p.Name], stepPrecision(p.Step)))
+		}
+	}
+	sb.WriteString("\nStart one with /strategy start <name> <symbol>, change parameters with /strategy tune <name>")
+
+	return NewResponse(msg).Text(sb.String()).Build(), nil
+}
+
+// strategyStart starts a strategy of the user on the symbol, "/strategy start <name> <symbol>"
+func (b *Bot) strategyStart(ctx context.Context, msg *Message, def *strategyDef, input string) (*Response, error) {
+	symbol, choice := b.resolveSymbol(ctx, msg, input, func(symbol string) string {
+		return "strategy:start:" + def.Name + ":" + symbol
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	if paused, reason := b.watchdog.Paused(); paused {
+		return NewResponse(msg).Textf("🛑 Trading is paused (%s), strategies can't start now.", reason).Build(), nil
+	}
+
+	if reason, err := b.tradingBlocked(ctx, symbol); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	b.strategies.storage.Lock()
+	defer b.strategies.storage.Unlock()
+
+	s, all, err := b.userStrategy(ctx, msg.Username, def)
+	if err != nil {
+		return nil, err
+	}
+
+	if reason, err := b.validateStake(ctx, b.formatter(ctx, msg), symbol, s.Params["stake"], "CALL", "PUT"); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	if s.Running {
+		b.stopStrategy(msg.Username, def.Name)
+	}
+
+	s.Symbol, s.ChatID, s.Running = symbol, msg.ChatID, true
+	if err := b.saveStrategies(ctx, msg.Username, all); err != nil {
+		return nil, err
+	}
+
+	if err := b.startStrategy(msg.Username, *s); err != nil {
+		s.Running = false
+		if err := b.saveStrategies(ctx, msg.Username, all); err != nil {
+			log.Printf("Failed to mark strategy %s of %s stopped: %v", def.Name, msg.Username, err)
+		}
+		return nil, err
+	}
+
+	return NewResponse(msg).Textf("▶️ Strategy %s is trading %s. Trades get the same checks as /buy and are tagged #%s. "+
+		"It stops after %d trades, when a trade is refused or with /strategy stop %s.",
+		def.Name, symbol, def.Name, int(s.Params["max_trades"]), def.Name).Build(), nil
+}
+
+// strategyStop stops a strategy of the user, "/strategy stop <name>"
+func (b *Bot) strategyStop(ctx context.Context, msg *Message, def *strategyDef) (*Response, error) {
+	b.strategies.storage.Lock()
+	defer b.strategies.storage.Unlock()
+
+	all, err := b.getStrategies(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := all[def.Name]
+	if !ok || !s.Running {
+		return NewResponse(msg).Textf("ℹ️ Strategy %s isn't running", def.Name).Build(), nil
+	}
+
+	b.stopStrategy(msg.Username, def.Name)
+
+	s.Running = false
+	if err := b.saveStrategies(ctx, msg.Username, all); err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg).Textf("⏹ Strategy %s stopped. Contracts it placed stay open.", def.Name).Build(), nil
+}
+
+// strategyTune shows the parameters of a strategy as a form, its buttons change a draft of them,
+// "/strategy tune <name> [<param> inc|dec | apply | revert]"
+func (b *Bot) strategyTune(ctx context.Context, msg *Message, def *strategyDef, args []string) (*Response, error) {
+	b.strategies.storage.Lock()
+	defer b.strategies.storage.Unlock()
+
+	s, all, err := b.userStrategy(ctx, msg.Username, def)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strategyKey(msg.Username, def.Name)
+
+	b.strategies.mu.Lock()
+	draft, ok := b.strategies.drafts[key]
+	if !ok {
+		draft = make(map[string]float64, len(s.Params))
+		for name, value := range s.Params {
+			draft[name] = value
+		}
+		b.strategies.drafts[key] = draft
+	}
+	b.strategies.mu.Unlock()
+
+	rb := NewResponse(msg)
+	if msg.CallbackData != "" {
+		rb.Edit(msg.MessageID)
+	}
+
+	switch {
+	case len(args) == 0:
+	case len(args) == 1 && strings.EqualFold(args[0], "revert"):
+		b.strategies.mu.Lock()
+		delete(b.strategies.drafts, key)
+		b.strategies.mu.Unlock()
+
+		return rb.Textf("↩️ Changes to %s discarded, parameters stay as they were.", def.Name).Build(), nil
+	case len(args) == 1 && strings.EqualFold(args[0], "apply"):
+		b.strategies.mu.Lock()
+		delete(b.strategies.drafts, key)
+		b.strategies.mu.Unlock()
+
+		return b.applyStrategyParams(ctx, rb, msg.Username, def, s, all, draft)
+	case len(args) == 2:
+		if err := stepParam(def, draft, args[0], args[1]); err != nil {
+			return rb.Textf("❌ %v", err).Build(), nil
+		}
+	default:
+		return rb.Text(strategyUsage).Build(), nil
+	}
+
+	f := b.formatter(ctx, msg)
+
+	return rb.Text(formatStrategyForm(f, def, s, draft)).Keyboard(strategyFormButtons(f, def, draft)).Build(), nil
+}
+
+// applyStrategyParams stores tuned parameters and restarts the strategy with them if it's running,
+// the running strategy finishes a trade it's placing before it's restarted
+func (b *Bot) applyStrategyParams(ctx context.Context, rb *ResponseBuilder, username string, def *strategyDef, s *UserStrategy, all map[string]*UserStrategy, params map[string]float64) (*Response, error) {
+	s.Params = params
+	if err := b.saveStrategies(ctx, username, all); err != nil {
+		return nil, err
+	}
+
+	if !s.Running {
+		return rb.Textf("✅ Parameters of %s saved, they're used from its next start.", def.Name).Build(), nil
+	}
+
+	b.stopStrategy(username, def.Name)
+	if err := b.startStrategy(username, *s); err != nil {
+		s.Running = false
+		if err := b.saveStrategies(ctx, username, all); err != nil {
+			log.Printf("Failed to mark strategy %s of %s stopped: %v", def.Name, username, err)
+		}
+		return nil, err
+	}
+
+	return rb.Textf("✅ Parameters of %s applied, the strategy restarted on %s.", def.Name, s.Symbol).Build(), nil
+}
+
+// stepParam increments or decrements a parameter of the draft by its step, keeping it within its bounds
+func stepParam(def *strategyDef, draft map[string]float64, name, direction string) error {
+	for _, p := range def.Params {
+		if p.Name != name {
+			continue
+		}
+
+		switch direction {
+		case "inc":
+			draft[name] = min(p.Max, draft[name]+p.Step)
+		case "dec":
+			draft[name] = max(p.Min, draft[name]-p.Step)
+		default:
+			return fmt.Errorf("unknown change %s, use inc or dec", direction)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("strategy %s has no parameter %s", def.Name, name)
+}
+
+// formatStrategyForm renders parameters of the draft, marking ones that differ from the applied values
+func formatStrategyForm(f Formatter, def *strategyDef, s *UserStrategy, draft map[string]float64) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🎛 Tuning %s\n%s\n\n", def.Name, def.Description)
+
+	for _, p := range def.Params {
+		fmt.Fprintf(&sb, "%s: %s", p.Label, f.Number(draft[p.Name], stepPrecision(p.Step)))
+		if draft[p.Name] != s.Params[p.Name] {
+			fmt.Fprintf(&sb, " (was %s)", f.Number(s.Params[p.Name], stepPrecision(p.Step)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\nChanges take effect once applied")
+	if s.Running {
+		sb.WriteString(", the running strategy restarts with them")
+	}
+
+	return sb.String()
+}
+
+// strategyFormButtons builds a row of decrement and increment buttons around each parameter's value
+// and the apply and revert row, the value button redraws the form
+func strategyFormButtons(f Formatter, def *strategyDef, draft map[string]float64) [][]Button {
+	form := "strategy:tune:" + def.Name
+
+	buttons := make([][]Button, 0, len(def.Params)+1)
+	for _, p := range def.Params {
+		buttons = append(buttons, []Button{
+			{Text: "➖", CallbackData: form + ":" + p.Name + ":dec"},
+			{Text: p.Label + ": " + f.Number(draft[p.Name], stepPrecision(p.Step)), CallbackData: form},
+			{Text: "➕", CallbackData: form + ":" + p.Name + ":inc"},
+		})
+	}
+
+	return append(buttons, []Button{
+		{Text: "✅ Apply", CallbackData: form + ":apply"},
+		{Text: "↩️ Revert", CallbackData: form + ":revert"},
+	})
+}
+
+// stepPrecision returns the number of decimals needed to show values changed by the step
+func stepPrecision(step float64) int {
+	s := strconv.FormatFloat(step, 'f', -1, 64)
+	if _, decimals, ok := strings.Cut(s, "."); ok {
+		return len(decimals)
+	}
+	return 0
+}
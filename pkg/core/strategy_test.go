@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStrategySignals(t *testing.T) {
+	b := &Bot{}
+	momentum, _ := b.findStrategy("momentum")
+	reversal, _ := b.findStrategy("Reversal")
+	params := momentum.defaultParams()
+
+	tests := []struct {
+		name     string
+		prices   []float64
+		momentum string
+		reversal string
+	}{
+		{name: "rising streak", prices: []float64{5, 1, 2, 3, 4}, momentum: "CALL", reversal: "PUT"},
+		{name: "falling streak", prices: []float64{1, 5, 4, 3, 2}, momentum: "PUT", reversal: "CALL"},
+		{name: "broken streak", prices: []float64{1, 2, 3, 3, 4}},
+		{name: "too few ticks", prices: []float64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := momentum.signal(context.Background(), "R_50", tt.prices, params); err != nil || got != tt.momentum {
+				t.Errorf("momentum signal = %q, %v, want %q", got, err, tt.momentum)
+			}
+			if got, err := reversal.signal(context.Background(), "R_50", tt.prices, params); err != nil || got != tt.reversal {
+				t.Errorf("reversal signal = %q, %v, want %q", got, err, tt.reversal)
+			}
+		})
+	}
+}
+
+func TestStepParamKeepsBounds(t *testing.T) {
+	def, _ := (&Bot{}).findStrategy("momentum")
+	draft := def.defaultParams()
+
+	for range 20 {
+		if err := stepParam(def, draft, "streak", "inc"); err != nil {
+			t.Fatalf("failed to step streak: %v", err)
+		}
+	}
+	if draft["streak"] != 10 {
+		t.Errorf("streak = %v after stepping past the maximum, want 10", draft["streak"])
+	}
+
+	if err := stepParam(def, draft, "cooldown", "dec"); err != nil {
+		t.Fatalf("failed to step cooldown: %v", err)
+	}
+	if draft["cooldown"] != 30 {
+		t.Errorf("cooldown = %v, want 30", draft["cooldown"])
+	}
+
+	if err := stepParam(def, draft, "leverage", "inc"); err == nil {
+		t.Error("stepping an unknown parameter succeeded")
+	}
+	if err := stepParam(def, draft, "stake", "double"); err == nil {
+		t.Error("an unknown change succeeded")
+	}
+}
+
+func TestStrategyTuneAppliesDraft(t *testing.T) {
+	ctx := context.Background()
+	b := &Bot{
+		storage:    newMemStorage(),
+		strategies: strategyRunners{running: make(map[string]*strategyRunner), drafts: make(map[string]map[string]float64)},
+	}
+	def, _ := b.findStrategy("momentum")
+	msg := &Message{Username: "alice", CallbackData: "strategy:tune:momentum", MessageID: 7}
+
+	resp, err := b.strategyTune(ctx, msg, def, []string{"stake", "inc"})
+	if err != nil {
+		t.Fatalf("failed to change the stake: %v", err)
+	}
+	if !strings.Contains(resp.Text, "Stake: 2 (was 1)") {
+		t.Errorf("form = %q, want the changed stake", resp.Text)
+	}
+	for _, row := range resp.Buttons {
+		for _, button := range row {
+			if len(button.CallbackData) > maxCallbackData {
+				t.Errorf("callback data %q is longer than %d bytes", button.CallbackData, maxCallbackData)
+			}
+		}
+	}
+
+	// The draft isn't used until it's applied
+	all, err := b.getStrategies(ctx, "alice")
+	if err != nil {
+		t.Fatalf("failed to load strategies: %v", err)
+	}
+	if s, ok := all["momentum"]; ok && s.Params["stake"] != 1 {
+		t.Errorf("stake = %v before the draft was applied, want 1", s.Params["stake"])
+	}
+
+	if _, err := b.strategyTune(ctx, msg, def, []string{"apply"}); err != nil {
+		t.Fatalf("failed to apply the draft: %v", err)
+	}
+
+	all, err = b.getStrategies(ctx, "alice")
+	if err != nil {
+		t.Fatalf("failed to load strategies: %v", err)
+	}
+	if s := all["momentum"]; s == nil || s.Params["stake"] != 2 || s.Params["streak"] != 3 {
+		t.Errorf("strategy = %+v, want stake 2 and the default streak", s)
+	}
+	if _, ok := b.strategies.drafts[strategyKey("alice", "momentum")]; ok {
+		t.Error("the draft is kept after it was applied")
+	}
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// bucketStreaks is the storage bucket holding loss streaks of users
+const bucketStreaks = "streaks"
+
+// LossStreakConfig configures responsible-trading nudges after consecutive losses
+type LossStreakConfig struct {
+	Threshold int           `mapstructure:"threshold"` // Consecutive losses triggering the cool-down prompt, 0 disables it
+	Cooldown  time.Duration `mapstructure:"cooldown"`  // Minimal pause after the last loss before the prompt can be acknowledged
+}
+
+// LossStreak tracks consecutive losses of a user
+type LossStreak struct {
+	Losses       int       `json:"losses"`
+	Acknowledged int       `json:"acknowledged"` // Streak length the user has acknowledged
+	LastLossAt   time.Time `json:"last_loss_at,omitempty"`
+}
+
+// settleUser settles open trades of the user and counts the newly settled ones towards the loss streak,
+// every view of trades settles them first so outcomes are never missed. Callers race to settle the same trades,
+// so it's done under the user's settling lock and each trade is counted once. It returns the loss streak.
+func (b *Bot) settleUser(ctx context.Context, username string) (*LossStreak, error) {
+	unlock := b.settling.lock(username)
+	defer unlock()
+
+	settled, err := b.settleTrades(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.updateStreak(ctx, username, settled)
+}
+
+// updateStreak counts outcomes of trades in order of settlement towards the loss streak of the user;
+// the caller must hold the user's settling lock
+func (b *Bot) updateStreak(ctx context.Context, username string, settled []*TradeRecord) (*LossStreak, error) {
+	var streak LossStreak
+	if err := b.storage.Get(ctx, bucketStreaks, username, &streak); err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load loss streak: %w", err)
+	}
+
+	if len(settled) == 0 {
+		return &streak, nil
+	}
+
+	for _, record := range settled {
+		if record.Profit < 0 {
+			streak.Losses++
+			streak.LastLossAt = record.SettledAt
+		} else {
+			streak = LossStreak{}
+		}
+	}
+
+	if err := b.storage.Put(ctx, bucketStreaks, username, &streak); err != nil {
+		return nil, fmt.Errorf("failed to save loss streak: %w", err)
+	}
+
+	return &streak, nil
+}
+
+// cooldownPrompt returns a mandatory cool-down prompt when the user has reached the loss streak threshold
+// and hasn't acknowledged it yet, or nil when trading may proceed
+func (b *Bot) cooldownPrompt(ctx context.Context, msg *Message) (*Response, error) {
+	threshold := b.cfg.LossStreak.Threshold
+	if threshold <= 0 {
+		return nil, nil
+	}
+
+	streak, err := b.settleUser(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if streak.Losses < threshold || streak.Losses <= streak.Acknowledged {
+		return nil, nil
+	}
+
+	text := fmt.Sprintf("🧘 You've had %d losing trades in a row.\n\n"+
+		"Take a short break before the next trade: review what happened, check your limits, "+
+		"and only continue if you're trading by plan rather than to win losses back.", streak.Losses)
+
+	return NewResponse(msg).Text(text).Keyboard([][]Button{
+		{{Text: "I understand, continue", CallbackData: "cooldown:ack"}},
+	}).Build(), nil
+}
+
+// handleCooldown acknowledges the cool-down prompt
+func (b *Bot) handleCooldown(ctx context.Context, msg *Message) (*Response, error) {
+	unlock := b.settling.lock(msg.Username)
+	defer unlock()
+
+	var streak LossStreak
+	if err := b.storage.Get(ctx, bucketStreaks, msg.Username, &streak); err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load loss streak: %w", err)
+	}
+
+	if wait := time.Until(streak.LastLossAt.Add(b.cfg.LossStreak.Cooldown)); wait > 0 {
+		return NewResponse(msg).Textf("⏳ Please take a break for another %s before trading again.", wait.Round(time.Second)).Build(), nil
+	}
+
+	streak.Acknowledged = streak.Losses
+	if err := b.storage.Put(ctx, bucketStreaks, msg.Username, &streak); err != nil {
+		return nil, fmt.Errorf("failed to save loss streak: %w", err)
+	}
+
+	return NewResponse(msg).Text("✅ Thanks. You can place your next trade now — trade carefully.").Build(), nil
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// subscriptionCheckInterval is the longest time between checks for idle subscriptions
+const subscriptionCheckInterval = time.Minute
+
+// subscription is a stream kept up to date for a chat, such as a dashboard or a tracked contract
+type subscription struct {
+	chatID  int64
+	kind    string // What is streamed, shown to the chat when it's torn down
+	command string // Command starting it again
+	started time.Time
+	cancel  context.CancelFunc
+}
+
+// subscriptions tracks streams of chats and when each chat was last interacted with
+type subscriptions struct {
+	mu       sync.Mutex
+	active   map[*subscription]struct{}
+	lastSeen map[int64]time.Time
+}
+
+// add registers the subscription and returns the function unregistering it
+func (s *subscriptions) add(sub *subscription) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.active[sub] = struct{}{}
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.active, sub)
+	}
+}
+
+// seen records an interaction with the chat
+func (s *subscriptions) seen(chatID int64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSeen[chatID] = at
+}
+
+// expire unregisters and returns subscriptions of chats nobody interacted with for the timeout,
+// chats without subscriptions are forgotten once they are idle
+func (s *subscriptions) expire(now time.Time, timeout time.Duration) []*subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []*subscription
+	busy := make(map[int64]bool)
+
+	for sub := range s.active {
+		lastActive := sub.started
+		if seen := s.lastSeen[sub.chatID]; seen.After(lastActive) {
+			lastActive = seen
+		}
+
+		if now.Sub(lastActive) < timeout {
+			busy[sub.chatID] = true
+			continue
+		}
+
+		expired = append(expired, sub)
+		delete(s.active, sub)
+	}
+
+	for chatID, seen := range s.lastSeen {
+		if !busy[chatID] && now.Sub(seen) >= timeout {
+			delete(s.lastSeen, chatID)
+		}
+	}
+
+	return expired
+}
+
+// subscribe registers a stream of the chat, which is canceled once nobody interacts with the chat for
+// the configured time. The returned function must be called when the stream ends.
+func (b *Bot) subscribe(ctx context.Context, chatID int64, kind, command string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	remove := b.subscriptions.add(&subscription{
+		chatID:  chatID,
+		kind:    kind,
+		command: command,
+		started: time.Now(),
+		cancel:  cancel,
+	})
+
+	return ctx, func() {
+		remove()
+		cancel()
+	}
+}
+
+// runSubscriptionGC tears down streams of chats without interaction for longer than the configured timeout,
+// so a long-running bot doesn't keep streams for chats nobody is looking at
+func (b *Bot) runSubscriptionGC(ctx context.Context, notifier Notifier) {
+	timeout := b.cfg.SubscriptionIdleTimeout
+
+	ticker := time.NewTicker(min(timeout, subscriptionCheckInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, sub := range b.subscriptions.expire(now, timeout) {
+				sub.cancel()
+
+				log.Printf("Stopped idle %s in chat %d", sub.kind, sub.chatID)
+
+				resp := NewNotification(sub.chatID).
+					Textf("💤 Your %s stopped after %s without activity in this chat. Use %s to start it again.", sub.kind, timeout, sub.command).
+					Build()
+				if _, err := notifier.Send(ctx, resp); err != nil {
+					log.Printf("Failed to notify chat %d about stopped %s: %v", sub.chatID, sub.kind, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRecentCommands is the number of latest commands kept per user for /user
+const maxRecentCommands = 10
+
+// commandEntry is a command run by a user, arguments aren't kept since they may hold tokens
+type commandEntry struct {
+	At      time.Time
+	Command string
+	Err     string // Error returned by the handler, empty on success
+}
+
+// commandLog keeps the latest commands of each user in memory
+type commandLog struct {
+	mu      sync.Mutex
+	entries map[string][]commandEntry
+}
+
+// add records the command, dropping the oldest one when the user has too many
+func (l *commandLog) add(username string, entry commandEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := append(l.entries[username], entry)
+	if len(entries) > maxRecentCommands {
+		entries = entries[len(entries)-maxRecentCommands:]
+	}
+	l.entries[username] = entries
+}
+
+// recent returns the latest commands of the user, oldest first
+func (l *commandLog) recent(username string) []commandEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]commandEntry(nil), l.entries[username]...)
+}
+
+// recordCommand keeps the command and its outcome for the support view
+func (b *Bot) recordCommand(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		resp, err := next(ctx, msg)
+
+		entry := commandEntry{At: time.Now(), Command: commandName(msg)}
+		if err != nil {
+			entry.Err = err.Error()
+		}
+		b.commands.add(msg.Username, entry)
+		if !msg.InGroup {
+			b.sessions.add(msg.Username, msg.ChatID)
+		}
+
+		return resp, err
+	}
+}
+
+// handleUser shows what affects trading of a user for support, "/user <username>", admin only.
+// It only reads state, so looking a user up doesn't settle trades or refresh their linked account.
+func (b *Bot) handleUser(ctx context.Context, msg *Message) (*Response, error) {
+	if !b.isAdmin(msg.Username) {
+		return NewResponse(msg).Text("⚠️ This command is available to admins only.").Build(), nil
+	}
+
+	if len(msg.Args) != 1 {
+		return NewResponse(msg).Text("❌ Usage: /user <username>").Build(), nil
+	}
+
+	username := strings.TrimPrefix(msg.Args[0], "@")
+
+	log.Printf("Audit: admin %s viewed support info of %s", msg.Username, username)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🛟 Support view of @%s (read-only)\n\n", username)
+
+	if !b.isUserAllowed(username) {
+		sb.WriteString("⛔ Not in the allowed users list, the bot rejects all messages\n")
+	}
+
+	account, err := b.describeAccount(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(&sb, "Account: %s\n", account)
+
+	if paused, reason := b.watchdog.Paused(); paused {
+		fmt.Fprintf(&sb, "Trading: 🛑 paused for everyone (%s)\n", reason)
+	}
+
+	if err := b.describeWipe(ctx, &sb, username); err != nil {
+		return nil, err
+	}
+
+	if err := b.describeLimits(ctx, &sb, username); err != nil {
+		return nil, err
+	}
+
+	if err := b.describeRisk(ctx, &sb, b.formatter(ctx, msg), username); err != nil {
+		return nil, err
+	}
+
+	settings, err := b.getSettings(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	sb.WriteString("\nSettings:\n")
+	sb.WriteString(describeSettings(b.formatter(ctx, msg), settings))
+
+	sb.WriteString("\nRecent commands:\n")
+	recent := b.commands.recent(username)
+	if len(recent) == 0 {
+		sb.WriteString("none since the bot started\n")
+	}
+	for i := len(recent) - 1; i >= 0; i-- {
+		entry := recent[i]
+		outcome := "✅"
+		if entry.Err != "" {
+			outcome = "❌ " + entry.Err
+		}
+		fmt.Fprintf(&sb, "%s /%s %s\n", entry.At.UTC().Format("Jan 2 15:04:05"), entry.Command, outcome)
+	}
+
+	records, err := b.userTrades(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	sb.WriteString("\nOpen positions (as last seen):\n")
+	var open int
+	for _, record := range records {
+		if record.Settled() {
+			continue
+		}
+		open++
+		fmt.Fprintf(&sb, "%d %s %s, stake %s, placed %s\n", record.ContractID, record.Symbol, record.ContractType,
+			b.formatter(ctx, msg).Number(record.Stake, 2), record.PlacedAt.UTC().Format("Jan 2 15:04"))
+	}
+	if open == 0 {
+		sb.WriteString("none\n")
+	}
+
+	return NewResponse(msg).Text(sb.String()).Build(), nil
+}
+
+// describeAccount tells which Deriv account the user trades with, without touching the linked session
+func (b *Bot) describeAccount(ctx context.Context, username string) (string, error) {
+	var creds Credentials
+	err := b.storage.Get(ctx, bucketCredentials, username, &creds)
+	switch {
+	case err == nil && creds.Expired:
+		return "🔒 linked account logged out after inactivity, needs /connect", nil
+	case err == nil:
+		text := "linked with /connect"
+		if !creds.LastUsed.IsZero() {
+			text += ", last used " + creds.LastUsed.UTC().Format("Jan 2 15:04 UTC")
+		}
+		return text, nil
+	case !errors.Is(err, ErrNotFound):
+		return "", fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	switch {
+	case b.cfg.UserTokens[username] != "":
+		return "token configured by the operator", nil
+	case b.cfg.RequireOwnAccount:
+		return "🔗 not linked, account commands are denied until /connect", nil
+	default:
+		return "shared account of the bot", nil
+	}
+}
+
+// describeLimits writes the loss streak state of the user as last settled
+func (b *Bot) describeLimits(ctx context.Context, sb *strings.Builder, username string) error {
+	threshold := b.cfg.LossStreak.Threshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	var streak LossStreak
+	if err := b.storage.Get(ctx, bucketStreaks, username, &streak); err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to load loss streak: %w", err)
+	}
+
+	fmt.Fprintf(sb, "Loss streak: %d of %d", streak.Losses, threshold)
+	if streak.Losses >= threshold && streak.Losses > streak.Acknowledged {
+		sb.WriteString(", 🧘 cool-down prompt shown before every trade until acknowledged")
+	}
+	sb.WriteString("\n")
+
+	return nil
+}
+
+// describeSettings lists user settings that differ from defaults
+func describeSettings(f Formatter, settings *UserSettings) string {
+	var lines []string
+
+	if settings.DisplayCurrency != "" {
+		lines = append(lines, "Display currency: "+settings.DisplayCurrency)
+	}
+	if settings.Locale != "" {
+		lines = append(lines, "Locale: "+settings.Locale)
+	}
+	if len(settings.StakePresets) > 0 {
+		stakes := make([]string, len(settings.StakePresets))
+		for i, stake := range settings.StakePresets {
+			stakes[i] = f.Number(stake, 2)
+		}
+		lines = append(lines, "Stake presets: "+strings.Join(stakes, ", "))
+	}
+	if settings.ExpiryAlerts {
+		lines = append(lines, "Expiry alerts: on")
+	}
+	if len(settings.MutedNotifications) > 0 {
+		lines = append(lines, "Muted notifications: "+strings.Join(settings.MutedNotifications, ", "))
+	}
+	if settings.HideBadges {
+		lines = append(lines, "Weekly badges: hidden")
+	}
+
+	if len(lines) == 0 {
+		return "defaults\n"
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
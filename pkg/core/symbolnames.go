@@ -0,0 +1,41 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// symbolNames returns display names of active symbols by code, e.g. "Volatility 50 Index" for R_50.
+// Names are optional decoration, so it returns none when they can't be fetched.
+func (b *Bot) symbolNames(ctx context.Context) map[string]string {
+	symbols, err := b.activeSymbols(ctx)
+	if err != nil {
+		log.Printf("Failed to get symbol names: %v", err)
+		return nil
+	}
+
+	names := make(map[string]string, len(symbols))
+	for _, s := range symbols {
+		names[s.Symbol] = s.DisplayName
+	}
+
+	return names
+}
+
+// symbolLabel renders the symbol code with its display name, or just the code when the name isn't known
+func symbolLabel(names map[string]string, symbol string) string {
+	if name := names[symbol]; name != "" && name != symbol {
+		return fmt.Sprintf("%s (%s)", symbol, name)
+	}
+	return symbol
+}
+
+// marketName returns the name of the market category in the user's language, Deriv's English name otherwise
+func marketName(lang, market, fallback string) string {
+	key := "market." + market
+	if name := translate(lang, key); name != key {
+		return name
+	}
+	return fallback
+}
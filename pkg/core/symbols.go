@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// maxSymbolChoices limits the number of buttons offered when a symbol is ambiguous
+const maxSymbolChoices = 8
+
+// builtinAliases are common shorthands for Deriv synthetic indices
+var builtinAliases = map[string]string{
+	"vol10":  "R_10",
+	"vol25":  "R_25",
+	"vol50":  "R_50",
+	"vol75":  "R_75",
+	"vol100": "R_100",
+	"v10":    "R_10",
+	"v25":    "R_25",
+	"v50":    "R_50",
+	"v75":    "R_75",
+	"v100":   "R_100",
+	"v10s":   "1HZ10V",
+	"v25s":   "1HZ25V",
+	"v50s":   "1HZ50V",
+	"v75s":   "1HZ75V",
+	"v100s":  "1HZ100V",
+}
+
+// symbolResolver maps user input to canonical Deriv symbols
+type symbolResolver struct {
+	symbols []string
+	aliases map[string]string // Canonical symbols by normalized alias
+}
+
+// newSymbolResolver creates a resolver for the symbols, custom aliases override built-in ones
+func newSymbolResolver(symbols []string, custom map[string]string) *symbolResolver {
+	r := &symbolResolver{
+		symbols: symbols,
+		aliases: make(map[string]string, len(builtinAliases)+len(custom)),
+	}
+
+	for alias, symbol := range builtinAliases {
+		r.aliases[normalizeSymbol(alias)] = symbol
+	}
+	for alias, symbol := range custom {
+		r.aliases[normalizeSymbol(alias)] = symbol
+	}
+
+	return r
+}
+
+// Resolve returns canonical symbols matching the input.
+// Exact symbol and alias matches win, otherwise all partial matches are returned.
+// Unknown input is returned as is, so Deriv can report it.
+func (r *symbolResolver) Resolve(input string) []string {
+	query := normalizeSymbol(input)
+
+	for _, symbol := range r.symbols {
+		if normalizeSymbol(symbol) == query {
+			return []string{symbol}
+		}
+	}
+
+	if symbol, ok := r.aliases[query]; ok {
+		return []string{symbol}
+	}
+
+	found := make(map[string]struct{})
+	for _, symbol := range r.symbols {
+		if strings.Contains(normalizeSymbol(symbol), query) {
+			found[symbol] = struct{}{}
+		}
+	}
+	for alias, symbol := range r.aliases {
+		if strings.HasPrefix(alias, query) {
+			found[symbol] = struct{}{}
+		}
+	}
+
+	if len(found) == 0 {
+		return []string{input}
+	}
+
+	matches := make([]string, 0, len(found))
+	for symbol := range found {
+		matches = append(matches, symbol)
+	}
+	sort.Strings(matches)
+
+	return matches
+}
+
+// normalizeSymbol makes symbols and aliases comparable regardless of case and separators
+func normalizeSymbol(s string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(s))
+}
+
+// resolveSymbol resolves the symbol typed by the user.
+// When it's ambiguous, a response asking to pick one is returned instead,
+// its buttons carry the callback data built by callback for each candidate.
+func (b *Bot) resolveSymbol(ctx context.Context, msg *Message, input string, callback func(symbol string) string) (string, *Response) {
+	matches := b.resolver.Resolve(input)
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+
+	if len(matches) > maxSymbolChoices {
+		matches = matches[:maxSymbolChoices]
+	}
+
+	names := b.symbolNames(ctx)
+
+	var buttons [][]Button
+	for i, symbol := range matches {
+		if i%2 == 0 {
+			buttons = append(buttons, nil)
+		}
+		buttons[len(buttons)-1] = append(buttons[len(buttons)-1], Button{
+			Text:         symbolLabel(names, symbol),
+			CallbackData: callback(symbol),
+		})
+	}
+
+	return "", NewResponse(msg).Textf("🤔 Several symbols match %q, which one did you mean?", input).Keyboard(buttons).Build()
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// untaggedLabel groups trades placed without tags in the per-tag breakdown
+const untaggedLabel = "(untagged)"
+
+// maxCallbackData is the limit Telegram puts on callback data of a button, in bytes
+const maxCallbackData = 64
+
+// tagPattern matches tag names, written as #name in commands
+var tagPattern = regexp.MustCompile(`^[a-z0-9_-]{1,24}$`)
+
+// splitTags separates #tags from the other command arguments, tags are lowercased and deduplicated
+func splitTags(args []string) (rest, tags []string, err error) {
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "#") {
+			rest = append(rest, arg)
+			continue
+		}
+
+		tag := strings.ToLower(strings.TrimPrefix(arg, "#"))
+		if !tagPattern.MatchString(tag) {
+			return nil, nil, fmt.Errorf("invalid tag %s", arg)
+		}
+
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	return rest, tags, nil
+}
+
+// formatTags renders tags the way they are written in commands
+func formatTags(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = "#" + tag
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// tagStats is the performance of settled trades sharing a tag
+type tagStats struct {
+	Tag    string
+	Trades int
+	Wins   int
+	Staked float64
+	Profit float64
+}
+
+// statsByTag groups settled trades by tag, a trade with several tags counts towards each of them
+func statsByTag(records []*TradeRecord) []*tagStats {
+	groups := make(map[string]*tagStats)
+	add := func(tag string, record *TradeRecord) {
+		stats, ok := groups[tag]
+		if !ok {
+			stats = &tagStats{Tag: tag}
+			groups[tag] = stats
+		}
+
+		stats.Trades++
+		stats.Staked += record.Stake
+		stats.Profit += record.Profit
+		if record.Profit > 0 {
+			stats.Wins++
+		}
+	}
+
+	for _, record := range records {
+		if !record.Settled() {
+			continue
+		}
+
+		if len(record.Tags) == 0 {
+			add(untaggedLabel, record)
+			continue
+		}
+
+		for _, tag := range record.Tags {
+			add(tag, record)
+		}
+	}
+
+	result := make([]*tagStats, 0, len(groups))
+	for _, stats := range groups {
+		result = append(result, stats)
+	}
+
+	// Most profitable setups first
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Profit != result[j].Profit {
+			return result[i].Profit > result[j].Profit
+		}
+		return result[i].Tag < result[j].Tag
+	})
+
+	return result
+}
+
+// handlePnL shows realized P&L of the user's settled trades, "/pnl by-tag" breaks it down by trade tags
+func (b *Bot) handlePnL(ctx context.Context, msg *Message) (*Response, error) {
+	byTag := len(msg.Args) > 0 && strings.EqualFold(msg.Args[0], "by-tag")
+	if len(msg.Args) > 0 && !byTag {
+		return NewResponse(msg).Text("❌ Usage: /pnl [by-tag]").Build(), nil
+	}
+
+	if _, err := b.settleUser(ctx, msg.Username); err != nil {
+		return nil, err
+	}
+
+	records, err := b.userTrades(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	var total tagStats
+	for _, record := range records {
+		if !record.Settled() {
+			continue
+		}
+
+		total.Trades++
+		total.Staked += record.Stake
+		total.Profit += record.Profit
+		if record.Profit > 0 {
+			total.Wins++
+		}
+	}
+
+	if total.Trades == 0 {
+		return NewResponse(msg).Text("📒 No settled trades yet.").Build(), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📒 Realized P&L\n\n")
+	f := b.formatter(ctx, msg)
+	currency := b.accountCurrency(ctx, msg.Username)
+	sb.WriteString(formatTagStats(f, currency, "All trades", &total))
+
+	if byTag {
+		sb.WriteString("\nBy tag:\n")
+		for _, stats := range statsByTag(records) {
+			label := stats.Tag
+			if label != untaggedLabel {
+				label = "#" + label
+			}
+			sb.WriteString(formatTagStats(f, currency, label, stats))
+		}
+		sb.WriteString("\nTrades with several tags count towards each of them.")
+	}
+
+	return NewResponse(msg).Text(sb.String()).Build(), nil
+}
+
+// formatTagStats renders a line with trade count, win rate and P&L of a group of trades
+func formatTagStats(f Formatter, currency, label string, stats *tagStats) string {
+	winRate := float64(stats.Wins) / float64(stats.Trades) * 100
+
+	var roi float64
+	if stats.Staked > 0 {
+		roi = stats.Profit / stats.Staked * 100
+	}
+
+	return fmt.Sprintf("%s: %d trades, %s%% won, P&L %s (%s of stake)\n",
+		label, stats.Trades, f.Number(winRate, 0), f.SignedMoney(stats.Profit, currency), f.Percent(roi, 1))
+}
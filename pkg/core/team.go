@@ -0,0 +1,222 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// bucketTeams is the storage bucket holding members of group chats sharing their portfolio, keyed by chat ID
+const bucketTeams = "teams"
+
+// Team lists users of a group chat who opted in to the shared portfolio
+type Team struct {
+	Members []string `json:"members"`
+}
+
+// memberExposure is the portfolio of a team member
+type memberExposure struct {
+	Username   string
+	Currency   string
+	Open       int
+	Staked     float64 // Stake of open trades
+	Unrealized float64
+	Realized   float64
+	Symbols    map[string]int // Open trades by symbol
+	Failed     bool           // The portfolio couldn't be loaded
+}
+
+// getTeam loads the team of the chat, returning an empty one when nobody joined yet
+func (b *Bot) getTeam(ctx context.Context, chatID int64) (*Team, error) {
+	var team Team
+
+	err := b.storage.Get(ctx, bucketTeams, strconv.FormatInt(chatID, 10), &team)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load team: %w", err)
+	}
+
+	return &team, nil
+}
+
+// saveTeam persists the team of the chat
+func (b *Bot) saveTeam(ctx context.Context, chatID int64, team *Team) error {
+	if err := b.storage.Put(ctx, bucketTeams, strconv.FormatInt(chatID, 10), team); err != nil {
+		return fmt.Errorf("failed to save team: %w", err)
+	}
+	return nil
+}
+
+// handleTeamPortfolio shows open positions and P&L of group members who opted in,
+// "/teamportfolio join" and "/teamportfolio leave" change the membership of the sender
+func (b *Bot) handleTeamPortfolio(ctx context.Context, msg *Message) (*Response, error) {
+	rb := NewResponse(msg)
+
+	if !msg.InGroup {
+		return rb.Text("👥 The team portfolio is available in group chats. Add the bot to your team's group and use /teamportfolio join there.").Build(), nil
+	}
+
+	team, err := b.getTeam(ctx, msg.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.Args) > 0 {
+		switch strings.ToLower(msg.Args[0]) {
+		case "join":
+			if !slices.Contains(team.Members, msg.Username) {
+				team.Members = append(team.Members, msg.Username)
+			}
+			rb.Text("✅ Your trades are now part of this chat's team portfolio. Use /teamportfolio leave to stop sharing.")
+		case "leave":
+			team.Members = slices.DeleteFunc(team.Members, func(member string) bool {
+				return member == msg.Username
+			})
+			rb.Text("✅ Your trades are no longer shared with this chat")
+		default:
+			return rb.Text("❌ Usage: /teamportfolio [join|leave]").Build(), nil
+		}
+
+		if err := b.saveTeam(ctx, msg.ChatID, team); err != nil {
+			return nil, err
+		}
+
+		return rb.Build(), nil
+	}
+
+	if len(team.Members) == 0 {
+		return rb.Text("👥 Nobody shares their trades in this chat yet. Use /teamportfolio join to opt in.").Build(), nil
+	}
+
+	exposures := make([]*memberExposure, 0, len(team.Members))
+	for _, member := range team.Members {
+		exposures = append(exposures, b.memberExposure(ctx, member))
+	}
+
+	return rb.Text(formatTeamPortfolio(b.formatter(ctx, msg), exposures)).Build(), nil
+}
+
+// memberExposure values the open trades of a team member, failures are logged and marked in the result
+func (b *Bot) memberExposure(ctx context.Context, username string) *memberExposure {
+	exposure := &memberExposure{Username: username, Symbols: make(map[string]int)}
+
+	if _, err := b.settleUser(ctx, username); err != nil {
+		log.Printf("Failed to settle trades of %s: %v", username, err)
+		exposure.Failed = true
+		return exposure
+	}
+
+	records, err := b.userTrades(ctx, username)
+	if err != nil {
+		log.Printf("Failed to load trades of %s: %v", username, err)
+		exposure.Failed = true
+		return exposure
+	}
+
+	client, err := b.clientFor(ctx, username)
+	if err != nil {
+		log.Printf("Failed to get client of %s: %v", username, err)
+		exposure.Failed = true
+		return exposure
+	}
+	exposure.Currency = client.Currency()
+
+	for _, record := range records {
+		if record.Settled() {
+			exposure.Realized += record.Profit
+			continue
+		}
+
+		exposure.Open++
+		exposure.Staked += record.Stake
+		exposure.Symbols[record.Symbol]++
+
+		info, err := client.GetContract(ctx, record.ContractID)
+		if err != nil {
+			log.Printf("Failed to value contract %d: %v", record.ContractID, err)
+			continue
+		}
+		exposure.Unrealized += info.Profit
+	}
+
+	return exposure
+}
+
+// formatTeamPortfolio renders per-member exposure followed by team totals, one per account currency
+func formatTeamPortfolio(f Formatter, exposures []*memberExposure) string {
+	var sb strings.Builder
+	sb.WriteString("👥 Team portfolio\n\n")
+
+	totals := make(map[string]*memberExposure)
+	symbols := make(map[string]int)
+
+	for _, exposure := range exposures {
+		if exposure.Failed {
+			fmt.Fprintf(&sb, "@%s: ⚠️ unavailable\n", exposure.Username)
+			continue
+		}
+
+		fmt.Fprintf(&sb, "@%s: %s\n", exposure.Username, formatExposure(f, exposure))
+
+		total, ok := totals[exposure.Currency]
+		if !ok {
+			total = &memberExposure{Currency: exposure.Currency}
+			totals[exposure.Currency] = total
+		}
+		total.Open += exposure.Open
+		total.Staked += exposure.Staked
+		total.Unrealized += exposure.Unrealized
+		total.Realized += exposure.Realized
+		for symbol, count := range exposure.Symbols {
+			symbols[symbol] += count
+		}
+	}
+
+	// Amounts in different currencies aren't added up
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	sb.WriteString("\n")
+	for _, currency := range currencies {
+		label := "Total"
+		if len(currencies) > 1 {
+			label += " " + currency
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", label, formatExposure(f, totals[currency]))
+	}
+
+	if len(symbols) > 0 {
+		names := make([]string, 0, len(symbols))
+		for symbol := range symbols {
+			names = append(names, symbol)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if symbols[names[i]] != symbols[names[j]] {
+				return symbols[names[i]] > symbols[names[j]]
+			}
+			return names[i] < names[j]
+		})
+
+		parts := make([]string, len(names))
+		for i, symbol := range names {
+			parts[i] = fmt.Sprintf("%s ×%d", symbol, symbols[symbol])
+		}
+		fmt.Fprintf(&sb, "Open by symbol: %s\n", strings.Join(parts, ", "))
+	}
+
+	return sb.String()
+}
+
+// formatExposure renders open trades and P&L of an exposure in its currency
+func formatExposure(f Formatter, exposure *memberExposure) string {
+	return fmt.Sprintf("%d open, staked %s, open P&L %s, realized %s",
+		exposure.Open, f.Money(exposure.Staked, exposure.Currency),
+		f.SignedMoney(exposure.Unrealized, exposure.Currency), f.SignedMoney(exposure.Realized, exposure.Currency))
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Outcomes of trades icons are configured for
+const (
+	outcomeWon  = "won"
+	outcomeLost = "lost"
+	outcomeEven = "even" // Sold for exactly the stake
+	outcomeOpen = "open"
+)
+
+// ThemeConfig overrides icons of trade messages, so a deployment can give them a consistent look
+type ThemeConfig struct {
+	Contracts map[string]string `mapstructure:"contracts"` // Icons by contract type, e.g. CALL or ONETOUCH
+	Outcomes  map[string]string `mapstructure:"outcomes"`  // Icons of won, lost, even and open trades
+}
+
+// defaultContractIcons are icons of contract types the bot trades
+var defaultContractIcons = map[string]string{
+	"CALL":       "⬆️",
+	"PUT":        "⬇️",
+	"MULTUP":     "⬆️",
+	"MULTDOWN":   "⬇️",
+	"ONETOUCH":   "🎯",
+	"NOTOUCH":    "🚫",
+	"DIGITMATCH": "🔢",
+	"DIGITDIFF":  "🔢",
+	"DIGITOVER":  "🔢",
+	"DIGITUNDER": "🔢",
+}
+
+// defaultOutcomeIcons make won and lost trades stand apart when scanning messages
+var defaultOutcomeIcons = map[string]string{
+	outcomeWon:  "🟢",
+	outcomeLost: "🔴",
+	outcomeEven: "⚪",
+	outcomeOpen: "⏳",
+}
+
+// unknownContractIcon is shown for contract types without an icon
+const unknownContractIcon = "📄"
+
+// Theme maps contract types and trade outcomes to icons
+type Theme struct {
+	contracts map[string]string
+	outcomes  map[string]string
+}
+
+// NewTheme creates a theme of the default icons overridden by the configured ones
+func NewTheme(cfg ThemeConfig) *Theme {
+	t := &Theme{
+		contracts: make(map[string]string, len(defaultContractIcons)),
+		outcomes:  make(map[string]string, len(defaultOutcomeIcons)),
+	}
+
+	for contractType, icon := range defaultContractIcons {
+		t.contracts[contractType] = icon
+	}
+	for contractType, icon := range cfg.Contracts {
+		t.contracts[strings.ToUpper(contractType)] = icon
+	}
+
+	for outcome, icon := range defaultOutcomeIcons {
+		t.outcomes[outcome] = icon
+	}
+	for outcome, icon := range cfg.Outcomes {
+		t.outcomes[strings.ToLower(outcome)] = icon
+	}
+
+	return t
+}
+
+// contractIcon returns the icon of the contract type
+func (t *Theme) contractIcon(contractType string) string {
+	icons := defaultContractIcons
+	if t != nil {
+		icons = t.contracts
+	}
+
+	if icon, ok := icons[contractType]; ok {
+		return icon
+	}
+
+	return unknownContractIcon
+}
+
+// outcomeIcon returns the icon of the outcome
+func (t *Theme) outcomeIcon(outcome string) string {
+	if t != nil {
+		return t.outcomes[outcome]
+	}
+
+	return defaultOutcomeIcons[outcome]
+}
+
+// tradeOutcome classifies a trade by its status and profit, sold contracts count as won or lost by their profit
+func tradeOutcome(status string, profit float64) string {
+	switch {
+	case status == ContractStatusOpen:
+		return outcomeOpen
+	case profit > 0:
+		return outcomeWon
+	case profit < 0:
+		return outcomeLost
+	default:
+		return outcomeEven
+	}
+}
+
+// ContractIcon returns the icon of the contract type in the theme of the deployment
+func (f Formatter) ContractIcon(contractType string) string {
+	return f.theme.contractIcon(contractType)
+}
+
+// Outcome describes the state of a trade with its icon, e.g. "🟢 Won" or "🔴 Lost (sold)"
+func (f Formatter) Outcome(status string, profit float64) string {
+	outcome := tradeOutcome(status, profit)
+	text := fmt.Sprintf("%s %s", f.theme.outcomeIcon(outcome), strings.ToUpper(outcome[:1])+outcome[1:])
+
+	if status == ContractStatusSold {
+		text += " (sold)"
+	}
+
+	return text
+}
+
+// ProfitIcon returns the outcome icon of a settled profit, e.g. of a basket or a day
+func (f Formatter) ProfitIcon(profit float64) string {
+	return f.theme.outcomeIcon(tradeOutcome("", profit))
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Number of ticks shown by /ticks
+const (
+	defaultTickCount = 20
+	maxTickCount     = 50
+)
+
+// handleTicks shows the latest ticks with per-tick changes in a monospace block, "/ticks <symbol> [n]"
+func (b *Bot) handleTicks(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) == 0 || len(msg.Args) > 2 {
+		return NewResponse(msg).Textf("❌ Usage: /ticks <symbol> [n], e.g. /ticks R_50 20, up to %d ticks", maxTickCount).Build(), nil
+	}
+
+	count := defaultTickCount
+	if len(msg.Args) == 2 {
+		n, err := strconv.Atoi(msg.Args[1])
+		if err != nil || n <= 0 || n > maxTickCount {
+			return NewResponse(msg).Textf("❌ The number of ticks must be between 1 and %d", maxTickCount).Build(), nil
+		}
+		count = n
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "ticks:" + strings.Join(append([]string{symbol}, msg.Args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	// One more tick is fetched, so the oldest shown tick has a change as well
+	ticks, err := b.derivClient.GetHistoricalData(ctx, HistoricalDataRequest{
+		Symbol:   symbol,
+		Interval: IntervalHour,
+		Style:    StyleTicks,
+		Count:    count + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticks: %w", err)
+	}
+
+	if len(ticks) < 2 {
+		return NewResponse(msg).Textf("❌ No recent ticks of %s", symbol).Build(), nil
+	}
+
+	return NewResponse(msg).Text(formatTickLadder(symbol, ticks)).Preformatted().Build(), nil
+}
+
+// formatTickLadder renders ticks newest first with their change and last digit, aligned in columns
+func formatTickLadder(symbol string, ticks []HistoricalDataPoint) string {
+	decimals := priceDecimals(ticks)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s, last %d ticks\n\n", symbol, len(ticks)-1)
+
+	type row struct{ time, price, delta, arrow, digit string }
+	rows := make([]row, 0, len(ticks)-1)
+	priceWidth, deltaWidth := len("Price"), len("Change")
+
+	for i := len(ticks) - 1; i > 0; i-- {
+		tick, prev := ticks[i], ticks[i-1]
+		delta := tick.Price - prev.Price
+
+		arrow := "="
+		switch {
+		case delta > 0:
+			arrow = "▲"
+		case delta < 0:
+			arrow = "▼"
+		}
+
+		price := strconv.FormatFloat(tick.Price, 'f', decimals, 64)
+		r := row{
+			time:  time.Unix(tick.Timestamp, 0).UTC().Format("15:04:05"),
+			price: price,
+			delta: strconv.FormatFloat(delta, 'f', decimals, 64),
+			arrow: arrow,
+			digit: price[len(price)-1:],
+		}
+		if delta >= 0 {
+			r.delta = "+" + r.delta
+		}
+
+		priceWidth = max(priceWidth, len(r.price))
+		deltaWidth = max(deltaWidth, len(r.delta))
+		rows = append(rows, r)
+	}
+
+	fmt.Fprintf(&sb, "%-8s  %*s  %*s    D\n", "Time", priceWidth, "Price", deltaWidth, "Change")
+	for _, r := range rows {
+		fmt.Fprintf(&sb, "%-8s  %*s  %*s %s  %s\n", r.time, priceWidth, r.price, deltaWidth, r.delta, r.arrow, r.digit)
+	}
+
+	return sb.String()
+}
+
+// priceDecimals returns the decimal places of the most precise price, so all prices line up
+func priceDecimals(ticks []HistoricalDataPoint) int {
+	decimals := 0
+	for _, tick := range ticks {
+		text := strconv.FormatFloat(tick.Price, 'f', -1, 64)
+		if _, fraction, ok := strings.Cut(text, "."); ok {
+			decimals = max(decimals, len(fraction))
+		}
+	}
+	return decimals
+}
@@ -0,0 +1,222 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// touchUsage explains the arguments of /touch
+const touchUsage = "❌ Usage: /touch <symbol> <stake> [duration] [barrier]\nExample: /touch R_50 10 5m +0.5"
+
+// barrierContracts are contract types offered by /touch once the barrier is chosen, by callback name
+var barrierContracts = map[string]string{
+	"touch":   "ONETOUCH",
+	"notouch": "NOTOUCH",
+	"higher":  "CALL",
+	"lower":   "PUT",
+}
+
+// barrierSteps are multiples of the default barrier of the symbol offered as barriers
+var barrierSteps = []float64{1, 2, 4}
+
+// handleTouch trades contracts with a barrier, "/touch <symbol> <stake> [duration] [barrier]". Without a barrier
+// barriers around the spot are offered, then the contract type: touch, no touch, higher or lower than the barrier.
+// Buttons call the command again with the choice appended.
+func (b *Bot) handleTouch(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) < 2 {
+		return NewResponse(msg).Text(touchUsage).Build(), nil
+	}
+
+	stake, err := strconv.ParseFloat(msg.Args[1], 64)
+	if err != nil || stake <= 0 {
+		return NewResponse(msg).Text(touchUsage).Build(), nil
+	}
+
+	var duration Duration
+	var barrier, kind string
+	for _, arg := range msg.Args[2:] {
+		switch {
+		case duration.Value == 0 && barrier == "" && isDuration(arg):
+			duration, _ = ParseDuration(arg)
+		case barrier == "" && isBarrier(arg):
+			barrier = arg
+		case barrier != "" && kind == "" && barrierContracts[strings.ToLower(arg)] != "":
+			kind = strings.ToLower(arg)
+		default:
+			return NewResponse(msg).Text(touchUsage).Build(), nil
+		}
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "touch:" + strings.Join(append([]string{symbol}, msg.Args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+
+	if duration.Value == 0 {
+		duration = b.defaultDuration(ctx, symbol)
+	}
+
+	if reason, err := b.tradingBlocked(ctx, symbol); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	f := b.formatter(ctx, msg)
+	currency := b.accountCurrency(ctx, msg.Username)
+	base := fmt.Sprintf("touch:%s:%s:%s", symbol, strconv.FormatFloat(stake, 'f', -1, 64), duration)
+
+	// The stake is checked on every step, buttons and typed barriers skip the earlier ones
+	contractTypes := []string{"ONETOUCH", "NOTOUCH"}
+	if kind != "" {
+		contractTypes = []string{barrierContracts[kind]}
+	}
+	if reason, err := b.validateStake(ctx, f, symbol, stake, contractTypes...); err != nil {
+		return nil, err
+	} else if reason != "" {
+		return NewResponse(msg).Text(reason).Build(), nil
+	}
+
+	if barrier == "" {
+		limits, err := b.contractLimits(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		barriers := barrierChoices(contractLimit(limits, "ONETOUCH").Barrier)
+		if len(barriers) == 0 {
+			return NewResponse(msg).
+				Textf("❌ No default barrier is known for %s, give one, e.g. /touch %s %s %s +0.5", symbol, symbol, msg.Args[1], duration).
+				Build(), nil
+		}
+
+		buttons := make([][]Button, len(barriers))
+		for i, offsets := range barriers {
+			for _, offset := range offsets {
+				buttons[i] = append(buttons[i], Button{Text: offset, CallbackData: base + ":" + offset})
+			}
+		}
+
+		return NewResponse(msg).
+			Textf("🎯 %s on %s for %s. Choose the barrier, relative to the spot:", f.Money(stake, currency), symbol, duration).
+			Keyboard(buttons).
+			Build(), nil
+	}
+
+	if kind == "" {
+		callback := base + ":" + barrier
+
+		return NewResponse(msg).
+			Textf("🎯 %s on %s for %s with barrier %s. Choose the contract:", f.Money(stake, currency), symbol, duration, barrier).
+			Keyboard([][]Button{
+				{
+					{Text: "🎯 Touch", CallbackData: callback + ":touch"},
+					{Text: "🚫 No touch", CallbackData: callback + ":notouch"},
+				},
+				{
+					{Text: "⬆️ Higher", CallbackData: callback + ":higher"},
+					{Text: "⬇️ Lower", CallbackData: callback + ":lower"},
+				},
+			}).
+			Build(), nil
+	}
+
+	client, err := b.clientFor(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &TradeRequest{
+		Symbol:       symbol,
+		Amount:       stake,
+		ContractType: barrierContracts[kind],
+		Duration:     duration,
+		Barrier:      barrier,
+	}
+
+	if stop, err := b.checkTrade(ctx, msg, client, req); err != nil || stop != nil {
+		return stop, err
+	}
+
+	if b.cfg.TradeConfirmTimeout > 0 {
+		return b.confirmTradePrompt(ctx, msg, client, req, nil)
+	}
+
+	return b.executeTrade(ctx, msg, client, req, nil)
+}
+
+// isDuration reports whether the argument is a contract duration, e.g. 5t or 15m
+func isDuration(arg string) bool {
+	_, err := ParseDuration(arg)
+	return err == nil
+}
+
+// isBarrier reports whether the argument is a barrier, either relative to the spot like +0.5 or absolute
+func isBarrier(arg string) bool {
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+// barrierChoices returns rows of barriers above and below the spot at multiples of the default barrier,
+// written with its decimals as Deriv rejects barriers more precise than the symbol
+func barrierChoices(defaultBarrier string) [][]string {
+	offset, err := strconv.ParseFloat(strings.TrimLeft(defaultBarrier, "+-"), 64)
+	if err != nil || offset <= 0 {
+		return nil
+	}
+
+	decimals := 0
+	if _, fraction, ok := strings.Cut(defaultBarrier, "."); ok {
+		decimals = len(fraction)
+	}
+
+	var above, below []string
+	for _, step := range barrierSteps {
+		value := strconv.FormatFloat(offset*step, 'f', decimals, 64)
+		above = append(above, "+"+value)
+		below = append(below, "-"+value)
+	}
+
+	return [][]string{above, below}
+}
+
+// contractLabel describes the contract type of the trade with its icon, and its barrier when it has one
+func contractLabel(f Formatter, req *TradeRequest) string {
+	return f.ContractIcon(req.ContractType) + " " + contractDescription(req)
+}
+
+// contractDescription describes the contract type of the trade in words
+func contractDescription(req *TradeRequest) string {
+	switch req.ContractType {
+	case "ONETOUCH":
+		return "Touch " + req.Barrier
+	case "NOTOUCH":
+		return "No touch " + req.Barrier
+	case "DIGITMATCH":
+		return "Last digit matches " + req.Barrier
+	case "DIGITDIFF":
+		return "Last digit differs from " + req.Barrier
+	case "DIGITOVER":
+		return "Last digit over " + req.Barrier
+	case "DIGITUNDER":
+		return "Last digit under " + req.Barrier
+	case "MULTUP":
+		return fmt.Sprintf("Up x%d", req.Multiplier)
+	case "MULTDOWN":
+		return fmt.Sprintf("Down x%d", req.Multiplier)
+	case "PUT":
+		if req.Barrier != "" {
+			return "Lower than " + req.Barrier
+		}
+		return "Down"
+	default:
+		if req.Barrier != "" {
+			return "Higher than " + req.Barrier
+		}
+		return "Up"
+	}
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// serializedCommands place or sell contracts, the ones of a user never run at the same time
+var serializedCommands = map[string]bool{
+	"buy":          true,
+	"basket":       true,
+	"mult":         true,
+	"touch":        true,
+	"digit":        true,
+	"sell":         true,
+	"confirmtrade": true,
+	"direction":    true,
+}
+
+// userLocks serializes operations of each user
+type userLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock acquires the lock of the user and returns the function releasing it
+func (l *userLocks) lock(username string) func() {
+	l.mu.Lock()
+	lock, ok := l.locks[username]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[username] = lock
+	}
+	l.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// serializeTrades runs trading commands of a user one at a time, so two fast taps or a button and a command
+// can't interleave their proposal and buy steps, and limits see contracts bought a moment ago.
+// Commands of different users still run concurrently.
+func (b *Bot) serializeTrades(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		if !serializedCommands[msg.Command] {
+			return next(ctx, msg)
+		}
+
+		unlock := b.trading.lock(msg.Username)
+		defer unlock()
+
+		return next(ctx, msg)
+	}
+}
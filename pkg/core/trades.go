@@ -0,0 +1,271 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// bucketTrades is the storage bucket holding the trade journal unless another journal is set
+const bucketTrades = "trades"
+
+// Contract statuses reported by Deriv
+const (
+	ContractStatusOpen = "open"
+	ContractStatusWon  = "won"
+	ContractStatusLost = "lost"
+	ContractStatusSold = "sold"
+)
+
+// TradeRequest describes a contract to buy
+type TradeRequest struct {
+	Symbol       string
+	Amount       float64
+	ContractType string // CALL, PUT, ONETOUCH, NOTOUCH, DIGITMATCH, DIGITDIFF, DIGITOVER, DIGITUNDER, MULTUP or MULTDOWN
+	Duration     Duration
+	Barrier      string    // Barrier of touch contracts or the predicted digit, CALL and PUT with one are higher/lower contracts
+	StartAt      time.Time // Start of a forward-starting contract, zero to start right away
+
+	// Multiplier contracts have no duration, they run until sold or closed by their limits
+	Multiplier int
+
+	// Limits are placed as native limit orders of multiplier contracts, other contracts are watched by the bot
+	Limits TradeLimits
+
+	// Quote is bought as it was offered instead of requesting a new one, e.g. a /proposal preview
+	Quote *Quote
+}
+
+// IsMultiplier reports whether the request is for a multiplier contract
+func (r *TradeRequest) IsMultiplier() bool {
+	return r.ContractType == "MULTUP" || r.ContractType == "MULTDOWN"
+}
+
+// contractDirection reports whether a contract gains from a rising (1) or falling (-1) price, 0 when it has
+// no direction such as digit contracts. Touch contracts go the way of their relative barrier.
+func contractDirection(contractType, barrier string) int {
+	switch contractType {
+	case "CALL", "CALLE", "MULTUP":
+		return 1
+	case "PUT", "PUTE", "MULTDOWN":
+		return -1
+	case "ONETOUCH", "NOTOUCH":
+		direction := 0
+		switch {
+		case strings.HasPrefix(barrier, "+"):
+			direction = 1
+		case strings.HasPrefix(barrier, "-"):
+			direction = -1
+		}
+
+		if contractType == "NOTOUCH" {
+			return -direction
+		}
+		return direction
+	}
+
+	return 0
+}
+
+// TradeResult contains details of a purchased contract
+type TradeResult struct {
+	ContractID   int64
+	BuyPrice     float64
+	Payout       float64
+	Longcode     string
+	PurchaseTime time.Time
+	ProposalSpot float64       // Spot of the quote the contract was bought at
+	Latency      time.Duration // Time from the quote to the confirmed purchase
+	Currency     string        // Currency of the stake and payout
+	Account      Account       // Account the contract was bought on
+}
+
+// Quote is the price a trade would be bought at, given before buying it
+type Quote struct {
+	ID       string // Proposal ID, buying it keeps the quoted price and payout
+	AskPrice float64
+	Payout   float64
+	Spot     float64
+	Longcode string
+	Currency string
+	QuotedAt time.Time // When the proposal was requested, execution latency is measured from it
+}
+
+// SellResult contains details of a contract sold back before expiry
+type SellResult struct {
+	ContractID    int64
+	SoldFor       float64
+	BalanceAfter  float64
+	TransactionID int64
+}
+
+// PositionInfo is an open contract of the account
+type PositionInfo struct {
+	ContractID    int64
+	Symbol        string
+	ContractType  string
+	Currency      string
+	Stake         float64 // Buy price
+	Payout        float64
+	Profit        float64 // Profit if sold now, known when Valued is set
+	Valued        bool
+	ExpiryTime    time.Time
+	IsValidToSell bool
+}
+
+// ContractInfo contains the current state of a contract
+type ContractInfo struct {
+	ContractID    int64
+	Symbol        string
+	ContractType  string
+	Currency      string
+	BuyPrice      float64
+	Payout        float64
+	Profit        float64
+	Status        string // One of ContractStatus* values
+	IsSold        bool
+	EntrySpot     float64
+	CurrentSpot   float64
+	ExpiryTime    time.Time
+	TickCount     int // Duration of tick contracts, zero for time-based ones
+	TicksPassed   int
+	IsValidToSell bool // Whether the contract can be sold back before expiry
+	Longcode      string
+	Barrier       string
+	EntryTime     time.Time
+	ExitSpot      float64
+	ExitTime      time.Time
+	PurchaseTime  time.Time
+	SellPrice     float64 // Amount the contract was sold or settled for
+	SellTime      time.Time
+}
+
+// TradeRecord is a trade placed through the bot, persisted in the journal
+type TradeRecord struct {
+	ContractID   int64     `json:"contract_id"`
+	Username     string    `json:"username"`
+	Symbol       string    `json:"symbol"`
+	ContractType string    `json:"contract_type"`
+	Barrier      string    `json:"barrier,omitempty"`
+	Stake        float64   `json:"stake"`
+	Payout       float64   `json:"payout"`
+	Status       string    `json:"status"`
+	Profit       float64   `json:"profit"`
+	Currency     string    `json:"currency,omitempty"` // Currency of the account the trade was placed on
+	PlacedAt     time.Time `json:"placed_at"`
+	SettledAt    time.Time `json:"settled_at,omitempty"`
+	Tags         []string  `json:"tags,omitempty"` // Setup labels given with #tag on /buy
+
+	// Execution quality
+	ProposalSpot float64 `json:"proposal_spot,omitempty"` // Spot of the quote
+	EntrySpot    float64 `json:"entry_spot,omitempty"`    // Spot the contract actually started at
+	ExitSpot     float64 `json:"exit_spot,omitempty"`     // Spot the contract was sold or settled at
+	LatencyMs    int64   `json:"latency_ms,omitempty"`    // Time from the quote to the confirmed purchase
+}
+
+// Slippage returns how much the entry spot moved against the trade compared to the quote,
+// negative values mean the entry was better than quoted. Contracts without a direction have none.
+func (r *TradeRecord) Slippage() (float64, bool) {
+	direction := contractDirection(r.ContractType, r.Barrier)
+	if r.ProposalSpot == 0 || r.EntrySpot == 0 || direction == 0 {
+		return 0, false
+	}
+
+	return float64(direction) * (r.EntrySpot - r.ProposalSpot), true
+}
+
+// Settled reports whether the trade outcome is known
+func (r *TradeRecord) Settled() bool {
+	return r.Status != "" && r.Status != ContractStatusOpen
+}
+
+// recordTrade adds a newly placed trade to the journal
+func (b *Bot) recordTrade(ctx context.Context, username, symbol, contractType, barrier string, tags []string, result *TradeResult) error {
+	record := &TradeRecord{
+		ContractID:   result.ContractID,
+		Username:     username,
+		Symbol:       symbol,
+		ContractType: contractType,
+		Barrier:      barrier,
+		Stake:        result.BuyPrice,
+		Payout:       result.Payout,
+		Currency:     result.Currency,
+		Status:       ContractStatusOpen,
+		PlacedAt:     result.PurchaseTime,
+		ProposalSpot: result.ProposalSpot,
+		LatencyMs:    result.Latency.Milliseconds(),
+		Tags:         tags,
+	}
+
+	if err := b.journal.Save(ctx, record); err != nil {
+		return fmt.Errorf("failed to record trade: %w", err)
+	}
+
+	b.events.Publish(Event{Type: EventTradeOpened, Symbol: symbol, Time: record.PlacedAt, Trade: record})
+
+	return nil
+}
+
+// userTrades returns journal records of the user ordered by placement time
+func (b *Bot) userTrades(ctx context.Context, username string) ([]*TradeRecord, error) {
+	records, err := b.journal.Query(ctx, &JournalFilter{Username: username})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades: %w", err)
+	}
+
+	return records, nil
+}
+
+// settleTrades refreshes open trades of the user from Deriv and returns the newly settled ones in order of
+// settlement; the caller must hold the user's settling lock
+func (b *Bot) settleTrades(ctx context.Context, username string) ([]*TradeRecord, error) {
+	records, err := b.userTrades(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := b.clientFor(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	var settled []*TradeRecord
+	for _, record := range records {
+		if record.Settled() {
+			continue
+		}
+
+		info, err := client.GetContract(ctx, record.ContractID)
+		if err != nil {
+			log.Printf("Failed to refresh contract %d: %v", record.ContractID, err)
+			continue
+		}
+
+		if !info.IsSold {
+			continue
+		}
+
+		record.Status = info.Status
+		record.Profit = info.Profit
+		record.EntrySpot = info.EntrySpot
+		record.ExitSpot = info.ExitSpot
+
+		// Trades settled late, e.g. after a restart, still count towards the day they were sold on
+		record.SettledAt = info.SellTime
+		if record.SettledAt.IsZero() {
+			record.SettledAt = time.Now()
+		}
+
+		if err := b.journal.Save(ctx, record); err != nil {
+			return nil, fmt.Errorf("failed to update trade: %w", err)
+		}
+
+		b.events.Publish(Event{Type: EventTradeSettled, Symbol: record.Symbol, Time: record.SettledAt, Trade: record})
+
+		settled = append(settled, record)
+	}
+
+	return settled, nil
+}
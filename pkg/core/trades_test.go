@@ -0,0 +1,36 @@
+package core
+
+import "testing"
+
+func TestTradeRecordSlippage(t *testing.T) {
+	tests := []struct {
+		name         string
+		contractType string
+		barrier      string
+		entry        float64
+		want         float64
+		ok           bool
+	}{
+		{name: "rise entered higher", contractType: "CALL", entry: 101, want: 1, ok: true},
+		{name: "fall entered higher", contractType: "PUT", entry: 101, want: -1, ok: true},
+		{name: "lower entered lower", contractType: "PUT", barrier: "-0.5", entry: 99, want: 1, ok: true},
+		{name: "higher entered lower", contractType: "CALL", barrier: "+0.5", entry: 99, want: -1, ok: true},
+		{name: "multiplier down entered lower", contractType: "MULTDOWN", entry: 99, want: 1, ok: true},
+		{name: "multiplier up entered lower", contractType: "MULTUP", entry: 99, want: -1, ok: true},
+		{name: "touch below entered higher", contractType: "ONETOUCH", barrier: "-2", entry: 101, want: -1, ok: true},
+		{name: "no touch above entered higher", contractType: "NOTOUCH", barrier: "+2", entry: 101, want: -1, ok: true},
+		{name: "digit contract", contractType: "DIGITMATCH", barrier: "5", entry: 101},
+		{name: "unknown entry", contractType: "CALL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &TradeRecord{ContractType: tt.contractType, Barrier: tt.barrier, ProposalSpot: 100, EntrySpot: tt.entry}
+
+			got, ok := record.Slippage()
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Slippage() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits of /watch
+const (
+	defaultWatchInterval = time.Minute
+	minWatchInterval     = 30 * time.Second
+	maxWatchesPerChat    = 10
+)
+
+// watchUsage explains the arguments of /watch
+const watchUsage = "❌ Usage: /watch <symbol> [every <interval>|on <move>%], e.g. /watch R_50 every 5m or /watch R_50 on 0.3%\n" +
+	"/watch lists watched symbols, /watch stop [symbol] stops watching"
+
+// priceWatch posts prices of a symbol to a chat, either periodically or when the price moves enough
+type priceWatch struct {
+	Symbol   string
+	Interval time.Duration // Time between updates, zero when updates follow price moves
+	Move     float64       // Percent move since the last update that triggers the next one
+	cancel   context.CancelFunc
+}
+
+// String describes when the watch posts updates
+func (w *priceWatch) String() string {
+	if w.Move > 0 {
+		return fmt.Sprintf("%s on %s%% moves", w.Symbol, strconv.FormatFloat(w.Move, 'f', -1, 64))
+	}
+	return fmt.Sprintf("%s every %s", w.Symbol, w.Interval)
+}
+
+// priceWatches tracks running watches by chat and symbol
+type priceWatches struct {
+	mu      sync.Mutex
+	running map[int64]map[string]*priceWatch
+}
+
+// start registers the watch, replacing the chat's previous watch of the symbol. It fails when the chat
+// already watches too many symbols.
+func (p *priceWatches) start(chatID int64, watch *priceWatch) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	watches := p.running[chatID]
+	if watches == nil {
+		watches = make(map[string]*priceWatch)
+		p.running[chatID] = watches
+	}
+
+	if prev, ok := watches[watch.Symbol]; ok {
+		prev.cancel()
+	} else if len(watches) >= maxWatchesPerChat {
+		return fmt.Errorf("too many watches")
+	}
+
+	watches[watch.Symbol] = watch
+
+	return nil
+}
+
+// full reports whether the chat can't watch the symbol without stopping another watch first
+func (p *priceWatches) full(chatID int64, symbol string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, replaced := p.running[chatID][symbol]
+	return !replaced && len(p.running[chatID]) >= maxWatchesPerChat
+}
+
+// finish unregisters the watch unless it was already replaced by a newer one
+func (p *priceWatches) finish(chatID int64, watch *priceWatch) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running[chatID][watch.Symbol] == watch {
+		delete(p.running[chatID], watch.Symbol)
+	}
+	if len(p.running[chatID]) == 0 {
+		delete(p.running, chatID)
+	}
+}
+
+// stop stops watches of the chat, all of them when symbol is empty, and returns their number
+func (p *priceWatches) stop(chatID int64, symbol string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stopped := 0
+	for s, watch := range p.running[chatID] {
+		if symbol != "" && !strings.EqualFold(s, symbol) {
+			continue
+		}
+		watch.cancel()
+		delete(p.running[chatID], s)
+		stopped++
+	}
+
+	return stopped
+}
+
+// list returns descriptions of the chat's watches ordered by symbol
+func (p *priceWatches) list(chatID int64) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var watches []string
+	for _, watch := range p.running[chatID] {
+		watches = append(watches, watch.String())
+	}
+	sort.Strings(watches)
+
+	return watches
+}
+
+// handleWatch posts prices of a symbol as they change, "/watch <symbol> [every <interval>|on <move>%]".
+// Updates are throttled by the watch: at most one per interval, or one per move of the given size.
+func (b *Bot) handleWatch(ctx context.Context, msg *Message) (*Response, error) {
+	if len(msg.Args) == 0 {
+		watches := b.watches.list(msg.ChatID)
+		if len(watches) == 0 {
+			return NewResponse(msg).Text("👀 Nothing is watched in this chat. Start with /watch <symbol> every 5m or /watch <symbol> on 0.3%").Build(), nil
+		}
+		return NewResponse(msg).Text("👀 Watched in this chat:\n• " + strings.Join(watches, "\n• ") + "\n\nStop with /watch stop [symbol]").Build(), nil
+	}
+
+	if strings.EqualFold(msg.Args[0], "stop") {
+		symbol := ""
+		if len(msg.Args) > 1 {
+			symbol = msg.Args[1]
+		}
+		if b.watches.stop(msg.ChatID, symbol) == 0 {
+			return NewResponse(msg).Text("ℹ️ No matching watch is running in this chat.").Build(), nil
+		}
+		return NewResponse(msg).Text("⏹ Stopped watching.").Build(), nil
+	}
+
+	watch, ok := parseWatch(msg.Args[1:])
+	if !ok {
+		return NewResponse(msg).Text(watchUsage).Build(), nil
+	}
+
+	symbol, choice := b.resolveSymbol(ctx, msg, msg.Args[0], func(symbol string) string {
+		return "watch:" + strings.Join(append([]string{symbol}, msg.Args[1:]...), ":")
+	})
+	if choice != nil {
+		return choice, nil
+	}
+	watch.Symbol = symbol
+
+	if b.watches.full(msg.ChatID, symbol) {
+		return NewResponse(msg).Textf("❌ A chat can watch up to %d symbols, stop one with /watch stop <symbol> first.", maxWatchesPerChat).Build(), nil
+	}
+
+	if err := b.startWatch(ctx, msg, watch); err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg).Textf("👀 Watching %s. Stop with /watch stop %s", watch, symbol).Build(), nil
+}
+
+// parseWatch reads when a watch posts updates, once a minute when nothing is given
+func parseWatch(args []string) (*priceWatch, bool) {
+	switch {
+	case len(args) == 0:
+		return &priceWatch{Interval: defaultWatchInterval}, true
+	case len(args) == 2 && strings.EqualFold(args[0], "every"):
+		interval, err := time.ParseDuration(args[1])
+		if err != nil || interval < minWatchInterval {
+			return nil, false
+		}
+		return &priceWatch{Interval: interval}, true
+	case len(args) == 2 && strings.EqualFold(args[0], "on"):
+		move, err := strconv.ParseFloat(strings.TrimSuffix(args[1], "%"), 64)
+		if err != nil || move <= 0 {
+			return nil, false
+		}
+		return &priceWatch{Move: move}, true
+	default:
+		return nil, false
+	}
+}
+
+// startWatch runs the watch as a subscription of the chat, so it stops when the chat goes idle
+func (b *Bot) startWatch(ctx context.Context, msg *Message, watch *priceWatch) error {
+	username := msg.Username
+	chatID := msg.ChatID
+	f := b.formatter(ctx, msg)
+
+	started := make(chan error, 1)
+	err := b.background.Go(func(bgCtx context.Context, notifier Notifier) {
+		notifier = b.userNotifier(bgCtx, notifier, username)
+
+		watchCtx, unsubscribe := b.subscribe(bgCtx, chatID, "watch of "+watch.Symbol, "/watch "+watch.Symbol)
+		defer unsubscribe()
+
+		watchCtx, watch.cancel = context.WithCancel(watchCtx)
+		defer watch.cancel()
+
+		if err := b.watches.start(chatID, watch); err != nil {
+			started <- err
+			return
+		}
+		defer b.watches.finish(chatID, watch)
+		started <- nil
+
+		// The newest tick replaces one the loop hasn't read yet, so a slow chat never holds up the feed
+		ticks := make(chan HistoricalDataPoint, 1)
+		unsubscribeTicks := b.events.Subscribe(EventFilter{Type: EventTick, Symbol: watch.Symbol}, func(event Event) {
+			select {
+			case <-ticks:
+			default:
+			}
+			select {
+			case ticks <- event.Price:
+			default:
+			}
+		})
+		defer unsubscribeTicks()
+
+		var interval <-chan time.Time
+		if watch.Interval > 0 {
+			ticker := time.NewTicker(watch.Interval)
+			defer ticker.Stop()
+			interval = ticker.C
+		}
+
+		var latest, posted HistoricalDataPoint
+		post := func(tick HistoricalDataPoint) {
+			b.notify(watchCtx, notifier, username, NotifyAlerts, NewNotification(chatID).Text(formatWatchUpdate(f, watch.Symbol, tick, posted)).Build())
+			posted = tick
+		}
+
+		for {
+			select {
+			case <-watchCtx.Done():
+				return
+			case tick := <-ticks:
+				latest = tick
+				if watch.Move == 0 {
+					continue
+				}
+				if posted.Price == 0 {
+					posted = tick
+					continue
+				}
+				if math.Abs(tick.Price-posted.Price)/posted.Price*100 >= watch.Move {
+					post(tick)
+				}
+			case <-interval:
+				if latest.Timestamp != 0 && latest.Timestamp != posted.Timestamp {
+					post(latest)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start watch: %w", err)
+	}
+
+	if err := <-started; err != nil {
+		return fmt.Errorf("failed to start watch: %w", err)
+	}
+
+	return nil
+}
+
+// formatWatchUpdate renders the price of a watched symbol with its change since the previous update
+func formatWatchUpdate(f Formatter, symbol string, tick, prev HistoricalDataPoint) string {
+	decimals := priceDecimals([]HistoricalDataPoint{tick, prev})
+	text := fmt.Sprintf("👀 %s: %s", symbol, f.Number(tick.Price, decimals))
+	if prev.Price != 0 {
+		text += fmt.Sprintf(" (%+.2f%%)", (tick.Price-prev.Price)/prev.Price*100)
+	}
+	return text
+}
@@ -0,0 +1,421 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/metrics"
+)
+
+// bucketAdminChats is the storage bucket holding chat IDs where admins receive alerts
+const bucketAdminChats = "admin_chats"
+
+// tradingCommands are commands refused while trading is paused by the watchdog
+var tradingCommands = map[string]bool{
+	"buy":          true,
+	"basket":       true,
+	"mult":         true,
+	"touch":        true,
+	"digit":        true,
+	"confirmtrade": true,
+	"direction":    true,
+}
+
+// staleDataCommands are read-only commands whose responses warn about stale data while the connection is degraded
+var staleDataCommands = map[string]bool{
+	"price":     true,
+	"chart":     true,
+	"compare":   true,
+	"mtf":       true,
+	"ticks":     true,
+	"markets":   true,
+	"position":  true,
+	"exposure":  true,
+	"portfolio": true,
+	"dashboard": true,
+	"size":      true,
+	"contract":  true,
+}
+
+// WatchdogConfig holds thresholds of the watchdog pausing trading on abnormal API behavior
+type WatchdogConfig struct {
+	CheckInterval time.Duration `mapstructure:"check_interval"` // How often health is evaluated, zero disables the watchdog
+	Window        time.Duration `mapstructure:"window"`         // Period error rate and reconnects are counted over
+	MaxErrorRate  float64       `mapstructure:"max_error_rate"` // Share of failed API calls, from 0 to 1
+	MinCalls      int           `mapstructure:"min_calls"`      // Calls needed in the window before the error rate is judged
+	MaxReconnects int           `mapstructure:"max_reconnects"` // Connection drops tolerated in the window
+	ProbeSymbol   string        `mapstructure:"probe_symbol"`   // Symbol whose latest tick is checked for staleness
+	MaxPriceAge   time.Duration `mapstructure:"max_price_age"`  // Age of the latest tick considered stale
+}
+
+// HealthObserver receives outcomes of Deriv API calls
+type HealthObserver interface {
+	// ObserveCall records a call and how long it took, failed is set for errors caused by the API or connection rather than the request
+	ObserveCall(failed bool, latency time.Duration)
+	// ObserveReconnect records a dropped connection
+	ObserveReconnect()
+}
+
+// Watchdog tracks health of Deriv API and puts the bot in read-only mode when it looks abnormal
+type Watchdog struct {
+	mu         sync.Mutex
+	cfg        WatchdogConfig
+	calls      []callOutcome
+	reconnects []time.Time
+	paused     bool
+	reason     string
+	degraded   string    // Why the connection looks degraded at the last check, empty when it's healthy
+	lastTick   time.Time // Time of the latest probed tick
+	latencies  *metrics.Registry
+}
+
+// callOutcome is a single observed API call
+type callOutcome struct {
+	at     time.Time
+	failed bool
+}
+
+// newWatchdog creates a watchdog with the given thresholds, latencies of observed calls are recorded in the registry
+func newWatchdog(cfg WatchdogConfig, latencies *metrics.Registry) *Watchdog {
+	return &Watchdog{cfg: cfg, latencies: latencies}
+}
+
+// ObserveCall implements HealthObserver
+func (w *Watchdog) ObserveCall(failed bool, latency time.Duration) {
+	w.latencies.Observe(metricDerivLatency, float64(latency.Milliseconds()))
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.calls = append(w.calls, callOutcome{at: now, failed: failed})
+	w.trim(now)
+}
+
+// ObserveReconnect implements HealthObserver
+func (w *Watchdog) ObserveReconnect() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.reconnects = append(w.reconnects, now)
+	w.trim(now)
+}
+
+// trim drops observations older than the window, it must be called with the lock held
+func (w *Watchdog) trim(now time.Time) {
+	since := now.Add(-w.cfg.Window)
+
+	i := 0
+	for i < len(w.calls) && w.calls[i].at.Before(since) {
+		i++
+	}
+	w.calls = w.calls[i:]
+
+	i = 0
+	for i < len(w.reconnects) && w.reconnects[i].Before(since) {
+		i++
+	}
+	w.reconnects = w.reconnects[i:]
+}
+
+// anomaly returns the reason API behavior looks abnormal, or an empty string when it's healthy
+func (w *Watchdog) anomaly(now time.Time) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.trim(now)
+
+	if w.cfg.MaxReconnects > 0 && len(w.reconnects) > w.cfg.MaxReconnects {
+		return fmt.Sprintf("%d reconnects in the last %s", len(w.reconnects), w.cfg.Window)
+	}
+
+	if w.cfg.MaxErrorRate > 0 && len(w.calls) >= w.cfg.MinCalls && len(w.calls) > 0 {
+		var failed int
+		for _, call := range w.calls {
+			if call.failed {
+				failed++
+			}
+		}
+
+		if rate := float64(failed) / float64(len(w.calls)); rate > w.cfg.MaxErrorRate {
+			return fmt.Sprintf("%.0f%% of %d API calls failed in the last %s", rate*100, len(w.calls), w.cfg.Window)
+		}
+	}
+
+	return ""
+}
+
+// reconnecting returns the reason the connection looks like it's being restored, it's a connection drop
+// observed within the given time, or an empty string
+func (w *Watchdog) reconnecting(now time.Time, within time.Duration) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if n := len(w.reconnects); n > 0 && now.Sub(w.reconnects[n-1]) < within {
+		return "reconnecting to Deriv API"
+	}
+
+	return ""
+}
+
+// observeTick records the time of the latest probed tick
+func (w *Watchdog) observeTick(at time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastTick = at
+}
+
+// setDegraded records the outcome of the last check, it reports whether the state changed
+func (w *Watchdog) setDegraded(reason string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changed := (w.degraded == "") != (reason == "")
+	w.degraded = reason
+
+	return changed
+}
+
+// Degraded returns why the connection looked degraded at the last check, or an empty string,
+// and the time of the latest probed tick, zero when it's unknown
+func (w *Watchdog) Degraded() (string, time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.degraded, w.lastTick
+}
+
+// pause switches to read-only mode, it reports false when trading is already paused
+func (w *Watchdog) pause(reason string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.paused {
+		return false
+	}
+
+	w.paused = true
+	w.reason = reason
+
+	return true
+}
+
+// resume allows trading again and forgets observations made before, it reports false when trading wasn't paused
+func (w *Watchdog) resume() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.paused {
+		return false
+	}
+
+	w.paused = false
+	w.reason = ""
+	w.calls = nil
+	w.reconnects = nil
+
+	return true
+}
+
+// Paused reports whether trading is paused and why
+func (w *Watchdog) Paused() (bool, string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.paused, w.reason
+}
+
+// Watchdog returns the watchdog to be fed with Deriv API call outcomes
+func (b *Bot) Watchdog() *Watchdog {
+	return b.watchdog
+}
+
+// watchdogGuard refuses trading commands while trading is paused or the connection is degraded
+func (b *Bot) watchdogGuard(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		if !tradingCommands[msg.Command] {
+			return next(ctx, msg)
+		}
+
+		if paused, reason := b.watchdog.Paused(); paused {
+			return NewResponse(msg).Textf("🛑 Trading is paused (%s). Prices and account info are still available.", reason).Build(), nil
+		}
+
+		if reason, _ := b.watchdog.Degraded(); reason != "" {
+			return NewResponse(msg).Textf("⏳ Trading is unavailable while the Deriv connection is degraded (%s). It's allowed again automatically once the connection recovers.", reason).Build(), nil
+		}
+
+		return next(ctx, msg)
+	}
+}
+
+// staleDataNotice warns that market data in responses may be stale while the connection is degraded
+func (b *Bot) staleDataNotice(next CommandHandler) CommandHandler {
+	return func(ctx context.Context, msg *Message) (*Response, error) {
+		resp, err := next(ctx, msg)
+		if resp == nil || resp.Text == "" || !staleDataCommands[msg.Command] {
+			return resp, err
+		}
+
+		reason, lastTick := b.watchdog.Degraded()
+		if reason == "" {
+			return resp, err
+		}
+
+		if lastTick.IsZero() {
+			resp.Text += fmt.Sprintf("\n\n⚠️ Data may be stale (%s)", reason)
+		} else {
+			resp.Text += fmt.Sprintf("\n\n⚠️ Data may be stale (last tick %s ago)", time.Since(lastTick).Round(time.Second))
+		}
+
+		return resp, err
+	}
+}
+
+// runWatchdog periodically checks API health and pauses trading on anomalies until an admin resumes it.
+// Checks go on while trading is paused, so the degraded state follows the connection and clears on its own.
+func (b *Bot) runWatchdog(ctx context.Context, notifier Notifier) {
+	interval := b.cfg.Watchdog.CheckInterval
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Nobody trades while idling, probing prices would only keep the API busy
+		if b.Idle() {
+			b.watchdog.setDegraded("")
+			continue
+		}
+
+		now := time.Now()
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		reason := b.watchdog.anomaly(now)
+		if reason == "" {
+			reason = b.priceStaleness(checkCtx)
+		}
+		cancel()
+
+		degraded := reason
+		if degraded == "" {
+			degraded = b.watchdog.reconnecting(now, interval)
+		}
+
+		if b.watchdog.setDegraded(degraded) {
+			if degraded != "" {
+				log.Printf("Deriv connection degraded, trading disabled: %s", degraded)
+			} else {
+				log.Printf("Deriv connection recovered, trading enabled")
+			}
+		}
+
+		if reason == "" || !b.watchdog.pause(reason) {
+			continue
+		}
+
+		log.Printf("Watchdog paused trading: %s", reason)
+		b.alertAdmins(ctx, notifier, fmt.Sprintf("🛑 Trading paused: %s.\nCheck Deriv API status and use /resume to allow trading again.", reason))
+	}
+}
+
+// priceStaleness checks the latest tick of the probe symbol, returning the reason it's stale or an empty string
+func (b *Bot) priceStaleness(ctx context.Context) string {
+	symbol := b.cfg.Watchdog.ProbeSymbol
+	if symbol == "" || b.cfg.Watchdog.MaxPriceAge <= 0 {
+		return ""
+	}
+
+	ticks, err := b.derivClient.GetHistoricalData(ctx, HistoricalDataRequest{
+		Symbol:   symbol,
+		Interval: IntervalHour,
+		Style:    StyleTicks,
+		Count:    1,
+	})
+	if err != nil {
+		// Failed calls are already counted towards the error rate
+		log.Printf("Watchdog failed to get the latest tick of %s: %v", symbol, err)
+		return ""
+	}
+
+	if len(ticks) == 0 {
+		return fmt.Sprintf("no ticks of %s in the last hour", symbol)
+	}
+
+	latest := time.Unix(ticks[len(ticks)-1].Timestamp, 0)
+	b.watchdog.observeTick(latest)
+
+	if age := time.Since(latest); age > b.cfg.Watchdog.MaxPriceAge {
+		return fmt.Sprintf("latest %s price is %s old", symbol, age.Round(time.Second))
+	}
+
+	return ""
+}
+
+// handleResume allows trading after the watchdog or the kill switch paused it, admin only
+func (b *Bot) handleResume(ctx context.Context, msg *Message) (*Response, error) {
+	if !b.isAdmin(msg.Username) {
+		return NewResponse(msg).Text("⚠️ This command is available to admins only.").Build(), nil
+	}
+
+	text := "ℹ️ Trading is not paused."
+	if b.watchdog.resume() {
+		log.Printf("Trading resumed by %s", msg.Username)
+		text = "▶️ Trading resumed."
+	}
+
+	return NewResponse(msg).Text(text).Build(), nil
+}
+
+// rememberAdminChat stores the chat an admin writes from, so alerts can reach them later
+func (b *Bot) rememberAdminChat(ctx context.Context, msg *Message) {
+	if !b.isAdmin(msg.Username) || msg.ChatID == 0 {
+		return
+	}
+
+	var chatID int64
+	err := b.storage.Get(ctx, bucketAdminChats, msg.Username, &chatID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		log.Printf("Failed to load chat of admin %s: %v", msg.Username, err)
+		return
+	}
+
+	if chatID == msg.ChatID {
+		return
+	}
+
+	if err := b.storage.Put(ctx, bucketAdminChats, msg.Username, msg.ChatID); err != nil {
+		log.Printf("Failed to save chat of admin %s: %v", msg.Username, err)
+	}
+}
+
+// alertAdmins sends the text to every admin who has written to the bot before
+func (b *Bot) alertAdmins(ctx context.Context, notifier Notifier, text string) {
+	var missing []string
+	for username := range b.admins {
+		var chatID int64
+		if err := b.storage.Get(ctx, bucketAdminChats, username, &chatID); err != nil {
+			missing = append(missing, username)
+			continue
+		}
+
+		if _, err := notifier.Send(ctx, NewNotification(chatID).Text(text).Build()); err != nil {
+			log.Printf("Failed to alert admin %s: %v", username, err)
+		}
+	}
+
+	if len(missing) > 0 {
+		log.Printf("Admins without a known chat were not alerted: %s", strings.Join(missing, ", "))
+	}
+}
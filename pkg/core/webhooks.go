@@ -0,0 +1,181 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// bucketWebhooks is the storage bucket holding webhooks of users, secrets make it sensitive
+const bucketWebhooks = "webhooks"
+
+// webhookTimeout bounds delivery of a single event
+const webhookTimeout = 30 * time.Second
+
+// WebhookSender posts signed payloads to user-defined URLs
+type WebhookSender interface {
+	Deliver(ctx context.Context, url, secret string, payload []byte) error
+}
+
+// Webhook is a URL receiving trade events of a user
+type Webhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"` // Key of the HMAC-SHA256 signature of payloads
+}
+
+// webhookPayload is the JSON body posted to webhooks
+type webhookPayload struct {
+	Event EventType    `json:"event"`
+	Time  time.Time    `json:"time"`
+	Trade *TradeRecord `json:"trade"`
+}
+
+// SetWebhookSender enables trade event webhooks delivered by the sender
+func (b *Bot) SetWebhookSender(sender WebhookSender) {
+	b.webhooks = sender
+}
+
+// getWebhook loads the user's webhook, nil when there is none
+func (b *Bot) getWebhook(ctx context.Context, username string) (*Webhook, error) {
+	var hook Webhook
+
+	err := b.storage.Get(ctx, bucketWebhooks, username, &hook)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to load webhook: %w", err)
+	}
+
+	return &hook, nil
+}
+
+// postTradeEvent delivers the trade event to the webhook of the trade's owner in the background
+func (b *Bot) postTradeEvent(event Event) {
+	if b.webhooks == nil || event.Trade == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+		defer cancel()
+
+		username := event.Trade.Username
+
+		hook, err := b.getWebhook(ctx, username)
+		if err != nil {
+			log.Printf("Failed to load webhook of %s: %v", username, err)
+			return
+		}
+		if hook == nil {
+			return
+		}
+
+		if err := b.postWebhook(ctx, hook, webhookPayload{Event: event.Type, Time: event.Time, Trade: event.Trade}); err != nil {
+			log.Printf("Failed to deliver %s webhook of %s: %v", event.Type, username, err)
+		}
+	}()
+}
+
+// postWebhook encodes and delivers the payload
+func (b *Bot) postWebhook(ctx context.Context, hook *Webhook, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	return b.webhooks.Deliver(ctx, hook.URL, hook.Secret, body)
+}
+
+// validWebhookURL reports whether the URL can receive webhooks, only https is accepted
+func validWebhookURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme == "https" && u.Host != "" && u.User == nil
+}
+
+// newWebhookSecret generates a random signing key
+func newWebhookSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// handleWebhook manages the user's webhook, "/webhook set <url>", "/webhook test" and "/webhook off"
+func (b *Bot) handleWebhook(ctx context.Context, msg *Message) (*Response, error) {
+	rb := NewResponse(msg)
+
+	if b.webhooks == nil {
+		return rb.Text("❌ Webhooks are not enabled on this bot").Build(), nil
+	}
+
+	hook, err := b.getWebhook(ctx, msg.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := "❌ Usage: /webhook set <https://...>, /webhook test or /webhook off"
+
+	if len(msg.Args) == 0 {
+		if hook == nil {
+			rb.Text("🪝 No webhook set. Use /webhook set <https://...> to receive your trade events as signed JSON.")
+		} else {
+			rb.Textf("🪝 Webhook: %s\nUse /webhook test to send a sample event or /webhook off to remove it.", hook.URL)
+		}
+		return rb.Build(), nil
+	}
+
+	switch strings.ToLower(msg.Args[0]) {
+	case "set":
+		if len(msg.Args) != 2 || !validWebhookURL(msg.Args[1]) {
+			return rb.Text("❌ Please give an https URL, e.g. /webhook set https://example.com/hooks/trades").Build(), nil
+		}
+
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := b.storage.Put(ctx, bucketWebhooks, msg.Username, &Webhook{URL: msg.Args[1], Secret: secret}); err != nil {
+			return nil, fmt.Errorf("failed to save webhook: %w", err)
+		}
+
+		rb.Textf("✅ Trade events will be posted to %s\n\n"+
+			"Events: trade_opened, trade_settled. Each request carries the header "+
+			"X-Teletrader-Signature: sha256=<HMAC-SHA256 of the body> signed with this secret:\n%s\n\n"+
+			"Keep the secret safe, it's shown only now. Setting the webhook again creates a new one.", msg.Args[1], secret)
+	case "test":
+		if hook == nil {
+			return rb.Text("❌ No webhook set. Use /webhook set <https://...> first.").Build(), nil
+		}
+
+		sample := webhookPayload{
+			Event: "test",
+			Time:  time.Now(),
+			Trade: &TradeRecord{Username: msg.Username, Symbol: "R_50", ContractType: "CALL", Status: ContractStatusOpen},
+		}
+		if err := b.postWebhook(ctx, hook, sample); err != nil {
+			log.Printf("Failed to deliver test webhook of %s: %v", msg.Username, err)
+			return rb.Textf("❌ The test event couldn't be delivered: %v", err).Build(), nil
+		}
+
+		rb.Text("✅ Test event delivered")
+	case "off":
+		if err := b.storage.Delete(ctx, bucketWebhooks, msg.Username); err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("failed to delete webhook: %w", err)
+		}
+		rb.Text("✅ Webhook removed")
+	default:
+		rb.Text(usage)
+	}
+
+	return rb.Build(), nil
+}
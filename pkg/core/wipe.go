@@ -0,0 +1,328 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const bucketWiped = "wiped"
+
+// wipePurgeInterval is how often wiped data past its retention is looked for
+const wipePurgeInterval = time.Hour
+
+// userBuckets hold one record per user keyed by the username
+var userBuckets = []string{bucketSettings, bucketStreaks, bucketDailyPnL, bucketAlerts, bucketOrders, bucketStrategies, bucketWebhooks, bucketCopying}
+
+// userPrefixedBuckets hold many records per user keyed by "username/..."
+var userPrefixedBuckets = []string{bucketBaskets}
+
+// wipedUser keeps soft-deleted data of a user until it's restored or purged
+type wipedUser struct {
+	RequestedAt time.Time                             `json:"requested_at"`
+	PurgeAt     time.Time                             `json:"purge_at"`
+	Records     map[string]map[string]json.RawMessage `json:"records"` // Stored values by bucket and key
+}
+
+// handleWipe soft-deletes or restores stored data of the user, "/wipe me|restore"
+func (b *Bot) handleWipe(ctx context.Context, msg *Message) (*Response, error) {
+	rb := NewResponse(msg)
+
+	retention := b.cfg.WipeRetention
+
+	switch {
+	case len(msg.Args) == 1 && msg.Args[0] == "me":
+		rb.Textf("🗑 This deletes your settings, trade history, baskets, alerts, pending orders and webhooks, and disconnects your Deriv account.\n"+
+			"You can undo it with /wipe restore for %s, after that the data is gone for good. Continue?", retention)
+		rb.Keyboard([][]Button{{
+			{Text: "🗑 Delete my data", CallbackData: "wipe:me:confirm"},
+		}})
+	case len(msg.Args) == 2 && msg.Args[0] == "me" && msg.Args[1] == "confirm":
+		wiped, err := b.wipeUser(ctx, msg.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		rb.Edit(msg.MessageID)
+		rb.Textf("✅ Your data has been deleted. Use /wipe restore before %s to bring it back.",
+			wiped.PurgeAt.UTC().Format("Jan 2 15:04 MST"))
+	case len(msg.Args) == 1 && msg.Args[0] == "restore":
+		restored, err := b.restoreUser(ctx, msg.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		if !restored {
+			return rb.Text("ℹ️ There is no deleted data to restore.").Build(), nil
+		}
+
+		rb.Text("✅ Your data has been restored. Use /connect to link your Deriv account again.")
+	default:
+		rb.Text("❌ Usage: /wipe me | /wipe restore")
+	}
+
+	return rb.Build(), nil
+}
+
+// wipeUser moves stored data of the user aside until the retention ends, credentials are deleted right away
+func (b *Bot) wipeUser(ctx context.Context, username string) (*wipedUser, error) {
+	wiped, err := b.getWiped(ctx, username)
+	if errors.Is(err, ErrNotFound) {
+		wiped = &wipedUser{Records: make(map[string]map[string]json.RawMessage)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	wiped.RequestedAt = now
+	wiped.PurgeAt = now.Add(b.cfg.WipeRetention)
+
+	keys := make(map[string][]string, len(userBuckets)+len(userPrefixedBuckets))
+	for _, bucket := range userBuckets {
+		keys[bucket] = []string{username}
+	}
+
+	for _, bucket := range userPrefixedBuckets {
+		all, err := b.storage.Keys(ctx, bucket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", bucket, err)
+		}
+
+		for _, key := range all {
+			if strings.HasPrefix(key, username+"/") {
+				keys[bucket] = append(keys[bucket], key)
+			}
+		}
+	}
+
+	for bucket, bucketKeys := range keys {
+		for _, key := range bucketKeys {
+			var value json.RawMessage
+			err := b.storage.Get(ctx, bucket, key, &value)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to load %s: %w", bucket, err)
+			}
+
+			if wiped.Records[bucket] == nil {
+				wiped.Records[bucket] = make(map[string]json.RawMessage)
+			}
+			wiped.Records[bucket][key] = value
+		}
+	}
+
+	// Trades live in the journal, they're kept under the trades bucket of the copy
+	trades, err := b.userTrades(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range trades {
+		value, err := json.Marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode trade %d: %w", record.ContractID, err)
+		}
+
+		if wiped.Records[bucketTrades] == nil {
+			wiped.Records[bucketTrades] = make(map[string]json.RawMessage)
+		}
+		wiped.Records[bucketTrades][tradeKey(username, record.ContractID)] = value
+	}
+
+	// The copy is saved before anything is deleted, so a failure never loses data
+	if err := b.storage.Put(ctx, bucketWiped, username, wiped); err != nil {
+		return nil, fmt.Errorf("failed to save wiped data: %w", err)
+	}
+
+	alerts, err := b.getAlerts(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range alerts {
+		b.unwatchAlert(username, &alerts[i])
+	}
+
+	orders, err := b.getOrders(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		b.unwatchOrder(username, &orders[i])
+	}
+
+	b.haltStrategies(ctx, username)
+
+	for bucket, bucketKeys := range keys {
+		for _, key := range bucketKeys {
+			if err := b.storage.Delete(ctx, bucket, key); err != nil && !errors.Is(err, ErrNotFound) {
+				return nil, fmt.Errorf("failed to delete %s: %w", bucket, err)
+			}
+		}
+	}
+
+	for _, record := range trades {
+		if err := b.journal.Delete(ctx, username, record.ContractID); err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("failed to delete trade %d: %w", record.ContractID, err)
+		}
+	}
+
+	var creds Credentials
+	err = b.storage.Get(ctx, bucketCredentials, username, &creds)
+	if err == nil {
+		if err := b.storage.Delete(ctx, bucketCredentials, username); err != nil {
+			return nil, fmt.Errorf("failed to delete credentials: %w", err)
+		}
+
+		if err := b.pool.Release(creds.Token); err != nil {
+			log.Printf("Failed to release connection of %s: %v", username, err)
+		}
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	log.Printf("Audit: %s wiped their data, purge at %s", username, wiped.PurgeAt.UTC().Format(time.RFC3339))
+
+	return wiped, nil
+}
+
+// restoreUser puts back soft-deleted data of the user, it reports false when there is nothing to restore
+func (b *Bot) restoreUser(ctx context.Context, username string) (bool, error) {
+	wiped, err := b.getWiped(ctx, username)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if time.Now().After(wiped.PurgeAt) {
+		return false, nil
+	}
+
+	for bucket, records := range wiped.Records {
+		for key, value := range records {
+			if bucket == bucketTrades {
+				var record TradeRecord
+				if err := json.Unmarshal(value, &record); err != nil {
+					return false, fmt.Errorf("failed to decode trade %s: %w", key, err)
+				}
+
+				if err := b.journal.Save(ctx, &record); err != nil {
+					return false, fmt.Errorf("failed to restore trade %s: %w", key, err)
+				}
+				continue
+			}
+
+			if err := b.storage.Put(ctx, bucket, key, value); err != nil {
+				return false, fmt.Errorf("failed to restore %s: %w", bucket, err)
+			}
+		}
+	}
+
+	if err := b.storage.Delete(ctx, bucketWiped, username); err != nil {
+		return false, fmt.Errorf("failed to delete wiped data: %w", err)
+	}
+
+	alerts, err := b.getAlerts(ctx, username)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range alerts {
+		b.watchAlert(username, &alerts[i])
+	}
+
+	orders, err := b.getOrders(ctx, username)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range orders {
+		b.watchOrder(username, &orders[i])
+	}
+
+	b.resumeStrategies(ctx, username)
+
+	log.Printf("Audit: %s restored their wiped data", username)
+
+	return true, nil
+}
+
+// getWiped loads soft-deleted data of the user
+func (b *Bot) getWiped(ctx context.Context, username string) (*wipedUser, error) {
+	var wiped wipedUser
+	if err := b.storage.Get(ctx, bucketWiped, username, &wiped); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to load wiped data: %w", err)
+	}
+
+	return &wiped, nil
+}
+
+// describeWipe writes the pending wipe of the user for the support view
+func (b *Bot) describeWipe(ctx context.Context, sb *strings.Builder, username string) error {
+	wiped, err := b.getWiped(ctx, username)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(sb, "🗑 Data wipe requested %s, purge at %s\n",
+		wiped.RequestedAt.UTC().Format("Jan 2 15:04"), wiped.PurgeAt.UTC().Format("Jan 2 15:04"))
+
+	return nil
+}
+
+// runWipePurge hard-deletes soft-deleted data once its retention is over
+func (b *Bot) runWipePurge(ctx context.Context, _ Notifier) {
+	ticker := time.NewTicker(wipePurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		b.purgeWiped(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeWiped deletes soft-deleted data past its retention
+func (b *Bot) purgeWiped(ctx context.Context, now time.Time) {
+	usernames, err := b.storage.Keys(ctx, bucketWiped)
+	if err != nil {
+		log.Printf("Failed to list wiped data: %v", err)
+		return
+	}
+
+	for _, username := range usernames {
+		wiped, err := b.getWiped(ctx, username)
+		if err != nil {
+			log.Printf("Failed to load wiped data of %s: %v", username, err)
+			continue
+		}
+
+		if now.Before(wiped.PurgeAt) {
+			continue
+		}
+
+		if err := b.storage.Delete(ctx, bucketWiped, username); err != nil {
+			log.Printf("Failed to purge wiped data of %s: %v", username, err)
+			continue
+		}
+
+		log.Printf("Audit: purged wiped data of %s requested %s", username, wiped.RequestedAt.UTC().Format(time.RFC3339))
+	}
+}
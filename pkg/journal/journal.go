@@ -0,0 +1,225 @@
+// Package journal keeps the trade journal in a SQLite database indexed by user, symbol and placement time
+package journal
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+
+	_ "modernc.org/sqlite" // Registers the pure Go "sqlite" driver
+)
+
+// schema creates the trades table, columns next to the record are the ones queries filter on
+const schema = `
+CREATE TABLE IF NOT EXISTS trades (
+	username      TEXT    NOT NULL,
+	contract_id   INTEGER NOT NULL,
+	symbol        TEXT    NOT NULL,
+	contract_type TEXT    NOT NULL,
+	stake         REAL    NOT NULL,
+	status        TEXT    NOT NULL,
+	profit        REAL    NOT NULL,
+	entry_spot    REAL    NOT NULL,
+	exit_spot     REAL    NOT NULL,
+	placed_at     INTEGER NOT NULL,
+	settled_at    INTEGER NOT NULL,
+	record        TEXT    NOT NULL,
+	PRIMARY KEY (username, contract_id)
+);
+CREATE INDEX IF NOT EXISTS trades_symbol ON trades (username, symbol, placed_at);
+CREATE INDEX IF NOT EXISTS trades_placed ON trades (username, placed_at);
+CREATE INDEX IF NOT EXISTS trades_status ON trades (status);
+`
+
+// DefaultFile is the name of the database kept in the data directory when no path is configured
+const DefaultFile = "journal.db"
+
+// Config holds settings of the trade journal
+type Config struct {
+	Path string `mapstructure:"path"` // Path to the SQLite database
+}
+
+// Journal is a trade journal kept in a SQLite database
+type Journal struct {
+	db *sql.DB
+}
+
+// Open opens the database of the journal, creating it and its schema when missing
+func Open(cfg *Config) (*Journal, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("journal path is not set")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	// SQLite has a single writer, one connection avoids "database is locked" errors between them
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000", schema} {
+		if _, err := db.Exec(stmt); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to prepare journal: %w", err)
+		}
+	}
+
+	return &Journal{db: db}, nil
+}
+
+// Close closes the database
+func (j *Journal) Close() error {
+	return j.db.Close()
+}
+
+// Save stores the trade, replacing the one of the same user with the same contract ID
+func (j *Journal) Save(ctx context.Context, record *core.TradeRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode trade: %w", err)
+	}
+
+	var settledAt int64
+	if !record.SettledAt.IsZero() {
+		settledAt = record.SettledAt.UnixNano()
+	}
+
+	_, err = j.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO trades
+			(username, contract_id, symbol, contract_type, stake, status, profit, entry_spot, exit_spot, placed_at, settled_at, record)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.Username, record.ContractID, record.Symbol, record.ContractType, record.Stake, record.Status, record.Profit,
+		record.EntrySpot, record.ExitSpot, record.PlacedAt.UnixNano(), settledAt, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to save trade: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the trade of the user with the contract ID, or core.ErrNotFound
+func (j *Journal) Get(ctx context.Context, username string, contractID int64) (*core.TradeRecord, error) {
+	var data string
+	err := j.db.QueryRowContext(ctx, "SELECT record FROM trades WHERE username = ? AND contract_id = ?", username, contractID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, core.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load trade: %w", err)
+	}
+
+	return decode(data)
+}
+
+// Delete removes the trade of the user with the contract ID
+func (j *Journal) Delete(ctx context.Context, username string, contractID int64) error {
+	if _, err := j.db.ExecContext(ctx, "DELETE FROM trades WHERE username = ? AND contract_id = ?", username, contractID); err != nil {
+		return fmt.Errorf("failed to delete trade: %w", err)
+	}
+
+	return nil
+}
+
+// Query returns trades passing the filter ordered by placement time
+func (j *Journal) Query(ctx context.Context, filter *core.JournalFilter) ([]*core.TradeRecord, error) {
+	var where []string
+	var args []any
+
+	if filter.Username != "" {
+		where = append(where, "username = ?")
+		args = append(args, filter.Username)
+	}
+	if filter.Symbol != "" {
+		where = append(where, "symbol = ?")
+		args = append(args, filter.Symbol)
+	}
+	if !filter.From.IsZero() {
+		where = append(where, "placed_at >= ?")
+		args = append(args, filter.From.UnixNano())
+	}
+	if !filter.To.IsZero() {
+		where = append(where, "placed_at < ?")
+		args = append(args, filter.To.UnixNano())
+	}
+	if filter.Open {
+		where = append(where, "status IN ('', ?)")
+		args = append(args, core.ContractStatusOpen)
+	}
+
+	query := "SELECT record FROM trades"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY placed_at, contract_id"
+
+	rows, err := j.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trades: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*core.TradeRecord
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read trade: %w", err)
+		}
+
+		record, err := decode(data)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query trades: %w", err)
+	}
+
+	return records, nil
+}
+
+// Usernames returns users having trades in the journal
+func (j *Journal) Usernames(ctx context.Context) ([]string, error) {
+	rows, err := j.db.QueryContext(ctx, "SELECT DISTINCT username FROM trades ORDER BY username")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list traders: %w", err)
+	}
+	defer rows.Close()
+
+	var usernames []string
+	for rows.Next() {
+		var username string
+		if err := rows.Scan(&username); err != nil {
+			return nil, fmt.Errorf("failed to read trader: %w", err)
+		}
+		usernames = append(usernames, username)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list traders: %w", err)
+	}
+
+	return usernames, nil
+}
+
+// decode parses a stored trade
+func decode(data string) (*core.TradeRecord, error) {
+	var record core.TradeRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, fmt.Errorf("failed to decode trade: %w", err)
+	}
+
+	return &record, nil
+}
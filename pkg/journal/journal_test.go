@@ -0,0 +1,131 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+)
+
+func TestJournalSurvivesReopen(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	cfg := &Config{Path: filepath.Join(t.TempDir(), "data", DefaultFile)}
+
+	j, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	for _, record := range []*core.TradeRecord{
+		{ContractID: 3, Username: "alice", Symbol: "R_50", Status: "won", Profit: 9.5, PlacedAt: day.Add(2 * time.Hour)},
+		{ContractID: 1, Username: "alice", Symbol: "R_50", Status: core.ContractStatusOpen, PlacedAt: day.Add(time.Hour)},
+		{ContractID: 2, Username: "alice", Symbol: "R_100", Status: "lost", PlacedAt: day.Add(time.Hour)},
+		{ContractID: 5, Username: "alice", Symbol: "R_50", Status: "lost", PlacedAt: day.AddDate(0, 0, 1)},
+		{ContractID: 4, Username: "bob", Symbol: "R_50", Status: "won", PlacedAt: day.Add(-time.Hour)},
+	} {
+		if err := j.Save(ctx, record); err != nil {
+			t.Fatalf("failed to save trade %d: %v", record.ContractID, err)
+		}
+	}
+
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	j, err = Open(cfg)
+	if err != nil {
+		t.Fatalf("failed to reopen the journal: %v", err)
+	}
+	defer j.Close()
+
+	records, err := j.Query(ctx, &core.JournalFilter{Username: "alice", Symbol: "R_50", From: day, To: day.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("failed to query the journal: %v", err)
+	}
+	if len(records) != 2 || records[0].ContractID != 1 || records[1].ContractID != 3 {
+		t.Errorf("R_50 trades of alice on %s = %+v, want #1 and #3 by placement time", day.Format(time.DateOnly), records)
+	}
+
+	record, err := j.Get(ctx, "alice", 3)
+	if err != nil {
+		t.Fatalf("failed to load trade #3: %v", err)
+	}
+	if record.Profit != 9.5 || !record.PlacedAt.Equal(day.Add(2*time.Hour)) {
+		t.Errorf("trade #3 = %+v, want it as saved", record)
+	}
+
+	if _, err := j.Get(ctx, "bob", 1); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("trade #1 of bob: err = %v, want core.ErrNotFound", err)
+	}
+}
+
+func TestJournalSaveReplacesTrade(t *testing.T) {
+	ctx := context.Background()
+	placed := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	j, err := Open(&Config{Path: filepath.Join(t.TempDir(), DefaultFile)})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer j.Close()
+
+	open := &core.TradeRecord{ContractID: 1, Username: "alice", Symbol: "R_50", Status: core.ContractStatusOpen, PlacedAt: placed}
+	if err := j.Save(ctx, open); err != nil {
+		t.Fatalf("failed to save the open trade: %v", err)
+	}
+	// The same contract of another user is a different trade
+	if err := j.Save(ctx, &core.TradeRecord{ContractID: 1, Username: "bob", Symbol: "R_50", Status: core.ContractStatusOpen, PlacedAt: placed}); err != nil {
+		t.Fatalf("failed to save the trade of bob: %v", err)
+	}
+
+	openTrades, err := j.Query(ctx, &core.JournalFilter{Open: true})
+	if err != nil {
+		t.Fatalf("failed to query open trades: %v", err)
+	}
+	if len(openTrades) != 2 {
+		t.Errorf("open trades = %+v, want both", openTrades)
+	}
+
+	settled := *open
+	settled.Status = "won"
+	settled.Profit = 9.5
+	settled.SettledAt = placed.Add(time.Minute)
+	if err := j.Save(ctx, &settled); err != nil {
+		t.Fatalf("failed to save the settled trade: %v", err)
+	}
+
+	records, err := j.Query(ctx, &core.JournalFilter{Username: "alice"})
+	if err != nil {
+		t.Fatalf("failed to query trades of alice: %v", err)
+	}
+	if len(records) != 1 || records[0].Status != "won" || records[0].Profit != 9.5 {
+		t.Errorf("trades of alice = %+v, want the settled trade replacing the open one", records)
+	}
+
+	openTrades, err = j.Query(ctx, &core.JournalFilter{Open: true})
+	if err != nil {
+		t.Fatalf("failed to query open trades: %v", err)
+	}
+	if len(openTrades) != 1 || openTrades[0].Username != "bob" {
+		t.Errorf("open trades = %+v, want the one of bob", openTrades)
+	}
+
+	usernames, err := j.Usernames(ctx)
+	if err != nil {
+		t.Fatalf("failed to list traders: %v", err)
+	}
+	if len(usernames) != 2 || usernames[0] != "alice" || usernames[1] != "bob" {
+		t.Errorf("usernames = %v, want alice and bob", usernames)
+	}
+
+	if err := j.Delete(ctx, "bob", 1); err != nil {
+		t.Fatalf("failed to delete the trade of bob: %v", err)
+	}
+	if usernames, _ := j.Usernames(ctx); len(usernames) != 1 || usernames[0] != "alice" {
+		t.Errorf("usernames after deleting the trade of bob = %v, want alice", usernames)
+	}
+}
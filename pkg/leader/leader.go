@@ -0,0 +1,130 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// leaseName is the name of the lease held by the leading instance
+const leaseName = "leader"
+
+// Config holds configuration of leader election between bot instances sharing a store
+type Config struct {
+	Enabled    bool          `mapstructure:"enabled"`     // Run as one of several instances, only the leader serves users
+	InstanceID string        `mapstructure:"instance_id"` // Unique name of the instance, hostname and PID by default
+	LeaseTTL   time.Duration `mapstructure:"lease_ttl"`   // Time after which a leader that stopped renewing is replaced
+}
+
+// Locker provides leases shared between instances
+type Locker interface {
+	AcquireLease(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, owner string) error
+}
+
+// Run campaigns for leadership and runs lead while the instance is the leader.
+// The context passed to lead is canceled when the lease can't be renewed, then the instance
+// campaigns again, so a follower takes over within the lease TTL when the leader dies.
+// Run returns when ctx is canceled.
+func Run(ctx context.Context, cfg *Config, locker Locker, lead func(ctx context.Context) error) error {
+	if cfg.LeaseTTL <= 0 {
+		return fmt.Errorf("leader lease TTL must be positive")
+	}
+
+	id := cfg.InstanceID
+	if id == "" {
+		id = defaultInstanceID()
+	}
+
+	// Renewing several times per TTL tolerates a slow store
+	interval := cfg.LeaseTTL / 3
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Instance %s is campaigning for leadership", id)
+
+	for {
+		acquired, err := locker.AcquireLease(ctx, leaseName, id, cfg.LeaseTTL)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to acquire leader lease: %v", err)
+		}
+
+		if acquired {
+			log.Printf("Instance %s became the leader", id)
+			term(ctx, id, cfg.LeaseTTL, interval, locker, lead)
+			log.Printf("Instance %s is no longer the leader", id)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// term runs lead until the lease is lost, lead fails or ctx is canceled, then gives up the lease
+func term(ctx context.Context, id string, ttl, interval time.Duration, locker Locker, lead func(ctx context.Context) error) {
+	leadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- lead(leadCtx)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for running := true; running; {
+		select {
+		case err := <-done:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("Leader failed: %v", err)
+			}
+			running = false
+			done = nil
+		case <-ctx.Done():
+			running = false
+		case <-ticker.C:
+			// The lease must not outlive the time lead may keep running, so renewal is bounded by the interval
+			renewCtx, cancelRenew := context.WithTimeout(leadCtx, interval)
+			renewed, err := locker.AcquireLease(renewCtx, leaseName, id, ttl)
+			cancelRenew()
+
+			if err != nil {
+				log.Printf("Failed to renew leader lease: %v", err)
+			}
+			if !renewed {
+				running = false
+			}
+		}
+	}
+
+	cancel()
+	if done != nil {
+		<-done
+	}
+
+	// Let a follower take over right away instead of waiting for the lease to expire
+	releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), interval)
+	defer cancelRelease()
+
+	if err := locker.ReleaseLease(releaseCtx, leaseName, id); err != nil {
+		log.Printf("Failed to release leader lease: %v", err)
+	}
+}
+
+// defaultInstanceID identifies the instance by host and process
+func defaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultWindow is the number of latest samples kept per metric
+const defaultWindow = 1000
+
+// Registry keeps recent samples of named metrics and counters in memory
+type Registry struct {
+	mu       sync.Mutex
+	window   int
+	samples  map[string]*series
+	counters map[string]int64
+	buckets  map[string][]float64 // Histogram upper bounds by metric family
+}
+
+// series is a ring buffer of the latest samples of a metric with totals since it was created
+type series struct {
+	values []float64
+	next   int
+	total  int64
+	sum    float64
+	counts []int64 // Samples up to each histogram bound, not cumulative
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		window:   defaultWindow,
+		samples:  make(map[string]*series),
+		counters: make(map[string]int64),
+		buckets:  make(map[string][]float64),
+	}
+}
+
+// Name returns the name of a metric of the family with labels given as key-value pairs,
+// e.g. Name("command_latency_ms", "command", "buy") is command_latency_ms{command="buy"}
+func Name(family string, labels ...string) string {
+	if len(labels) < 2 {
+		return family
+	}
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", labels[i], labels[i+1]))
+	}
+
+	return family + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// family returns the metric family of a name, the part before labels
+func family(name string) string {
+	if i := strings.IndexByte(name, '{'); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// SetBuckets makes metrics of the family histograms with the given upper bounds in ascending order.
+// It applies to samples observed afterwards.
+func (r *Registry) SetBuckets(family string, bounds ...float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.buckets[family] = append([]float64(nil), bounds...)
+}
+
+// Inc increments the counter
+func (r *Registry) Inc(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counters[name]++
+}
+
+// Counter returns the value of the counter
+func (r *Registry) Counter(name string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.counters[name]
+}
+
+// Observe records a sample of the metric
+func (r *Registry) Observe(name string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.samples[name]
+	if !ok {
+		s = &series{values: make([]float64, 0, r.window)}
+		if bounds, ok := r.buckets[family(name)]; ok {
+			s.counts = make([]int64, len(bounds))
+		}
+		r.samples[name] = s
+	}
+
+	if bounds := r.buckets[family(name)]; len(s.counts) == len(bounds) {
+		if i := sort.SearchFloat64s(bounds, value); i < len(bounds) {
+			s.counts[i]++
+		}
+	}
+
+	if len(s.values) < r.window {
+		s.values = append(s.values, value)
+	} else {
+		s.values[s.next] = value
+	}
+	s.next = (s.next + 1) % r.window
+	s.total++
+	s.sum += value
+}
+
+// Count returns the total number of samples recorded for the metric
+func (r *Registry) Count(name string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.samples[name]; ok {
+		return s.total
+	}
+	return 0
+}
+
+// Percentiles returns the given percentiles (0-100) of recent samples of the metric,
+// it returns nil when there are no samples
+func (r *Registry) Percentiles(name string, ps ...float64) []float64 {
+	r.mu.Lock()
+	s, ok := r.samples[name]
+	var values []float64
+	if ok {
+		values = append(values, s.values...)
+	}
+	r.mu.Unlock()
+
+	return Percentiles(values, ps...)
+}
+
+// Names returns names of all recorded metrics in sorted order
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.samples))
+	for name := range r.samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Percentiles computes percentiles (0-100) of the values with the nearest-rank method,
+// it returns nil for empty values
+func Percentiles(values []float64, ps ...float64) []float64 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	result := make([]float64, len(ps))
+	for i, p := range ps {
+		rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+		rank = min(max(rank, 1), len(sorted))
+		result[i] = sorted[rank-1]
+	}
+
+	return result
+}
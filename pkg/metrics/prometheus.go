@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// summaryQuantiles are the quantiles exported for metrics without histogram buckets
+var summaryQuantiles = []float64{0.5, 0.9, 0.99}
+
+// WritePrometheus writes all metrics in the Prometheus text format.
+// Metrics with buckets are histograms, other samples are summaries over recent samples and counters are counters.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	typed := make(map[string]bool)
+	writeType := func(name, kind string) {
+		if f := family(name); !typed[f] {
+			typed[f] = true
+			fmt.Fprintf(bw, "# TYPE %s %s\n", f, kind)
+		}
+	}
+
+	for _, name := range sortedKeys(r.counters) {
+		writeType(name, "counter")
+		fmt.Fprintf(bw, "%s %d\n", name, r.counters[name])
+	}
+
+	for _, name := range sortedKeys(r.samples) {
+		s := r.samples[name]
+		f, labels := family(name), labelsOf(name)
+
+		if bounds := r.buckets[f]; len(s.counts) == len(bounds) && len(bounds) > 0 {
+			writeType(name, "histogram")
+
+			var cumulative int64
+			for i, bound := range bounds {
+				cumulative += s.counts[i]
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", f, withLabel(labels, "le", formatFloat(bound)), cumulative)
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", f, withLabel(labels, "le", "+Inf"), s.total)
+		} else {
+			writeType(name, "summary")
+
+			values := Percentiles(s.values, 50, 90, 99)
+			for i, q := range summaryQuantiles {
+				if values != nil {
+					fmt.Fprintf(bw, "%s%s %s\n", f, withLabel(labels, "quantile", formatFloat(q)), formatFloat(values[i]))
+				}
+			}
+		}
+
+		fmt.Fprintf(bw, "%s_sum%s %s\n", f, labels, formatFloat(s.sum))
+		fmt.Fprintf(bw, "%s_count%s %d\n", f, labels, s.total)
+	}
+
+	return bw.Flush()
+}
+
+// labelsOf returns the labels part of a name including braces, empty when it has none
+func labelsOf(name string) string {
+	return strings.TrimPrefix(name, family(name))
+}
+
+// withLabel adds a label to the labels part of a name
+func withLabel(labels, key, value string) string {
+	label := fmt.Sprintf("%s=%q", key, value)
+	if labels == "" {
+		return "{" + label + "}"
+	}
+	return strings.TrimSuffix(labels, "}") + "," + label + "}"
+}
+
+// formatFloat formats a sample value the way Prometheus parses it
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns keys of the map in sorted order, so the output is stable between scrapes
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,278 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+)
+
+// Config holds configuration of a single external plugin
+type Config struct {
+	Name        string            `mapstructure:"name"`
+	Command     string            `mapstructure:"command"`     // Executable to start
+	Args        []string          `mapstructure:"args"`        // Arguments passed to the executable
+	Env         map[string]string `mapstructure:"env"`         // Extra environment variables
+	Commands    []string          `mapstructure:"commands"`    // Chat commands handled by the plugin
+	Strategies  []string          `mapstructure:"strategies"`  // Strategies of /strategy whose signals the plugin gives
+	Description string            `mapstructure:"description"` // Shown in /help next to plugin commands and in /strategy list
+}
+
+// Request is a message sent to the plugin process, one JSON object per line. Requests of commands carry
+// the command, requests of strategy signals carry the strategy with its symbol, prices and parameters.
+type Request struct {
+	ID           int64              `json:"id"`
+	Command      string             `json:"command,omitempty"`
+	Args         []string           `json:"args,omitempty"`
+	ChatID       int64              `json:"chat_id,omitempty"`
+	Username     string             `json:"username,omitempty"`
+	CallbackData string             `json:"callback_data,omitempty"`
+	Strategy     string             `json:"strategy,omitempty"`
+	Symbol       string             `json:"symbol,omitempty"`
+	Prices       []float64          `json:"prices,omitempty"`
+	Params       map[string]float64 `json:"params,omitempty"`
+}
+
+// Button is a keyboard button returned by the plugin
+type Button struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// Reply is a message received from the plugin process, one JSON object per line
+type Reply struct {
+	ID        int64      `json:"id"`
+	Text      string     `json:"text"`
+	Buttons   [][]Button `json:"buttons,omitempty"`
+	PhotoPath string     `json:"photo_path,omitempty"`
+	Signal    string     `json:"signal,omitempty"` // CALL, PUT or empty when a strategy has no trade
+	Error     string     `json:"error,omitempty"`
+}
+
+// Plugin is an external process handling chat commands via JSON over stdio
+type Plugin struct {
+	cfg   *Config
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan *Reply
+	done    chan struct{}
+}
+
+// Start launches the plugin process
+func Start(ctx context.Context, cfg *Config) (*Plugin, error) {
+	if cfg.Command == "" {
+		return nil, fmt.Errorf("plugin %s: command is required", cfg.Name)
+	}
+
+	cmd := exec.CommandContext(ctx, cfg.Command, cfg.Args...)
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	for key, value := range cfg.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: failed to open stdin: %w", cfg.Name, err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: failed to open stdout: %w", cfg.Name, err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("plugin %s: failed to start: %w", cfg.Name, err)
+	}
+
+	p := &Plugin{
+		cfg:     cfg,
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[int64]chan *Reply),
+		done:    make(chan struct{}),
+	}
+
+	go p.readReplies(stdout)
+
+	return p, nil
+}
+
+// Name returns the plugin name
+func (p *Plugin) Name() string {
+	return p.cfg.Name
+}
+
+// Commands returns chat commands handled by the plugin
+func (p *Plugin) Commands() []string {
+	return p.cfg.Commands
+}
+
+// Description returns the plugin description
+func (p *Plugin) Description() string {
+	return p.cfg.Description
+}
+
+// Strategies returns strategies whose signals the plugin gives
+func (p *Plugin) Strategies() []string {
+	return p.cfg.Strategies
+}
+
+// Handle implements core.CommandHandler by forwarding the message to the plugin process
+func (p *Plugin) Handle(ctx context.Context, msg *core.Message) (*core.Response, error) {
+	reply, err := p.call(ctx, &Request{
+		Command:      msg.Command,
+		Args:         msg.Args,
+		ChatID:       msg.ChatID,
+		Username:     msg.Username,
+		CallbackData: msg.CallbackData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buttons [][]core.Button
+	for _, row := range reply.Buttons {
+		var line []core.Button
+		for _, btn := range row {
+			line = append(line, core.Button{Text: btn.Text, CallbackData: btn.CallbackData})
+		}
+		buttons = append(buttons, line)
+	}
+
+	return core.NewResponse(msg).Text(reply.Text).Keyboard(buttons).Photo(reply.PhotoPath).Build(), nil
+}
+
+// Signal returns the signal of a strategy of the plugin, it has the signature of core.StrategySignal
+// once bound to the strategy name
+func (p *Plugin) Signal(ctx context.Context, strategy, symbol string, prices []float64, params map[string]float64) (string, error) {
+	reply, err := p.call(ctx, &Request{
+		Strategy: strategy,
+		Symbol:   symbol,
+		Prices:   prices,
+		Params:   params,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch reply.Signal {
+	case "", "CALL", "PUT":
+		return reply.Signal, nil
+	default:
+		return "", fmt.Errorf("plugin %s: invalid signal %q of strategy %s", p.cfg.Name, reply.Signal, strategy)
+	}
+}
+
+// call sends the request with a new ID to the plugin process and waits for its reply
+func (p *Plugin) call(ctx context.Context, req *Request) (*Reply, error) {
+	replyCh := make(chan *Reply, 1)
+
+	p.mu.Lock()
+	p.nextID++
+	req.ID = p.nextID
+	p.pending[req.ID] = replyCh
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		delete(p.pending, req.ID)
+		p.mu.Unlock()
+	}()
+
+	if err := p.send(req); err != nil {
+		return nil, err
+	}
+
+	var reply *Reply
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.done:
+		return nil, fmt.Errorf("plugin %s exited", p.cfg.Name)
+	case reply = <-replyCh:
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("plugin %s: %s", p.cfg.Name, reply.Error)
+	}
+
+	return reply, nil
+}
+
+// Close stops the plugin process
+func (p *Plugin) Close() error {
+	if err := p.stdin.Close(); err != nil {
+		return fmt.Errorf("plugin %s: failed to close stdin: %w", p.cfg.Name, err)
+	}
+
+	if err := p.cmd.Wait(); err != nil {
+		return fmt.Errorf("plugin %s: %w", p.cfg.Name, err)
+	}
+
+	return nil
+}
+
+// send writes a request to the plugin stdin
+func (p *Plugin) send(req *Request) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("plugin %s: failed to encode request: %w", p.cfg.Name, err)
+	}
+
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if _, err := p.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("plugin %s: failed to send request: %w", p.cfg.Name, err)
+	}
+
+	return nil
+}
+
+// readReplies reads replies from the plugin stdout and dispatches them to waiting handlers
+func (p *Plugin) readReplies(stdout io.Reader) {
+	defer close(p.done)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		var reply Reply
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil {
+			log.Printf("Plugin %s sent invalid reply: %v", p.cfg.Name, err)
+			continue
+		}
+
+		p.mu.Lock()
+		replyCh, ok := p.pending[reply.ID]
+		p.mu.Unlock()
+
+		if !ok {
+			log.Printf("Plugin %s sent a reply to unknown or timed out request %d, dropped", p.cfg.Name, reply.ID)
+			continue
+		}
+
+		// A duplicate reply finds the channel full, it never blocks replies to other requests
+		select {
+		case replyCh <- &reply:
+		default:
+			log.Printf("Plugin %s sent a duplicate reply to request %d, dropped", p.cfg.Name, reply.ID)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Printf("Plugin %s output closed: %v", p.cfg.Name, err)
+	}
+}
@@ -0,0 +1,69 @@
+package deriv
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"time"
+)
+
+// chaosErrorCode is the transient error replayed requests are failed with, it's retried like a real outage
+const chaosErrorCode = "ServiceUnavailable"
+
+// ChaosConfig makes the replay proxy misbehave, so retries, reconnects and the watchdog can be exercised locally
+type ChaosConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	MaxLatency     time.Duration `mapstructure:"max_latency"`     // Random delay before each response, up to this
+	ErrorRate      float64       `mapstructure:"error_rate"`      // Share of requests answered with a transient API error, 0-1
+	DisconnectRate float64       `mapstructure:"disconnect_rate"` // Share of requests dropping the connection instead, 0-1
+}
+
+// chaosOutcome is what happens to a replayed request
+type chaosOutcome int
+
+const (
+	chaosNone chaosOutcome = iota
+	chaosError
+	chaosDisconnect
+)
+
+// inject delays the response by a random latency and picks the outcome of the request.
+// It reports false when the context is canceled while waiting.
+func (c *ChaosConfig) inject(ctx context.Context) (chaosOutcome, bool) {
+	if !c.Enabled {
+		return chaosNone, true
+	}
+
+	if c.MaxLatency > 0 {
+		select {
+		case <-ctx.Done():
+			return chaosNone, false
+		case <-time.After(rand.N(c.MaxLatency)):
+		}
+	}
+
+	switch roll := rand.Float64(); {
+	case roll < c.DisconnectRate:
+		return chaosDisconnect, true
+	case roll < c.DisconnectRate+c.ErrorRate:
+		return chaosError, true
+	default:
+		return chaosNone, true
+	}
+}
+
+// chaosErrorResponse builds a transient API error response to the request
+func chaosErrorResponse(data []byte) json.RawMessage {
+	var req map[string]any
+	_ = json.Unmarshal(data, &req)
+
+	resp, _ := json.Marshal(map[string]any{
+		"echo_req": req,
+		"error": map[string]any{
+			"code":    chaosErrorCode,
+			"message": "Injected by chaos mode",
+		},
+	})
+
+	return resp
+}
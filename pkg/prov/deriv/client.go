@@ -3,6 +3,7 @@ package deriv
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
 	"time"
 
@@ -11,17 +12,49 @@ import (
 	"github.com/ksysoev/deriv-api/schema"
 )
 
+// defaultCurrency is used for proposals and limits until the account currency is known
+const defaultCurrency = "USD"
+
 // Config holds Deriv-specific configuration
 type Config struct {
 	AppID    string   `mapstructure:"app_id"`
 	APIToken string   `mapstructure:"api_token"`
 	Endpoint string   `mapstructure:"endpoint"`
+	Preset   string   `mapstructure:"preset"` // Connection preset, "demo" for demo accounts only
 	Symbols  []string `mapstructure:"symbols"`
+
+	// Currency of proposals, detected from the account on authorize when empty
+	Currency string `mapstructure:"currency"`
+
+	// Stake bounds by contract category, overriding Deriv defaults
+	StakeLimits map[string]StakeLimit `mapstructure:"stake_limits"`
+
+	// Record/replay of API traffic
+	TrafficMode string `mapstructure:"traffic_mode"` // "record", "replay" or empty for direct connection
+	Fixtures    string `mapstructure:"fixtures"`     // Path to the fixtures file used by record/replay modes
+
+	// Chaos injects latency, errors and disconnects into replayed traffic for local resilience testing
+	Chaos ChaosConfig `mapstructure:"chaos"`
+
+	Retry RetryConfig `mapstructure:"retry"`
 }
 
 type Client struct {
-	api *deriv.Client
-	cfg *Config
+	api      *deriv.Client
+	cfg      *Config
+	recorder *recorder
+	health   core.HealthObserver
+	currency string // Account currency reported on authorize
+	account  AccountInfo
+}
+
+// AccountInfo describes the account a token was authorized for
+type AccountInfo struct {
+	LoginID  string
+	Virtual  bool // Demo account trading with virtual money
+	Currency string
+	Scopes   []string       // Permissions granted to the token
+	List     []core.Account // All accounts of the user, from account_list
 }
 
 // NewClient creates a new Deriv API client
@@ -31,23 +64,47 @@ func NewClient(cfg *Config) (*Client, error) {
 		return nil, fmt.Errorf("invalid app ID: %w", err)
 	}
 
+	endpoint := cfg.Endpoint
+
+	var rec *recorder
+	if cfg.TrafficMode != "" {
+		rec, err = newRecorder(cfg.TrafficMode, cfg.Fixtures, cfg.Endpoint, cfg.Chaos)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create recording proxy: %w", err)
+		}
+
+		endpoint, err = rec.Start()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	api, err := deriv.NewDerivAPI(
-		cfg.Endpoint,
+		endpoint,
 		appID,
 		"en",
 		"https://deriv-teletrader",
 		deriv.Debug,
 	)
 	if err != nil {
+		if rec != nil {
+			rec.Close()
+		}
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
 	return &Client{
-		api: api,
-		cfg: cfg,
+		api:      api,
+		cfg:      cfg,
+		recorder: rec,
 	}, nil
 }
 
+// SetHealthObserver makes the client report outcomes of API calls to the observer
+func (c *Client) SetHealthObserver(health core.HealthObserver) {
+	c.health = health
+}
+
 // Connect establishes connection to Deriv API and authorizes the session
 func (c *Client) Connect(ctx context.Context) error {
 	if err := c.api.Connect(); err != nil {
@@ -56,17 +113,94 @@ func (c *Client) Connect(ctx context.Context) error {
 
 	// Authorize the connection
 	reqAuth := schema.Authorize{Authorize: c.cfg.APIToken}
-	if _, err := c.api.Authorize(ctx, reqAuth); err != nil {
+	resp, err := c.api.Authorize(ctx, reqAuth)
+	if err != nil {
+		c.api.Disconnect()
+		return fmt.Errorf("failed to authorize: %w", mapError(err))
+	}
+
+	c.account = accountInfo(resp.Authorize)
+	c.currency = c.account.Currency
+
+	if c.cfg.Preset == PresetDemo && !c.account.Virtual {
 		c.api.Disconnect()
-		return fmt.Errorf("failed to authorize: %w", err)
+		return fmt.Errorf("the demo preset accepts demo accounts only, %s is a real account", c.account.LoginID)
 	}
 
 	return nil
 }
 
+// Account returns details of the account authorized on Connect
+func (c *Client) Account() AccountInfo {
+	return c.account
+}
+
+// accountInfo extracts account details from the authorize response
+func accountInfo(auth *schema.AuthorizeRespAuthorize) AccountInfo {
+	var info AccountInfo
+	if auth == nil {
+		return info
+	}
+
+	if auth.Loginid != nil {
+		info.LoginID = *auth.Loginid
+	}
+	if auth.IsVirtual != nil {
+		info.Virtual = *auth.IsVirtual == 1
+	}
+	if auth.Currency != nil {
+		info.Currency = *auth.Currency
+	}
+	info.Scopes = auth.Scopes
+
+	for _, elem := range auth.AccountList {
+		var account core.Account
+		if elem.Loginid != nil {
+			account.LoginID = *elem.Loginid
+		}
+		if elem.IsVirtual != nil {
+			account.Virtual = *elem.IsVirtual == 1
+		}
+		if elem.Currency != nil {
+			account.Currency = *elem.Currency
+		}
+		info.List = append(info.List, account)
+	}
+
+	return info
+}
+
+// ActiveAccount returns the account the token is authorized for
+func (c *Client) ActiveAccount() core.Account {
+	return core.Account{LoginID: c.account.LoginID, Virtual: c.account.Virtual, Currency: c.account.Currency}
+}
+
+// AccountList returns all accounts of the user reported on authorize, including ones the token can't trade on
+func (c *Client) AccountList() []core.Account {
+	return c.account.List
+}
+
+// Currency returns the currency stakes and payouts are quoted in, the configured one takes precedence
+// over the account currency, accounts without a currency set fall back to USD
+func (c *Client) Currency() string {
+	switch {
+	case c.cfg.Currency != "":
+		return c.cfg.Currency
+	case c.currency != "":
+		return c.currency
+	default:
+		return defaultCurrency
+	}
+}
+
 // Close closes the connection
 func (c *Client) Close() error {
 	c.api.Disconnect()
+
+	if c.recorder != nil {
+		return c.recorder.Close()
+	}
+
 	return nil
 }
 
@@ -79,9 +213,11 @@ func (c *Client) GetAvailableSymbols(ctx context.Context) ([]string, error) {
 func (c *Client) GetBalance(ctx context.Context) (*core.BalanceInfo, error) {
 	req := schema.Balance{Balance: 1}
 
-	resp, err := c.api.Balance(ctx, req)
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.BalanceResp, error) {
+		return c.api.Balance(ctx, req)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get balance: %w", err)
+		return nil, fmt.Errorf("failed to get balance: %w", mapError(err))
 	}
 
 	return &core.BalanceInfo{
@@ -90,15 +226,156 @@ func (c *Client) GetBalance(ctx context.Context) (*core.BalanceInfo, error) {
 	}, nil
 }
 
+// GetStatement returns transactions of the account newest first, skipping offset of them
+func (c *Client) GetStatement(ctx context.Context, limit, offset int) ([]core.Transaction, error) {
+	req := schema.Statement{
+		Statement: 1,
+		Limit:     float64(limit),
+		Offset:    &offset,
+	}
+
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.StatementResp, error) {
+		return c.api.Statement(ctx, req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement: %w", mapError(err))
+	}
+
+	if resp.Statement == nil {
+		return nil, nil
+	}
+
+	transactions := make([]core.Transaction, 0, len(resp.Statement.Transactions))
+	for _, t := range resp.Statement.Transactions {
+		var tx core.Transaction
+		if t.TransactionId != nil {
+			tx.ID = int64(*t.TransactionId)
+		}
+		if t.TransactionTime != nil {
+			tx.Time = time.Unix(int64(*t.TransactionTime), 0)
+		}
+		if t.ActionType != nil {
+			tx.Action = string(*t.ActionType)
+		}
+		if t.Amount != nil {
+			tx.Amount = *t.Amount
+		}
+		if t.BalanceAfter != nil {
+			tx.BalanceAfter = *t.BalanceAfter
+		}
+		if t.ContractId != nil {
+			tx.ContractID = int64(*t.ContractId)
+		}
+		if t.Longcode != nil {
+			tx.Longcode = *t.Longcode
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, nil
+}
+
+// profitTablePage is the largest number of contracts the profit table returns at once
+const profitTablePage = 500
+
+// GetProfitTable returns contracts bought between from and to that are already closed, newest first
+func (c *Client) GetProfitTable(ctx context.Context, from, to time.Time) ([]core.ClosedContract, error) {
+	dateFrom := strconv.FormatInt(from.Unix(), 10)
+	dateTo := strconv.FormatInt(to.Unix(), 10)
+
+	var contracts []core.ClosedContract
+	for {
+		offset := len(contracts)
+		req := schema.ProfitTable{
+			ProfitTable: 1,
+			DateFrom:    &dateFrom,
+			DateTo:      &dateTo,
+			Limit:       profitTablePage,
+			Offset:      &offset,
+			Sort:        schema.ProfitTableSortDESC,
+		}
+
+		resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.ProfitTableResp, error) {
+			return c.api.ProfitTable(ctx, req)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get profit table: %w", mapError(err))
+		}
+
+		if resp.ProfitTable == nil {
+			return contracts, nil
+		}
+
+		for _, t := range resp.ProfitTable.Transactions {
+			var contract core.ClosedContract
+			if t.ContractId != nil {
+				contract.ContractID = int64(*t.ContractId)
+			}
+			if t.UnderlyingSymbol != nil {
+				contract.Symbol = *t.UnderlyingSymbol
+			}
+			if t.ContractType != nil {
+				contract.ContractType = *t.ContractType
+			}
+			if t.BuyPrice != nil {
+				contract.BuyPrice = *t.BuyPrice
+			}
+			if t.SellPrice != nil {
+				contract.SellPrice = *t.SellPrice
+			}
+			if t.PurchaseTime != nil {
+				contract.PurchaseTime = time.Unix(int64(*t.PurchaseTime), 0)
+			}
+			if t.SellTime != nil {
+				contract.SellTime = time.Unix(int64(*t.SellTime), 0)
+			}
+			contracts = append(contracts, contract)
+		}
+
+		if len(resp.ProfitTable.Transactions) < profitTablePage {
+			return contracts, nil
+		}
+	}
+}
+
+// GetExchangeRate returns the rate for converting amounts from one currency to another
+func (c *Client) GetExchangeRate(ctx context.Context, from, to string) (float64, error) {
+	req := schema.ExchangeRates{
+		ExchangeRates:  1,
+		BaseCurrency:   from,
+		TargetCurrency: &to,
+	}
+
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.ExchangeRatesResp, error) {
+		return c.api.ExchangeRates(ctx, req)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get exchange rates: %w", mapError(err))
+	}
+
+	if resp.ExchangeRates == nil {
+		return 0, fmt.Errorf("no exchange rates available for %s", from)
+	}
+
+	rate, ok := resp.ExchangeRates.Rates[to].(float64)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate available for %s/%s", from, to)
+	}
+
+	return rate, nil
+}
+
 // GetPrice retrieves current price for a symbol
 func (c *Client) GetPrice(ctx context.Context, symbol string) (float64, error) {
 	req := schema.Ticks{
 		Ticks: symbol,
 	}
 
-	resp, err := c.api.Ticks(ctx, req)
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.TicksResp, error) {
+		return c.api.Ticks(ctx, req)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to get price: %w", err)
+		return 0, fmt.Errorf("failed to get price: %w", mapError(err))
 	}
 
 	if resp.Tick.Quote == nil {
@@ -107,18 +384,28 @@ func (c *Client) GetPrice(ctx context.Context, symbol string) (float64, error) {
 	return *resp.Tick.Quote, nil
 }
 
-// PlaceTrade places a trade order
-func (c *Client) PlaceTrade(ctx context.Context, symbol string, amount float64, direction string) error {
-	// Create a proposal
-	duration := 5
+// proposalContractTypes maps contract types of trade requests to Deriv proposal contract types
+var proposalContractTypes = map[string]schema.ProposalContractType{
+	"CALL":       schema.ProposalContractTypeCALL,
+	"PUT":        schema.ProposalContractTypePUT,
+	"ONETOUCH":   schema.ProposalContractTypeONETOUCH,
+	"NOTOUCH":    schema.ProposalContractTypeNOTOUCH,
+	"DIGITMATCH": schema.ProposalContractTypeDIGITMATCH,
+	"DIGITDIFF":  schema.ProposalContractTypeDIGITDIFF,
+	"DIGITOVER":  schema.ProposalContractTypeDIGITOVER,
+	"DIGITUNDER": schema.ProposalContractTypeDIGITUNDER,
+	"MULTUP":     schema.ProposalContractTypeMULTUP,
+	"MULTDOWN":   schema.ProposalContractTypeMULTDOWN,
+}
+
+// proposal requests a quote for the trade
+func (c *Client) proposal(ctx context.Context, trade *core.TradeRequest) (*schema.ProposalRespProposal, error) {
+	amount := trade.Amount
 	basis := schema.ProposalBasisStake
 
-	// Convert direction string to ProposalContractType
-	var contractType schema.ProposalContractType
-	if direction == "CALL" {
-		contractType = schema.ProposalContractTypeCALL
-	} else {
-		contractType = schema.ProposalContractTypePUT
+	contractType, ok := proposalContractTypes[trade.ContractType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported contract type %q", trade.ContractType)
 	}
 
 	req := schema.Proposal{
@@ -126,29 +413,148 @@ func (c *Client) PlaceTrade(ctx context.Context, symbol string, amount float64,
 		Amount:       &amount,
 		Basis:        &basis,
 		ContractType: contractType,
-		Currency:     "USD",
-		Duration:     &duration,
-		DurationUnit: "t",
-		Symbol:       symbol,
+		Currency:     c.Currency(),
+		Symbol:       trade.Symbol,
+	}
+
+	if trade.IsMultiplier() {
+		multiplier := float64(trade.Multiplier)
+		req.Multiplier = &multiplier
+
+		if limits := trade.Limits; limits.IsSet() {
+			req.LimitOrder = &schema.ProposalLimitOrder{}
+			if limits.StopLoss > 0 {
+				req.LimitOrder.StopLoss = &limits.StopLoss
+			}
+			if limits.TakeProfit > 0 {
+				req.LimitOrder.TakeProfit = &limits.TakeProfit
+			}
+		}
+	} else {
+		duration := trade.Duration.Value
+		req.Duration = &duration
+		req.DurationUnit = schema.ProposalDurationUnit(trade.Duration.Unit)
+	}
+
+	if trade.Barrier != "" {
+		barrier := trade.Barrier
+		req.Barrier = &barrier
 	}
 
-	resp, err := c.api.Proposal(ctx, req)
+	if !trade.StartAt.IsZero() {
+		dateStart := int(trade.StartAt.Unix())
+		req.DateStart = &dateStart
+	}
+
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.ProposalResp, error) {
+		return c.api.Proposal(ctx, req)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create proposal: %w", err)
+		return nil, fmt.Errorf("failed to create proposal: %w", mapError(err))
+	}
+
+	if resp.Proposal == nil {
+		return nil, fmt.Errorf("empty proposal response")
+	}
+
+	return resp.Proposal, nil
+}
+
+// GetQuote returns the price and payout the trade would be bought at now, without buying it
+func (c *Client) GetQuote(ctx context.Context, trade *core.TradeRequest) (*core.Quote, error) {
+	quotedAt := time.Now()
+
+	proposal, err := c.proposal(ctx, trade)
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.Quote{
+		ID:       proposal.Id,
+		AskPrice: proposal.AskPrice,
+		Payout:   proposal.Payout,
+		Spot:     proposal.Spot,
+		Longcode: proposal.Longcode,
+		Currency: c.Currency(),
+		QuotedAt: quotedAt,
+	}, nil
+}
+
+// PlaceTrade places a trade order and returns the purchased contract, a quote of the request is bought as is
+func (c *Client) PlaceTrade(ctx context.Context, trade *core.TradeRequest) (*core.TradeResult, error) {
+	// Latency is measured from the proposal request to the confirmed purchase
+	quote := trade.Quote
+	if quote == nil || quote.ID == "" {
+		quotedAt := time.Now()
+
+		proposal, err := c.proposal(ctx, trade)
+		if err != nil {
+			return nil, err
+		}
+
+		quote = &core.Quote{ID: proposal.Id, Spot: proposal.Spot, QuotedAt: quotedAt}
 	}
 
 	// Buy the contract
 	buyReq := schema.Buy{
-		Buy:   resp.Proposal.Id,
-		Price: amount,
+		Buy:   quote.ID,
+		Price: trade.Amount,
 	}
 
-	_, err = c.api.Buy(ctx, buyReq)
+	buyResp, err := withRetry(ctx, c.cfg.Retry, c.health, tradingCall, func() (schema.BuyResp, error) {
+		return c.api.Buy(ctx, buyReq)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to buy contract: %w", err)
+		return nil, fmt.Errorf("failed to buy contract: %w", mapError(err))
 	}
 
-	return nil
+	if buyResp.Buy == nil {
+		return nil, fmt.Errorf("empty buy response")
+	}
+
+	return &core.TradeResult{
+		ContractID:   int64(buyResp.Buy.ContractId),
+		BuyPrice:     buyResp.Buy.BuyPrice,
+		Payout:       buyResp.Buy.Payout,
+		Longcode:     buyResp.Buy.Longcode,
+		PurchaseTime: time.Unix(int64(buyResp.Buy.PurchaseTime), 0),
+		ProposalSpot: quote.Spot,
+		Latency:      time.Since(quote.QuotedAt),
+		Currency:     c.Currency(),
+		Account:      c.ActiveAccount(),
+	}, nil
+}
+
+// SellContract sells an open contract back at the market price
+func (c *Client) SellContract(ctx context.Context, contractID int64) (*core.SellResult, error) {
+	req := schema.Sell{
+		Sell:  int(contractID),
+		Price: 0, // Sell at market
+	}
+
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, tradingCall, func() (schema.SellResp, error) {
+		return c.api.Sell(ctx, req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sell contract %d: %w", contractID, mapError(err))
+	}
+
+	if resp.Sell == nil {
+		return nil, fmt.Errorf("empty sell response")
+	}
+
+	result := &core.SellResult{ContractID: contractID}
+	if resp.Sell.SoldFor != nil {
+		result.SoldFor = *resp.Sell.SoldFor
+	}
+	if resp.Sell.BalanceAfter != nil {
+		result.BalanceAfter = *resp.Sell.BalanceAfter
+	}
+	if resp.Sell.TransactionId != nil {
+		result.TransactionID = int64(*resp.Sell.TransactionId)
+	}
+
+	return result, nil
 }
 
 // convertDataStyle converts core.DataStyle to schema.TicksHistoryStyle
@@ -181,6 +587,9 @@ func (c *Client) GetHistoricalData(ctx context.Context, req core.HistoricalDataR
 	}
 
 	granularity := schema.TicksHistoryGranularity(60) // 1 minute candles by default
+	if req.Granularity > 0 {
+		granularity = schema.TicksHistoryGranularity(req.Granularity)
+	}
 	style := convertDataStyle(req.Style)
 
 	// Prepare the tick history request
@@ -193,9 +602,11 @@ func (c *Client) GetHistoricalData(ctx context.Context, req core.HistoricalDataR
 		Granularity:  &granularity,
 	}
 
-	resp, err := c.api.TicksHistory(ctx, historyReq)
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.TicksHistoryResp, error) {
+		return c.api.TicksHistory(ctx, historyReq)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get historical data: %w", err)
+		return nil, fmt.Errorf("failed to get historical data: %w", mapError(err))
 	}
 
 	var result []core.HistoricalDataPoint
@@ -236,28 +647,295 @@ func (c *Client) GetHistoricalData(ctx context.Context, req core.HistoricalDataR
 	return result, nil
 }
 
-// GetPosition retrieves current trading position
-func (c *Client) GetPosition(ctx context.Context) (string, error) {
+// GetOpenPositions lists open contracts of the account, including ones bought outside the bot,
+// valued at their current sell price
+func (c *Client) GetOpenPositions(ctx context.Context) ([]core.PositionInfo, error) {
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.PortfolioResp, error) {
+		return c.api.Portfolio(ctx, schema.Portfolio{Portfolio: 1})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", mapError(err))
+	}
+
+	if resp.Portfolio == nil {
+		return nil, nil
+	}
+
+	positions := make([]core.PositionInfo, 0, len(resp.Portfolio.Contracts))
+	for _, contract := range resp.Portfolio.Contracts {
+		var position core.PositionInfo
+		if contract.ContractId != nil {
+			position.ContractID = int64(*contract.ContractId)
+		}
+		if contract.Symbol != nil {
+			position.Symbol = *contract.Symbol
+		}
+		if contract.ContractType != nil {
+			position.ContractType = *contract.ContractType
+		}
+		if contract.Currency != nil {
+			position.Currency = *contract.Currency
+		}
+		if contract.BuyPrice != nil {
+			position.Stake = *contract.BuyPrice
+		}
+		if contract.Payout != nil {
+			position.Payout = *contract.Payout
+		}
+		if contract.ExpiryTime != nil {
+			position.ExpiryTime = time.Unix(int64(*contract.ExpiryTime), 0)
+		}
+
+		// The portfolio doesn't value contracts, a position without a valuation is still worth listing
+		if info, err := c.GetContract(ctx, position.ContractID); err != nil {
+			log.Printf("Failed to value contract %d: %v", position.ContractID, err)
+		} else {
+			position.Profit = info.Profit
+			position.IsValidToSell = info.IsValidToSell
+			position.Valued = true
+		}
+
+		positions = append(positions, position)
+	}
+
+	return positions, nil
+}
+
+// GetContract retrieves the current state of a contract
+func (c *Client) GetContract(ctx context.Context, contractID int64) (*core.ContractInfo, error) {
+	id := int(contractID)
 	req := schema.ProposalOpenContract{
 		ProposalOpenContract: 1,
+		ContractId:           &id,
 	}
 
-	resp, err := c.api.ProposalOpenContract(ctx, req)
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.ProposalOpenContractResp, error) {
+		return c.api.ProposalOpenContract(ctx, req)
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to get position: %w", err)
+		return nil, fmt.Errorf("failed to get contract %d: %w", contractID, mapError(err))
 	}
 
 	if resp.ProposalOpenContract == nil {
-		return "No open positions", nil
+		return nil, fmt.Errorf("contract %d not found", contractID)
 	}
 
-	var result string
-	result += fmt.Sprintf("Contract ID: %d\nType: %s\nEntry Spot: %.2f\nCurrent Spot: %.2f\nProfit: %.2f\n\n",
-		*resp.ProposalOpenContract.ContractId,
-		*resp.ProposalOpenContract.ContractType,
-		*resp.ProposalOpenContract.EntrySpot,
-		*resp.ProposalOpenContract.CurrentSpot,
-		*resp.ProposalOpenContract.Profit)
+	return contractInfo(contractID, resp.ProposalOpenContract), nil
+}
 
-	return result, nil
+// WatchContract streams updates of an open contract until it's settled or the context is canceled
+func (c *Client) WatchContract(ctx context.Context, contractID int64) (<-chan *core.ContractInfo, error) {
+	id := int(contractID)
+	req := schema.ProposalOpenContract{
+		ProposalOpenContract: 1,
+		ContractId:           &id,
+	}
+
+	// Canceling the subscription context closes its stream, Deriv ends the subscription itself on settlement
+	subCtx, cancel := context.WithCancel(ctx)
+
+	_, sub, err := c.api.SubscribeProposalOpenContract(subCtx, req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to watch contract %d: %w", contractID, mapError(err))
+	}
+
+	updates := make(chan *core.ContractInfo)
+
+	go func() {
+		defer close(updates)
+		defer cancel()
+
+		for resp := range sub.Stream {
+			if resp.ProposalOpenContract == nil {
+				continue
+			}
+
+			info := contractInfo(contractID, resp.ProposalOpenContract)
+
+			select {
+			case updates <- info:
+			case <-ctx.Done():
+				return
+			}
+
+			if info.IsSold || info.Status != core.ContractStatusOpen {
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+// WatchTicks streams ticks of the symbol until the context is canceled or the subscription ends
+func (c *Client) WatchTicks(ctx context.Context, symbol string) (<-chan core.HistoricalDataPoint, error) {
+	subCtx, cancel := context.WithCancel(ctx)
+
+	_, sub, err := c.api.SubscribeTicks(subCtx, schema.Ticks{Ticks: symbol})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to watch ticks of %s: %w", symbol, mapError(err))
+	}
+
+	ticks := make(chan core.HistoricalDataPoint)
+
+	go func() {
+		defer close(ticks)
+		defer cancel()
+
+		for resp := range sub.Stream {
+			if resp.Tick == nil || resp.Tick.Epoch == nil || resp.Tick.Quote == nil {
+				continue
+			}
+
+			tick := core.HistoricalDataPoint{
+				Timestamp: int64(*resp.Tick.Epoch),
+				Price:     *resp.Tick.Quote,
+			}
+
+			select {
+			case ticks <- tick:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ticks, nil
+}
+
+// WatchTransactions streams transactions of the account as they happen, including ones made outside the bot,
+// until the context is canceled or the subscription ends
+func (c *Client) WatchTransactions(ctx context.Context) (<-chan core.Transaction, error) {
+	subCtx, cancel := context.WithCancel(ctx)
+
+	_, sub, err := c.api.SubscribeTransaction(subCtx, schema.Transaction{Transaction: 1})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to watch transactions: %w", mapError(err))
+	}
+
+	transactions := make(chan core.Transaction)
+
+	go func() {
+		defer close(transactions)
+		defer cancel()
+
+		for resp := range sub.Stream {
+			t := resp.Transaction
+			if t == nil || t.TransactionId == nil || t.Action == nil {
+				continue
+			}
+
+			tx := core.Transaction{
+				ID:     int64(*t.TransactionId),
+				Action: string(*t.Action),
+			}
+			if t.TransactionTime != nil {
+				tx.Time = time.Unix(int64(*t.TransactionTime), 0)
+			}
+			if t.Amount != nil {
+				tx.Amount = *t.Amount
+			}
+			if t.Balance != nil {
+				tx.BalanceAfter = *t.Balance
+			}
+			if t.ContractId != nil {
+				tx.ContractID = int64(*t.ContractId)
+			}
+			if t.Longcode != nil {
+				tx.Longcode = *t.Longcode
+			}
+
+			select {
+			case transactions <- tx:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return transactions, nil
+}
+
+// contractInfo converts the contract state reported by Deriv
+func contractInfo(contractID int64, poc *schema.ProposalOpenContractRespProposalOpenContract) *core.ContractInfo {
+	info := &core.ContractInfo{
+		ContractID: contractID,
+		Status:     core.ContractStatusOpen,
+	}
+
+	if poc.Underlying != nil {
+		info.Symbol = *poc.Underlying
+	}
+	if poc.ContractType != nil {
+		info.ContractType = *poc.ContractType
+	}
+	if poc.Currency != nil {
+		info.Currency = *poc.Currency
+	}
+	if poc.BuyPrice != nil {
+		info.BuyPrice = *poc.BuyPrice
+	}
+	if poc.Payout != nil {
+		info.Payout = *poc.Payout
+	}
+	if poc.Profit != nil {
+		info.Profit = *poc.Profit
+	}
+	if poc.Status != nil {
+		// Status is an enum decoded into an untyped value, nil for contracts without status
+		if status, ok := poc.Status.Value.(string); ok {
+			info.Status = status
+		}
+	}
+	if poc.IsSold != nil {
+		info.IsSold = *poc.IsSold == 1
+	}
+	if poc.EntrySpot != nil {
+		info.EntrySpot = *poc.EntrySpot
+	}
+	if poc.CurrentSpot != nil {
+		info.CurrentSpot = *poc.CurrentSpot
+	}
+	if poc.DateExpiry != nil {
+		info.ExpiryTime = time.Unix(int64(*poc.DateExpiry), 0)
+	}
+
+	if poc.TickCount != nil {
+		info.TickCount = *poc.TickCount
+	}
+	if poc.TickPassed != nil {
+		info.TicksPassed = *poc.TickPassed
+	}
+	if poc.IsValidToSell != nil {
+		info.IsValidToSell = *poc.IsValidToSell == 1
+	}
+	if poc.Longcode != nil {
+		info.Longcode = *poc.Longcode
+	}
+	if poc.Barrier != nil {
+		info.Barrier = *poc.Barrier
+	}
+	if poc.EntryTickTime != nil {
+		info.EntryTime = time.Unix(int64(*poc.EntryTickTime), 0)
+	}
+	if poc.ExitTick != nil {
+		info.ExitSpot = *poc.ExitTick
+	}
+	if poc.ExitTickTime != nil {
+		info.ExitTime = time.Unix(int64(*poc.ExitTickTime), 0)
+	}
+	if poc.PurchaseTime != nil {
+		info.PurchaseTime = time.Unix(int64(*poc.PurchaseTime), 0)
+	}
+	if poc.SellPrice != nil {
+		info.SellPrice = *poc.SellPrice
+	}
+	if poc.SellTime != nil {
+		info.SellTime = time.Unix(int64(*poc.SellTime), 0)
+	}
+
+	return info
 }
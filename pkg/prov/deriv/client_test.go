@@ -0,0 +1,220 @@
+package deriv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/kirill/deriv-teletrader/pkg/core"
+)
+
+// fakeDeriv answers authorize with a recorded payload, proposals with a fixed quote and buys with a fixed contract,
+// keeping currencies of the proposals it was asked for
+type fakeDeriv struct {
+	authorize string
+
+	mu         sync.Mutex
+	currencies []string
+}
+
+func (f *fakeDeriv) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{InsecureSkipVerify: true})
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+
+	for {
+		_, data, err := conn.Read(r.Context())
+		if err != nil {
+			return
+		}
+
+		var req map[string]any
+		if err := json.Unmarshal(data, &req); err != nil {
+			return
+		}
+
+		var resp string
+		switch {
+		case req["authorize"] != nil:
+			resp = `{"msg_type":"authorize","echo_req":{},"authorize":` + f.authorize + `}`
+		case req["proposal"] != nil:
+			f.mu.Lock()
+			f.currencies = append(f.currencies, req["currency"].(string))
+			f.mu.Unlock()
+
+			resp = `{"msg_type":"proposal","echo_req":{},"proposal":{"id":"p1","ask_price":10,"payout":19.5,` +
+				`"spot":1234.5,"spot_time":1700000000,"date_start":1700000000,"display_value":"10.00","longcode":"Win payout"}}`
+		case req["buy"] != nil:
+			resp = `{"msg_type":"buy","echo_req":{},"buy":{"contract_id":42,"buy_price":10,"payout":19.5,"longcode":"Win payout",` +
+				`"purchase_time":1700000000,"start_time":1700000000,"transaction_id":7,"balance_after":990,"shortcode":"CALL_R_50"}}`
+		default:
+			resp = `{"msg_type":"error","echo_req":{},"error":{"code":"UnrecognisedRequest","message":"Unrecognised request"}}`
+		}
+
+		var out map[string]any
+		if err := json.Unmarshal([]byte(resp), &out); err != nil {
+			panic(err)
+		}
+		out["req_id"] = req["req_id"]
+
+		data, _ = json.Marshal(out)
+		if err := conn.Write(r.Context(), websocket.MessageText, data); err != nil {
+			return
+		}
+	}
+}
+
+func TestClientDetectsAccountCurrency(t *testing.T) {
+	tests := []struct {
+		name      string
+		authorize string
+		config    string // Currency set in the configuration
+		want      string
+		formatted string // 1234.5 formatted in the currency
+	}{
+		{
+			name:      "EUR account",
+			authorize: `{"loginid":"CR100","currency":"EUR","is_virtual":0,"account_list":[{"loginid":"CR100","currency":"EUR","is_virtual":0}]}`,
+			want:      "EUR",
+			formatted: "€1,234.50",
+		},
+		{
+			name:      "GBP account",
+			authorize: `{"loginid":"MX200","currency":"GBP","is_virtual":0}`,
+			want:      "GBP",
+			formatted: "£1,234.50",
+		},
+		{
+			name:      "BTC account",
+			authorize: `{"loginid":"CR300","currency":"BTC","is_virtual":0}`,
+			want:      "BTC",
+			formatted: "1,234.50000000 BTC",
+		},
+		{
+			name:      "account without currency",
+			authorize: `{"loginid":"VRTC400","is_virtual":1}`,
+			want:      "USD",
+			formatted: "$1,234.50",
+		},
+		{
+			name:      "configured currency overrides the account",
+			authorize: `{"loginid":"CR100","currency":"EUR","is_virtual":0}`,
+			config:    "USD",
+			want:      "USD",
+			formatted: "$1,234.50",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDeriv{authorize: tt.authorize}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			client, err := NewClient(&Config{
+				AppID:    "1089",
+				APIToken: "a1-token",
+				Endpoint: "ws" + strings.TrimPrefix(server.URL, "http"),
+				Currency: tt.config,
+			})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := client.Connect(ctx); err != nil {
+				t.Fatalf("Connect() error = %v", err)
+			}
+
+			if got := client.Currency(); got != tt.want {
+				t.Errorf("Currency() = %s, want %s", got, tt.want)
+			}
+
+			quote, err := client.GetQuote(ctx, &core.TradeRequest{
+				Symbol:       "R_50",
+				Amount:       10,
+				ContractType: "CALL",
+				Duration:     core.Duration{Value: 5, Unit: "t"},
+			})
+			if err != nil {
+				t.Fatalf("GetQuote() error = %v", err)
+			}
+
+			fake.mu.Lock()
+			currencies := fake.currencies
+			fake.mu.Unlock()
+
+			if len(currencies) != 1 || currencies[0] != tt.want {
+				t.Errorf("proposals were asked in %v, want %s", currencies, tt.want)
+			}
+			if quote.Currency != tt.want {
+				t.Errorf("quote currency = %s, want %s", quote.Currency, tt.want)
+			}
+
+			if got := core.NewFormatter("en").Money(1234.5, quote.Currency); got != tt.formatted {
+				t.Errorf("Money() = %s, want %s", got, tt.formatted)
+			}
+		})
+	}
+}
+
+func TestPooledClientTradesInAccountCurrency(t *testing.T) {
+	fake := &fakeDeriv{authorize: `{"loginid":"CR100","currency":"EUR","is_virtual":0}`}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	// The configured currency belongs to the default account, a linked EUR account still trades in EUR
+	pool := NewPool(&Config{
+		AppID:    "1089",
+		Endpoint: "ws" + strings.TrimPrefix(server.URL, "http"),
+		Currency: "USD",
+	})
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := pool.Client(ctx, "a1-user-token")
+	if err != nil {
+		t.Fatalf("Client() error = %v", err)
+	}
+
+	if got := client.Currency(); got != "EUR" {
+		t.Errorf("Currency() = %s, want EUR", got)
+	}
+
+	result, err := client.PlaceTrade(ctx, &core.TradeRequest{
+		Symbol:       "R_50",
+		Amount:       10,
+		ContractType: "CALL",
+		Duration:     core.Duration{Value: 5, Unit: "t"},
+	})
+	if err != nil {
+		t.Fatalf("PlaceTrade() error = %v", err)
+	}
+
+	fake.mu.Lock()
+	currencies := fake.currencies
+	fake.mu.Unlock()
+
+	if len(currencies) != 1 || currencies[0] != "EUR" {
+		t.Errorf("proposals were asked in %v, want EUR", currencies)
+	}
+	if result.Currency != "EUR" {
+		t.Errorf("trade currency = %s, want EUR", result.Currency)
+	}
+	if got := core.NewFormatter("en").Money(result.BuyPrice, result.Currency); got != "€10.00" {
+		t.Errorf("Money() = %s, want €10.00", got)
+	}
+}
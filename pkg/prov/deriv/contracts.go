@@ -0,0 +1,139 @@
+package deriv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+	"github.com/ksysoev/deriv-api/schema"
+)
+
+// StakeLimit holds stake bounds for a contract category
+type StakeLimit struct {
+	Min float64 `mapstructure:"min"`
+	Max float64 `mapstructure:"max"`
+}
+
+// defaultStakeLimits are Deriv stake rules for USD accounts by contract category,
+// used when stake_limits in config doesn't override them
+var defaultStakeLimits = map[string]StakeLimit{
+	"callput":      {Min: 0.35, Max: 50000},
+	"touchnotouch": {Min: 0.35, Max: 50000},
+	"digits":       {Min: 0.35, Max: 50000},
+	"multiplier":   {Min: 1, Max: 2000},
+	"accumulator":  {Min: 1, Max: 2000},
+}
+
+// GetContractLimits returns constraints of contracts available for the symbol
+func (c *Client) GetContractLimits(ctx context.Context, symbol string) ([]core.ContractLimits, error) {
+	currency := c.Currency()
+	req := schema.ContractsFor{
+		ContractsFor: symbol,
+		Currency:     currency,
+	}
+
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.ContractsForResp, error) {
+		return c.api.ContractsFor(ctx, req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contracts for %s: %w", symbol, mapError(err))
+	}
+
+	if resp.ContractsFor == nil {
+		return nil, fmt.Errorf("no contracts available for %s", symbol)
+	}
+
+	limits := make([]core.ContractLimits, 0, len(resp.ContractsFor.Available))
+	for _, contract := range resp.ContractsFor.Available {
+		stake := c.stakeLimit(contract.ContractCategory, currency)
+
+		limits = append(limits, core.ContractLimits{
+			Symbol:        symbol,
+			ContractType:  contract.ContractType,
+			Category:      contract.ContractCategory,
+			Currency:      currency,
+			MinStake:      stake.Min,
+			MaxStake:      stake.Max,
+			MinDuration:   contract.MinContractDuration,
+			MaxDuration:   contract.MaxContractDuration,
+			Barriers:      int(contract.Barriers),
+			Barrier:       barrier(contract.Barrier),
+			StartType:     contract.StartType,
+			ForwardStarts: forwardStarts(contract.ForwardStartingOptions),
+		})
+	}
+
+	return limits, nil
+}
+
+// barrier returns the default barrier of the contract, empty when it has none
+func barrier(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// forwardStarts converts forward starting sessions of contracts_for, skipping malformed ones
+func forwardStarts(options []schema.ContractsForRespContractsForAvailableElemForwardStartingOptionsElem) []core.StartWindow {
+	var windows []core.StartWindow
+	for _, option := range options {
+		if option.Open == nil || option.Close == nil {
+			continue
+		}
+
+		open, err := strconv.ParseInt(*option.Open, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		closeAt, err := strconv.ParseInt(*option.Close, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		windows = append(windows, core.StartWindow{Open: time.Unix(open, 0), Close: time.Unix(closeAt, 0)})
+	}
+
+	return windows
+}
+
+// stakeLimit returns stake bounds for the contract category, Deriv defaults only apply to USD accounts
+// and other currencies are left unbounded unless configured
+func (c *Client) stakeLimit(category, currency string) StakeLimit {
+	if limit, ok := c.cfg.StakeLimits[category]; ok {
+		return limit
+	}
+	if currency != defaultCurrency {
+		return StakeLimit{}
+	}
+	return defaultStakeLimits[category]
+}
+
+// GetActiveSymbols returns symbols currently offered by Deriv
+func (c *Client) GetActiveSymbols(ctx context.Context) ([]core.SymbolInfo, error) {
+	req := schema.ActiveSymbols{ActiveSymbols: schema.ActiveSymbolsActiveSymbolsBrief}
+
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.ActiveSymbolsResp, error) {
+		return c.api.ActiveSymbols(ctx, req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active symbols: %w", mapError(err))
+	}
+
+	symbols := make([]core.SymbolInfo, 0, len(resp.ActiveSymbols))
+	for _, s := range resp.ActiveSymbols {
+		symbols = append(symbols, core.SymbolInfo{
+			Symbol:        s.Symbol,
+			DisplayName:   s.DisplayName,
+			Market:        s.Market,
+			MarketName:    s.MarketDisplayName,
+			SubmarketName: s.SubmarketDisplayName,
+			IsOpen:        s.ExchangeIsOpen == 1 && s.IsTradingSuspended == 0,
+		})
+	}
+
+	return symbols, nil
+}
@@ -0,0 +1,124 @@
+package deriv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+	"github.com/ksysoev/deriv-api/schema"
+)
+
+// GetCopyTradingList returns traders the account copies and accounts copying it
+func (c *Client) GetCopyTradingList(ctx context.Context) (*core.CopyTradingList, error) {
+	req := schema.CopytradingList{CopytradingList: 1}
+
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.CopytradingListResp, error) {
+		return c.api.CopytradingList(ctx, req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get copy trading list: %w", mapError(err))
+	}
+
+	list := &core.CopyTradingList{}
+	if resp.CopytradingList == nil {
+		return list, nil
+	}
+
+	for _, t := range resp.CopytradingList.Traders {
+		trader := core.CopyTrader{
+			Assets:     t.Assets,
+			TradeTypes: t.TradeTypes,
+		}
+		if t.Loginid != nil {
+			trader.LoginID = *t.Loginid
+		}
+		if t.Token != nil {
+			trader.Token = *t.Token
+		}
+		if t.MinTradeStake != nil {
+			trader.MinStake = *t.MinTradeStake
+		}
+		if t.MaxTradeStake != nil {
+			trader.MaxStake = *t.MaxTradeStake
+		}
+		list.Traders = append(list.Traders, trader)
+	}
+
+	for _, copier := range resp.CopytradingList.Copiers {
+		list.Copiers = append(list.Copiers, copier.Loginid)
+	}
+
+	return list, nil
+}
+
+// GetCopyStatistics returns the track record of the trader with the login ID
+func (c *Client) GetCopyStatistics(ctx context.Context, traderID string) (*core.CopyStats, error) {
+	req := schema.CopytradingStatistics{
+		CopytradingStatistics: 1,
+		TraderId:              traderID,
+	}
+
+	resp, err := withRetry(ctx, c.cfg.Retry, c.health, readOnlyCall, func() (schema.CopytradingStatisticsResp, error) {
+		return c.api.CopytradingStatistics(ctx, req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statistics of trader %s: %w", traderID, mapError(err))
+	}
+
+	if resp.CopytradingStatistics == nil {
+		return nil, fmt.Errorf("empty copy trading statistics response")
+	}
+
+	s := resp.CopytradingStatistics
+	stats := &core.CopyStats{
+		TraderID:          traderID,
+		Copiers:           int(s.Copiers),
+		TotalTrades:       s.TotalTrades,
+		ProfitableTrades:  s.TradesProfitable,
+		AvgProfit:         s.AvgProfit,
+		AvgLoss:           s.AvgLoss,
+		AvgDuration:       time.Duration(s.AvgDuration) * time.Second,
+		Last12Months:      s.Last12MonthsProfitableTrades,
+		PerformanceChance: s.PerformanceProbability,
+	}
+	if s.ActiveSince > 0 {
+		stats.ActiveSince = time.Unix(int64(s.ActiveSince), 0)
+	}
+
+	return stats, nil
+}
+
+// StartCopying copies new trades of the trader whose read-only token is given onto the account
+func (c *Client) StartCopying(ctx context.Context, traderToken string, opts core.CopyOptions) error {
+	req := schema.CopyStart{CopyStart: traderToken}
+	if opts.MaxStake > 0 {
+		req.MaxTradeStake = &opts.MaxStake
+	}
+	if len(opts.Assets) > 0 {
+		req.Assets = opts.Assets
+	}
+
+	_, err := withRetry(ctx, c.cfg.Retry, c.health, tradingCall, func() (schema.CopyStartResp, error) {
+		return c.api.CopyStart(ctx, req)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start copying: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// StopCopying stops copying trades of the trader whose token copying was started with
+func (c *Client) StopCopying(ctx context.Context, traderToken string) error {
+	req := schema.CopyStop{CopyStop: traderToken}
+
+	_, err := withRetry(ctx, c.cfg.Retry, c.health, tradingCall, func() (schema.CopyStopResp, error) {
+		return c.api.CopyStop(ctx, req)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stop copying: %w", mapError(err))
+	}
+
+	return nil
+}
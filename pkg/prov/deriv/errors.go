@@ -0,0 +1,35 @@
+package deriv
+
+import (
+	"errors"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+	deriv "github.com/ksysoev/deriv-api"
+)
+
+// errorKinds maps Deriv API error codes to core error kinds
+var errorKinds = map[string]error{
+	"InvalidToken":               core.ErrInvalidToken,
+	"AuthorizationRequired":      core.ErrInvalidToken,
+	"MarketIsClosed":             core.ErrMarketClosed,
+	"ContractBuyValidationError": core.ErrContractValidation,
+	"ContractCreationFailure":    core.ErrContractValidation,
+	"OfferingsValidationError":   core.ErrContractValidation,
+	"RateLimit":                  core.ErrRateLimit,
+	"InsufficientBalance":        core.ErrInsufficientBalance,
+	"InvalidSymbol":              core.ErrInvalidSymbol,
+}
+
+// mapError converts Deriv API errors to core.DerivError, other errors are returned as is
+func mapError(err error) error {
+	var apiErr *deriv.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	return &core.DerivError{
+		Kind:    errorKinds[apiErr.Code],
+		Code:    apiErr.Code,
+		Message: apiErr.Message,
+	}
+}
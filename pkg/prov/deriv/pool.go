@@ -0,0 +1,99 @@
+package deriv
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+)
+
+// Pool keeps Deriv connections authorized with user tokens, one connection per token
+type Pool struct {
+	cfg     *Config
+	mu      sync.Mutex
+	clients map[string]*Client
+	health  core.HealthObserver
+}
+
+// NewPool creates a pool of per-token clients sharing the given configuration
+func NewPool(cfg *Config) *Pool {
+	return &Pool{
+		cfg:     cfg,
+		clients: make(map[string]*Client),
+	}
+}
+
+// SetHealthObserver makes clients created by the pool report outcomes of API calls to the observer
+func (p *Pool) SetHealthObserver(health core.HealthObserver) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.health = health
+}
+
+// Client returns a connected client authorized with the token, creating it on first use
+func (p *Pool) Client(ctx context.Context, token string) (core.DerivClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, ok := p.clients[token]; ok {
+		return client, nil
+	}
+
+	cfg := *p.cfg
+	cfg.APIToken = token
+	// Linked accounts trade in their own currency, the configured one belongs to the default account
+	cfg.Currency = ""
+	if cfg.TrafficMode == TrafficModeRecord {
+		// Only the default client records traffic, so pooled clients don't overwrite its fixtures
+		cfg.TrafficMode = ""
+	}
+
+	client, err := NewClient(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	client.SetHealthObserver(p.health)
+
+	if err := client.Connect(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	p.clients[token] = client
+
+	return client, nil
+}
+
+// Release disconnects the client authorized with the token
+func (p *Pool) Release(token string) error {
+	p.mu.Lock()
+	client, ok := p.clients[token]
+	delete(p.clients, token)
+	p.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if err := client.Close(); err != nil {
+		return fmt.Errorf("failed to close client: %w", err)
+	}
+
+	return nil
+}
+
+// Close disconnects all pooled clients
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	clients := p.clients
+	p.clients = make(map[string]*Client)
+	p.mu.Unlock()
+
+	for _, client := range clients {
+		client.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,436 @@
+package deriv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/coder/websocket"
+)
+
+// Traffic modes supported by the recording proxy
+const (
+	TrafficModeRecord = "record"
+	TrafficModeReplay = "replay"
+)
+
+// maxMessageSize limits the size of a single websocket message passing through the proxy
+const maxMessageSize = 16 << 20
+
+// volatileFields are request fields ignored when matching requests against fixtures,
+// because they differ between runs (request IDs, timestamps computed from now)
+var volatileFields = []string{"req_id", "passthrough", "start", "date_from", "date_to"}
+
+// secretFields are request fields carrying API tokens, they're redacted in fixtures and ignored when matching
+var secretFields = []string{"authorize", "tokens", "copy_start", "copy_stop"}
+
+// redactedValue replaces secrets in recorded fixtures
+const redactedValue = "REDACTED"
+
+// Fixture is a recorded request together with all responses received for it
+type Fixture struct {
+	Request   json.RawMessage   `json:"request"`
+	Responses []json.RawMessage `json:"responses"`
+}
+
+// recorder is a local websocket proxy placed between the Deriv API client and the real endpoint.
+// In record mode it forwards traffic upstream and captures request/response pairs,
+// in replay mode it serves previously captured responses without any network access.
+type recorder struct {
+	mode     string
+	path     string
+	upstream string
+	chaos    ChaosConfig
+	server   *http.Server
+	listener net.Listener
+
+	mu       sync.Mutex
+	fixtures []*Fixture
+	replay   map[string][]*Fixture
+}
+
+// newRecorder creates a recording proxy for the given mode and fixtures file.
+// Chaos is only injected into replayed traffic, so it never ends up in recorded fixtures.
+func newRecorder(mode, path, upstream string, chaos ChaosConfig) (*recorder, error) {
+	if path == "" {
+		return nil, fmt.Errorf("fixtures path is required for %s mode", mode)
+	}
+
+	r := &recorder{
+		mode:     mode,
+		path:     path,
+		upstream: upstream,
+		chaos:    chaos,
+	}
+
+	switch mode {
+	case TrafficModeRecord:
+		if chaos.Enabled {
+			return nil, fmt.Errorf("chaos mode is only supported in %s mode", TrafficModeReplay)
+		}
+	case TrafficModeReplay:
+		if err := r.load(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown traffic mode: %s", mode)
+	}
+
+	return r, nil
+}
+
+// Start begins listening on a random local port and returns the websocket endpoint of the proxy
+func (r *recorder) Start() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to start recording proxy: %w", err)
+	}
+
+	r.listener = listener
+	r.server = &http.Server{Handler: http.HandlerFunc(r.serveWS)}
+
+	go func() {
+		if err := r.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Recording proxy stopped: %v", err)
+		}
+	}()
+
+	return "ws://" + listener.Addr().String(), nil
+}
+
+// Close stops the proxy and, in record mode, writes captured fixtures to disk
+func (r *recorder) Close() error {
+	if r.server != nil {
+		if err := r.server.Close(); err != nil {
+			return fmt.Errorf("failed to stop recording proxy: %w", err)
+		}
+	}
+
+	if r.mode != TrafficModeRecord {
+		return nil
+	}
+
+	return r.save()
+}
+
+// serveWS accepts a websocket connection from the Deriv API client
+func (r *recorder) serveWS(w http.ResponseWriter, req *http.Request) {
+	conn, err := websocket.Accept(w, req, &websocket.AcceptOptions{InsecureSkipVerify: true})
+	if err != nil {
+		log.Printf("Recording proxy failed to accept connection: %v", err)
+		return
+	}
+	defer conn.CloseNow()
+
+	conn.SetReadLimit(maxMessageSize)
+
+	if r.mode == TrafficModeReplay {
+		err = r.serveReplay(req.Context(), conn)
+	} else {
+		err = r.serveRecord(req.Context(), conn, req)
+	}
+
+	if err != nil && websocket.CloseStatus(err) == -1 && !errors.Is(err, context.Canceled) {
+		log.Printf("Recording proxy connection closed: %v", err)
+	}
+}
+
+// serveRecord forwards traffic to the upstream endpoint and captures it
+func (r *recorder) serveRecord(ctx context.Context, client *websocket.Conn, req *http.Request) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	upstreamURL := r.upstream
+	if req.URL.RawQuery != "" {
+		upstreamURL += "?" + req.URL.RawQuery
+	}
+
+	upstream, _, err := websocket.Dial(ctx, upstreamURL, &websocket.DialOptions{
+		HTTPHeader: http.Header{"Origin": []string{req.Header.Get("Origin")}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect upstream: %w", err)
+	}
+	defer upstream.CloseNow()
+
+	upstream.SetReadLimit(maxMessageSize)
+
+	// pending maps request IDs to fixtures awaiting responses
+	var pendingMu sync.Mutex
+	pending := make(map[int]*Fixture)
+
+	errCh := make(chan error, 2)
+
+	go func() {
+		for {
+			typ, data, err := client.Read(ctx)
+			if err != nil {
+				errCh <- err
+				return
+			}
+
+			if reqID, ok := requestID(data); ok {
+				fixture := &Fixture{Request: redact(data)}
+				pendingMu.Lock()
+				pending[reqID] = fixture
+				pendingMu.Unlock()
+				r.add(fixture)
+			}
+
+			if err := upstream.Write(ctx, typ, data); err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			typ, data, err := upstream.Read(ctx)
+			if err != nil {
+				errCh <- err
+				return
+			}
+
+			if reqID, ok := requestID(data); ok {
+				pendingMu.Lock()
+				if fixture, ok := pending[reqID]; ok {
+					r.mu.Lock()
+					fixture.Responses = append(fixture.Responses, redact(data))
+					r.mu.Unlock()
+				}
+				pendingMu.Unlock()
+			}
+
+			if err := client.Write(ctx, typ, data); err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	return <-errCh
+}
+
+// serveReplay answers client requests with recorded responses
+func (r *recorder) serveReplay(ctx context.Context, client *websocket.Conn) error {
+	for {
+		_, data, err := client.Read(ctx)
+		if err != nil {
+			return err
+		}
+
+		reqID, _ := requestID(data)
+
+		outcome, ok := r.chaos.inject(ctx)
+		if !ok {
+			return ctx.Err()
+		}
+
+		if outcome == chaosDisconnect {
+			return client.Close(websocket.StatusGoingAway, "connection dropped by chaos mode")
+		}
+
+		responses, err := r.match(data)
+		if err != nil {
+			responses = []json.RawMessage{replayError(data, err)}
+		}
+
+		if outcome == chaosError {
+			responses = []json.RawMessage{chaosErrorResponse(data)}
+		}
+
+		for _, resp := range responses {
+			out, err := withRequestID(resp, reqID)
+			if err != nil {
+				return fmt.Errorf("failed to prepare replayed response: %w", err)
+			}
+
+			if err := client.Write(ctx, websocket.MessageText, out); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// add appends a newly captured fixture
+func (r *recorder) add(fixture *Fixture) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fixtures = append(r.fixtures, fixture)
+}
+
+// match returns recorded responses for the request.
+// Fixtures with the same request are served in recorded order, the last one is reused when exhausted.
+func (r *recorder) match(data []byte) ([]json.RawMessage, error) {
+	key, err := fixtureKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	queue := r.replay[key]
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("no recorded response for request %s", key)
+	}
+
+	if len(queue) > 1 {
+		r.replay[key] = queue[1:]
+	}
+
+	return queue[0].Responses, nil
+}
+
+// load reads fixtures from disk and indexes them for replay
+func (r *recorder) load() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &r.fixtures); err != nil {
+		return fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+
+	r.replay = make(map[string][]*Fixture)
+	for _, fixture := range r.fixtures {
+		key, err := fixtureKey(fixture.Request)
+		if err != nil {
+			return fmt.Errorf("invalid fixture request: %w", err)
+		}
+		r.replay[key] = append(r.replay[key], fixture)
+	}
+
+	return nil
+}
+
+// save writes captured fixtures to disk, readable by the owner only as they hold account data
+func (r *recorder) save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.fixtures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixtures: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write fixtures: %w", err)
+	}
+
+	// WriteFile keeps permissions of an existing file
+	if err := os.Chmod(r.path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict fixtures permissions: %w", err)
+	}
+
+	return nil
+}
+
+// fixtureKey builds a canonical representation of a request without volatile fields
+func fixtureKey(data []byte) (string, error) {
+	var req map[string]any
+	if err := json.Unmarshal(data, &req); err != nil {
+		return "", fmt.Errorf("failed to parse request: %w", err)
+	}
+
+	for _, field := range volatileFields {
+		delete(req, field)
+	}
+	redactSecrets(req)
+
+	// Maps are marshaled with sorted keys, which makes the key stable
+	key, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	return string(key), nil
+}
+
+// redact returns a copy of the request or response with tokens replaced, including ones echoed in echo_req.
+// Messages that aren't JSON objects are kept as they are.
+func redact(data []byte) json.RawMessage {
+	// Numbers are kept as recorded rather than round-tripped through float64
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var msg map[string]any
+	if err := dec.Decode(&msg); err != nil {
+		return append(json.RawMessage(nil), data...)
+	}
+
+	redactSecrets(msg)
+	if echo, ok := msg["echo_req"].(map[string]any); ok {
+		redactSecrets(echo)
+	}
+
+	out, err := json.Marshal(msg)
+	if err != nil {
+		return append(json.RawMessage(nil), data...)
+	}
+
+	return out
+}
+
+// redactSecrets replaces tokens in secret fields of a decoded message. Responses reuse the field names
+// for their results, e.g. the account of authorize, which replay needs, so only strings and lists are replaced.
+func redactSecrets(msg map[string]any) {
+	for _, field := range secretFields {
+		switch msg[field].(type) {
+		case string, []any:
+			msg[field] = redactedValue
+		}
+	}
+}
+
+// requestID extracts req_id from a request or response message
+func requestID(data []byte) (int, bool) {
+	var msg struct {
+		ReqID *int `json:"req_id"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil || msg.ReqID == nil {
+		return 0, false
+	}
+	return *msg.ReqID, true
+}
+
+// withRequestID rewrites req_id of a recorded response to match the replayed request
+func withRequestID(data json.RawMessage, reqID int) ([]byte, error) {
+	var resp map[string]any
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	resp["req_id"] = reqID
+	if echo, ok := resp["echo_req"].(map[string]any); ok {
+		echo["req_id"] = reqID
+	}
+
+	return json.Marshal(resp)
+}
+
+// replayError builds an API error response for requests missing from fixtures
+func replayError(data []byte, err error) json.RawMessage {
+	var req map[string]any
+	_ = json.Unmarshal(data, &req)
+
+	resp, _ := json.Marshal(map[string]any{
+		"echo_req": req,
+		"error": map[string]any{
+			"code":    "ReplayMissing",
+			"message": err.Error(),
+		},
+	})
+
+	return resp
+}
@@ -0,0 +1,94 @@
+package deriv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "authorize request",
+			data: `{"authorize":"a1-secret","req_id":1}`,
+			want: `{"authorize":"REDACTED","req_id":1}`,
+		},
+		{
+			name: "echoed token",
+			data: `{"authorize":{"balance":10.50},"echo_req":{"authorize":"a1-secret","req_id":1},"req_id":1}`,
+			want: `{"authorize":{"balance":10.50},"echo_req":{"authorize":"REDACTED","req_id":1},"req_id":1}`,
+		},
+		{
+			name: "token list",
+			data: `{"authorize":"a1-secret","tokens":["a1-other"],"req_id":1}`,
+			want: `{"authorize":"REDACTED","req_id":1,"tokens":"REDACTED"}`,
+		},
+		{
+			name: "request without secrets",
+			data: `{"ticks":"R_50","req_id":2,"price":1234.50}`,
+			want: `{"price":1234.50,"req_id":2,"ticks":"R_50"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(redact([]byte(tt.data))); got != tt.want {
+				t.Errorf("redact() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixtureKeyIgnoresToken(t *testing.T) {
+	recorded, err := fixtureKey(redact([]byte(`{"authorize":"a1-old","req_id":1}`)))
+	if err != nil {
+		t.Fatalf("fixtureKey() error = %v", err)
+	}
+
+	replayed, err := fixtureKey([]byte(`{"authorize":"a1-new","req_id":7}`))
+	if err != nil {
+		t.Fatalf("fixtureKey() error = %v", err)
+	}
+
+	if recorded != replayed {
+		t.Errorf("keys differ: %s and %s", recorded, replayed)
+	}
+	if strings.Contains(replayed, "a1-new") {
+		t.Errorf("key %s contains the token", replayed)
+	}
+}
+
+func TestRecorderSaveRestrictsPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recorder{mode: TrafficModeRecord, path: path}
+	r.add(&Fixture{Request: redact([]byte(`{"authorize":"a1-secret","req_id":1}`))})
+
+	if err := r.save(); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("fixtures permissions = %o, want 600", perm)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "a1-secret") {
+		t.Errorf("fixtures contain the token: %s", data)
+	}
+}
@@ -0,0 +1,106 @@
+package deriv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+	deriv "github.com/ksysoev/deriv-api"
+)
+
+// RetryConfig controls retries of read-only Deriv calls failing with transient errors
+type RetryConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`    // Total attempts including the first one, 1 disables retries
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"` // Backoff before the first retry, doubled for each next one
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`     // Upper bound of the backoff
+}
+
+// callKind tells the retry wrapper whether a call is safe to repeat
+type callKind int
+
+const (
+	// readOnlyCall doesn't change account state and may be retried
+	readOnlyCall callKind = iota
+	// tradingCall changes account state, e.g. buys or sells a contract.
+	// Deriv API has no idempotency keys, so a repeated call could open a duplicate position.
+	tradingCall
+)
+
+// transientCodes are Deriv error codes worth retrying
+var transientCodes = map[string]bool{
+	"RateLimit":           true,
+	"ServiceUnavailable":  true,
+	"InternalServerError": true,
+}
+
+// withRetry runs the call, retrying transient failures of read-only calls with exponential backoff and full jitter.
+// Trading calls are always executed exactly once. Outcomes of every attempt are reported to the health observer if it's set.
+func withRetry[T any](ctx context.Context, policy RetryConfig, health core.HealthObserver, kind callKind, call func() (T, error)) (T, error) {
+	attempts := policy.MaxAttempts
+	if attempts < 1 || kind == tradingCall {
+		attempts = 1
+	}
+
+	backoff := policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		resp, err := call()
+		observe(health, err, time.Since(start))
+
+		if err == nil || attempt >= attempts || !isTransient(err) {
+			return resp, err
+		}
+
+		delay := time.Duration(0)
+		if backoff > 0 {
+			delay = rand.N(backoff) + 1
+		}
+
+		log.Printf("Deriv call failed (attempt %d of %d), retrying in %s: %v", attempt, attempts, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return resp, err
+		case <-time.After(delay):
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// observe reports the outcome of a call to the health observer
+func observe(health core.HealthObserver, err error, latency time.Duration) {
+	if health == nil {
+		return
+	}
+
+	// The client reconnects on the next call after the connection drops
+	if errors.Is(err, deriv.ErrConnectionClosed) {
+		health.ObserveReconnect()
+	}
+
+	health.ObserveCall(err != nil && isTransient(err), latency)
+}
+
+// isTransient reports whether the error is likely to go away on its own
+func isTransient(err error) bool {
+	var apiErr *deriv.APIError
+	if errors.As(err, &apiErr) {
+		return transientCodes[apiErr.Code]
+	}
+
+	var netErr net.Error
+
+	return errors.Is(err, deriv.ErrConnectionClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.As(err, &netErr)
+}
@@ -0,0 +1,99 @@
+package deriv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	deriv "github.com/ksysoev/deriv-api"
+	"github.com/ksysoev/deriv-api/schema"
+)
+
+// PresetDemo connects to the public endpoint with Deriv's shared test app and refuses real accounts
+const PresetDemo = "demo"
+
+// Preset holds connection settings selected with deriv.preset
+type Preset struct {
+	Endpoint string
+	AppID    string // Used when deriv.app_id isn't set
+}
+
+// presets are the known connection presets
+var presets = map[string]Preset{
+	PresetDemo: {
+		Endpoint: "wss://ws.derivws.com/websockets/v3",
+		AppID:    "1089",
+	},
+}
+
+// ApplyPreset fills connection settings from the configured preset, it does nothing when no preset is set
+func ApplyPreset(cfg *Config) error {
+	if cfg.Preset == "" {
+		return nil
+	}
+
+	preset, ok := presets[cfg.Preset]
+	if !ok {
+		return fmt.Errorf("unknown deriv.preset %q", cfg.Preset)
+	}
+
+	cfg.Endpoint = preset.Endpoint
+	if cfg.AppID == "" {
+		cfg.AppID = preset.AppID
+	}
+
+	return nil
+}
+
+// AppRegistration describes an application to register with Deriv
+type AppRegistration struct {
+	Name        string
+	RedirectURI string   // Where OAuth logins return to, optional
+	Scopes      []string // Permissions users grant the app, e.g. read and trade
+}
+
+// RegisterApp registers an application owned by the account of the token, which needs the admin scope,
+// and returns its app ID
+func RegisterApp(ctx context.Context, endpoint, token string, app AppRegistration) (string, error) {
+	appID, err := strconv.Atoi(presets[PresetDemo].AppID)
+	if err != nil {
+		return "", fmt.Errorf("invalid app ID: %w", err)
+	}
+
+	// Any app ID may be used to connect, the new app belongs to the account of the token
+	api, err := deriv.NewDerivAPI(endpoint, appID, "en", "https://deriv-teletrader")
+	if err != nil {
+		return "", fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	if err := api.Connect(); err != nil {
+		return "", fmt.Errorf("failed to connect: %w", err)
+	}
+	defer api.Disconnect()
+
+	if _, err := api.Authorize(ctx, schema.Authorize{Authorize: token}); err != nil {
+		return "", fmt.Errorf("failed to authorize: %w", mapError(err))
+	}
+
+	req := schema.AppRegister{
+		AppRegister: 1,
+		Name:        app.Name,
+	}
+	if app.RedirectURI != "" {
+		req.RedirectUri = &app.RedirectURI
+	}
+	for _, scope := range app.Scopes {
+		req.Scopes = append(req.Scopes, schema.AppRegisterScopesElem(scope))
+	}
+
+	resp, err := api.AppRegister(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to register app: %w", mapError(err))
+	}
+
+	if resp.AppRegister == nil {
+		return "", fmt.Errorf("failed to register app: empty response")
+	}
+
+	return strconv.Itoa(resp.AppRegister.AppId), nil
+}
@@ -18,8 +18,15 @@ import (
 type Config struct {
 	APIKey string `mapstructure:"api_key"`
 	Model  string `mapstructure:"model"`
+
+	// Market data the tools may fetch to answer one question, 0 is unlimited
+	MaxToolCalls int `mapstructure:"max_tool_calls"`
+	MaxCandles   int `mapstructure:"max_candles"`
 }
 
+// defaultMaxIterations bounds the agent steps when tool calls are unlimited
+const defaultMaxIterations = 3
+
 type Client struct {
 	llm llms.Model
 	cfg *Config
@@ -74,10 +81,17 @@ func (c *Client) ProcessWithFunctions(ctx context.Context, input string, provide
 		return "", fmt.Errorf("input text cannot be empty")
 	}
 
-	// Create tools
+	// Tools share the budget, so a confused model can't fire dozens of history requests in one answer
+	budget := tools.NewBudget(c.cfg.MaxToolCalls, c.cfg.MaxCandles)
 	marketTools := []lctools.Tool{
-		tools.NewGetPriceTool(provider),
-		tools.NewGetHistoricalDataTool(provider),
+		tools.NewGetPriceTool(provider, budget),
+		tools.NewGetHistoricalDataTool(provider, budget),
+	}
+
+	// The model gets one more step than it has tool calls to answer with what it gathered
+	iterations := defaultMaxIterations
+	if c.cfg.MaxToolCalls > 0 {
+		iterations = c.cfg.MaxToolCalls + 1
 	}
 
 	// Create agent executor with more specific instructions
@@ -102,7 +116,7 @@ Remember:
 - Always verify data before making suggestions
 - Explain your reasoning based on the data
 - Keep responses focused on trading information`),
-		agents.WithMaxIterations(3),
+		agents.WithMaxIterations(iterations),
 	)
 	if err != nil {
 		return "", fmt.Errorf("failed to initialize agent: %w", err)
@@ -114,6 +128,10 @@ Remember:
 	}
 
 	result, err := executor.Call(ctx, agentInput)
+	if err != nil && budget.Exceeded() {
+		return "⚠️ I couldn't finish the analysis: answering needed more market data than allowed, " + budget.Describe() +
+			". Please ask a narrower question, e.g. about one symbol and timeframe.", nil
+	}
 	if err != nil {
 		// Try to handle the error gracefully
 		if strings.Contains(err.Error(), "could not find symbol") {
@@ -128,5 +146,9 @@ Remember:
 		return "I encountered an issue while processing your request. Please try asking in a different way.", nil
 	}
 
+	if budget.Exceeded() {
+		output += "\n\n⚠️ This answer is partial: it needed more market data than allowed, " + budget.Describe() + "."
+	}
+
 	return output, nil
 }
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// budgetExhausted is the observation tools return once the budget is spent, it asks the model to wrap up
+const budgetExhausted = "The market data budget of this question is exhausted, no more data can be fetched. " +
+	"Answer with the data gathered so far and say that the analysis is partial."
+
+// Budget limits market data fetched by the tools while answering one question, all tools of the question share it.
+// Zero limits are unlimited.
+type Budget struct {
+	mu         sync.Mutex
+	maxCalls   int
+	maxCandles int
+	calls      int
+	candles    int
+	exceeded   bool
+}
+
+// NewBudget creates a budget of tool calls and data points for one question
+func NewBudget(maxCalls, maxCandles int) *Budget {
+	return &Budget{maxCalls: maxCalls, maxCandles: maxCandles}
+}
+
+// call takes a tool call from the budget, it reports false when no calls are left
+func (b *Budget) call() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.maxCalls > 0 && b.calls >= b.maxCalls {
+		b.exceeded = true
+		return false
+	}
+
+	b.calls++
+
+	return true
+}
+
+// takeCandles takes up to count data points from the budget and returns how many may be fetched, 0 when none are left
+func (b *Budget) takeCandles(count int) int {
+	if b == nil || b.maxCandles <= 0 {
+		return count
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	left := b.maxCandles - b.candles
+	if count > left {
+		b.exceeded = true
+		count = max(left, 0)
+	}
+
+	b.candles += count
+
+	return count
+}
+
+// Exceeded reports whether a tool was refused or cut short by the budget
+func (b *Budget) Exceeded() bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.exceeded
+}
+
+// Describe explains the limits of the budget to the user
+func (b *Budget) Describe() string {
+	var limits []string
+	if b.maxCalls > 0 {
+		limits = append(limits, fmt.Sprintf("%d market data requests", b.maxCalls))
+	}
+	if b.maxCandles > 0 {
+		limits = append(limits, fmt.Sprintf("%d data points", b.maxCandles))
+	}
+
+	return "at most " + strings.Join(limits, " and ") + " per question"
+}
@@ -16,21 +16,23 @@ var _ tools.Tool = (*GetHistoricalDataTool)(nil)
 // GetPriceTool is a tool for getting current price of a symbol
 type GetPriceTool struct {
 	provider core.MarketDataProvider
+	budget   *Budget
 }
 
 // GetHistoricalDataTool is a tool for getting historical data
 type GetHistoricalDataTool struct {
 	provider core.MarketDataProvider
+	budget   *Budget
 }
 
-// NewGetPriceTool creates a new GetPriceTool
-func NewGetPriceTool(provider core.MarketDataProvider) *GetPriceTool {
-	return &GetPriceTool{provider: provider}
+// NewGetPriceTool creates a new GetPriceTool, a nil budget is unlimited
+func NewGetPriceTool(provider core.MarketDataProvider, budget *Budget) *GetPriceTool {
+	return &GetPriceTool{provider: provider, budget: budget}
 }
 
-// NewGetHistoricalDataTool creates a new GetHistoricalDataTool
-func NewGetHistoricalDataTool(provider core.MarketDataProvider) *GetHistoricalDataTool {
-	return &GetHistoricalDataTool{provider: provider}
+// NewGetHistoricalDataTool creates a new GetHistoricalDataTool, a nil budget is unlimited
+func NewGetHistoricalDataTool(provider core.MarketDataProvider, budget *Budget) *GetHistoricalDataTool {
+	return &GetHistoricalDataTool{provider: provider, budget: budget}
 }
 
 // Name implements Tool interface
@@ -58,6 +60,10 @@ func (t *GetPriceTool) Call(ctx context.Context, input string) (string, error) {
 		args.Symbol = symbol
 	}
 
+	if !t.budget.call() {
+		return budgetExhausted, nil
+	}
+
 	price, err := t.provider.GetPrice(ctx, args.Symbol)
 	if err != nil {
 		return "", fmt.Errorf("failed to get price: %w", err)
@@ -106,6 +112,15 @@ func (t *GetHistoricalDataTool) Call(ctx context.Context, input string) (string,
 		args.Style = "candles" // default style
 	}
 
+	if !t.budget.call() {
+		return budgetExhausted, nil
+	}
+
+	requested := args.Count
+	if args.Count = t.budget.takeCandles(args.Count); args.Count == 0 {
+		return budgetExhausted, nil
+	}
+
 	req := core.HistoricalDataRequest{
 		Symbol:   args.Symbol,
 		Interval: core.TimeInterval(args.Interval),
@@ -121,6 +136,9 @@ func (t *GetHistoricalDataTool) Call(ctx context.Context, input string) (string,
 	// Format the data as a string
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("Historical data for %s (%s, %s):\n", args.Symbol, args.Interval, args.Style))
+	if args.Count < requested {
+		result.WriteString(fmt.Sprintf("Only %d of %d points were fetched, the data budget of this question is exhausted.\n", args.Count, requested))
+	}
 	for _, point := range data {
 		if req.Style == core.StyleCandles {
 			result.WriteString(fmt.Sprintf("Time: %d, Open: %.2f, High: %.2f, Low: %.2f, Close: %.2f\n",
@@ -0,0 +1,154 @@
+package ohlc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+)
+
+// defaultCount is the number of candles requested when the request leaves it out
+const defaultCount = 60
+
+// maxResponseSize bounds the body read from the data source
+const maxResponseSize = 4 << 20
+
+// Config holds settings of a read-only OHLC data source used as a benchmark for Deriv prices
+type Config struct {
+	Enabled bool              `mapstructure:"enabled"`
+	Name    string            `mapstructure:"name"`    // Shown next to its prices, e.g. "Binance"
+	URL     string            `mapstructure:"url"`     // Candles endpoint with {symbol}, {granularity} (seconds) and {count} placeholders
+	Symbols map[string]string `mapstructure:"symbols"` // Symbols of the data source by Deriv symbol, others aren't covered
+	Timeout time.Duration     `mapstructure:"timeout"`
+}
+
+// candle is a candle as returned by the data source
+type candle struct {
+	Time  int64   `json:"time"` // Unix time of the candle open
+	Open  float64 `json:"open"`
+	High  float64 `json:"high"`
+	Low   float64 `json:"low"`
+	Close float64 `json:"close"`
+}
+
+// Client fetches candles from a generic REST endpoint returning a JSON array of candles
+type Client struct {
+	cfg  *Config
+	http *http.Client
+}
+
+var _ core.MarketDataProvider = (*Client)(nil)
+
+// NewClient creates a client of the data source
+func NewClient(cfg *Config) *Client {
+	return &Client{
+		cfg:  cfg,
+		http: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Name returns the name of the data source shown to users
+func (c *Client) Name() string {
+	return c.cfg.Name
+}
+
+// GetAvailableSymbols returns Deriv symbols the data source covers
+func (c *Client) GetAvailableSymbols(_ context.Context) ([]string, error) {
+	symbols := make([]string, 0, len(c.cfg.Symbols))
+	for symbol := range c.cfg.Symbols {
+		symbols = append(symbols, symbol)
+	}
+	slices.Sort(symbols)
+
+	return symbols, nil
+}
+
+// GetPrice returns the close of the latest candle of the symbol
+func (c *Client) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	candles, err := c.GetHistoricalData(ctx, core.HistoricalDataRequest{Symbol: symbol, Style: core.StyleCandles, Count: 1})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(candles) == 0 {
+		return 0, fmt.Errorf("no price of %s", symbol)
+	}
+
+	return candles[len(candles)-1].Close, nil
+}
+
+// GetHistoricalData returns candles of the symbol oldest first, ticks are approximated by candle closes
+func (c *Client) GetHistoricalData(ctx context.Context, req core.HistoricalDataRequest) ([]core.HistoricalDataPoint, error) {
+	remote, ok := c.cfg.Symbols[req.Symbol]
+	if !ok {
+		return nil, fmt.Errorf("symbol %s is not covered by %s", req.Symbol, c.cfg.Name)
+	}
+
+	granularity := req.Granularity
+	if granularity == 0 {
+		granularity = 60
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = defaultCount
+	}
+
+	endpoint := strings.NewReplacer(
+		"{symbol}", url.QueryEscape(remote),
+		"{granularity}", strconv.Itoa(granularity),
+		"{count}", strconv.Itoa(count),
+	).Replace(c.cfg.URL)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", "deriv-teletrader")
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candles of %s: %w", req.Symbol, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded with status %d", c.cfg.Name, resp.StatusCode)
+	}
+
+	var candles []candle
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&candles); err != nil {
+		return nil, fmt.Errorf("failed to decode candles of %s: %w", req.Symbol, err)
+	}
+
+	slices.SortFunc(candles, func(a, b candle) int {
+		return int(a.Time - b.Time)
+	})
+
+	if len(candles) > count {
+		candles = candles[len(candles)-count:]
+	}
+
+	points := make([]core.HistoricalDataPoint, 0, len(candles))
+	for _, c := range candles {
+		points = append(points, core.HistoricalDataPoint{
+			Timestamp: c.Time,
+			Price:     c.Close,
+			Open:      c.Open,
+			High:      c.High,
+			Low:       c.Low,
+			Close:     c.Close,
+		})
+	}
+
+	return points, nil
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body, keyed with the user's webhook secret
+const SignatureHeader = "X-Teletrader-Signature"
+
+// Config holds settings of webhook delivery
+type Config struct {
+	Enabled      bool          `mapstructure:"enabled"`       // Let users register webhooks with /webhook
+	Timeout      time.Duration `mapstructure:"timeout"`       // Time limit of a single delivery
+	AllowPrivate bool          `mapstructure:"allow_private"` // Allow URLs resolving to loopback and private addresses
+}
+
+// Client posts signed JSON payloads to user webhooks
+type Client struct {
+	http *http.Client
+}
+
+// NewClient creates a webhook client, by default it refuses to connect to private networks,
+// so users can't reach services next to the bot through their webhooks
+func NewClient(cfg *Config) *Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivate {
+		dialer.Control = denyPrivate
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &Client{
+		http: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+			// Redirects could lead to addresses the URL check didn't see
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Deliver posts the payload to the URL signed with the secret, non-2xx responses are reported as errors
+func (c *Client) Deliver(ctx context.Context, url, secret string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "deriv-teletrader")
+	req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Draining the body lets the connection be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// denyPrivate refuses connections to loopback, private and link-local addresses
+func denyPrivate(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("webhook address %s is not allowed", host)
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+// Package recording archives ticks into gzip-compressed CSV files, one per symbol and UTC day
+package recording
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirill/deriv-teletrader/pkg/types"
+)
+
+// dayLayout names daily files
+const dayLayout = "2006-01-02"
+
+// fileSuffix is the extension of daily files
+const fileSuffix = ".csv.gz"
+
+// Config holds settings of tick recording
+type Config struct {
+	Enabled   bool     `mapstructure:"enabled"`
+	Dir       string   `mapstructure:"dir"`       // Directory holding a subdirectory of daily files per symbol
+	Symbols   []string `mapstructure:"symbols"`   // Symbols whose ticks are recorded
+	Retention int      `mapstructure:"retention"` // Days files are kept for, zero keeps them forever
+}
+
+// dayFile is the open file of the current day of a symbol
+type dayFile struct {
+	day  string
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+}
+
+// Recorder appends ticks to daily files. Each time a file is opened a new gzip member is appended to it,
+// readers see them as a single stream.
+type Recorder struct {
+	cfg   *Config
+	mu    sync.Mutex
+	files map[string]*dayFile
+}
+
+// New creates a recorder writing to the configured directory
+func New(cfg *Config) (*Recorder, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("recording directory is not set")
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	return &Recorder{cfg: cfg, files: make(map[string]*dayFile)}, nil
+}
+
+// Symbols returns the symbols whose ticks are recorded
+func (r *Recorder) Symbols() []string {
+	return r.cfg.Symbols
+}
+
+// Record appends the tick to the file of its symbol and day, rotating the file when the day changes
+func (r *Recorder) Record(symbol string, tick types.HistoricalDataPoint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	day := time.Unix(tick.Timestamp, 0).UTC().Format(dayLayout)
+
+	f, ok := r.files[symbol]
+	if ok && f.day != day {
+		if err := f.close(); err != nil {
+			return err
+		}
+		delete(r.files, symbol)
+		ok = false
+	}
+
+	if !ok {
+		var err error
+		if f, err = r.open(symbol, day); err != nil {
+			return err
+		}
+		r.files[symbol] = f
+	}
+
+	if _, err := fmt.Fprintf(f.buf, "%d,%s\n", tick.Timestamp, strconv.FormatFloat(tick.Price, 'f', -1, 64)); err != nil {
+		return fmt.Errorf("failed to write tick: %w", err)
+	}
+
+	return nil
+}
+
+// Flush makes recorded ticks readable, without it they may stay buffered until the file is closed
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for _, f := range r.files {
+		if err := f.buf.Flush(); err != nil {
+			errs = append(errs, err)
+		} else if err := f.gz.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to flush recordings: %w", err)
+	}
+
+	return nil
+}
+
+// Close finishes all open files
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for symbol, f := range r.files {
+		errs = append(errs, f.close())
+		delete(r.files, symbol)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Cleanup removes files older than the retention period and returns their number
+func (r *Recorder) Cleanup(now time.Time) (int, error) {
+	if r.cfg.Retention <= 0 {
+		return 0, nil
+	}
+
+	oldest := now.UTC().AddDate(0, 0, -r.cfg.Retention).Format(dayLayout)
+
+	paths, err := filepath.Glob(filepath.Join(r.cfg.Dir, "*", "*"+fileSuffix))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list recordings: %w", err)
+	}
+
+	var removed int
+	for _, path := range paths {
+		// Names are dates, so they sort like the days they hold
+		if day := strings.TrimSuffix(filepath.Base(path), fileSuffix); day >= oldest {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove recording: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Replay reads ticks of the symbol recorded on the UTC day of the given time, it returns none when nothing was recorded.
+// A file that wasn't closed properly, e.g. the one of the current day, is read up to its last complete tick.
+func (r *Recorder) Replay(symbol string, day time.Time) ([]types.HistoricalDataPoint, error) {
+	f, err := os.Open(r.path(symbol, day.UTC().Format(dayLayout)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	defer gz.Close()
+
+	var ticks []types.HistoricalDataPoint
+
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		epoch, price, ok := strings.Cut(scanner.Text(), ",")
+		if !ok {
+			continue
+		}
+
+		timestamp, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(price, 64)
+		if err != nil {
+			continue
+		}
+
+		ticks = append(ticks, types.HistoricalDataPoint{Timestamp: timestamp, Price: value})
+	}
+
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	return ticks, nil
+}
+
+// path returns the file of the symbol and day
+func (r *Recorder) path(symbol, day string) string {
+	return filepath.Join(r.cfg.Dir, filepath.Base(symbol), day+fileSuffix)
+}
+
+// open opens the file of the symbol and day for appending
+func (r *Recorder) open(symbol, day string) (*dayFile, error) {
+	path := r.path(symbol, day)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+
+	gz := gzip.NewWriter(file)
+
+	return &dayFile{day: day, file: file, gz: gz, buf: bufio.NewWriter(gz)}, nil
+}
+
+// close flushes and closes the file
+func (f *dayFile) close() error {
+	err := errors.Join(f.buf.Flush(), f.gz.Close(), f.file.Close())
+	if err != nil {
+		return fmt.Errorf("failed to close recording: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// encryptedPrefix marks encrypted values, the format is "enc:<key id>:<base64 nonce+ciphertext>"
+const encryptedPrefix = "enc:"
+
+// sealer encrypts stored values with AES-GCM
+type sealer struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// newSealer creates a sealer with a key derived from the passphrase
+func newSealer(passphrase string) (*sealer, error) {
+	key := sha256.Sum256([]byte(passphrase))
+
+	// The key ID is derived from the key itself, so it reveals nothing about the passphrase
+	id := sha256.Sum256(key[:])
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AEAD: %w", err)
+	}
+
+	return &sealer{id: hex.EncodeToString(id[:4]), aead: aead}, nil
+}
+
+// seal encrypts the value and encodes it as a JSON string
+func (s *sealer) seal(plain []byte) (json.RawMessage, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := s.aead.Seal(nonce, nonce, plain, nil)
+
+	return json.Marshal(encryptedPrefix + s.id + ":" + base64.StdEncoding.EncodeToString(sealed))
+}
+
+// open decrypts a value sealed with this key
+func (s *sealer) open(sealed []byte) ([]byte, error) {
+	if len(sealed) < s.aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted value is too short")
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+
+	return s.aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// keyring holds the current encryption key and previous keys still accepted for decryption
+type keyring struct {
+	current *sealer
+	keys    map[string]*sealer
+}
+
+// newKeyring creates a keyring, the current key may be empty when encryption is not configured
+func newKeyring(current string, previous []string) (*keyring, error) {
+	k := &keyring{keys: make(map[string]*sealer)}
+
+	for _, passphrase := range previous {
+		if _, err := k.add(passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	if current != "" {
+		s, err := k.add(current)
+		if err != nil {
+			return nil, err
+		}
+		k.current = s
+	}
+
+	return k, nil
+}
+
+// add registers a key for decryption
+func (k *keyring) add(passphrase string) (*sealer, error) {
+	s, err := newSealer(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	k.keys[s.id] = s
+
+	return s, nil
+}
+
+// isEncrypted reports whether the stored value was produced by seal
+func isEncrypted(raw json.RawMessage) bool {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return false
+	}
+	return strings.HasPrefix(encoded, encryptedPrefix)
+}
+
+// seal encrypts the value with the current key
+func (k *keyring) seal(plain []byte) (json.RawMessage, error) {
+	if k.current == nil {
+		return nil, fmt.Errorf("encryption key is not configured")
+	}
+	return k.current.seal(plain)
+}
+
+// open decrypts the value with the key it was sealed with
+func (k *keyring) open(raw json.RawMessage) ([]byte, error) {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil || !strings.HasPrefix(encoded, encryptedPrefix) {
+		return nil, fmt.Errorf("value is not encrypted")
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(encoded, encryptedPrefix), ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed encrypted value")
+	}
+
+	s, ok := k.keys[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key %s", parts[0])
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+
+	plain, err := s.open(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return plain, nil
+}
+
+// LoadKey returns the encryption passphrase, reading it from the file when a path is given
+func LoadKey(key, keyFile string) (string, error) {
+	if keyFile == "" {
+		return key, nil
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read encryption key file: %w", err)
+	}
+
+	key = strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("encryption key file %s is empty", keyFile)
+	}
+
+	return key, nil
+}
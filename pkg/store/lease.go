@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Lock file guarding lease updates, locks older than this are left by crashed processes
+const (
+	leaseLockStale = 10 * time.Second
+	leaseLockWait  = 50 * time.Millisecond
+)
+
+// lease is the content of a lease file
+type lease struct {
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AcquireLease takes the named lease for the owner or renews it when the owner already holds it.
+// It reports false while the lease is held by another owner and hasn't expired.
+// Leases are kept in files next to the data file, so instances sharing the store directory see each other.
+func (s *Store) AcquireLease(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	path := s.leasePath(name)
+
+	unlock, err := lockFile(ctx, path+".lock")
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	current, err := readLease(path)
+	if err != nil {
+		return false, err
+	}
+
+	if current != nil && current.Owner != owner && time.Now().Before(current.ExpiresAt) {
+		return false, nil
+	}
+
+	if err := writeLease(path, &lease{Owner: owner, ExpiresAt: time.Now().Add(ttl)}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseLease gives up the named lease if it's held by the owner, so another instance can take it right away
+func (s *Store) ReleaseLease(ctx context.Context, name, owner string) error {
+	path := s.leasePath(name)
+
+	unlock, err := lockFile(ctx, path+".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := readLease(path)
+	if err != nil {
+		return err
+	}
+
+	if current == nil || current.Owner != owner {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to release lease %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// leasePath returns the path of the named lease file
+func (s *Store) leasePath(name string) string {
+	return s.path + "." + name + ".lease"
+}
+
+// readLease loads the lease file, returning nil when there is no lease
+func readLease(path string) (*lease, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lease: %w", err)
+	}
+
+	var l lease
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse lease: %w", err)
+	}
+
+	return &l, nil
+}
+
+// writeLease atomically replaces the lease file
+func writeLease(path string, l *lease) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to encode lease: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write lease: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace lease: %w", err)
+	}
+
+	return nil
+}
+
+// lockFile takes an exclusive lock by creating the file, waiting while another process holds it.
+// It returns the function releasing the lock.
+func lockFile(ctx context.Context, path string) (func(), error) {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		// A process crashing while holding the lock leaves the file behind
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > leaseLockStale {
+			os.Remove(path)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(leaseLockWait):
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/kirill/deriv-teletrader/pkg/core"
+)
+
+// Config holds persistence configuration
+type Config struct {
+	Path string `mapstructure:"path"` // Path to the data file
+
+	// Encryption at rest of sensitive buckets
+	EncryptionKey     string   `mapstructure:"encryption_key"`      // Passphrase for values of encrypted buckets
+	EncryptionKeyFile string   `mapstructure:"encryption_key_file"` // File with the passphrase, takes precedence over encryption_key
+	OldEncryptionKeys []string `mapstructure:"old_encryption_keys"` // Previous passphrases still accepted for decryption during rotation
+	EncryptedBuckets  []string `mapstructure:"encrypted_buckets"`   // Buckets holding sensitive values
+}
+
+// Store is a key-value store organized in buckets and persisted to a JSON file
+type Store struct {
+	mu        sync.RWMutex
+	path      string
+	data      map[string]map[string]json.RawMessage
+	keys      *keyring
+	encrypted map[string]bool
+	dirty     bool // Data has changes not written to disk
+}
+
+// New opens the store, loading existing data from disk
+func New(cfg *Config) (*Store, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("store path is required")
+	}
+
+	s := &Store{
+		path:      cfg.Path,
+		data:      make(map[string]map[string]json.RawMessage),
+		encrypted: make(map[string]bool),
+	}
+
+	for _, bucket := range cfg.EncryptedBuckets {
+		s.encrypted[bucket] = true
+	}
+
+	key, err := LoadKey(cfg.EncryptionKey, cfg.EncryptionKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	s.keys, err = newKeyring(key, cfg.OldEncryptionKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == "" && len(cfg.EncryptedBuckets) > 0 {
+		log.Printf("Store encryption key is not configured, sensitive data is stored unencrypted")
+	}
+
+	data, err := os.ReadFile(cfg.Path)
+	switch {
+	case os.IsNotExist(err):
+		if err := os.MkdirAll(filepath.Dir(cfg.Path), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create store directory: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	default:
+		if err := json.Unmarshal(data, &s.data); err != nil {
+			return nil, fmt.Errorf("failed to parse store: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Get decodes the value stored under the key into v, returns core.ErrNotFound when it's missing
+func (s *Store) Get(_ context.Context, bucket, key string, v any) error {
+	s.mu.RLock()
+	raw, ok := s.data[bucket][key]
+	s.mu.RUnlock()
+
+	if !ok {
+		return core.ErrNotFound
+	}
+
+	// Plain values may remain in a bucket marked sensitive until the store is rekeyed
+	if isEncrypted(raw) {
+		plain, err := s.keys.open(raw)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s/%s: %w", bucket, key, err)
+		}
+		raw = plain
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to decode %s/%s: %w", bucket, key, err)
+	}
+
+	return nil
+}
+
+// Put stores the value under the key
+func (s *Store) Put(_ context.Context, bucket, key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s/%s: %w", bucket, key, err)
+	}
+
+	if s.encrypted[bucket] && s.keys.current != nil {
+		raw, err = s.keys.seal(raw)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s/%s: %w", bucket, key, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data[bucket] == nil {
+		s.data[bucket] = make(map[string]json.RawMessage)
+	}
+	s.data[bucket][key] = raw
+	s.dirty = true
+
+	return s.flush()
+}
+
+// Delete removes the key from the bucket
+func (s *Store) Delete(_ context.Context, bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.data[bucket][key]; !ok {
+		return nil
+	}
+	delete(s.data[bucket], key)
+	s.dirty = true
+
+	return s.flush()
+}
+
+// Keys returns all keys of the bucket in sorted order
+func (s *Store) Keys(_ context.Context, bucket string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.data[bucket]))
+	for key := range s.data[bucket] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Reload replaces data in memory with the content of the data file, e.g. after another instance wrote to it
+func (s *Store) Reload() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read store: %w", err)
+	}
+
+	loaded := make(map[string]map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("failed to parse store: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data = loaded
+	s.dirty = false
+
+	return nil
+}
+
+// Rekey re-encrypts values of encrypted buckets with a new key and makes it the current one.
+// Plain values stored before their bucket was marked sensitive get encrypted as well.
+// It returns the number of re-encrypted values.
+func (s *Store) Rekey(newKey string) (int, error) {
+	if newKey == "" {
+		return 0, fmt.Errorf("new encryption key is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := newSealer(newKey)
+	if err != nil {
+		return 0, err
+	}
+
+	rekeyed := make(map[string]map[string]json.RawMessage, len(s.data))
+	count := 0
+
+	for bucket, values := range s.data {
+		rekeyed[bucket] = make(map[string]json.RawMessage, len(values))
+
+		for key, raw := range values {
+			if !s.encrypted[bucket] && !isEncrypted(raw) {
+				rekeyed[bucket][key] = raw
+				continue
+			}
+
+			plain := []byte(raw)
+			if isEncrypted(raw) {
+				plain, err = s.keys.open(raw)
+				if err != nil {
+					return 0, fmt.Errorf("failed to decrypt %s/%s: %w", bucket, key, err)
+				}
+			}
+
+			sealed, err := next.seal(plain)
+			if err != nil {
+				return 0, fmt.Errorf("failed to encrypt %s/%s: %w", bucket, key, err)
+			}
+
+			rekeyed[bucket][key] = sealed
+			count++
+		}
+	}
+
+	s.data = rekeyed
+	s.keys.keys[next.id] = next
+	s.keys.current = next
+	s.dirty = true
+
+	if err := s.flush(); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Close flushes pending data to disk. Unchanged data isn't written, so an instance that only
+// read the store doesn't overwrite changes made by another one.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	return s.flush()
+}
+
+// flush atomically writes data to disk, must be called with the lock held
+func (s *Store) flush() error {
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace store: %w", err)
+	}
+
+	s.dirty = false
+
+	return nil
+}
@@ -3,29 +3,55 @@ package telegram
 import (
 	"context"
 	"fmt"
+	"html"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/kirill/deriv-teletrader/pkg/core"
 )
 
+// Long polling timeouts, Telegram answers right away when an update arrives
+const (
+	pollTimeout    = 60 * time.Second
+	pollRetryDelay = 3 * time.Second // Pause after a failed poll
+)
+
 // MessageProcessor defines the interface for processing chat messages
 type MessageProcessor interface {
 	ProcessMessage(ctx context.Context, msg *core.Message) (*core.Response, error)
 }
 
+// IdleReporter is implemented by processors that know when nobody is using the bot
+type IdleReporter interface {
+	Idle() bool
+}
+
+// LatencyReporter is implemented by processors that keep track of how long sending messages takes
+type LatencyReporter interface {
+	ObserveSendLatency(latency time.Duration)
+}
+
 // Config holds configuration specific to the Telegram bot
 type Config struct {
 	Token            string   `mapstructure:"token"`
 	AllowedUsernames []string `mapstructure:"allowed_usernames"`
 	Debug            bool     `mapstructure:"debug"`
+
+	// IdlePollTimeout is the long polling timeout used while the processor is idle
+	IdlePollTimeout time.Duration `mapstructure:"idle_poll_timeout"`
 }
 
 type Bot struct {
 	api       *tgbotapi.BotAPI
 	processor MessageProcessor
+	cfg       *Config
+
+	mu        sync.Mutex
+	stopPolls context.CancelFunc
+	handling  sync.WaitGroup // Updates being handled
 }
 
 // NewBot creates a new instance of the Telegram bot
@@ -40,6 +66,7 @@ func NewBot(cfg *Config, processor MessageProcessor) (*Bot, error) {
 	bot := &Bot{
 		api:       api,
 		processor: processor,
+		cfg:       cfg,
 	}
 
 	return bot, nil
@@ -47,26 +74,103 @@ func NewBot(cfg *Config, processor MessageProcessor) (*Bot, error) {
 
 // Start begins polling for updates from Telegram
 func (b *Bot) Start(ctx context.Context) error {
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	pollCtx, cancel := context.WithCancel(ctx)
 
-	updates := b.api.GetUpdatesChan(u)
+	b.mu.Lock()
+	b.stopPolls = cancel
+	b.mu.Unlock()
+
+	updates := make(chan tgbotapi.Update, b.api.Buffer)
+	go b.poll(pollCtx, updates)
 
 	for {
 		select {
 		case <-ctx.Done():
+			// Stop long polling, so another instance can take over receiving updates
+			b.Stop()
+			b.handling.Wait()
 			return ctx.Err()
 		case update := <-updates:
-			if err := b.handleUpdate(ctx, update); err != nil {
-				log.Printf("Error handling update: %v", err)
-			}
+			// Updates are handled concurrently, so a button like Cancel works while a slow command runs
+			b.handling.Add(1)
+			go func() {
+				defer b.handling.Done()
+
+				if err := b.handleUpdate(ctx, update); err != nil {
+					log.Printf("Error handling update: %v", err)
+				}
+			}()
 		}
 	}
 }
 
 // Stop gracefully shuts down the bot
 func (b *Bot) Stop() {
-	b.api.StopReceivingUpdates()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopPolls != nil {
+		b.stopPolls()
+	}
+}
+
+// poll long polls Telegram for updates until ctx is canceled. While the processor is idle
+// polls are held open longer, so a rarely used bot makes fewer requests and still replies right away.
+func (b *Bot) poll(ctx context.Context, updates chan<- tgbotapi.Update) {
+	u := tgbotapi.NewUpdate(0)
+	idle := false
+
+	for ctx.Err() == nil {
+		timeout := pollTimeout
+		if b.idle() {
+			timeout = b.cfg.IdlePollTimeout
+		}
+
+		if nowIdle := timeout != pollTimeout; nowIdle != idle {
+			idle = nowIdle
+			if idle {
+				log.Printf("No recent activity, holding Telegram polls open for %s", timeout)
+			} else {
+				log.Printf("Activity resumed, holding Telegram polls open for %s", timeout)
+			}
+		}
+
+		u.Timeout = int(timeout.Seconds())
+
+		batch, err := b.api.GetUpdates(u)
+		if err != nil {
+			log.Printf("Failed to get updates: %v", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pollRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range batch {
+			if update.UpdateID >= u.Offset {
+				u.Offset = update.UpdateID + 1
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case updates <- update:
+			}
+		}
+	}
+}
+
+// idle reports whether long polls should use the idle timeout
+func (b *Bot) idle() bool {
+	if b.cfg.IdlePollTimeout <= pollTimeout {
+		return false
+	}
+
+	reporter, ok := b.processor.(IdleReporter)
+	return ok && reporter.Idle()
 }
 
 // handleUpdate processes incoming updates
@@ -84,7 +188,9 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) error {
 			ChatID:       chatID,
 			MessageID:    messageID,
 			Username:     update.CallbackQuery.From.UserName,
+			LanguageCode: update.CallbackQuery.From.LanguageCode,
 			CallbackData: update.CallbackQuery.Data,
+			InGroup:      isGroup(update.CallbackQuery.Message.Chat),
 		}
 
 		// Answer callback query to remove loading state
@@ -99,15 +205,18 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) error {
 		messageID = msg.MessageID
 
 		coreMsg = &core.Message{
-			ChatID:    chatID,
-			MessageID: messageID,
-			Username:  msg.From.UserName,
+			ChatID:       chatID,
+			MessageID:    messageID,
+			Username:     msg.From.UserName,
+			LanguageCode: msg.From.LanguageCode,
+			InGroup:      isGroup(msg.Chat),
 		}
 
 		// Handle commands
 		if msg.IsCommand() {
 			coreMsg.Command = msg.Command()
 			coreMsg.Args = strings.Fields(msg.CommandArguments())
+			coreMsg.ArgsText = msg.CommandArguments()
 		} else if msg.Text != "" {
 			// Handle regular messages by putting the text into Args
 			coreMsg.Args = []string{msg.Text}
@@ -154,51 +263,143 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) error {
 		return fmt.Errorf("failed to process message: %w", err)
 	}
 
+	// Nothing to reply, e.g. the handler delivers messages itself
+	if response == nil {
+		return nil
+	}
+
+	_, err = b.Send(ctx, response)
+
+	return err
+}
+
+// Send delivers the response to its chat and returns the ID of the sent or edited message
+func (b *Bot) Send(ctx context.Context, response *core.Response) (int, error) {
+	start := time.Now()
+	id, err := b.send(ctx, response)
+
+	if reporter, ok := b.processor.(LatencyReporter); ok {
+		reporter.ObserveSendLatency(time.Since(start))
+	}
+
+	return id, err
+}
+
+// send delivers the response to Telegram
+func (b *Bot) send(_ context.Context, response *core.Response) (int, error) {
+	// Delete the original message if requested, e.g. when it contains secrets
+	if response.DeleteMessageID != 0 {
+		deleteMsg := tgbotapi.NewDeleteMessage(response.ChatID, response.DeleteMessageID)
+		if _, err := b.api.Request(deleteMsg); err != nil {
+			log.Printf("Failed to delete message: %v", err)
+		}
+	}
+
+	// Edit an existing message in place
+	if response.EditMessageID != 0 {
+		edit := tgbotapi.NewEditMessageText(response.ChatID, response.EditMessageID, response.Text)
+		if response.Preformatted {
+			edit.Text, edit.ParseMode = preformatted(response.Text), tgbotapi.ModeHTML
+		}
+		if len(response.Buttons) > 0 {
+			keyboard := buildKeyboard(response.Buttons)
+			edit.ReplyMarkup = &keyboard
+		}
+
+		if _, err := b.api.Send(edit); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+			return 0, fmt.Errorf("failed to edit message: %w", err)
+		}
+
+		return response.EditMessageID, nil
+	}
+
 	// Send photo if provided
 	if response.PhotoPath != "" {
 		photo := tgbotapi.NewPhoto(response.ChatID, tgbotapi.FilePath(response.PhotoPath))
 		photo.ReplyToMessageID = response.ReplyToMessageID
+		photo.DisableNotification = response.Silent
 
 		// Add inline keyboard if buttons are provided
 		if len(response.Buttons) > 0 {
-			var keyboard [][]tgbotapi.InlineKeyboardButton
-			for _, row := range response.Buttons {
-				var keyboardRow []tgbotapi.InlineKeyboardButton
-				for _, btn := range row {
-					keyboardRow = append(keyboardRow, tgbotapi.NewInlineKeyboardButtonData(btn.Text, btn.CallbackData))
-				}
-				keyboard = append(keyboard, keyboardRow)
-			}
-			photo.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+			photo.ReplyMarkup = buildKeyboard(response.Buttons)
 		}
 
 		// Send photo with caption
 		photo.Caption = response.Text
-		if _, err := b.api.Send(photo); err != nil {
-			return fmt.Errorf("failed to send photo: %w", err)
+		sent, err := b.api.Send(photo)
+		if err != nil {
+			return 0, fmt.Errorf("failed to send photo: %w", err)
 		}
-	} else {
-		// Send text message
-		reply := tgbotapi.NewMessage(response.ChatID, response.Text)
-		reply.ReplyToMessageID = response.ReplyToMessageID
 
-		// Add inline keyboard if buttons are provided
+		return sent.MessageID, nil
+	}
+
+	// Send document if provided
+	if response.Document != nil {
+		doc := tgbotapi.NewDocument(response.ChatID, tgbotapi.FileBytes{
+			Name:  response.Document.Name,
+			Bytes: response.Document.Data,
+		})
+		doc.ReplyToMessageID = response.ReplyToMessageID
+		doc.DisableNotification = response.Silent
+		doc.Caption = response.Text
+
 		if len(response.Buttons) > 0 {
-			var keyboard [][]tgbotapi.InlineKeyboardButton
-			for _, row := range response.Buttons {
-				var keyboardRow []tgbotapi.InlineKeyboardButton
-				for _, btn := range row {
-					keyboardRow = append(keyboardRow, tgbotapi.NewInlineKeyboardButtonData(btn.Text, btn.CallbackData))
-				}
-				keyboard = append(keyboard, keyboardRow)
-			}
-			reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboard...)
+			doc.ReplyMarkup = buildKeyboard(response.Buttons)
 		}
 
-		if _, err := b.api.Send(reply); err != nil {
-			return fmt.Errorf("failed to send reply: %w", err)
+		sent, err := b.api.Send(doc)
+		if err != nil {
+			return 0, fmt.Errorf("failed to send document: %w", err)
 		}
+
+		return sent.MessageID, nil
+	}
+
+	// Send text message
+	reply := tgbotapi.NewMessage(response.ChatID, response.Text)
+	reply.ReplyToMessageID = response.ReplyToMessageID
+	reply.DisableNotification = response.Silent
+	if response.Preformatted {
+		reply.Text, reply.ParseMode = preformatted(response.Text), tgbotapi.ModeHTML
+	}
+
+	// Add inline keyboard if buttons are provided
+	if len(response.Buttons) > 0 {
+		reply.ReplyMarkup = buildKeyboard(response.Buttons)
+	}
+
+	sent, err := b.api.Send(reply)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send reply: %w", err)
 	}
 
-	return nil
+	return sent.MessageID, nil
+}
+
+// preformatted wraps the text into an HTML monospace block
+func preformatted(text string) string {
+	return "<pre>" + html.EscapeString(text) + "</pre>"
+}
+
+// isGroup reports whether the chat is a group or a supergroup
+func isGroup(chat *tgbotapi.Chat) bool {
+	return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+}
+
+// buildKeyboard converts response buttons to an inline keyboard
+func buildKeyboard(buttons [][]core.Button) tgbotapi.InlineKeyboardMarkup {
+	var keyboard [][]tgbotapi.InlineKeyboardButton
+	for _, row := range buttons {
+		var keyboardRow []tgbotapi.InlineKeyboardButton
+		for _, btn := range row {
+			if btn.URL != "" {
+				keyboardRow = append(keyboardRow, tgbotapi.NewInlineKeyboardButtonURL(btn.Text, btn.URL))
+			} else {
+				keyboardRow = append(keyboardRow, tgbotapi.NewInlineKeyboardButtonData(btn.Text, btn.CallbackData))
+			}
+		}
+		keyboard = append(keyboard, keyboardRow)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(keyboard...)
 }
@@ -24,10 +24,11 @@ const (
 
 // HistoricalDataRequest represents parameters for historical data request
 type HistoricalDataRequest struct {
-	Symbol   string
-	Interval TimeInterval // Time interval (hour, day, week, month)
-	Style    DataStyle    // "ticks" or "candles"
-	Count    int          // Number of ticks/candles to return
+	Symbol      string
+	Interval    TimeInterval // Time interval (hour, day, week, month)
+	Style       DataStyle    // "ticks" or "candles"
+	Count       int          // Number of ticks/candles to return
+	Granularity int          // Candle duration in seconds, 1 minute when zero
 }
 
 // HistoricalDataPoint represents a single historical data point